@@ -0,0 +1,47 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche config cloud-budget command
+func newCloudBudgetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cloud-budget [monthlyBudget | disable]",
+		Short: "set the monthly budget of the cloud servers created by the CLI",
+		Long: `set the monthly budget, in USD, of the cloud servers created by the CLI.
+
+Node creation commands print the estimated cost of the cloud servers to create, and
+refuse to create them if their estimated monthly cost exceeds the budget, unless
+--ignore-budget is given.`,
+		RunE:         handleCloudBudgetSettings,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+
+	return cmd
+}
+
+func handleCloudBudgetSettings(_ *cobra.Command, args []string) error {
+	if args[0] == constants.Disable {
+		ux.Logger.PrintToUser("Cloud budget disabled")
+		return saveCloudBudgetPreferences(0)
+	}
+	budget, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || budget <= 0 {
+		return errors.New("Invalid cloud-budget argument '" + args[0] + "': expected a positive amount of USD or 'disable'")
+	}
+	ux.Logger.PrintToUser("Cloud budget set to $%.2f per month", budget)
+	return saveCloudBudgetPreferences(budget)
+}
+
+func saveCloudBudgetPreferences(budget float64) error {
+	return app.Conf.SetConfigValue(constants.ConfigCloudMonthlyBudgetKey, budget)
+}
@@ -30,5 +30,7 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newSingleNodeCmd())
 	cmd.AddCommand(newAuthorizeCloudAccessCmd())
+	cmd.AddCommand(newCloudBudgetCmd())
+	cmd.AddCommand(newPriceTableCmd())
 	return cmd
 }
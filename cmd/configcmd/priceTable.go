@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/cost"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const defaultPriceTableArg = "default"
+
+// avalanche config price-table command
+func newPriceTableCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "price-table [priceTableFile | default]",
+		Short: "set the price table used to estimate cloud costs",
+		Long: `set the price table used to estimate the cost of the cloud servers created by the CLI,
+replacing the one bundled with the CLI. Use 'default' to go back to the bundled one.
+
+The price table is a JSON file with the on-demand hourly prices, in USD, of each
+instance type by region, and of static IPs, such as:
+
+{
+  "updatedAt": "2024-03-01",
+  "aws": {
+    "instances": {"c5.2xlarge": {"default": 0.4, "us-east-1": 0.34}},
+    "staticIP": 0.005
+  },
+  "gcp": {
+    "instances": {"e2-standard-8": {"default": 0.31, "us-east1": 0.268}},
+    "staticIP": 0.005
+  }
+}
+
+The price at the 'default' region is used for the regions not listed.`,
+		RunE:         handlePriceTableSettings,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+
+	return cmd
+}
+
+func handlePriceTableSettings(_ *cobra.Command, args []string) error {
+	priceTablePath := app.GetPriceTablePath()
+	if args[0] == defaultPriceTableArg {
+		if utils.FileExists(priceTablePath) {
+			if err := os.Remove(priceTablePath); err != nil {
+				return err
+			}
+		}
+		priceTable, err := cost.DefaultPriceTable()
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Using the bundled price table, with prices as of %s", priceTable.UpdatedAt)
+		return nil
+	}
+	priceTableBytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	priceTable, err := cost.ParsePriceTable(priceTableBytes)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(priceTablePath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(priceTablePath, priceTableBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Using the given price table, with prices as of %s", priceTable.UpdatedAt)
+	return nil
+}
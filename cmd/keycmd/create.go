@@ -4,6 +4,8 @@ package keycmd
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"regexp"
 
 	"github.com/ava-labs/avalanche-cli/pkg/key"
@@ -17,8 +19,13 @@ const (
 )
 
 var (
-	forceCreate bool
-	filename    string
+	forceCreate    bool
+	filename       string
+	encryptKey     bool
+	newMnemonic    bool
+	importMnemonic bool
+	mnemonicWords  int
+	hdIndices      []uint
 )
 
 func createKey(_ *cobra.Command, args []string) error {
@@ -32,6 +39,16 @@ func createKey(_ *cobra.Command, args []string) error {
 		return errors.New("key already exists. Use --" + forceFlag + " parameter to overwrite")
 	}
 
+	if newMnemonic || importMnemonic {
+		if filename != "" {
+			return errors.New("--file can't be used together with mnemonic based key creation")
+		}
+		if newMnemonic && importMnemonic {
+			return errors.New("--mnemonic and --import-mnemonic are mutually exclusive")
+		}
+		return createHDKey(keyName)
+	}
+
 	if filename == "" {
 		// Create key from scratch
 		ux.Logger.PrintToUser("Generating new key...")
@@ -40,7 +57,7 @@ func createKey(_ *cobra.Command, args []string) error {
 			return err
 		}
 		keyPath := app.GetKeyPath(keyName)
-		if err := k.Save(keyPath); err != nil {
+		if err := saveKey(k, keyPath); err != nil {
 			return err
 		}
 		ux.Logger.PrintToUser("Key created")
@@ -48,27 +65,120 @@ func createKey(_ *cobra.Command, args []string) error {
 		// Load key from file
 		// TODO add validation that key is legal
 		ux.Logger.PrintToUser("Loading user key...")
-		if err := app.CopyKeyFile(filename, keyName); err != nil {
-			return err
-		}
 		keyPath := app.GetKeyPath(keyName)
-		ux.Logger.PrintToUser("Key loaded")
-		networks := []models.Network{models.NewFujiNetwork(), models.NewMainnetNetwork()}
-		pchain := true
-		cchain := true
-		xchain := true
-		pClients, xClients, cClients, evmClients, err := getClients(networks, pchain, cchain, xchain, "")
+		kb, err := os.ReadFile(filename)
 		if err != nil {
 			return err
 		}
-		addrInfos, err := getStoredKeyInfo(pClients, xClients, cClients, evmClients, networks, keyPath)
+		switch {
+		case !encryptKey || key.IsEncryptedKeyFile(kb):
+			if err := app.CopyKeyFile(filename, keyName); err != nil {
+				return err
+			}
+		case key.IsHDKeyFile(kb):
+			f, err := key.ParseHDKeyFile(kb)
+			if err != nil {
+				return err
+			}
+			if err := saveHDKeyFile(f, keyPath); err != nil {
+				return err
+			}
+		default:
+			k, err := key.LoadSoftFromBytes(0, kb)
+			if err != nil {
+				return err
+			}
+			if err := saveKey(k, keyPath); err != nil {
+				return err
+			}
+		}
+		ux.Logger.PrintToUser("Key loaded")
+		return printPublicNetworksKeyInfo(keyPath)
+	}
+
+	return nil
+}
+
+// createHDKey creates a key from a new or imported BIP-39 mnemonic,
+// storing the requested account indices
+func createHDKey(keyName string) error {
+	var (
+		mnemonic string
+		err      error
+	)
+	if importMnemonic {
+		mnemonic, err = app.Prompt.CapturePassword("Enter the BIP-39 mnemonic")
+	} else {
+		ux.Logger.PrintToUser("Generating new mnemonic...")
+		mnemonic, err = key.NewMnemonic(mnemonicWords)
+	}
+	if err != nil {
+		return err
+	}
+	indices := make([]uint32, 0, len(hdIndices))
+	for _, index := range hdIndices {
+		indices = append(indices, uint32(index))
+	}
+	f, err := key.NewHDKeyFile(mnemonic, indices)
+	if err != nil {
+		return err
+	}
+	keyPath := app.GetKeyPath(keyName)
+	if err := saveHDKeyFile(f, keyPath); err != nil {
+		return err
+	}
+	if newMnemonic {
+		ux.Logger.PrintToUser("Write down the following mnemonic and keep it in a safe place.")
+		ux.Logger.PrintToUser("It is the only way to recover the key:")
+		// not using the user logger, so the mnemonic does not end up in the log file
+		fmt.Printf("\n%s\n\n", f.Mnemonic)
+	}
+	ux.Logger.PrintToUser("Key created")
+	return printPublicNetworksKeyInfo(keyPath)
+}
+
+// printPublicNetworksKeyInfo prints the addresses and balances of
+// the key at [keyPath] on fuji and mainnet
+func printPublicNetworksKeyInfo(keyPath string) error {
+	networks := []models.Network{models.NewFujiNetwork(), models.NewMainnetNetwork()}
+	pchain := true
+	cchain := true
+	xchain := true
+	pClients, xClients, cClients, evmClients, err := getClients(networks, pchain, cchain, xchain, "")
+	if err != nil {
+		return err
+	}
+	addrInfos, err := getStoredKeyInfo(pClients, xClients, cClients, evmClients, networks, keyPath)
+	if err != nil {
+		return err
+	}
+	printAddrInfos(addrInfos)
+	return nil
+}
+
+// saveHDKeyFile saves [f] at [keyPath], with the mnemonic encrypted if so requested
+func saveHDKeyFile(f *key.HDKeyFile, keyPath string) error {
+	passphrase := ""
+	if encryptKey {
+		var err error
+		passphrase, err = getNewKeyPassphrase()
 		if err != nil {
 			return err
 		}
-		printAddrInfos(addrInfos)
 	}
+	return f.Save(keyPath, passphrase)
+}
 
-	return nil
+// saveKey saves [k] at [keyPath], encrypted if so requested
+func saveKey(k *key.SoftKey, keyPath string) error {
+	if !encryptKey {
+		return k.Save(keyPath)
+	}
+	passphrase, err := getNewKeyPassphrase()
+	if err != nil {
+		return err
+	}
+	return k.SaveEncrypted(keyPath, passphrase)
 }
 
 func newCreateCmd() *cobra.Command {
@@ -83,7 +193,17 @@ The command works by generating a secp256 key and storing it with the provided k
 can use this key in other commands by providing this keyName.
 
 If you'd like to import an existing key instead of generating one from scratch, provide the
---file flag.`,
+--file flag.
+
+Use --encrypt to store the key encrypted with a passphrase. The passphrase is asked for, unless it
+is given by the AVALANCHE_CLI_KEY_PASSPHRASE environment variable, or by a file which path is given
+by the AVALANCHE_CLI_KEY_PASSPHRASE_FILE environment variable.
+
+Use --mnemonic to generate the key from a new BIP-39 mnemonic, or --import-mnemonic to create
+it from an existing one. Keys are derived following BIP-44, using m/44'/9000'/0'/0/index
+for P-Chain and X-Chain addresses, and m/44'/60'/0'/0/index for C-Chain addresses. Several
+account indices can be stored under the same key name with --indices. Commands that take
+the key name as a signing key use the first stored index.`,
 		Args:         cobra.ExactArgs(1),
 		RunE:         createKey,
 		SilenceUsage: true,
@@ -95,6 +215,36 @@ If you'd like to import an existing key instead of generating one from scratch,
 		"",
 		"import the key from an existing key file",
 	)
+	cmd.Flags().BoolVar(
+		&encryptKey,
+		"encrypt",
+		false,
+		"store the key encrypted with a passphrase",
+	)
+	cmd.Flags().BoolVar(
+		&newMnemonic,
+		"mnemonic",
+		false,
+		"generate the key from a new BIP-39 mnemonic",
+	)
+	cmd.Flags().BoolVar(
+		&importMnemonic,
+		"import-mnemonic",
+		false,
+		"create the key from an existing BIP-39 mnemonic",
+	)
+	cmd.Flags().IntVar(
+		&mnemonicWords,
+		"mnemonic-words",
+		24,
+		"number of words of the generated mnemonic (12 or 24)",
+	)
+	cmd.Flags().UintSliceVar(
+		&hdIndices,
+		"indices",
+		[]uint{0},
+		"BIP-44 account indices to store for a mnemonic based key",
+	)
 	cmd.Flags().BoolVarP(
 		&forceCreate,
 		forceFlag,
@@ -0,0 +1,166 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package keycmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	encryptAll bool
+
+	// keys managed by the CLI, that are used without user interaction
+	cliManagedKeyNames = []string{constants.TeleporterKeyName, constants.AWMRelayerKeyName}
+)
+
+func newEncryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [keyName]",
+		Short: "Encrypts stored signing keys with a passphrase",
+		Long: `The key encrypt command encrypts existing plaintext signing keys in place, using
+the ethereum keystore v3 format. Afterwards, every command that uses the key asks for its
+passphrase, unless it is given by the AVALANCHE_CLI_KEY_PASSPHRASE environment variable, or
+by a file which path is given by the AVALANCHE_CLI_KEY_PASSPHRASE_FILE environment variable.
+
+Use --all to encrypt all stored plaintext keys with the same passphrase. Keys managed by
+the CLI itself (teleporter deployer and relayer keys) are skipped in that case.`,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         encryptKeys,
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&encryptAll, "all", false, "encrypt all plaintext keys")
+	return cmd
+}
+
+func encryptKeys(_ *cobra.Command, args []string) error {
+	keyNames := args
+	switch {
+	case len(args) == 1 && encryptAll:
+		return errors.New("a key name can't be given together with --all")
+	case len(args) == 0 && !encryptAll:
+		return errors.New("a key name or --all must be given")
+	case encryptAll:
+		var err error
+		keyNames, err = getPlaintextKeyNames()
+		if err != nil {
+			return err
+		}
+		if len(keyNames) == 0 {
+			ux.Logger.PrintToUser("All keys are already encrypted")
+			return nil
+		}
+	default:
+		if !app.KeyExists(args[0]) {
+			return fmt.Errorf("key %s does not exist", args[0])
+		}
+	}
+	passphrase, err := getNewKeyPassphrase()
+	if err != nil {
+		return err
+	}
+	for _, keyName := range keyNames {
+		encrypted, err := encryptKeyFile(app.GetKeyPath(keyName), passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt key %s: %w", keyName, err)
+		}
+		if encrypted {
+			ux.Logger.PrintToUser("Key %s encrypted", keyName)
+		} else {
+			ux.Logger.PrintToUser("Key %s is already encrypted", keyName)
+		}
+	}
+	return nil
+}
+
+// getPlaintextKeyNames returns the names of the stored keys that are not
+// encrypted, excluding the ones managed by the CLI
+func getPlaintextKeyNames() ([]string, error) {
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil {
+		return nil, err
+	}
+	keyNames := []string{}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), constants.KeySuffix) {
+			continue
+		}
+		keyName := strings.TrimSuffix(f.Name(), constants.KeySuffix)
+		if slices.Contains(cliManagedKeyNames, keyName) {
+			continue
+		}
+		encrypted, err := key.IsEncryptedKeyPath(filepath.Join(app.GetKeyDir(), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !encrypted {
+			keyNames = append(keyNames, keyName)
+		}
+	}
+	return keyNames, nil
+}
+
+// encryptKeyFile encrypts the plaintext key at [keyPath] in place. Returns
+// false if it was already encrypted
+func encryptKeyFile(keyPath string, passphrase string) (bool, error) {
+	kb, err := os.ReadFile(keyPath)
+	if err != nil {
+		return false, err
+	}
+	if key.IsEncryptedKeyFile(kb) {
+		return false, nil
+	}
+	// write to a temporary file first, so the key is never lost on failure
+	tmpPath := keyPath + ".tmp"
+	if key.IsHDKeyFile(kb) {
+		var f *key.HDKeyFile
+		f, err = key.ParseHDKeyFile(kb)
+		if err != nil {
+			return false, err
+		}
+		err = f.Save(tmpPath, passphrase)
+	} else {
+		var k *key.SoftKey
+		k, err = key.LoadSoftFromBytes(models.NewLocalNetwork().ID, kb)
+		if err != nil {
+			return false, err
+		}
+		err = k.SaveEncrypted(tmpPath, passphrase)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return false, err
+	}
+	return true, os.Rename(tmpPath, keyPath)
+}
+
+// getNewKeyPassphrase gets the passphrase to encrypt a key with, either from
+// the environment or from the user, asking for it twice
+func getNewKeyPassphrase() (string, error) {
+	passphrase, ok, err := key.GetPassphraseFromEnv()
+	if err != nil || ok {
+		return passphrase, err
+	}
+	passphrase, err = app.Prompt.CapturePassword("Enter new key passphrase")
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := app.Prompt.CapturePassword("Confirm key passphrase")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -3,10 +3,13 @@
 package keycmd
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 
 	"github.com/spf13/cobra"
 )
@@ -19,7 +22,9 @@ func newExportCmd() *cobra.Command {
 applications or import it into another instance of Avalanche-CLI.
 
 By default, the tool writes the hex encoded key to stdout. If you provide the --output
-flag, the command writes the key to a file of your choosing.`,
+flag, the command writes the key to a file of your choosing. Encrypted keys are decrypted
+before being exported. For mnemonic based keys, the key of the first stored account
+index is exported.`,
 		Args:         cobra.ExactArgs(1),
 		RunE:         exportKey,
 		SilenceUsage: true,
@@ -30,7 +35,7 @@ flag, the command writes the key to a file of your choosing.`,
 		"output",
 		"o",
 		"",
-		"write the key to the provided file path",
+		"write the key to the provided file path (this flag shadows the global --output format flag)",
 	)
 
 	return cmd
@@ -44,6 +49,13 @@ func exportKey(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if key.IsEncryptedKeyFile(keyBytes) || key.IsHDKeyFile(keyBytes) {
+		k, err := key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+		if err != nil {
+			return err
+		}
+		keyBytes = []byte(hex.EncodeToString(k.Raw()))
+	}
 
 	if filename == "" {
 		fmt.Println(string(keyBytes))
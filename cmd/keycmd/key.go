@@ -43,6 +43,9 @@ To get started, use the key create command.`,
 	// avalanche key export
 	cmd.AddCommand(newExportCmd())
 
+	// avalanche key encrypt
+	cmd.AddCommand(newEncryptCmd())
+
 	// avalanche key transfer
 	cmd.AddCommand(newTransferCmd())
 
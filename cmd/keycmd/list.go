@@ -56,7 +56,8 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List stored signing keys or ledger addresses",
 		Long: `The key list command prints information for all stored signing
-keys or for the ledger addresses associated to certain indices.`,
+keys or for the ledger addresses associated to certain indices. For mnemonic
+based keys, the addresses of each stored account index are shown.`,
 		RunE:         listKeys,
 		SilenceUsage: true,
 	}
@@ -275,6 +276,43 @@ func getStoredKeysInfo(
 	return addrInfos, nil
 }
 
+// storedKeyAccount is a set of addresses of a stored key to show
+type storedKeyAccount struct {
+	name         string
+	avalancheKey *key.SoftKey
+	evmKey       *key.SoftKey
+}
+
+// getStoredKeyAccounts loads the stored key at [keyPath]. Mnemonic based keys
+// give an account for each stored index
+func getStoredKeyAccounts(network models.Network, keyPath string) ([]storedKeyAccount, error) {
+	keyName := strings.TrimSuffix(filepath.Base(keyPath), constants.KeySuffix)
+	kb, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	if !key.IsHDKeyFile(kb) {
+		sk, err := key.LoadSoft(network.ID, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return []storedKeyAccount{{name: keyName, avalancheKey: sk, evmKey: sk}}, nil
+	}
+	hdAccounts, err := key.LoadHD(network.ID, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	accounts := []storedKeyAccount{}
+	for _, hdAccount := range hdAccounts {
+		accounts = append(accounts, storedKeyAccount{
+			name:         fmt.Sprintf("%s index %d", keyName, hdAccount.Index),
+			avalancheKey: hdAccount.AvalancheKey,
+			evmKey:       hdAccount.EVMKey,
+		})
+	}
+	return accounts, nil
+}
+
 func getStoredKeyInfo(
 	pClients map[models.Network]platformvm.Client,
 	xClients map[models.Network]avm.Client,
@@ -285,46 +323,47 @@ func getStoredKeyInfo(
 ) ([]addressInfo, error) {
 	addrInfos := []addressInfo{}
 	for _, network := range networks {
-		keyName := strings.TrimSuffix(filepath.Base(keyPath), constants.KeySuffix)
-		sk, err := key.LoadSoft(network.ID, keyPath)
+		accounts, err := getStoredKeyAccounts(network, keyPath)
 		if err != nil {
 			return nil, err
 		}
-		if _, ok := evmClients[network]; ok {
-			evmAddr := sk.C()
-			addrInfo, err := getEvmBasedChainAddrInfo(subnetName, evmClients, network, evmAddr, "stored", keyName)
-			if err != nil {
-				return nil, err
-			}
-			addrInfos = append(addrInfos, addrInfo)
-		}
-		if _, ok := cClients[network]; ok {
-			cChainAddr := sk.C()
-			addrInfo, err := getEvmBasedChainAddrInfo("C-Chain", cClients, network, cChainAddr, "stored", keyName)
-			if err != nil {
-				return nil, err
-			}
-			addrInfos = append(addrInfos, addrInfo)
-		}
-		if _, ok := pClients[network]; ok {
-			pChainAddrs := sk.P()
-			for _, pChainAddr := range pChainAddrs {
-				addrInfo, err := getPChainAddrInfo(pClients, network, pChainAddr, "stored", keyName)
+		for _, account := range accounts {
+			if _, ok := evmClients[network]; ok {
+				evmAddr := account.evmKey.C()
+				addrInfo, err := getEvmBasedChainAddrInfo(subnetName, evmClients, network, evmAddr, "stored", account.name)
 				if err != nil {
 					return nil, err
 				}
 				addrInfos = append(addrInfos, addrInfo)
 			}
-		}
-		if _, ok := xClients[network]; ok {
-			xChainAddrs := sk.X()
-			for _, xChainAddr := range xChainAddrs {
-				addrInfo, err := getXChainAddrInfo(xClients, network, xChainAddr, "stored", keyName)
+			if _, ok := cClients[network]; ok {
+				cChainAddr := account.evmKey.C()
+				addrInfo, err := getEvmBasedChainAddrInfo("C-Chain", cClients, network, cChainAddr, "stored", account.name)
 				if err != nil {
 					return nil, err
 				}
 				addrInfos = append(addrInfos, addrInfo)
 			}
+			if _, ok := pClients[network]; ok {
+				pChainAddrs := account.avalancheKey.P()
+				for _, pChainAddr := range pChainAddrs {
+					addrInfo, err := getPChainAddrInfo(pClients, network, pChainAddr, "stored", account.name)
+					if err != nil {
+						return nil, err
+					}
+					addrInfos = append(addrInfos, addrInfo)
+				}
+			}
+			if _, ok := xClients[network]; ok {
+				xChainAddrs := account.avalancheKey.X()
+				for _, xChainAddr := range xChainAddrs {
+					addrInfo, err := getXChainAddrInfo(xClients, network, xChainAddr, "stored", account.name)
+					if err != nil {
+						return nil, err
+					}
+					addrInfos = append(addrInfos, addrInfo)
+				}
+			}
 		}
 	}
 	return addrInfos, nil
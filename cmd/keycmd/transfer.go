@@ -4,10 +4,17 @@ package keycmd
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
@@ -26,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
 	"github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +46,10 @@ const (
 	amountFlag              = "amount"
 	wrongLedgerIndexVal     = 32768
 	receiveRecoveryStepFlag = "receive-recovery-step"
+	sourceChainFlag         = "source-chain"
+	destinationChainFlag    = "destination-chain"
+	tokenFlag               = "token"
+	evmNativeTokenDecimals  = 18
 )
 
 var (
@@ -52,13 +64,24 @@ var (
 	receiveRecoveryStep             uint64
 	PToX                            bool
 	PToP                            bool
+	sourceChain                     string
+	destinationChain                string
+	tokenAddrStr                    string
 )
 
 func newTransferCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "transfer [options]",
-		Short:        "Fund a ledger address or stored key from another one",
-		Long:         `The key transfer command allows to transfer funds between stored keys or ledger addresses.`,
+		Use:   "transfer [options]",
+		Short: "Fund a ledger address or stored key from another one",
+		Long: `The key transfer command allows to transfer funds between stored keys or ledger addresses.
+
+Transfers between the P-Chain, X-Chain and C-Chain are atomic transfers, made in two steps:
+the sender exports the funds from the source chain (--send), and the receiver imports
+them on the destination chain (--receive).
+
+Transfers inside the C-Chain or a subnet EVM (given by the subnet name) are plain EVM
+transfers of the native token, or of an ERC-20 token if --token is given. They are made
+in one step, from a stored key.`,
 		RunE:         transferF,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
@@ -76,6 +99,24 @@ func newTransferCmd() *cobra.Command {
 		false,
 		"fund P-Chain account on target",
 	)
+	cmd.Flags().StringVar(
+		&sourceChain,
+		sourceChainFlag,
+		"",
+		"chain to transfer from (P, X, C or a subnet name)",
+	)
+	cmd.Flags().StringVar(
+		&destinationChain,
+		destinationChainFlag,
+		"",
+		"chain to transfer to (P, X, C or a subnet name)",
+	)
+	cmd.Flags().StringVar(
+		&tokenAddrStr,
+		tokenFlag,
+		"",
+		"ERC-20 token address to transfer, instead of the native token (C-Chain and subnet EVMs only)",
+	)
 	cmd.Flags().BoolVar(
 		&force,
 		forceFlag,
@@ -129,7 +170,7 @@ func newTransferCmd() *cobra.Command {
 		amountFlag,
 		"o",
 		0,
-		"amount to send or receive (AVAX units)",
+		"amount to send or receive (AVAX, subnet native token or ERC-20 token units)",
 	)
 	return cmd
 }
@@ -154,6 +195,16 @@ func transferF(*cobra.Command, []string) error {
 		return err
 	}
 
+	if err := getTransferChains(); err != nil {
+		return err
+	}
+	if sourceChain == destinationChain && (sourceChain == subnet.CChainAlias || !isPrimaryChain(sourceChain)) {
+		return evmTransfer(network)
+	}
+	if tokenAddrStr != "" {
+		return fmt.Errorf("--%s is only supported for transfers inside the C-Chain or a subnet EVM", tokenFlag)
+	}
+
 	if !send && !receive {
 		option, err := app.Prompt.CaptureList(
 			"Step of the transfer",
@@ -170,38 +221,11 @@ func transferF(*cobra.Command, []string) error {
 	}
 
 	if !PToP && !PToX {
-		option, err := app.Prompt.CaptureList(
-			"Destination Chain",
-			[]string{"P-Chain", "X-Chain"},
-		)
-		if err != nil {
-			return err
-		}
-		if option == "P-Chain" {
-			PToP = true
-		} else {
-			PToX = true
-		}
+		return atomicTransfer(network)
 	}
 
-	if keyName == "" && ledgerIndex == wrongLedgerIndexVal {
-		var useLedger bool
-		goalStr := ""
-		if send {
-			goalStr = " for the sender address"
-		} else {
-			goalStr = " for the receiver address"
-		}
-		useLedger, keyName, err = prompts.GetFujiKeyOrLedger(app.Prompt, goalStr, app.GetKeyDir())
-		if err != nil {
-			return err
-		}
-		if useLedger {
-			ledgerIndex, err = app.Prompt.CaptureUint32("Ledger index to use")
-			if err != nil {
-				return err
-			}
-		}
+	if err := getTransferKeyOrLedger(); err != nil {
+		return err
 	}
 
 	if amountFlt == 0 {
@@ -211,13 +235,7 @@ func transferF(*cobra.Command, []string) error {
 		} else {
 			promptStr = "Amount to receive (AVAX units)"
 		}
-		amountFlt, err = app.Prompt.CaptureFloat(promptStr, func(v float64) error {
-			if v <= 0 {
-				return fmt.Errorf("value %f must be greater than zero", v)
-			}
-			return nil
-		})
-		if err != nil {
+		if err := captureTransferAmount(promptStr); err != nil {
 			return err
 		}
 	}
@@ -225,24 +243,9 @@ func transferF(*cobra.Command, []string) error {
 
 	fee := network.GenesisParams().TxFee
 
-	var kc keychain.Keychain
-	if keyName != "" {
-		keyPath := app.GetKeyPath(keyName)
-		sk, err := key.LoadSoft(network.ID, keyPath)
-		if err != nil {
-			return err
-		}
-		kc = sk.KeyChain()
-	} else {
-		ledgerDevice, err := ledger.New()
-		if err != nil {
-			return err
-		}
-		ledgerIndices := []uint32{ledgerIndex}
-		kc, err = keychain.NewLedgerKeychainFromIndices(ledgerDevice, ledgerIndices)
-		if err != nil {
-			return err
-		}
+	kc, _, err := getTransferKeychains(network)
+	if err != nil {
+		return err
 	}
 
 	var receiverAddr ids.ShortID
@@ -294,16 +297,8 @@ func transferF(*cobra.Command, []string) error {
 	}
 	ux.Logger.PrintToUser("")
 
-	if !force {
-		confStr := "Confirm transfer"
-		conf, err := app.Prompt.CaptureNoYes(confStr)
-		if err != nil {
-			return err
-		}
-		if !conf {
-			ux.Logger.PrintToUser("Cancelled")
-			return nil
-		}
+	if conf, err := confirmTransfer(); err != nil || !conf {
+		return err
 	}
 
 	to := secp256k1fx.OutputOwners{
@@ -479,3 +474,465 @@ func transferF(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// getTransferChains sets the source and destination chains of the transfer, from
+// the flags or by asking the user. Primary network chains are normalized to their
+// aliases (P, X, C). Any other value is taken as the name of a subnet EVM
+func getTransferChains() error {
+	if PToP && PToX {
+		return errors.New("only one of --fund-p-chain, --fund-x-chain flags should be selected")
+	}
+	sourceChain = normalizeChain(sourceChain)
+	destinationChain = normalizeChain(destinationChain)
+	if PToP || PToX {
+		fundedChain := subnet.PChainAlias
+		if PToX {
+			fundedChain = subnet.XChainAlias
+		}
+		if (sourceChain != "" && sourceChain != subnet.PChainAlias) ||
+			(destinationChain != "" && destinationChain != fundedChain) {
+			return fmt.Errorf("--fund-p-chain and --fund-x-chain transfer from the P-Chain, and can't be combined with other --%s or --%s values", sourceChainFlag, destinationChainFlag)
+		}
+		sourceChain = subnet.PChainAlias
+		destinationChain = fundedChain
+	}
+	if sourceChain == "" {
+		option, err := app.Prompt.CaptureList(
+			"Source Chain",
+			[]string{"P-Chain", "X-Chain", "C-Chain", "Subnet"},
+		)
+		if err != nil {
+			return err
+		}
+		if option == "Subnet" {
+			subnetNames, err := app.GetSidecarNames()
+			if err != nil {
+				return err
+			}
+			if len(subnetNames) == 0 {
+				return errors.New("no subnets have been found")
+			}
+			sourceChain, err = app.Prompt.CaptureList("Subnet", subnetNames)
+			if err != nil {
+				return err
+			}
+		} else {
+			sourceChain = normalizeChain(option)
+		}
+	}
+	if destinationChain == "" {
+		if isPrimaryChain(sourceChain) {
+			options := []string{"P-Chain", "X-Chain", "C-Chain"}
+			if sourceChain == subnet.XChainAlias {
+				options = []string{"P-Chain", "C-Chain"}
+			}
+			option, err := app.Prompt.CaptureList("Destination Chain", options)
+			if err != nil {
+				return err
+			}
+			destinationChain = normalizeChain(option)
+		} else {
+			destinationChain = sourceChain
+		}
+	}
+	if !isPrimaryChain(sourceChain) || !isPrimaryChain(destinationChain) {
+		if sourceChain != destinationChain {
+			return errors.New("transfers between a subnet and another chain are not supported")
+		}
+		if !app.SidecarExists(sourceChain) {
+			return fmt.Errorf("subnet %s does not exist", sourceChain)
+		}
+		isEVM, err := subnetcmd.HasSubnetEVMGenesis(sourceChain)
+		if err != nil {
+			return err
+		}
+		if !isEVM {
+			return fmt.Errorf("subnet %s is not a Subnet-EVM, only EVM transfers are supported", sourceChain)
+		}
+	}
+	if sourceChain == subnet.XChainAlias && destinationChain == subnet.XChainAlias {
+		return errors.New("transfers inside the X-Chain are not supported")
+	}
+	PToP = sourceChain == subnet.PChainAlias && destinationChain == subnet.PChainAlias
+	PToX = sourceChain == subnet.PChainAlias && destinationChain == subnet.XChainAlias
+	return nil
+}
+
+// normalizeChain converts the primary network chain names accepted from the
+// user (p, P, P-Chain...) to their aliases. Other values are left unchanged
+func normalizeChain(chain string) string {
+	alias := strings.TrimSuffix(strings.ToUpper(chain), "-CHAIN")
+	if isPrimaryChain(alias) {
+		return alias
+	}
+	return chain
+}
+
+// isPrimaryChain returns true if [chain] is the alias of a primary network chain
+func isPrimaryChain(chain string) bool {
+	return chain == subnet.PChainAlias || chain == subnet.XChainAlias || chain == subnet.CChainAlias
+}
+
+// getTransferKeyOrLedger asks the user for the key or ledger to use, if none was given
+func getTransferKeyOrLedger() error {
+	if keyName != "" || ledgerIndex != wrongLedgerIndexVal {
+		return nil
+	}
+	goalStr := ""
+	if send {
+		goalStr = " for the sender address"
+	} else {
+		goalStr = " for the receiver address"
+	}
+	useLedger, name, err := prompts.GetFujiKeyOrLedger(app.Prompt, goalStr, app.GetKeyDir())
+	if err != nil {
+		return err
+	}
+	keyName = name
+	if useLedger {
+		ledgerIndex, err = app.Prompt.CaptureUint32("Ledger index to use")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTransferKeychains returns the keychain of the selected stored key or ledger,
+// and the keychain to use for EVM addresses. Mnemonic based keys use their first
+// account. Ledgers are not supported on EVM addresses, so their EVM keychain is empty
+func getTransferKeychains(network models.Network) (keychain.Keychain, *secp256k1fx.Keychain, error) {
+	if keyName != "" {
+		accounts, err := getStoredKeyAccounts(network, app.GetKeyPath(keyName))
+		if err != nil {
+			return nil, nil, err
+		}
+		return accounts[0].avalancheKey.KeyChain(), accounts[0].evmKey.KeyChain(), nil
+	}
+	ledgerDevice, err := ledger.New()
+	if err != nil {
+		return nil, nil, err
+	}
+	ledgerIndices := []uint32{ledgerIndex}
+	kc, err := keychain.NewLedgerKeychainFromIndices(ledgerDevice, ledgerIndices)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kc, secp256k1fx.NewKeychain(), nil
+}
+
+func captureTransferAmount(promptStr string) error {
+	var err error
+	amountFlt, err = app.Prompt.CaptureFloat(promptStr, func(v float64) error {
+		if v <= 0 {
+			return fmt.Errorf("value %f must be greater than zero", v)
+		}
+		return nil
+	})
+	return err
+}
+
+// confirmTransfer asks the user to confirm the transfer, unless --force is given
+func confirmTransfer() (bool, error) {
+	if force {
+		return true, nil
+	}
+	conf, err := app.Prompt.CaptureNoYes("Confirm transfer")
+	if err != nil {
+		return false, err
+	}
+	if !conf {
+		ux.Logger.PrintToUser("Cancelled")
+	}
+	return conf, nil
+}
+
+// atomicTransfer makes the send (export) or receive (import) step of an atomic
+// transfer between primary network chains, other than the P -> P and P -> X ones
+func atomicTransfer(network models.Network) error {
+	if err := getTransferKeyOrLedger(); err != nil {
+		return err
+	}
+	usingLedger := ledgerIndex != wrongLedgerIndexVal
+	if usingLedger && send && sourceChain == subnet.CChainAlias {
+		return errors.New("ledger is not supported to export from the C-Chain, use a stored key")
+	}
+	kc, evmKc, err := getTransferKeychains(network)
+	if err != nil {
+		return err
+	}
+	fee := network.GenesisParams().TxFee
+	hrp := key.GetHRP(network.ID)
+	cChainFeeMsg := "the C-Chain %s fee, which depends on the current base fee, is taken from %s"
+
+	if send {
+		if amountFlt == 0 {
+			if err := captureTransferAmount("Amount to send (AVAX units)"); err != nil {
+				return err
+			}
+		}
+		amount := uint64(amountFlt * float64(units.Avax))
+		if receiverAddrStr == "" {
+			switch destinationChain {
+			case subnet.PChainAlias:
+				receiverAddrStr, err = app.Prompt.CapturePChainAddress("Receiver address", network)
+			case subnet.XChainAlias:
+				receiverAddrStr, err = app.Prompt.CaptureXChainAddress("Receiver address", network)
+			default:
+				receiverAddrStr, err = app.Prompt.CaptureValidatedString(
+					"Receiver address (P-Chain or X-Chain address of the key that is going to import the funds)",
+					func(s string) error {
+						_, err := address.ParseToID(s)
+						return err
+					},
+				)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		receiverAddr, err := address.ParseToID(receiverAddrStr)
+		if err != nil {
+			return err
+		}
+		var senderAddrStr string
+		if sourceChain == subnet.CChainAlias {
+			senderAddrStr = evmKc.EthAddresses().List()[0].Hex()
+		} else {
+			senderAddr := kc.Addresses().List()[0]
+			senderAddrStr, err = address.Format(sourceChain, hrp, senderAddr[:])
+			if err != nil {
+				return err
+			}
+		}
+		// the sender pays the P-Chain and X-Chain fees, so the receiver gets [amount]
+		exportAmount := amount
+		totalFee := uint64(0)
+		if sourceChain != subnet.CChainAlias {
+			totalFee += fee
+		}
+		if destinationChain != subnet.CChainAlias {
+			exportAmount += fee
+			totalFee += fee
+		}
+
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("this operation is going to:")
+		ux.Logger.PrintToUser("- send %.9f AVAX from %s-Chain address %s to target address %s on the %s-Chain",
+			float64(amount)/float64(units.Avax), sourceChain, senderAddrStr, receiverAddrStr, destinationChain)
+		if totalFee > 0 {
+			ux.Logger.PrintToUser("- take a fee of %.9f AVAX from source address %s", float64(totalFee)/float64(units.Avax), senderAddrStr)
+		}
+		if sourceChain == subnet.CChainAlias {
+			ux.Logger.PrintToUser("- "+cChainFeeMsg, "export", "source address "+senderAddrStr)
+		}
+		if destinationChain == subnet.CChainAlias {
+			ux.Logger.PrintToUser("- "+cChainFeeMsg, "import", "the received amount")
+		}
+		ux.Logger.PrintToUser("")
+		if conf, err := confirmTransfer(); err != nil || !conf {
+			return err
+		}
+
+		wallet, err := primary.MakeWallet(
+			context.Background(),
+			&primary.WalletConfig{
+				URI:          network.Endpoint,
+				AVAXKeychain: kc,
+				EthKeychain:  evmKc,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Issuing ExportTx %s -> %s", sourceChain, destinationChain)
+		to := secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{receiverAddr},
+		}
+		txID, err := subnet.IssueAtomicExportTx(wallet, usingLedger, true, sourceChain, destinationChain, exportAmount, &to)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("ExportTx %s issued. The receiver can now import the funds with --%s --%s %s --%s %s",
+			txID, receiveFlag, sourceChainFlag, sourceChain, destinationChainFlag, destinationChain)
+		return nil
+	}
+
+	receiverAddr := kc.Addresses().List()[0]
+	var evmReceiverAddr ethcommon.Address
+	if destinationChain == subnet.CChainAlias {
+		switch {
+		case receiverAddrStr != "":
+			if !ethcommon.IsHexAddress(receiverAddrStr) {
+				return fmt.Errorf("invalid EVM address %s", receiverAddrStr)
+			}
+			evmReceiverAddr = ethcommon.HexToAddress(receiverAddrStr)
+		case evmKc.EthAddresses().Len() > 0:
+			evmReceiverAddr = evmKc.EthAddresses().List()[0]
+		default:
+			evmReceiverAddr, err = app.Prompt.CaptureAddress("C-Chain address to receive the funds at")
+			if err != nil {
+				return err
+			}
+		}
+		receiverAddrStr = evmReceiverAddr.Hex()
+	} else {
+		receiverAddrStr, err = address.Format(destinationChain, hrp, receiverAddr[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("this operation is going to:")
+	ux.Logger.PrintToUser("- receive all the funds exported from the %s-Chain at target address %s on the %s-Chain",
+		sourceChain, receiverAddrStr, destinationChain)
+	if destinationChain == subnet.CChainAlias {
+		ux.Logger.PrintToUser("- "+cChainFeeMsg, "import", "the received funds")
+	} else {
+		ux.Logger.PrintToUser("- take a fee of %.9f AVAX from the received funds", float64(fee)/float64(units.Avax))
+	}
+	ux.Logger.PrintToUser("")
+	if conf, err := confirmTransfer(); err != nil || !conf {
+		return err
+	}
+
+	wallet, err := primary.MakeWallet(
+		context.Background(),
+		&primary.WalletConfig{
+			URI:          network.Endpoint,
+			AVAXKeychain: kc,
+			EthKeychain:  evmKc,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Issuing ImportTx %s -> %s", sourceChain, destinationChain)
+	to := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{receiverAddr},
+	}
+	txID, err := subnet.IssueAtomicImportTx(wallet, usingLedger, true, sourceChain, destinationChain, &to, evmReceiverAddr)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("ImportTx %s issued", txID)
+	return nil
+}
+
+// evmTransfer transfers native tokens, or ERC-20 tokens if --token is given,
+// inside the C-Chain or a subnet EVM
+func evmTransfer(network models.Network) error {
+	if ledgerIndex != wrongLedgerIndexVal {
+		return errors.New("ledger is not supported for transfers inside EVM chains, use a stored key")
+	}
+	if receive {
+		return fmt.Errorf("transfers inside EVM chains are made in one step, --%s is not needed", receiveFlag)
+	}
+	if tokenAddrStr != "" && !ethcommon.IsHexAddress(tokenAddrStr) {
+		return fmt.Errorf("invalid token address %s", tokenAddrStr)
+	}
+	rpcURL, chainDesc, symbol, err := getEVMChainInfo(network, sourceChain)
+	if err != nil {
+		return err
+	}
+	if keyName == "" {
+		keyName, err = prompts.GetKeyName(app.Prompt, "send the funds", app.GetKeyDir())
+		if err != nil {
+			return err
+		}
+	}
+	accounts, err := getStoredKeyAccounts(network, app.GetKeyPath(keyName))
+	if err != nil {
+		return err
+	}
+	sk := accounts[0].evmKey
+	senderAddrStr := sk.C()
+	if receiverAddrStr == "" {
+		receiverAddr, err := app.Prompt.CaptureAddress("Receiver address")
+		if err != nil {
+			return err
+		}
+		receiverAddrStr = receiverAddr.Hex()
+	}
+	if !ethcommon.IsHexAddress(receiverAddrStr) {
+		return fmt.Errorf("invalid EVM address %s", receiverAddrStr)
+	}
+	if ethcommon.HexToAddress(receiverAddrStr) == ethcommon.HexToAddress(senderAddrStr) {
+		return fmt.Errorf("sender addr is the same as receiver addr")
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	nativeSymbol := symbol
+	decimals := uint8(evmNativeTokenDecimals)
+	if tokenAddrStr != "" {
+		symbol, decimals, err = evm.GetERC20Info(client, tokenAddrStr)
+		if err != nil {
+			return err
+		}
+	}
+	if amountFlt == 0 {
+		if err := captureTransferAmount(fmt.Sprintf("Amount to send (%s units)", symbol)); err != nil {
+			return err
+		}
+	}
+	amount, err := utils.ToTokenUnits(amountFlt, decimals)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("this operation is going to:")
+	ux.Logger.PrintToUser("- send %s %s from %s to target address %s on %s", utils.FromTokenUnits(amount, decimals), symbol, senderAddrStr, receiverAddrStr, chainDesc)
+	if tokenAddrStr == "" {
+		gasFeeCap, _, _, err := evm.CalculateTxParams(client, senderAddrStr)
+		if err != nil {
+			return err
+		}
+		maxFee := new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(evm.NativeTransferGas))
+		ux.Logger.PrintToUser("- take a fee of at most %s %s from source address %s", utils.FromTokenUnits(maxFee, evmNativeTokenDecimals), nativeSymbol, senderAddrStr)
+	} else {
+		ux.Logger.PrintToUser("- take the gas fee, in %s, from source address %s", nativeSymbol, senderAddrStr)
+	}
+	ux.Logger.PrintToUser("")
+	if conf, err := confirmTransfer(); err != nil || !conf {
+		return err
+	}
+
+	privKeyStr := hex.EncodeToString(sk.Raw())
+	if tokenAddrStr == "" {
+		err = evm.FundAddress(client, privKeyStr, receiverAddrStr, amount)
+	} else {
+		err = evm.TransferERC20(client, privKeyStr, tokenAddrStr, receiverAddrStr, amount)
+	}
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Transfer completed")
+	return nil
+}
+
+// getEVMChainInfo returns the RPC endpoint, a description and the native token
+// symbol of [chain], the C-Chain or the name of a subnet EVM deployed on [network]
+func getEVMChainInfo(network models.Network, chain string) (string, string, string, error) {
+	if chain == subnet.CChainAlias {
+		return network.CChainEndpoint(), "the C-Chain", "AVAX", nil
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return "", "", "", err
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return "", "", "", fmt.Errorf("subnet %s is not deployed to %s", chain, network.Name())
+	}
+	symbol := sc.TokenSymbol
+	if symbol == "" {
+		symbol = "native tokens"
+	}
+	return network.BlockchainEndpoint(blockchainID.String()), "subnet " + chain, symbol, nil
+}
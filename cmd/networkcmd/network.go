@@ -40,5 +40,9 @@ This network currently supports multiple, concurrently deployed Subnets.`,
 	cmd.AddCommand(newCleanCmd())
 	// network status
 	cmd.AddCommand(newStatusCmd())
+	// network snapshot
+	cmd.AddCommand(newSnapshotCmd())
+	// network node
+	cmd.AddCommand(newNodeCmd())
 	return cmd
 }
@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanche-network-runner/server"
+	"github.com/spf13/cobra"
+)
+
+var forceNodeRemove bool
+
+// avalanche network node
+func newNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Manage the nodes of the local network",
+		Long: `The network node command suite provides a collection of tools for managing the
+individual nodes of the running local network, to test how the deployed subnets behave when
+validators drop out.
+
+Nodes are referred to by their local network name (node1, node2, ...), as shown by
+network status.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network node pause
+	cmd.AddCommand(newNodePauseCmd())
+	// network node resume
+	cmd.AddCommand(newNodeResumeCmd())
+	// network node restart
+	cmd.AddCommand(newNodeRestartCmd())
+	// network node remove
+	cmd.AddCommand(newNodeRemoveCmd())
+	// network node add
+	cmd.AddCommand(newNodeAddCmd())
+	return cmd
+}
+
+// getLocalNetworkClient returns a client to the running local network, together with its cluster info
+func getLocalNetworkClient(ctx context.Context) (client.Client, *rpcpb.ClusterInfo, error) {
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			return nil, nil, errors.New("no local network running")
+		}
+		return nil, nil, err
+	}
+	if status == nil || status.ClusterInfo == nil {
+		return nil, nil, errors.New("no local network running")
+	}
+	return cli, status.ClusterInfo, nil
+}
+
+// getLocalNetworkNode returns the info of the local network node [nodeName]
+func getLocalNetworkNode(clusterInfo *rpcpb.ClusterInfo, nodeName string) (*rpcpb.NodeInfo, error) {
+	nodeInfo, ok := clusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found in the local network", nodeName)
+	}
+	return nodeInfo, nil
+}
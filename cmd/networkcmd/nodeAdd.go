@@ -0,0 +1,113 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network node add
+func newNodeAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [nodeName]",
+		Short: "Add a new node to the local network",
+		Long: `The network node add command starts a new node with the given name and joins it
+to the local network. The node uses the same avalanchego binary and tracks the same subnets
+as the existing nodes, unless --avalanchego-path is given.
+
+The new node is not a validator of the primary network nor of any subnet.`,
+		RunE:         addNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeAvagoBinaryPath, "avalanchego-path", "", "use this avalanchego binary path for the new node")
+	return cmd
+}
+
+func addNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	cli, clusterInfo, err := getLocalNetworkClient(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := clusterInfo.NodeInfos[nodeName]; ok {
+		return fmt.Errorf("node %s already exists in the local network", nodeName)
+	}
+	refNode := getReferenceNode(clusterInfo)
+	if refNode == nil {
+		return errors.New("no nodes found in the local network")
+	}
+	execPath := nodeAvagoBinaryPath
+	if execPath == "" {
+		execPath = refNode.ExecPath
+	}
+	nodeConfig, err := getAddedNodeConfig(refNode)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Adding node %s...", nodeName)
+	resp, err := cli.AddNode(
+		ctx,
+		nodeName,
+		execPath,
+		client.WithPluginDir(app.GetPluginsDir()),
+		client.WithGlobalNodeConfig(nodeConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("failed adding node %s: %w", nodeName, err)
+	}
+	if nodeInfo, ok := resp.ClusterInfo.NodeInfos[nodeName]; ok {
+		ux.Logger.PrintToUser("Node %s added with ID %s and endpoint %s", nodeName, nodeInfo.Id, nodeInfo.Uri)
+	} else {
+		ux.Logger.PrintToUser("Node %s added", nodeName)
+	}
+	return nil
+}
+
+// getReferenceNode returns the first (by name) node of the network, to take the new node settings from
+func getReferenceNode(clusterInfo *rpcpb.ClusterInfo) *rpcpb.NodeInfo {
+	nodeNames := make([]string, 0, len(clusterInfo.NodeInfos))
+	for nodeName := range clusterInfo.NodeInfos {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	if len(nodeNames) == 0 {
+		return nil
+	}
+	sort.Strings(nodeNames)
+	return clusterInfo.NodeInfos[nodeNames[0]]
+}
+
+// getAddedNodeConfig returns the node config for a new node: the global node config, if any,
+// tracking the same subnets as [refNode]
+func getAddedNodeConfig(refNode *rpcpb.NodeInfo) (string, error) {
+	nodeConfig := map[string]interface{}{}
+	configStr, err := app.Conf.LoadNodeConfig()
+	if err != nil {
+		return "", err
+	}
+	if configStr != "" {
+		if err := json.Unmarshal([]byte(configStr), &nodeConfig); err != nil {
+			return "", fmt.Errorf("invalid node config: %w", err)
+		}
+	}
+	if refNode.WhitelistedSubnets != "" {
+		nodeConfig[config.TrackSubnetsKey] = refNode.WhitelistedSubnets
+	}
+	nodeConfigBytes, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(nodeConfigBytes), nil
+}
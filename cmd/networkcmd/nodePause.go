@@ -0,0 +1,46 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network node pause
+func newNodePauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause [nodeName]",
+		Short: "Pause a node of the local network",
+		Long: `The network node pause command stops the avalanchego process of the given local
+network node, keeping its data, so that it can be brought back with network node resume.`,
+		RunE:         pauseNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func pauseNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	cli, clusterInfo, err := getLocalNetworkClient(ctx)
+	if err != nil {
+		return err
+	}
+	nodeInfo, err := getLocalNetworkNode(clusterInfo, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeInfo.Paused {
+		return fmt.Errorf("node %s is already paused", nodeName)
+	}
+	if _, err := cli.PauseNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed pausing node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s paused", nodeName)
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network node remove
+func newNodeRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [nodeName]",
+		Short: "Remove a node from the local network",
+		Long: `The network node remove command stops the given local network node and removes it
+from the network. Unlike network node pause, the node can't be brought back.
+
+The command prompts for confirmation before removing the node. To skip the confirmation,
+provide the --force flag.`,
+		RunE:         removeNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceNodeRemove, "force", "f", false, "remove the node without confirmation")
+	return cmd
+}
+
+func removeNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	cli, clusterInfo, err := getLocalNetworkClient(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := getLocalNetworkNode(clusterInfo, nodeName); err != nil {
+		return err
+	}
+	if len(clusterInfo.NodeNames) == 1 {
+		return fmt.Errorf("node %s is the last node of the local network. Use network stop instead", nodeName)
+	}
+	if !forceNodeRemove {
+		conf, err := app.Prompt.CaptureNoYes("Are you sure you want to remove node " + nodeName + "?")
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Remove cancelled")
+			return nil
+		}
+	}
+	if _, err := cli.RemoveNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed removing node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s removed", nodeName)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/spf13/cobra"
+)
+
+var nodeAvagoBinaryPath string
+
+// avalanche network node restart
+func newNodeRestartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart [nodeName]",
+		Short: "Restart a node of the local network",
+		Long: `The network node restart command stops and starts again the avalanchego process of
+the given local network node, keeping its data. A different avalanchego binary can be given
+with --avalanchego-path.`,
+		RunE:         restartNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&nodeAvagoBinaryPath, "avalanchego-path", "", "restart the node with this avalanchego binary path")
+	return cmd
+}
+
+func restartNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	cli, clusterInfo, err := getLocalNetworkClient(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := getLocalNetworkNode(clusterInfo, nodeName); err != nil {
+		return err
+	}
+	opts := []client.OpOption{}
+	if nodeAvagoBinaryPath != "" {
+		opts = append(opts, client.WithExecPath(nodeAvagoBinaryPath))
+	}
+	ux.Logger.PrintToUser("Restarting node %s...", nodeName)
+	if _, err := cli.RestartNode(ctx, nodeName, opts...); err != nil {
+		return fmt.Errorf("failed restarting node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s restarted", nodeName)
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network node resume
+func newNodeResumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [nodeName]",
+		Short: "Resume a paused node of the local network",
+		Long: `The network node resume command starts again the avalanchego process of a local
+network node paused with network node pause.`,
+		RunE:         resumeNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func resumeNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	cli, clusterInfo, err := getLocalNetworkClient(ctx)
+	if err != nil {
+		return err
+	}
+	nodeInfo, err := getLocalNetworkNode(clusterInfo, nodeName)
+	if err != nil {
+		return err
+	}
+	if !nodeInfo.Paused {
+		return fmt.Errorf("node %s is not paused", nodeName)
+	}
+	if _, err := cli.ResumeNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed resuming node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s resumed", nodeName)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var forceSnapshot bool
+
+// avalanche network snapshot
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage named snapshots of the local network",
+		Long: `The network snapshot command suite provides a collection of tools for managing the
+named snapshots of the local network, as saved by network stop --snapshot-name.
+
+Each snapshot holds the state of all the local nodes, together with the relayer configuration
+and extra network data saved with it. Snapshots can be exported to a single archive, to be
+imported on another machine.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network snapshot list
+	cmd.AddCommand(newSnapshotListCmd())
+	// network snapshot save
+	cmd.AddCommand(newSnapshotSaveCmd())
+	// network snapshot restore
+	cmd.AddCommand(newSnapshotRestoreCmd())
+	// network snapshot delete
+	cmd.AddCommand(newSnapshotDeleteCmd())
+	// network snapshot export
+	cmd.AddCommand(newSnapshotExportCmd())
+	// network snapshot import
+	cmd.AddCommand(newSnapshotImportCmd())
+	return cmd
+}
+
+// isLocalNetworkRunning returns true if the local network is up
+func isLocalNetworkRunning() (bool, error) {
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithAvoidRPCVersionCheck(true),
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if errors.Is(err, binutils.ErrGRPCTimeout) {
+		// no server running
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	return checkNetworkIsAlreadyBootstrapped(ctx, cli)
+}
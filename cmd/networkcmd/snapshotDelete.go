@@ -0,0 +1,56 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network snapshot delete
+func newSnapshotDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete [snapshotName]",
+		Short: "Delete a local network snapshot",
+		Long: `The network snapshot delete command deletes the named snapshot of the local network,
+together with the relayer configuration and extra network data saved with it.
+
+The command prompts for confirmation before deleting the snapshot. To skip the
+confirmation, provide the --force flag. The default snapshot can't be deleted,
+use network clean instead.`,
+		RunE:         deleteSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceSnapshot, "force", "f", false, "delete the snapshot without confirmation")
+	return cmd
+}
+
+func deleteSnapshot(_ *cobra.Command, args []string) error {
+	name := args[0]
+	if name == constants.DefaultSnapshotName {
+		return errors.New("the default snapshot can't be deleted, use network clean instead")
+	}
+	if !subnet.LocalSnapshotExists(app, name) {
+		return errors.New("snapshot does not exist")
+	}
+	if !forceSnapshot {
+		conf, err := app.Prompt.CaptureNoYes("Are you sure you want to delete snapshot " + name + "?")
+		if err != nil {
+			return err
+		}
+		if !conf {
+			ux.Logger.PrintToUser("Delete cancelled")
+			return nil
+		}
+	}
+	if err := subnet.DeleteLocalSnapshot(app, name); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Snapshot deleted")
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var snapshotArchivePath string
+
+// avalanche network snapshot export
+func newSnapshotExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [snapshotName]",
+		Short: "Export a local network snapshot to an archive",
+		Long: `The network snapshot export command writes the named snapshot of the local network,
+together with its relayer configuration and extra network data, to a single tar.gz archive
+that can be imported on another machine with network snapshot import.
+
+The subnet configurations are not part of the archive. Share them with subnet export.`,
+		RunE:         exportSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&snapshotArchivePath, "archive", "", "path of the archive to write (default: <snapshotName>.tar.gz)")
+	cmd.Flags().BoolVarP(&forceSnapshot, "force", "f", false, "overwrite the archive if it already exists")
+	return cmd
+}
+
+func exportSnapshot(_ *cobra.Command, args []string) error {
+	name := args[0]
+	archivePath := snapshotArchivePath
+	if archivePath == "" {
+		archivePath = name + ".tar.gz"
+	}
+	if utils.FileExists(archivePath) && !forceSnapshot {
+		return fmt.Errorf("file %s already exists, use --force to overwrite it", archivePath)
+	}
+	if err := subnet.ExportLocalSnapshot(app, name, archivePath); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Snapshot %s exported to %s", name, archivePath)
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var importedSnapshotName string
+
+// avalanche network snapshot import
+func newSnapshotImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import [archivePath]",
+		Short: "Import a local network snapshot from an archive",
+		Long: `The network snapshot import command installs a local network snapshot archive, as
+written by network snapshot export. The snapshot keeps its original name, unless --name
+is given. It can then be started with network snapshot restore.
+
+The command fails if a snapshot with the same name already exists, unless --force is given.`,
+		RunE:         importSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&importedSnapshotName, "name", "", "name to give to the imported snapshot")
+	cmd.Flags().BoolVarP(&forceSnapshot, "force", "f", false, "overwrite the snapshot if it already exists")
+	return cmd
+}
+
+func importSnapshot(_ *cobra.Command, args []string) error {
+	name, err := subnet.ImportLocalSnapshot(app, args[0], importedSnapshotName, forceSnapshot)
+	if err != nil {
+		return err
+	}
+	info, err := subnet.GetLocalSnapshotInfo(app, name)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Snapshot %s imported (avalanchego %s)", name, info.AvalancheGoVersion)
+	if len(info.Subnets) > 0 {
+		ux.Logger.PrintToUser("Subnets deployed on it: %s", strings.Join(info.Subnets, ", "))
+		ux.Logger.PrintToUser("Import their configurations with subnet import to manage them from this machine")
+	}
+	return nil
+}
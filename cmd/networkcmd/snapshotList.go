@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network snapshot list
+func newSnapshotListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the local network snapshots",
+		Long: `The network snapshot list command lists the named snapshots of the local network,
+with their size on disk, the avalanchego version they were saved with, and the subnets
+deployed on them.`,
+		RunE:         listSnapshots,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		Annotations:  ux.StructuredOutputAnnotations(),
+	}
+}
+
+func listSnapshots(*cobra.Command, []string) error {
+	infos, err := subnet.ListLocalSnapshots(app)
+	if err != nil {
+		return err
+	}
+	if ux.IsStructuredOutput() {
+		return ux.PrintStructured("LocalSnapshotList", infos)
+	}
+	if len(infos) == 0 {
+		ux.Logger.PrintToUser("No local network snapshots found")
+		return nil
+	}
+	header := []string{"snapshot", "size", "avalanchego version", "subnets", "relayer config", "saved at"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	for _, info := range infos {
+		name := info.Name
+		if name == constants.DefaultSnapshotName {
+			name += " (default)"
+		}
+		table.Append([]string{
+			name,
+			ux.FormatSize(info.Size),
+			info.AvalancheGoVersion,
+			strings.Join(info.Subnets, "\n"),
+			strconv.FormatBool(info.HasRelayerConfig),
+			info.ModTime.Format(constants.TimeParseLayout),
+		})
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network snapshot restore
+func newSnapshotRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [snapshotName]",
+		Short: "Start the local network from a named snapshot",
+		Long: `The network snapshot restore command starts the local network from the named
+snapshot, same as network start --snapshot-name. The local network must be stopped.`,
+		RunE:         restoreSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&userProvidedAvagoVersion, "avalanchego-version", latest, "use this version of avalanchego (ex: v1.17.12)")
+	cmd.Flags().StringVar(&avagoBinaryPath, "avalanchego-path", "", "use this avalanchego binary path")
+	return cmd
+}
+
+func restoreSnapshot(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if _, err := subnet.GetLocalSnapshotInfo(app, name); err != nil {
+		return err
+	}
+	running, err := isLocalNetworkRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		return errors.New("the local network is running. Stop it first with network stop")
+	}
+	snapshotName = name
+	return StartNetwork(cmd, nil)
+}
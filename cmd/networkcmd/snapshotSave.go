@@ -0,0 +1,50 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche network snapshot save
+func newSnapshotSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save [snapshotName]",
+		Short: "Save the running local network into a named snapshot",
+		Long: `The network snapshot save command saves the state of the running local network into
+the named snapshot. The network is stopped to safely save its state, same as with
+network stop --snapshot-name. Restart it with network snapshot restore.
+
+The command fails if the snapshot already exists, unless --force is given.`,
+		RunE:         saveSnapshot,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVarP(&forceSnapshot, "force", "f", false, "overwrite the snapshot if it already exists")
+	return cmd
+}
+
+func saveSnapshot(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if subnet.LocalSnapshotExists(app, name) && !forceSnapshot {
+		return fmt.Errorf("snapshot %s already exists, use --force to overwrite it", name)
+	}
+	running, err := isLocalNetworkRunning()
+	if err != nil {
+		return err
+	}
+	if !running {
+		return errors.New("no local network running")
+	}
+	snapshotName = name
+	if err := StopNetwork(cmd, nil); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local network saved into snapshot %s", name)
+	return nil
+}
@@ -4,8 +4,10 @@ package networkcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -25,6 +27,8 @@ var (
 	userProvidedAvagoVersion string
 	snapshotName             string
 	avagoBinaryPath          string
+	numNodes                 uint32
+	nodeConfigDir            string
 )
 
 const (
@@ -40,7 +44,22 @@ func newStartCmd() *cobra.Command {
 
 By default, the command loads the default snapshot. If you provide the --snapshot-name
 flag, the network loads that snapshot instead. The command fails if the local network is
-already running.`,
+already running.
+
+To reproduce issues that depend on the size or configuration of the validator set, the
+--num-nodes and --node-config-dir flags start a new network from scratch instead, with
+the given number of nodes and avalanchego config overrides. The overrides dir has the layout:
+
+  chain-configs/<chain>/config.json          chain configs for all nodes
+  node<i>/flags.json                         avalanchego flags for node i
+  node<i>/chain-configs/<chain>/config.json  chain configs for node i
+  node<i>/staking/staker.key                 staking keys for node i
+  node<i>/staking/staker.crt
+  node<i>/staking/signer.key
+
+Nodes with their own staking keys are not genesis validators. Network stop saves the new
+network into the default snapshot, so the next network start keeps its topology. Use network
+clean to go back to the default one.`,
 
 		RunE:         StartNetwork,
 		Args:         cobra.ExactArgs(0),
@@ -50,6 +69,8 @@ already running.`,
 	cmd.Flags().StringVar(&userProvidedAvagoVersion, "avalanchego-version", latest, "use this version of avalanchego (ex: v1.17.12)")
 	cmd.Flags().StringVar(&avagoBinaryPath, "avalanchego-path", "", "use this avalanchego binary path")
 	cmd.Flags().StringVar(&snapshotName, "snapshot-name", constants.DefaultSnapshotName, "name of snapshot to use to start the network from")
+	cmd.Flags().Uint32Var(&numNodes, "num-nodes", 0, "start a new network with this number of nodes")
+	cmd.Flags().StringVar(&nodeConfigDir, "node-config-dir", "", "start a new network with the avalanchego config overrides of this dir")
 
 	return cmd
 }
@@ -58,7 +79,17 @@ func StartNetwork(*cobra.Command, []string) error {
 	var (
 		err          error
 		avagoVersion string
+		topology     *subnet.LocalNetworkTopology
 	)
+	if numNodes != 0 || nodeConfigDir != "" {
+		if snapshotName != constants.DefaultSnapshotName {
+			return errors.New("--num-nodes and --node-config-dir start a new network, and can't be used with --snapshot-name")
+		}
+		topology, err = getLocalNetworkTopology()
+		if err != nil {
+			return err
+		}
+	}
 	if avagoBinaryPath == "" {
 		avagoVersion, err = determineAvagoVersion(userProvidedAvagoVersion)
 		if err != nil {
@@ -89,6 +120,13 @@ func StartNetwork(*cobra.Command, []string) error {
 		return err
 	}
 
+	if topology != nil {
+		if bootstrapped {
+			return errors.New("the local network is already running. Stop it and run network clean before starting a new one")
+		}
+		return startNetworkWithTopology(ctx, cli, avalancheGoBinPath, topology)
+	}
+
 	if bootstrapped {
 		if !needsRestart {
 			ux.Logger.PrintToUser("Network has already been booted.")
@@ -182,6 +220,109 @@ func StartNetwork(*cobra.Command, []string) error {
 	return nil
 }
 
+// getLocalNetworkTopology returns the topology given by --num-nodes and --node-config-dir.
+// As the network is started from scratch, there should be no subnets deployed on the current one
+func getLocalNetworkTopology() (*subnet.LocalNetworkTopology, error) {
+	locallyDeployedSubnets, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
+	if err != nil {
+		return nil, err
+	}
+	if len(locallyDeployedSubnets) > 0 {
+		return nil, fmt.Errorf(
+			"subnets %s are deployed on the current local network. Run network clean before starting a new one",
+			strings.Join(locallyDeployedSubnets, ", "),
+		)
+	}
+	n := numNodes
+	if n == 0 {
+		n = constants.LocalNetworkNumNodes
+		if app.Conf.GetConfigBoolValue(constants.ConfigSingleNodeEnabledKey) {
+			n = 1
+		}
+	}
+	return subnet.LoadLocalNetworkTopology(n, nodeConfigDir)
+}
+
+// startNetworkWithTopology starts a new local network with the given topology. The per node
+// chain configs are not supported on start by avalanche-network-runner, so those nodes are
+// restarted with them after the network is healthy
+func startNetworkWithTopology(
+	ctx context.Context,
+	cli client.Client,
+	avalancheGoBinPath string,
+	topology *subnet.LocalNetworkTopology,
+) error {
+	outputDirPrefix := filepath.Join(app.GetRunDir(), "network")
+	outputDir, err := anrutils.MkDirWithTimestamp(outputDirPrefix)
+	if err != nil {
+		return err
+	}
+
+	customNodeConfigs, err := topology.CustomNodeConfigs()
+	if err != nil {
+		return err
+	}
+	startOpts := []client.OpOption{
+		client.WithNumNodes(topology.NumNodes),
+		client.WithRootDataDir(outputDir),
+		client.WithReassignPortsIfUsed(true),
+		client.WithPluginDir(app.GetPluginsDir()),
+		client.WithCustomNodeConfigs(customNodeConfigs),
+	}
+	if len(topology.ChainConfigs) > 0 {
+		startOpts = append(startOpts, client.WithChainConfigs(topology.ChainConfigs))
+	}
+
+	// load global node configs if they exist
+	configStr, err := app.Conf.LoadNodeConfig()
+	if err != nil {
+		return err
+	}
+	if configStr != "" {
+		startOpts = append(startOpts, client.WithGlobalNodeConfig(configStr))
+	}
+
+	ux.Logger.PrintToUser("Starting a new local network with %d nodes", topology.NumNodes)
+	if nodeNames := topology.NodesWithCustomStakingKeys(); len(nodeNames) > 0 {
+		ux.Logger.PrintToUser("Nodes %s use their own staking keys and are not genesis validators", strings.Join(nodeNames, ", "))
+	}
+	ux.Logger.PrintToUser("Booting Network. Wait until healthy...")
+	if _, err := cli.Start(ctx, avalancheGoBinPath, startOpts...); err != nil {
+		return fmt.Errorf("failed to start network: %w", err)
+	}
+	clusterInfo, err := subnet.WaitForHealthy(ctx, cli)
+	if err != nil {
+		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+
+	if nodeNames := topology.NodesWithChainConfigs(); len(nodeNames) > 0 {
+		for _, nodeName := range nodeNames {
+			ux.Logger.PrintToUser("Restarting %s with its chain configs...", nodeName)
+			if _, err := cli.RestartNode(
+				ctx,
+				nodeName,
+				client.WithChainConfigs(topology.Nodes[nodeName].ChainConfigs),
+			); err != nil {
+				return fmt.Errorf("failed restarting %s: %w", nodeName, err)
+			}
+		}
+		clusterInfo, err = subnet.WaitForHealthy(ctx, cli)
+		if err != nil {
+			return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+		}
+	}
+
+	ux.Logger.PrintToUser("Node logs directory: %s/node<i>/logs", clusterInfo.RootDataDir)
+	ux.Logger.PrintToUser("Network ready to use.")
+
+	if subnet.HasEndpoints(clusterInfo) {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Local network node endpoints:")
+		ux.PrintTableEndpoints(clusterInfo)
+	}
+	return nil
+}
+
 func determineAvagoVersion(userProvidedAvagoVersion string) (string, error) {
 	// a specific user provided version should override this calculation, so just return
 	if userProvidedAvagoVersion != latest {
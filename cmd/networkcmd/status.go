@@ -3,12 +3,18 @@
 package networkcmd
 
 import (
+	"os"
+	"sort"
+
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
 	"github.com/ava-labs/avalanche-network-runner/server"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 )
 
 func newStatusCmd() *cobra.Command {
@@ -16,7 +22,8 @@ func newStatusCmd() *cobra.Command {
 		Use:   "status",
 		Short: "Prints the status of the local network",
 		Long: `The network status command prints whether or not a local Avalanche
-network is running and some basic stats about the network.`,
+network is running and some basic stats about the network, including the state of each
+of its nodes (running or paused).`,
 
 		RunE:         networkStatus,
 		Args:         cobra.ExactArgs(0),
@@ -52,11 +59,10 @@ func networkStatus(*cobra.Command, []string) error {
 		ux.Logger.PrintToUser("Healthy: %t", status.ClusterInfo.Healthy)
 		ux.Logger.PrintToUser("Custom VMs healthy: %t", status.ClusterInfo.CustomChainsHealthy)
 		ux.Logger.PrintToUser("Number of nodes: %d", len(status.ClusterInfo.NodeNames))
+		ux.Logger.PrintToUser("Number of paused nodes: %d", countPausedNodes(status.ClusterInfo))
 		ux.Logger.PrintToUser("Number of custom VMs: %d", len(status.ClusterInfo.CustomChains))
 		ux.Logger.PrintToUser("======================================== Node information ========================================")
-		for n, nodeInfo := range status.ClusterInfo.NodeInfos {
-			ux.Logger.PrintToUser("%s has ID %s and endpoint %s ", n, nodeInfo.Id, nodeInfo.Uri)
-		}
+		printNodesTable(status.ClusterInfo)
 		ux.Logger.PrintToUser("==================================== Custom VM information =======================================")
 		for _, nodeInfo := range status.ClusterInfo.NodeInfos {
 			for blockchainID := range status.ClusterInfo.CustomChains {
@@ -72,3 +78,30 @@ func networkStatus(*cobra.Command, []string) error {
 
 	return nil
 }
+
+func countPausedNodes(clusterInfo *rpcpb.ClusterInfo) int {
+	paused := 0
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		if nodeInfo.Paused {
+			paused++
+		}
+	}
+	return paused
+}
+
+func printNodesTable(clusterInfo *rpcpb.ClusterInfo) {
+	nodeNames := maps.Keys(clusterInfo.NodeInfos)
+	sort.Strings(nodeNames)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"node", "ID", "endpoint", "state"})
+	table.SetRowLine(true)
+	for _, nodeName := range nodeNames {
+		nodeInfo := clusterInfo.NodeInfos[nodeName]
+		state := "running"
+		if nodeInfo.Paused {
+			state = "paused"
+		}
+		table.Append([]string{nodeName, nodeInfo.Id, nodeInfo.Uri, state})
+	}
+	table.Render()
+}
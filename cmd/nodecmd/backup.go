@@ -0,0 +1,192 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOutputPath     string
+	backupIncludeDB      bool
+	backupPassphraseFile string
+)
+
+// staking files held by node archives, relative to the avalanchego dir
+var backupStakingFiles = []string{
+	"staking/" + constants.StakerCertFileName,
+	"staking/" + constants.StakerKeyFileName,
+	"staking/" + constants.BLSKeyFileName,
+}
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup [clusterName]",
+		Short: "(ALPHA Warning) Backup the identity and state of the nodes of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node backup command saves into a local archive, for each node of a cluster,
+the staking certificate and key, the BLS key, and the avalanchego node and chain
+configs. With --include-db, a snapshot of the node database is also saved,
+stopping avalanchego while it is taken.
+
+The archive is encrypted with a passphrase, read from --passphrase-file, from the
+AVALANCHE_CLI_BACKUP_PASSPHRASE or AVALANCHE_CLI_BACKUP_PASSPHRASE_FILE environment
+variables, or asked for.
+
+A backed up node can be restored on a replacement host with node restore,
+keeping its NodeID.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         backupCluster,
+	}
+	cmd.Flags().StringVar(&backupOutputPath, "output", "", "write the backup to the given file. Defaults to a timestamped file in the CLI backups dir")
+	cmd.Flags().BoolVar(&backupIncludeDB, "include-db", false, "also backup the node databases. avalanchego is stopped while backing them up")
+	cmd.Flags().StringVar(&backupPassphraseFile, "passphrase-file", "", "read the backup passphrase from the given file")
+	return cmd
+}
+
+func backupCluster(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	outputPath := backupOutputPath
+	if outputPath == "" {
+		outputPath = filepath.Join(
+			app.GetBackupsDir(),
+			fmt.Sprintf("%s-%s.bak", clusterName, time.Now().UTC().Format("20060102-150405")),
+		)
+	}
+	if utils.FileExists(outputPath) {
+		return fmt.Errorf("backup file %s already exists", outputPath)
+	}
+	passphrase, err := getBackupPassphrase(backupPassphraseFile, true)
+	if err != nil {
+		return err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	hosts = utils.Filter(hosts, func(h *models.Host) bool { return clusterConfig.IsAvalancheGoHost(h.GetCloudID()) })
+	if len(hosts) == 0 {
+		return fmt.Errorf("cluster %s has no nodes to backup", clusterName)
+	}
+	defer disconnectHosts(hosts)
+	tmpDir, err := os.MkdirTemp("", "avalanche-cli-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	results := ssh.NewExecutor("Backup Node").Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		return backupNode(host, filepath.Join(tmpDir, host.GetCloudID()+".tar.gz"), backupIncludeDB)
+	}))
+	if results.HasErrors() {
+		return fmt.Errorf("failed to backup node(s) %s", results.GetErrorHostMap())
+	}
+
+	manifest := backup.Manifest{
+		ClusterName: clusterName,
+		Network:     clusterConfig.Network.Name(),
+		CreatedAt:   time.Now().UTC(),
+	}
+	nodeArchivePaths := map[string]string{}
+	for _, host := range hosts {
+		cloudID := host.GetCloudID()
+		nodeArchivePath := filepath.Join(tmpDir, cloudID+".tar.gz")
+		stakingDir := filepath.Join(tmpDir, cloudID)
+		if err := backup.ExtractNodeArchiveFiles(nodeArchivePath, backupStakingFiles, stakingDir); err != nil {
+			return fmt.Errorf("invalid backup of node %s: %w", cloudID, err)
+		}
+		nodeID, err := getNodeID(stakingDir)
+		if err != nil {
+			return err
+		}
+		manifest.Nodes = append(manifest.Nodes, backup.NodeBackup{
+			CloudID: cloudID,
+			NodeID:  nodeID.String(),
+			IsAPI:   clusterConfig.IsAPIHost(cloudID),
+			HasDB:   backupIncludeDB,
+		})
+		nodeArchivePaths[cloudID] = nodeArchivePath
+	}
+	if err := backup.Write(outputPath, passphrase, manifest, nodeArchivePaths); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	for _, node := range manifest.Nodes {
+		ux.Logger.PrintToUser("Node %s [%s] backed up", node.CloudID, node.NodeID)
+	}
+	ux.Logger.PrintToUser("Cluster %s backed up to %s", clusterName, outputPath)
+	return nil
+}
+
+// backupNode archives the node files of [host] and downloads the archive to [localPath].
+// When backing up the database, avalanchego is stopped while archiving, and always restarted
+func backupNode(host *models.Host, localPath string, includeDB bool) error {
+	if includeDB {
+		if err := ssh.RunSSHStopNode(host); err != nil {
+			return err
+		}
+	}
+	err := ssh.RunSSHBackupNode(host, constants.CloudNodeBackupPath, includeDB)
+	if includeDB {
+		if startErr := ssh.RunSSHStartNode(host); startErr != nil {
+			return errors.Join(err, startErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return ssh.RunSSHDownloadNodeBackup(host, constants.CloudNodeBackupPath, localPath)
+}
+
+// getBackupPassphrase gets the passphrase of a backup from [passphraseFile] if given,
+// from the environment if set, or otherwise from the user, asking for it twice if [confirm]
+func getBackupPassphrase(passphraseFile string, confirm bool) (string, error) {
+	if passphraseFile != "" {
+		return key.ReadPassphraseFile(passphraseFile)
+	}
+	if passphrase := os.Getenv(constants.BackupPassphraseEnvVarName); passphrase != "" {
+		return passphrase, nil
+	}
+	if envPassphraseFile := os.Getenv(constants.BackupPassphraseFileEnvVarName); envPassphraseFile != "" {
+		return key.ReadPassphraseFile(envPassphraseFile)
+	}
+	passphrase, err := app.Prompt.CapturePassword("Enter backup passphrase")
+	if err != nil {
+		return "", err
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+	confirmation, err := app.Prompt.CapturePassword("Confirm backup passphrase")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
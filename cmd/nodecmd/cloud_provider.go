@@ -0,0 +1,105 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// cloudProviderGetter returns the cloud provider that manages the instance of a node
+type cloudProviderGetter func(nodeConfig models.NodeConfig) (cloud.CloudProvider, error)
+
+// newCloudProviderGetter returns a cloudProviderGetter that creates the cloud clients
+// on first use: one per AWS region, and one for GCP. If [requireAuth] is set, the user
+// is asked for cloud access authorization before creating them, if not already given
+func newCloudProviderGetter(requireAuth bool) cloudProviderGetter {
+	providers := map[string]cloud.CloudProvider{}
+	return func(nodeConfig models.NodeConfig) (cloud.CloudProvider, error) {
+		cloudService := nodeConfig.CloudService
+		if cloudService == "" {
+			// cloud service was not set when only AWS was supported
+			cloudService = constants.AWSCloudService
+		}
+		providerKey := cloudService
+		if cloudService == constants.AWSCloudService {
+			// AWS clients are bound to a region
+			providerKey += "/" + nodeConfig.Region
+		}
+		if provider, ok := providers[providerKey]; ok {
+			return provider, nil
+		}
+		if cloudService != constants.AWSCloudService && cloudService != constants.GCPCloudService {
+			return nil, fmt.Errorf("node %s is not managed by a supported cloud provider (%s)", nodeConfig.NodeID, cloudService)
+		}
+		if requireAuth && !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(cloudService) != nil) {
+			return nil, fmt.Errorf("cloud access is required")
+		}
+		var provider cloud.CloudProvider
+		switch cloudService {
+		case constants.AWSCloudService:
+			ec2Svc, err := awsAPI.NewAwsCloud(awsProfile, nodeConfig.Region)
+			if err != nil {
+				return nil, err
+			}
+			provider = ec2Svc
+		case constants.GCPCloudService:
+			gcpClient, projectName, _, err := getGCPCloudCredentials()
+			if err != nil {
+				return nil, err
+			}
+			gcpCloud, err := gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
+			if err != nil {
+				return nil, err
+			}
+			provider = gcpCloud
+		}
+		providers[providerKey] = provider
+		return provider, nil
+	}
+}
+
+// destroyCloudNode terminates the cloud instance of [nodeConfig], considering an already
+// terminated instance as a success. It also forgets the host key pinned for its IP, and
+// revokes the access given to its IP for monitoring
+func destroyCloudNode(provider cloud.CloudProvider, nodeConfig models.NodeConfig, clusterName string) error {
+	if err := provider.DestroyNode(nodeConfig, clusterName); err != nil {
+		if !errors.Is(err, cloud.ErrNodeNotFoundToBeRunning) {
+			return err
+		}
+		ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
+	}
+	if nodeConfig.ElasticIP != "" {
+		// the IP may be reused by another instance
+		if _, err := (&models.Host{IP: nodeConfig.ElasticIP}).ResetHostKey(); err != nil {
+			ux.Logger.RedXToUser("unable to remove the pinned host key of node %s: %s", nodeConfig.NodeID, err)
+		}
+	}
+	if err := deleteMonitoringFirewallRules(provider, nodeConfig.ElasticIP, nodeConfig.SecurityGroup); err != nil {
+		ux.Logger.RedXToUser("unable to delete IP address %s from security group %s in region %s due to %s, please delete it manually",
+			nodeConfig.ElasticIP, nodeConfig.SecurityGroup, nodeConfig.Region, err.Error())
+	}
+	return nil
+}
+
+// deleteMonitoringFirewallRules revokes the access to the metrics and API ports given to
+// [publicIP] on [group], when [publicIP] is a monitoring host
+func deleteMonitoringFirewallRules(provider cloud.CloudProvider, publicIP, group string) error {
+	if publicIP == "" {
+		return nil
+	}
+	for _, port := range []int32{constants.AvalanchegoMachineMetricsPort, constants.AvalanchegoAPIPort} {
+		if err := provider.DeleteFirewallRule(group, publicIP+constants.IPAddressSuffix, port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/cloud/fake"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestroyCloudNode(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	provider := fake.New(constants.AWSCloudService, []string{"us-east-1"})
+	const sg = "avalanche-cli-sg"
+
+	staticIPs, err := provider.CreateStaticIPs("us-east-1", "node", 1)
+	require.NoError(err)
+	instanceIDs, err := provider.CreateInstances(cloud.InstanceSpec{Location: "us-east-1", Count: 2, FirewallGroup: sg})
+	require.NoError(err)
+	monitoringIDs, err := provider.CreateInstances(cloud.InstanceSpec{
+		Location:      "us-east-1",
+		Count:         1,
+		FirewallGroup: sg,
+		StaticIPs:     staticIPs,
+		ForMonitoring: true,
+	})
+	require.NoError(err)
+	monitoringIP := staticIPs[0]
+	for _, port := range []int32{constants.AvalanchegoMachineMetricsPort, constants.AvalanchegoAPIPort} {
+		require.NoError(provider.AddFirewallRule(sg, monitoringIP+constants.IPAddressSuffix, port))
+	}
+
+	nodeConfig := models.NodeConfig{NodeID: instanceIDs[0], Region: "us-east-1", SecurityGroup: sg}
+	require.NoError(destroyCloudNode(provider, nodeConfig, "cluster"))
+	_, ok := provider.Instance(instanceIDs[0])
+	require.False(ok)
+	// already destroyed nodes are not an error
+	require.NoError(destroyCloudNode(provider, nodeConfig, "cluster"))
+
+	// monitoring host access is revoked and its static IP released
+	monitoringConfig := models.NodeConfig{
+		NodeID:        monitoringIDs[0],
+		Region:        "us-east-1",
+		SecurityGroup: sg,
+		ElasticIP:     monitoringIP,
+		UseStaticIP:   true,
+		IsMonitor:     true,
+	}
+	require.NoError(destroyCloudNode(provider, monitoringConfig, "cluster"))
+	require.False(provider.HasFirewallRule(sg, monitoringIP+constants.IPAddressSuffix, constants.AvalanchegoMachineMetricsPort))
+	require.False(provider.HasFirewallRule(sg, monitoringIP+constants.IPAddressSuffix, constants.AvalanchegoAPIPort))
+	require.Empty(provider.StaticIPs())
+
+	errDestroy := errors.New("destroy failed")
+	provider.FailOn("DestroyNode", errDestroy)
+	err = destroyCloudNode(provider, models.NodeConfig{NodeID: instanceIDs[1], Region: "us-east-1"}, "cluster")
+	require.ErrorIs(err, errDestroy)
+	_, ok = provider.Instance(instanceIDs[1])
+	require.True(ok)
+}
+
+func TestGetPublicIPsForNodesWithDynamicIP(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	awsProvider := fake.New(constants.AWSCloudService, []string{"us-east-1"})
+	gcpProvider := fake.New(constants.GCPCloudService, []string{"us-east1-b"})
+	awsIDs, err := awsProvider.CreateInstances(cloud.InstanceSpec{Location: "us-east-1", Count: 1})
+	require.NoError(err)
+	gcpIDs, err := gcpProvider.CreateInstances(cloud.InstanceSpec{Location: "us-east1-b", Prefix: "gcp", Count: 1})
+	require.NoError(err)
+	newAWSIP, err := awsProvider.RotatePublicIP(awsIDs[0])
+	require.NoError(err)
+	newGCPIP, err := gcpProvider.RotatePublicIP(gcpIDs[0])
+	require.NoError(err)
+
+	getCloudProvider := func(nodeConfig models.NodeConfig) (cloud.CloudProvider, error) {
+		if nodeConfig.CloudService == constants.GCPCloudService {
+			return gcpProvider, nil
+		}
+		return awsProvider, nil
+	}
+	publicIPs, err := getPublicIPsForNodesWithDynamicIP([]models.NodeConfig{
+		{NodeID: awsIDs[0], Region: "us-east-1"},
+		{NodeID: gcpIDs[0], Region: "us-east1-b", CloudService: constants.GCPCloudService},
+	}, getCloudProvider)
+	require.NoError(err)
+	require.Equal(map[string]string{awsIDs[0]: newAWSIP, gcpIDs[0]: newGCPIP}, publicIPs)
+
+	gcpProvider.FailOn("GetInstancePublicIPs", errors.New("unreachable"))
+	_, err = getPublicIPsForNodesWithDynamicIP([]models.NodeConfig{
+		{NodeID: gcpIDs[0], Region: "us-east1-b", CloudService: constants.GCPCloudService},
+	}, getCloudProvider)
+	require.Error(err)
+}
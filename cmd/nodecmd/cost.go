@@ -0,0 +1,148 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/cost"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"golang.org/x/exp/maps"
+)
+
+const ignoreBudgetFlag = "ignore-budget"
+
+var ignoreBudget bool
+
+// getCreateCloudResources returns the cloud resources needed to create [numNodesMap] nodes
+// of type [instanceType], and a monitoring host at [monitoringHostRegion] if [addMonitoringHost],
+// with static IPs if [useStaticIP]
+func getCreateCloudResources(
+	cloudService string,
+	instanceType string,
+	numNodesMap map[string]NumNodes,
+	monitoringHostRegion string,
+	addMonitoringHost bool,
+	useStaticIP bool,
+) []cost.Resource {
+	resources := []cost.Resource{}
+	regions := maps.Keys(numNodesMap)
+	sort.Strings(regions)
+	for _, region := range regions {
+		for _, group := range []struct {
+			description string
+			count       int
+		}{
+			{"validator node(s)", numNodesMap[region].numValidators},
+			{"API node(s)", numNodesMap[region].numAPI},
+		} {
+			if group.count == 0 {
+				continue
+			}
+			resources = append(resources, cost.Resource{
+				Description:  group.description,
+				CloudService: cloudService,
+				InstanceType: instanceType,
+				Location:     region,
+				Count:        group.count,
+				UseStaticIP:  useStaticIP,
+			})
+		}
+	}
+	if addMonitoringHost {
+		resources = append(resources, cost.Resource{
+			Description:  "monitoring host",
+			CloudService: cloudService,
+			InstanceType: instanceType,
+			Location:     monitoringHostRegion,
+			Count:        1,
+			UseStaticIP:  useStaticIP,
+		})
+	}
+	return resources
+}
+
+// getLoadTestCloudResources returns the cloud resources needed to create a load test host at [region]
+func getLoadTestCloudResources(cloudService string, region string) []cost.Resource {
+	return []cost.Resource{{
+		Description:  "load test host",
+		CloudService: cloudService,
+		InstanceType: nodeType,
+		Location:     region,
+		Count:        1,
+		UseStaticIP:  useStaticIP,
+	}}
+}
+
+// checkCloudCost prints the estimated cost of [resources], and fails if its monthly cost
+// exceeds the budget set by the user, unless the budget is ignored
+func checkCloudCost(resources []cost.Resource) error {
+	priceTable, err := cost.LoadPriceTable(app.GetPriceTablePath())
+	if err != nil {
+		return err
+	}
+	estimate := priceTable.EstimateCost(resources)
+	printCostEstimate(estimate, priceTable.UpdatedAt)
+	budget := app.Conf.GetConfigFloat64Value(constants.ConfigCloudMonthlyBudgetKey)
+	return checkBudget(estimate, budget, ignoreBudget)
+}
+
+// checkBudget fails if the monthly cost of [estimate] exceeds [budget], unless [ignore].
+// A budget of zero means no budget
+func checkBudget(estimate cost.Estimate, budget float64, ignore bool) error {
+	if budget <= 0 || estimate.Monthly() <= budget {
+		return nil
+	}
+	if ignore {
+		ux.Logger.PrintToUser("Estimated monthly cost exceeds the budget of $%.2f. Proceeding as --%s is set", budget, ignoreBudgetFlag)
+		return nil
+	}
+	return fmt.Errorf(
+		"estimated monthly cost of $%.2f exceeds the budget of $%.2f. Use --%s to proceed anyway, or change the budget with avalanche config cloud-budget",
+		estimate.Monthly(),
+		budget,
+		ignoreBudgetFlag,
+	)
+}
+
+func printCostEstimate(estimate cost.Estimate, pricesDate string) {
+	ux.Logger.PrintToUser("Estimated cloud cost, using on-demand prices as of %s:", pricesDate)
+	for _, item := range estimate.Items {
+		staticIPStr := ""
+		if item.UseStaticIP {
+			staticIPStr = " with static IP"
+		}
+		resourceStr := fmt.Sprintf("%d x %s %s at %s%s", item.Count, item.InstanceType, item.Description, item.Location, staticIPStr)
+		if item.Err != nil {
+			ux.Logger.PrintToUser("  %s: %s", resourceStr, item.Err)
+			continue
+		}
+		ux.Logger.PrintToUser("  %s: $%.3f/hour", resourceStr, item.Hourly())
+	}
+	ux.Logger.PrintToUser("  Total: $%.3f/hour, $%.2f/month", estimate.Hourly(), estimate.Monthly())
+	if estimate.HasUnknownPrices() {
+		ux.Logger.PrintToUser("  Resources with unknown prices are not included. Set a price table with them with avalanche config price-table")
+	}
+}
+
+// isCloudNode returns true if the node of [nodeConfig] runs on a cloud server created by the CLI
+func isCloudNode(nodeConfig models.NodeConfig) bool {
+	return nodeConfig.CloudService != constants.ExistingHostsService && nodeConfig.CloudService != constants.E2EDocker
+}
+
+// getNodeUptimeCost returns the cost of the cloud server of [nodeConfig] since it was created,
+// or an error if it is not known
+func getNodeUptimeCost(priceTable cost.PriceTable, nodeConfig models.NodeConfig, now time.Time) (float64, error) {
+	if nodeConfig.CreatedAt.IsZero() || nodeConfig.InstanceType == "" {
+		return 0, fmt.Errorf("%w: node %s has no instance type or creation time", cost.ErrUnknownPrice, nodeConfig.NodeID)
+	}
+	hourlyPrice, err := priceTable.GetHostHourlyPrice(nodeConfig.CloudService, nodeConfig.InstanceType, nodeConfig.Region, nodeConfig.UseStaticIP)
+	if err != nil {
+		return 0, err
+	}
+	return cost.GetUptimeCost(hourlyPrice, nodeConfig.CreatedAt, now), nil
+}
@@ -0,0 +1,80 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/cost"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCreateCloudResources(t *testing.T) {
+	require := require.New(t)
+	resources := getCreateCloudResources(
+		constants.AWSCloudService,
+		"c5.2xlarge",
+		map[string]NumNodes{"us-east-1": {2, 1}, "eu-west-1": {3, 0}},
+		"us-east-1",
+		true,
+		true,
+	)
+	require.Equal([]string{"eu-west-1", "us-east-1", "us-east-1", "us-east-1"}, []string{
+		resources[0].Location,
+		resources[1].Location,
+		resources[2].Location,
+		resources[3].Location,
+	})
+	require.Equal([]int{3, 2, 1, 1}, []int{resources[0].Count, resources[1].Count, resources[2].Count, resources[3].Count})
+	require.Equal("API node(s)", resources[2].Description)
+	require.Equal("monitoring host", resources[3].Description)
+	require.True(resources[3].UseStaticIP)
+
+	resources = getCreateCloudResources(constants.AWSCloudService, "c5.2xlarge", map[string]NumNodes{"us-east-1": {1, 0}}, "us-east-1", false, false)
+	require.Len(resources, 1)
+}
+
+func TestCheckBudget(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	priceTable, err := cost.ParsePriceTable([]byte(`{"aws": {"instances": {"c5.2xlarge": {"default": 1}}}}`))
+	require.NoError(err)
+	estimate := priceTable.EstimateCost([]cost.Resource{
+		{CloudService: constants.AWSCloudService, InstanceType: "c5.2xlarge", Location: "us-east-1", Count: 2},
+	})
+	require.NoError(checkBudget(estimate, 0, false))
+	require.NoError(checkBudget(estimate, 2*cost.HoursPerMonth, false))
+	require.ErrorContains(checkBudget(estimate, 1000, false), "exceeds the budget of $1000.00")
+	require.NoError(checkBudget(estimate, 1000, true))
+}
+
+func TestGetNodeUptimeCost(t *testing.T) {
+	require := require.New(t)
+	priceTable, err := cost.ParsePriceTable([]byte(`{"gcp": {"instances": {"e2-standard-8": {"us-east1": 0.25}}, "staticIP": 0.05}}`))
+	require.NoError(err)
+	now := time.Now()
+	nodeConfig := models.NodeConfig{
+		NodeID:       "node1",
+		Region:       "us-east1-b",
+		CloudService: constants.GCPCloudService,
+		UseStaticIP:  true,
+		InstanceType: "e2-standard-8",
+		CreatedAt:    now.Add(-10 * time.Hour),
+	}
+	nodeCost, err := getNodeUptimeCost(priceTable, nodeConfig, now)
+	require.NoError(err)
+	require.InDelta(3.0, nodeCost, 1e-9)
+
+	nodeConfig.CreatedAt = time.Time{}
+	_, err = getNodeUptimeCost(priceTable, nodeConfig, now)
+	require.ErrorIs(err, cost.ErrUnknownPrice)
+	require.True(isCloudNode(nodeConfig))
+	require.True(isCloudNode(models.NodeConfig{}))
+	require.False(isCloudNode(models.NodeConfig{CloudService: constants.ExistingHostsService}))
+}
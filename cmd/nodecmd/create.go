@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
 
@@ -67,6 +68,7 @@ var (
 	useSSHAgent                           bool
 	sshIdentity                           string
 	numAPINodes                           []int
+	existingHostsPath                     string
 	versionComments                       = map[string]string{
 		"v1.11.0-fuji": " (recommended for fuji durango)",
 	}
@@ -89,7 +91,24 @@ status by running avalanche node status
 
 The created node will be part of group of validators called <clusterName> 
 and users can call node commands with <clusterName> so that the command
-will apply to all nodes in the cluster`,
+will apply to all nodes in the cluster
+
+Before creating cloud servers, the command prints their estimated cost. If a
+monthly budget is set with avalanche config cloud-budget, cloud servers whose
+estimated monthly cost exceeds it are not created, unless --ignore-budget is given.
+
+Instead of creating cloud servers, the node(s) can be set up on existing
+SSH reachable machines (e.g. bare metal or on-prem VMs) with --existing-hosts,
+given a JSON file such as:
+
+{"hosts": [
+  {"ip": "10.0.0.1", "user": "ubuntu", "port": 22, "sshKey": "~/.ssh/id_ed25519"},
+  {"ip": "10.0.0.2", "api": true},
+  {"ip": "10.0.0.3", "monitoring": true}
+]}
+
+The ssh user must have passwordless sudo and write access to /home/ubuntu.
+If sshKey is not given, the ssh agent is used.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         createNodes,
@@ -115,6 +134,8 @@ will apply to all nodes in the cluster`,
 	cmd.Flags().StringVar(&sshIdentity, "ssh-agent-identity", "", "use given ssh identity(only for ssh agent). If not set, default will be used")
 	cmd.Flags().BoolVar(&addMonitoring, enableMonitoringFlag, false, "set up Prometheus monitoring for created nodes. This option creates a separate monitoring cloud instance and incures additional cost")
 	cmd.Flags().IntSliceVar(&numAPINodes, "num-apis", []int{}, "number of API nodes(nodes without stake) to create in the new Devnet")
+	cmd.Flags().StringVar(&existingHostsPath, "existing-hosts", "", "set up the node(s) on the SSH reachable hosts listed in the given JSON file, instead of creating cloud servers")
+	cmd.Flags().BoolVar(&ignoreBudget, ignoreBudgetFlag, false, "create the cloud servers even if their estimated cost exceeds the configured budget")
 	return cmd
 }
 
@@ -125,17 +146,27 @@ func preCreateChecks() error {
 	if useAWS && useGCP {
 		return fmt.Errorf("could not use both AWS and GCP cloud options")
 	}
+	if existingHostsPath != "" {
+		if useAWS || useGCP {
+			return fmt.Errorf("could not use existing hosts together with a cloud option")
+		}
+		if len(cmdLineRegion) > 0 || len(numValidatorsNodes) > 0 || len(numAPINodes) > 0 {
+			return fmt.Errorf("number of nodes and regions are taken from the existing hosts file")
+		}
+		if useSSHAgent {
+			return fmt.Errorf("ssh agent usage for existing hosts is set by leaving their ssh key empty")
+		}
+	}
 	if !useAWS && awsProfile != constants.AWSDefaultCredential {
 		return fmt.Errorf("could not use AWS profile for non AWS cloud option")
 	}
 	if len(utils.Unique(cmdLineRegion)) != len(numValidatorsNodes) {
 		return fmt.Errorf("regions provided is not consistent with number of nodes provided. Please make sure list of regions is unique")
 	}
-	if len(numValidatorsNodes) > 0 {
-		for _, num := range numValidatorsNodes {
-			if num <= 0 {
-				return fmt.Errorf("number of nodes per region must be greater than 0")
-			}
+	for i, num := range numValidatorsNodes {
+		// a devnet region may only get API nodes
+		if num < 0 || (num == 0 && (i >= len(numAPINodes) || numAPINodes[i] <= 0)) {
+			return fmt.Errorf("number of nodes per region must be greater than 0")
 		}
 	}
 	if sshIdentity != "" && !useSSHAgent {
@@ -150,11 +181,9 @@ func preCreateChecks() error {
 	if globalNetworkFlags.UseDevnet && len(numAPINodes) != len(numValidatorsNodes) {
 		return fmt.Errorf("API nodes and Validator nodes must be deployed to same number of regions")
 	}
-	if len(numAPINodes) > 0 {
-		for _, num := range numValidatorsNodes {
-			if num <= 0 {
-				return fmt.Errorf("number of API nodes per region must be greater than 0")
-			}
+	for _, num := range numAPINodes {
+		if num < 0 {
+			return fmt.Errorf("number of API nodes per region can't be negative")
 		}
 	}
 	if remoteCLIVersion != "" {
@@ -170,6 +199,8 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	clusterName := args[0]
+	// nodes added to a cluster with nodes join its network
+	isExistingCluster := checkCluster(clusterName) == nil
 	network, err := networkoptions.GetNetworkFromCmdLineFlags(
 		app,
 		globalNetworkFlags,
@@ -202,6 +233,16 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	if cloudService != constants.GCPCloudService && cmdLineGCPProjectName != "" {
 		return fmt.Errorf("set to use GCP project but cloud option is not GCP")
 	}
+	var existingHosts []ExistingHost
+	if cloudService == constants.ExistingHostsService {
+		existingHosts, err = loadExistingHosts(existingHostsPath)
+		if err != nil {
+			return err
+		}
+		if network.Kind != models.Devnet && utils.Any(existingHosts, func(h ExistingHost) bool { return h.API }) {
+			return fmt.Errorf("API nodes can only be created in Devnet")
+		}
+	}
 	// for devnet add nonstake api nodes for each region with stake
 	cloudConfigMap := models.CloudConfig{}
 	publicIPMap := map[string]string{}
@@ -221,12 +262,23 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if existingMonitoringInstance == "" && !cmd.Flags().Changed(enableMonitoringFlag) {
+	switch {
+	case cloudService == constants.ExistingHostsService:
+		hasMonitoringHost := utils.Any(existingHosts, func(h ExistingHost) bool { return h.Monitoring })
+		if hasMonitoringHost && existingMonitoringInstance != "" {
+			return fmt.Errorf("cluster %s already has a monitoring host", clusterName)
+		}
+		if addMonitoring && !hasMonitoringHost && existingMonitoringInstance == "" {
+			return fmt.Errorf("monitoring requires a host with monitoring set in the existing hosts file")
+		}
+		addMonitoring = hasMonitoringHost
+	case existingMonitoringInstance == "" && !cmd.Flags().Changed(enableMonitoringFlag):
 		if addMonitoring, err = promptSetUpMonitoring(); err != nil {
 			return err
 		}
 	}
-	if utils.IsE2E() {
+	switch {
+	case utils.IsE2E():
 		usr, err := user.Current()
 		if err != nil {
 			return err
@@ -313,7 +365,19 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		if err := utils.StartDockerCompose(dockerComposeFile); err != nil {
 			return err
 		}
-	} else {
+	case cloudService == constants.ExistingHostsService:
+		cloudConfigMap, monitoringNodeConfig, publicIPMap, apiNodeIPMap, err = getExistingHostsConfig(existingHosts)
+		if err != nil {
+			return err
+		}
+		if existingMonitoringInstance != "" {
+			addMonitoring = true
+			monitoringNodeConfig, _, err = getNodeCloudConfig(existingMonitoringInstance)
+			if err != nil {
+				return err
+			}
+		}
+	default:
 		if cloudService == constants.AWSCloudService {
 			// Get AWS Credential, region and AMI
 			if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.AWSCloudService) != nil) {
@@ -327,6 +391,16 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			if existingMonitoringInstance == "" {
 				monitoringHostRegion = regions[0]
 			}
+			if err := checkCloudCost(getCreateCloudResources(
+				cloudService,
+				nodeType,
+				numNodesMap,
+				monitoringHostRegion,
+				addMonitoring && existingMonitoringInstance == "",
+				useStaticIP,
+			)); err != nil {
+				return err
+			}
 			cloudConfigMap, err = createAWSInstances(ec2SvcMap, nodeType, numNodesMap, regions, ami, false)
 			if err != nil {
 				return err
@@ -352,7 +426,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 				}
 			}
 			if !useStaticIP && addMonitoring {
-				monitoringPublicIPMap, err := monitoringEc2SvcMap[monitoringHostRegion].GetInstancePublicIPs(monitoringNodeConfig.InstanceIDs)
+				monitoringPublicIPMap, err := monitoringEc2SvcMap[monitoringHostRegion].GetInstancePublicIPs(monitoringHostRegion, monitoringNodeConfig.InstanceIDs)
 				if err != nil {
 					return err
 				}
@@ -361,7 +435,7 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			for region, numNodes := range numNodesMap {
 				currentRegionConfig := cloudConfigMap[region]
 				if !useStaticIP {
-					tmpIPMap, err := ec2SvcMap[region].GetInstancePublicIPs(currentRegionConfig.InstanceIDs)
+					tmpIPMap, err := ec2SvcMap[region].GetInstancePublicIPs(region, currentRegionConfig.InstanceIDs)
 					if err != nil {
 						return err
 					}
@@ -398,6 +472,16 @@ func createNodes(cmd *cobra.Command, args []string) error {
 			if existingMonitoringInstance == "" {
 				monitoringHostRegion = maps.Keys(numNodesMap)[0]
 			}
+			if err := checkCloudCost(getCreateCloudResources(
+				cloudService,
+				nodeType,
+				numNodesMap,
+				monitoringHostRegion,
+				addMonitoring && existingMonitoringInstance == "",
+				useStaticIP,
+			)); err != nil {
+				return err
+			}
 			cloudConfigMap, err = createGCPInstance(gcpClient, nodeType, numNodesMap, imageID, clusterName, false)
 			if err != nil {
 				return err
@@ -473,32 +557,37 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err = CreateClusterNodeConfig(
-		network,
-		cloudConfigMap,
-		monitoringNodeConfig,
-		monitoringHostRegion,
-		clusterName,
-		cloudService,
-		addMonitoring,
-	); err != nil {
-		return err
-	}
-	if cloudService == constants.GCPCloudService {
-		if err = updateClustersConfigGCPKeyFilepath(gcpProjectName, gcpCredentialFilepath); err != nil {
+	inventoryPath := app.GetAnsibleInventoryDirPath(clusterName)
+	if cloudService == constants.ExistingHostsService {
+		if err = createExistingHostsClusterConfig(network, clusterName, existingHosts); err != nil {
+			return err
+		}
+	} else {
+		if err = CreateClusterNodeConfig(
+			network,
+			cloudConfigMap,
+			monitoringNodeConfig,
+			monitoringHostRegion,
+			clusterName,
+			cloudService,
+			addMonitoring,
+		); err != nil {
+			return err
+		}
+		if cloudService == constants.GCPCloudService {
+			if err = updateClustersConfigGCPKeyFilepath(gcpProjectName, gcpCredentialFilepath); err != nil {
+				return err
+			}
+		}
+		if err = ansible.CreateAnsibleHostInventory(inventoryPath, "", cloudService, publicIPMap, cloudConfigMap); err != nil {
 			return err
 		}
-	}
-
-	inventoryPath := app.GetAnsibleInventoryDirPath(clusterName)
-	if err = ansible.CreateAnsibleHostInventory(inventoryPath, "", cloudService, publicIPMap, cloudConfigMap); err != nil {
-		return err
 	}
 	monitoringInventoryPath := ""
 	var monitoringHosts []*models.Host
 	if addMonitoring {
 		monitoringInventoryPath = app.GetMonitoringInventoryDir(clusterName)
-		if existingMonitoringInstance == "" {
+		if existingMonitoringInstance == "" && cloudService != constants.ExistingHostsService {
 			if err = ansible.CreateAnsibleHostInventory(monitoringInventoryPath, monitoringNodeConfig.CertFilePath, cloudService, map[string]string{monitoringNodeConfig.InstanceIDs[0]: monitoringNodeConfig.PublicIPs[0]}, nil); err != nil {
 				return err
 			}
@@ -513,6 +602,17 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	hosts := utils.Filter(allHosts, func(h *models.Host) bool { return slices.Contains(cloudConfigMap.GetAllInstanceIDs(), h.GetCloudID()) })
+	if cloudService != constants.ExistingHostsService {
+		// cloud public IPs get recycled: keys pinned for them belong to previous instances
+		if err := forgetHostKeys(hosts); err != nil {
+			return err
+		}
+		if addMonitoring && existingMonitoringInstance == "" {
+			if err := forgetHostKeys(monitoringHosts); err != nil {
+				return err
+			}
+		}
+	}
 	// waiting for all nodes to become accessible
 	failedHosts := waitForHosts(hosts)
 	if failedHosts.Len() > 0 {
@@ -521,6 +621,15 @@ func createNodes(cmd *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("failed to provision node(s) %s", failedHosts.GetNodeList())
 	}
+	if cloudService == constants.ExistingHostsService {
+		hostsToCheck := hosts
+		if addMonitoring && existingMonitoringInstance == "" {
+			hostsToCheck = append(hostsToCheck, monitoringHosts...)
+		}
+		if err := checkExistingHostsRequirements(hostsToCheck); err != nil {
+			return err
+		}
+	}
 	ux.Logger.PrintToUser("Installing AvalancheGo and Avalanche-CLI and starting bootstrap process on the newly created Avalanche node(s)...")
 	wg := sync.WaitGroup{}
 	wgResults := models.NodeResults{}
@@ -705,7 +814,12 @@ func createNodes(cmd *cobra.Command, args []string) error {
 	}
 	spinSession.Stop()
 	if network.Kind == models.Devnet {
-		if err := setupDevnet(clusterName, hosts, apiNodeIPMap); err != nil {
+		if isExistingCluster {
+			err = joinDevnet(clusterName, hosts)
+		} else {
+			err = setupDevnet(clusterName, hosts, apiNodeIPMap)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -764,6 +878,8 @@ func CreateClusterNodeConfig(
 				CloudService:  cloudService,
 				UseStaticIP:   useStaticIP,
 				IsMonitor:     false,
+				InstanceType:  cloudConfig.InstanceType,
+				CreatedAt:     time.Now().UTC(),
 			}
 			err := app.CreateNodeCloudConfigFile(cloudConfig.InstanceIDs[i], &nodeConfig)
 			if err != nil {
@@ -800,6 +916,8 @@ func saveExternalHostConfig(externalHostConfig models.RegionConfig, hostRegion,
 		UseStaticIP:   useStaticIP,
 		IsMonitor:     isMonitoring,
 		IsLoadTest:    isLoadTest,
+		InstanceType:  externalHostConfig.InstanceType,
+		CreatedAt:     time.Now().UTC(),
 	}
 	if err := app.CreateNodeCloudConfigFile(externalHostConfig.InstanceIDs[0], &nodeConfig); err != nil {
 		return err
@@ -1102,6 +1220,9 @@ func setCloudService() (string, error) {
 	if useGCP {
 		return constants.GCPCloudService, nil
 	}
+	if existingHostsPath != "" {
+		return constants.ExistingHostsService, nil
+	}
 	txt := "Which cloud service would you like to launch your Avalanche Node(s) in?"
 	cloudOptions := []string{constants.AWSCloudService, constants.GCPCloudService}
 	chosenCloudService, err := app.Prompt.CaptureList(txt, cloudOptions)
@@ -1115,6 +1236,9 @@ func setCloudInstanceType(cloudService string) (string, error) {
 	if utils.IsE2E() && utils.E2EDocker() {
 		return constants.E2EDocker, nil
 	}
+	if cloudService == constants.ExistingHostsService {
+		return "", nil
+	}
 	switch { // backwards compatibility
 	case nodeType == constants.DefaultNodeType && cloudService == constants.AWSCloudService:
 		nodeType = constants.AWSDefaultInstanceType
@@ -1182,7 +1306,9 @@ func printResults(cloudConfigMap models.CloudConfig, publicIPMap map[string]stri
 			ux.Logger.PrintLineSeparator()
 			ux.Logger.PrintToUser("")
 		}
-		ux.Logger.PrintToUser("Don't delete or replace your ssh private key file at %s as you won't be able to access your cloud server without it", cloudConfig.CertFilePath)
+		if cloudConfig.CertFilePath != "" {
+			ux.Logger.PrintToUser("Don't delete or replace your ssh private key file at %s as you won't be able to access your cloud server without it", cloudConfig.CertFilePath)
+		}
 		ux.Logger.PrintLineSeparator()
 		for _, instanceID := range cloudConfig.InstanceIDs {
 			nodeID, _ := getNodeID(app.GetNodeInstanceDirPath(instanceID))
@@ -342,7 +342,7 @@ func createEC2Instances(ec2Svc map[string]*awsAPI.AwsCloud,
 			}
 			elasticIPs[region] = publicIPs
 		} else {
-			instanceEIPMap, err := ec2Svc[region].GetInstancePublicIPs(instanceIDs[region])
+			instanceEIPMap, err := ec2Svc[region].GetInstancePublicIPs(region, instanceIDs[region])
 			if err != nil {
 				return instanceIDs, elasticIPs, sshCertPath, keyPairName, err
 			}
@@ -398,29 +398,6 @@ func AddMonitoringSecurityGroupRule(ec2Svc map[string]*awsAPI.AwsCloud, monitori
 	return nil
 }
 
-func deleteMonitoringSecurityGroupRule(ec2Svc *awsAPI.AwsCloud, monitoringHostPublicIP, securityGroupName, region string) error {
-	securityGroupExists, sg, err := ec2Svc.CheckSecurityGroupExists(securityGroupName)
-	if err != nil {
-		return err
-	}
-	if !securityGroupExists {
-		return fmt.Errorf("security group %s doesn't exist in region %s", securityGroupName, region)
-	}
-	metricsPortInSG := awsAPI.CheckUserIPInSg(&sg, monitoringHostPublicIP, constants.AvalanchegoMachineMetricsPort)
-	apiPortInSG := awsAPI.CheckUserIPInSg(&sg, monitoringHostPublicIP, constants.AvalanchegoAPIPort)
-	if metricsPortInSG {
-		if err = ec2Svc.DeleteSecurityGroupRule(*sg.GroupId, "ingress", "tcp", monitoringHostPublicIP+constants.IPAddressSuffix, constants.AvalanchegoMachineMetricsPort); err != nil {
-			return err
-		}
-	}
-	if apiPortInSG {
-		if err = ec2Svc.DeleteSecurityGroupRule(*sg.GroupId, "ingress", "tcp", monitoringHostPublicIP+constants.IPAddressSuffix, constants.AvalanchegoAPIPort); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func grantAccessToPublicIPViaSecurityGroup(ec2Svc *awsAPI.AwsCloud, publicIP, securityGroupName, region string) error {
 	securityGroupExists, sg, err := ec2Svc.CheckSecurityGroupExists(securityGroupName)
 	if err != nil {
@@ -507,6 +484,7 @@ func createAWSInstances(
 			SecurityGroup: regionConf[region].SecurityGroupName,
 			CertFilePath:  certFilePath[region],
 			ImageID:       ami[region],
+			InstanceType:  nodeType,
 		}
 	}
 	return awsCloudConfig, nil
@@ -284,3 +284,88 @@ func setupDevnet(clusterName string, hosts []*models.Host, apiNodeIPMap map[stri
 	clustersConfig.Clusters[clusterName] = clusterConfig
 	return app.WriteClustersConfigFile(&clustersConfig)
 }
+
+// joinDevnet sets up [hosts] as new nodes of the devnet already run by [clusterName]. They
+// get the genesis and the avalanchego config of a previous node, and bootstrap from the
+// previous validators. New nodes are not added to the devnet validator set
+func joinDevnet(clusterName string, hosts []*models.Host) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	inventory, err := loadInventoryByCloudID(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	newCloudIDs := utils.Map(hosts, func(h *models.Host) string { return h.GetCloudID() })
+	var genesisBytes []byte
+	confMap := map[string]interface{}{}
+	bootstrapIPs := []string{}
+	bootstrapIDs := []string{}
+	for _, cloudID := range clusterConfig.Nodes {
+		host, ok := inventory[cloudID]
+		if !ok || slices.Contains(newCloudIDs, cloudID) || clusterConfig.IsAPIHost(cloudID) {
+			continue
+		}
+		nodeDir := app.GetNodeInstanceDirPath(cloudID)
+		if genesisBytes == nil {
+			if !utils.FileExists(filepath.Join(nodeDir, "genesis.json")) {
+				continue
+			}
+			if genesisBytes, err = os.ReadFile(filepath.Join(nodeDir, "genesis.json")); err != nil {
+				return err
+			}
+			confBytes, err := os.ReadFile(filepath.Join(nodeDir, "node.json"))
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(confBytes, &confMap); err != nil {
+				return err
+			}
+		}
+		nodeID, err := getNodeID(nodeDir)
+		if err != nil {
+			return err
+		}
+		bootstrapIDs = append(bootstrapIDs, nodeID.String())
+		bootstrapIPs = append(bootstrapIPs, fmt.Sprintf("%s:9651", host.IP))
+	}
+	if genesisBytes == nil {
+		return fmt.Errorf("devnet genesis of cluster %s not found", clusterName)
+	}
+	for _, host := range hosts {
+		confMap[config.PublicIPKey] = host.IP
+		confMap[config.BootstrapIDsKey] = strings.Join(bootstrapIDs, ",")
+		confMap[config.BootstrapIPsKey] = strings.Join(bootstrapIPs, ",")
+		confBytes, err := json.MarshalIndent(confMap, "", " ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(app.GetNodeInstanceDirPath(host.GetCloudID()), "genesis.json"), genesisBytes, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(app.GetNodeInstanceDirPath(host.GetCloudID()), "node.json"), confBytes, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+	}
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			keyPath := filepath.Join(app.GetNodesDir(), host.GetCloudID())
+			if err := ssh.RunSSHSetupDevNet(host, keyPath); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, err)
+				ux.Logger.RedXToUser(utils.ScriptLog(host.NodeID, "Join devnet err: %v", err))
+				return
+			}
+			ux.Logger.GreenCheckmarkToUser(utils.ScriptLog(host.NodeID, "Join devnet"))
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return fmt.Errorf("failed to deploy node(s) %s", wgResults.GetErrorHostMap())
+	}
+	return nil
+}
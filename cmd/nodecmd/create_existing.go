@@ -0,0 +1,241 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// the remote setup scripts install everything under this home dir
+const existingHostRequirementsCheck = "sudo -n true && test -w /home/ubuntu"
+
+var existingHostNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// ExistingHost is an SSH reachable machine, not created by the CLI, that is to be
+// set up as a node
+type ExistingHost struct {
+	// name used as the host cloud ID. Defaults to the IP (and port, if not the default one)
+	Name string `json:"name"`
+	IP   string `json:"ip"`
+	// ssh user. Defaults to ubuntu
+	User string `json:"user"`
+	// ssh port. Defaults to 22
+	Port uint `json:"port"`
+	// path to the ssh private key. If empty, the ssh agent is used
+	SSHKey string `json:"sshKey"`
+	// set up as an API node (devnet only)
+	API bool `json:"api"`
+	// set up as the cluster monitoring host instead of as a node
+	Monitoring bool `json:"monitoring"`
+}
+
+type existingHostsInventory struct {
+	Hosts []ExistingHost `json:"hosts"`
+}
+
+// loadExistingHosts reads and validates the existing hosts inventory file at [inventoryPath]
+func loadExistingHosts(inventoryPath string) ([]ExistingHost, error) {
+	inventoryBytes, err := os.ReadFile(utils.GetRealFilePath(inventoryPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading existing hosts file: %w", err)
+	}
+	var inventory existingHostsInventory
+	if err := json.Unmarshal(inventoryBytes, &inventory); err != nil {
+		return nil, fmt.Errorf("invalid existing hosts file %s: %w", inventoryPath, err)
+	}
+	if len(inventory.Hosts) == 0 {
+		return nil, fmt.Errorf("no hosts found at existing hosts file %s", inventoryPath)
+	}
+	names := map[string]bool{}
+	addresses := map[string]bool{}
+	numMonitoring := 0
+	numNodes := 0
+	for i := range inventory.Hosts {
+		host := &inventory.Hosts[i]
+		if !utils.IsValidIP(host.IP) {
+			return nil, fmt.Errorf("invalid IP %q for host %d", host.IP, i+1)
+		}
+		if host.User == "" {
+			host.User = constants.AnsibleSSHUser
+		}
+		if host.Port == 0 {
+			host.Port = constants.SSHTCPPort
+		}
+		if host.Name == "" {
+			host.Name = strings.ReplaceAll(host.IP, ".", "-")
+			if host.Port != constants.SSHTCPPort {
+				host.Name = fmt.Sprintf("%s-%d", host.Name, host.Port)
+			}
+		}
+		if !existingHostNameRegexp.MatchString(host.Name) {
+			return nil, fmt.Errorf("invalid name %q for host %s: only letters, digits and - are allowed", host.Name, host.IP)
+		}
+		if names[host.Name] {
+			return nil, fmt.Errorf("duplicated host name %s", host.Name)
+		}
+		names[host.Name] = true
+		address := fmt.Sprintf("%s:%d", host.IP, host.Port)
+		if addresses[address] {
+			return nil, fmt.Errorf("duplicated host address %s", address)
+		}
+		addresses[address] = true
+		if host.SSHKey != "" {
+			host.SSHKey = utils.GetRealFilePath(host.SSHKey)
+			if !utils.FileExists(host.SSHKey) {
+				return nil, fmt.Errorf("ssh key %s for host %s not found", host.SSHKey, host.Name)
+			}
+		}
+		if host.Monitoring {
+			if host.API {
+				return nil, fmt.Errorf("host %s can't be both a monitoring host and an API node", host.Name)
+			}
+			numMonitoring++
+		} else {
+			numNodes++
+		}
+	}
+	if numMonitoring > 1 {
+		return nil, errors.New("only one monitoring host is allowed")
+	}
+	if numNodes == 0 {
+		return nil, errors.New("at least one host must be set up as a node")
+	}
+	return inventory.Hosts, nil
+}
+
+// toHost returns the host as used by the ssh and ansible layers
+func (h ExistingHost) toHost() (*models.Host, error) {
+	ansibleID, err := models.HostCloudIDToAnsibleID(constants.ExistingHostsService, h.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Host{
+		NodeID:            ansibleID,
+		IP:                h.IP,
+		SSHUser:           h.User,
+		SSHPort:           h.Port,
+		SSHPrivateKeyPath: h.SSHKey,
+		SSHCommonArgs:     constants.AnsibleSSHUseAgentParams,
+	}, nil
+}
+
+// getExistingHostsConfig builds the cloud config of the existing [hosts], in the same way
+// as it is done for cloud instances, so the rest of the node set up can be shared.
+// Also returns the monitoring host config, if any
+func getExistingHostsConfig(hosts []ExistingHost) (
+	models.CloudConfig,
+	models.RegionConfig,
+	map[string]string,
+	map[string]string,
+	error,
+) {
+	regionConfig := models.RegionConfig{}
+	monitoringConfig := models.RegionConfig{}
+	publicIPMap := map[string]string{}
+	apiNodeIPMap := map[string]string{}
+	for _, host := range hosts {
+		if utils.DirectoryExists(app.GetNodeInstanceDirPath(host.Name)) {
+			return nil, models.RegionConfig{}, nil, nil, fmt.Errorf("host name %s is already in use by another node", host.Name)
+		}
+		if host.Monitoring {
+			monitoringConfig = models.RegionConfig{
+				InstanceIDs:  []string{host.Name},
+				PublicIPs:    []string{host.IP},
+				CertFilePath: host.SSHKey,
+				NumNodes:     1,
+			}
+			continue
+		}
+		regionConfig.InstanceIDs = append(regionConfig.InstanceIDs, host.Name)
+		regionConfig.PublicIPs = append(regionConfig.PublicIPs, host.IP)
+		publicIPMap[host.Name] = host.IP
+		if host.API {
+			regionConfig.APIInstanceIDs = append(regionConfig.APIInstanceIDs, host.Name)
+			apiNodeIPMap[host.Name] = host.IP
+		}
+	}
+	regionConfig.NumNodes = len(regionConfig.InstanceIDs)
+	return models.CloudConfig{constants.ExistingHostsRegion: regionConfig}, monitoringConfig, publicIPMap, apiNodeIPMap, nil
+}
+
+// createExistingHostsClusterConfig registers the existing [hosts] into the nodes and clusters
+// config, and into the cluster ansible inventories, keeping their own ssh settings
+func createExistingHostsClusterConfig(network models.Network, clusterName string, hosts []ExistingHost) error {
+	nodeHosts := []*models.Host{}
+	monitoringHosts := []*models.Host{}
+	for _, host := range hosts {
+		nodeConfig := models.NodeConfig{
+			NodeID:       host.Name,
+			Region:       constants.ExistingHostsRegion,
+			CertPath:     host.SSHKey,
+			ElasticIP:    host.IP,
+			CloudService: constants.ExistingHostsService,
+			UseStaticIP:  true,
+			IsMonitor:    host.Monitoring,
+		}
+		if err := app.CreateNodeCloudConfigFile(host.Name, &nodeConfig); err != nil {
+			return err
+		}
+		if host.Monitoring {
+			if err := addNodeToClustersConfig(network, host.Name, clusterName, false, true, constants.MonitorRole, ""); err != nil {
+				return err
+			}
+		} else {
+			if err := addNodeToClustersConfig(network, host.Name, clusterName, host.API, false, "", ""); err != nil {
+				return err
+			}
+		}
+		ansibleHost, err := host.toHost()
+		if err != nil {
+			return err
+		}
+		if host.Monitoring {
+			monitoringHosts = append(monitoringHosts, ansibleHost)
+		} else {
+			nodeHosts = append(nodeHosts, ansibleHost)
+		}
+	}
+	if err := ansible.CreateAnsibleHostInventoryFromHosts(app.GetAnsibleInventoryDirPath(clusterName), nodeHosts); err != nil {
+		return err
+	}
+	if len(monitoringHosts) > 0 {
+		return ansible.CreateAnsibleHostInventoryFromHosts(app.GetMonitoringInventoryDir(clusterName), monitoringHosts)
+	}
+	return nil
+}
+
+// checkExistingHostsRequirements verifies that the setup scripts can be executed on [hosts]:
+// the ssh user must have passwordless sudo and write access to /home/ubuntu
+func checkExistingHostsRequirements(hosts []*models.Host) error {
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			if output, err := host.Command(existingHostRequirementsCheck, nil, constants.SSHScriptTimeout); err != nil {
+				nodeResults.AddResult(host.NodeID, nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output))))
+			}
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		for nodeID, err := range wgResults.GetErrorHostMap() {
+			ux.Logger.RedXToUser("Host %s does not meet the requirements: %s", nodeID, err)
+		}
+		return fmt.Errorf("existing hosts must allow passwordless sudo and write access to /home/ubuntu for the ssh user")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExistingHosts(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519")
+	require.NoError(os.WriteFile(keyPath, []byte("key"), constants.WriteReadUserOnlyPerms))
+	hostsPath := filepath.Join(dir, "hosts.json")
+	writeHosts := func(contents string) {
+		require.NoError(os.WriteFile(hostsPath, []byte(contents), constants.WriteReadReadPerms))
+	}
+
+	writeHosts(`{"hosts":[
+{"ip":"10.0.0.1","sshKey":"` + keyPath + `"},
+{"name":"api-1","ip":"127.0.0.1","user":"admin","port":2222,"api":true},
+{"ip":"127.0.0.1","port":2223,"monitoring":true}
+]}`)
+	hosts, err := loadExistingHosts(hostsPath)
+	require.NoError(err)
+	require.Len(hosts, 3)
+	require.Equal("10-0-0-1", hosts[0].Name)
+	require.Equal(constants.AnsibleSSHUser, hosts[0].User)
+	require.Equal(uint(constants.SSHTCPPort), hosts[0].Port)
+	require.Equal("api-1", hosts[1].Name)
+	require.Equal("127-0-0-1-2223", hosts[2].Name)
+
+	host, err := hosts[1].toHost()
+	require.NoError(err)
+	require.Equal(constants.ExistingHostAnsiblePrefix+"_api-1", host.NodeID)
+	require.Equal("api-1", host.GetCloudID())
+	require.Equal(uint(2222), host.GetSSHPort())
+	require.Contains(host.GetAnsibleInventoryRecord(), "ansible_user=admin ansible_port=2222")
+
+	for _, invalid := range []string{
+		`{"hosts":[]}`,
+		`{"hosts":[{"ip":"not-an-ip"}]}`,
+		`{"hosts":[{"ip":"10.0.0.1"},{"ip":"10.0.0.1","name":"other"}]}`,
+		`{"hosts":[{"ip":"10.0.0.1","name":"node_1"}]}`,
+		`{"hosts":[{"ip":"10.0.0.1","sshKey":"/missing/key"}]}`,
+		`{"hosts":[{"ip":"10.0.0.1","monitoring":true}]}`,
+		`{"hosts":[{"ip":"10.0.0.1"},{"ip":"10.0.0.2","monitoring":true,"api":true}]}`,
+	} {
+		writeHosts(invalid)
+		_, err := loadExistingHosts(hostsPath)
+		require.Error(err, invalid)
+	}
+}
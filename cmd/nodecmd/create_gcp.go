@@ -131,10 +131,14 @@ func getGCPConfig(singleNode bool) (*gcpAPI.GcpCloud, map[string]NumNodes, strin
 	if err != nil {
 		return nil, nil, "", "", "", err
 	}
+	availableRegions, err := gcpCloud.ListRegions()
+	if err != nil {
+		return nil, nil, "", "", "", err
+	}
 	finalZones := map[string]NumNodes{}
 	// verify regions are valid and place in random zones per region
 	for region, numNodes := range finalRegions {
-		if !slices.Contains(gcpCloud.ListRegions(), region) {
+		if !slices.Contains(availableRegions, region) {
 			return nil, nil, "", "", "", fmt.Errorf("invalid region %s", region)
 		} else {
 			finalZone, err := gcpCloud.GetRandomZone(region)
@@ -366,6 +370,7 @@ func createGCPInstance(
 			SecurityGroup: fmt.Sprintf("%s-network", prefix),
 			CertFilePath:  certFilePath,
 			ImageID:       imageID,
+			InstanceType:  instanceType,
 		}
 	}
 	return ccm, nil
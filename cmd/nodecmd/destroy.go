@@ -8,14 +8,10 @@ import (
 	"os"
 	"strings"
 
-	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
 	"golang.org/x/exp/maps"
-	"golang.org/x/net/context"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 
-	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
-
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 
@@ -119,9 +115,7 @@ func destroyNodes(_ *cobra.Command, args []string) error {
 		nodesToStop = append(nodesToStop, monitoringNode)
 	}
 	nodeErrors := map[string]error{}
-	lastRegion := ""
-	var ec2Svc *awsAPI.AwsCloud
-	var gcpCloud *gcpAPI.GcpCloud
+	getCloudProvider := newCloudProviderGetter(true)
 	for _, node := range nodesToStop {
 		nodeConfig, err := app.LoadClusterNodeConfig(node)
 		if err != nil {
@@ -129,54 +123,21 @@ func destroyNodes(_ *cobra.Command, args []string) error {
 			ux.Logger.PrintToUser("Failed to destroy node %s due to %s", node, err.Error())
 			continue
 		}
-		if nodeConfig.CloudService == "" || nodeConfig.CloudService == constants.AWSCloudService {
-			if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.AWSCloudService) != nil) {
-				return fmt.Errorf("cloud access is required")
-			}
-			// need to check if it's empty because we didn't set cloud service when only using AWS
-			if nodeConfig.Region != lastRegion {
-				ec2Svc, err = awsAPI.NewAwsCloud(awsProfile, nodeConfig.Region)
-				if err != nil {
-					return err
-				}
-				lastRegion = nodeConfig.Region
+		if nodeConfig.CloudService == constants.ExistingHostsService {
+			ux.Logger.PrintToUser("Node %s is an existing host: removing it from cluster %s without stopping it", nodeConfig.NodeID, clusterName)
+		} else {
+			provider, err := getCloudProvider(nodeConfig)
+			if err != nil {
+				return err
 			}
-			if err = ec2Svc.DestroyAWSNode(nodeConfig, clusterName); err != nil {
+			if err := destroyCloudNode(provider, nodeConfig, clusterName); err != nil {
 				if isExpiredCredentialError(err) {
 					ux.Logger.PrintToUser("")
 					printExpiredCredentialsOutput(awsProfile)
 					return nil
 				}
-				if !errors.Is(err, awsAPI.ErrNodeNotFoundToBeRunning) {
-					nodeErrors[node] = err
-					continue
-				}
-				ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
-			}
-			if err = deleteMonitoringSecurityGroupRule(ec2Svc, nodeConfig.ElasticIP, nodeConfig.SecurityGroup, nodeConfig.Region); err != nil {
-				ux.Logger.RedXToUser("unable to delete IP address %s from security group %s in region %s due to %s, please delete it manually",
-					nodeConfig.ElasticIP, nodeConfig.SecurityGroup, nodeConfig.Region, err.Error())
-			}
-		} else {
-			if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.GCPCloudService) != nil) {
-				return fmt.Errorf("cloud access is required")
-			}
-			if gcpCloud == nil {
-				gcpClient, projectName, _, err := getGCPCloudCredentials()
-				if err != nil {
-					return err
-				}
-				gcpCloud, err = gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
-				if err != nil {
-					return err
-				}
-			}
-			if err = gcpCloud.DestroyGCPNode(nodeConfig, clusterName); err != nil {
-				if !errors.Is(err, gcpAPI.ErrNodeNotFoundToBeRunning) {
-					nodeErrors[node] = err
-					continue
-				}
-				ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
+				nodeErrors[node] = err
+				continue
 			}
 		}
 		ux.Logger.PrintToUser("Node instance %s in cluster %s successfully destroyed!", nodeConfig.NodeID, clusterName)
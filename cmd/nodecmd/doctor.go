@@ -0,0 +1,544 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var doctorFix bool
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor [clusterName]",
+		Short: "(ALPHA Warning) Check the local information of clusters against the cloud and the nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node doctor command compares the local information kept for a cluster, or for all clusters
+if none is given (cluster configuration, node configurations and ansible inventories), against
+the cloud APIs and the SSH reachability of the nodes, and reports every inconsistency found:
+- node, monitoring and load test instances that are no longer running at the cloud
+- public IPs that changed at the cloud
+- hosts missing from the ansible inventory, and inventory hosts that are not part of the cluster
+- firewall groups that were deleted at the cloud
+- nodes that are not reachable over SSH
+- instances created by the CLI that are not part of any cluster
+- node configurations that are not used by any cluster
+
+With --fix, the local information is updated to match the real state where possible. Cloud
+resources are never created nor destroyed, and node staking keys are never deleted.`,
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         doctor,
+	}
+	cmd.Flags().BoolVar(&doctorFix, "fix", false, "fix the inconsistencies found, where possible")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to access cloud resources")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+
+	return cmd
+}
+
+// clusterIssue is an inconsistency between the local information of a cluster and its real state
+type clusterIssue struct {
+	ClusterName string // empty for issues not bound to a cluster
+	Description string
+	// Fix updates the local information to solve the issue. Nil if it can't be done automatically
+	Fix func() error
+	// Hint tells the user how to solve the issue when there is no Fix
+	Hint string
+}
+
+// clusterInstance is an instance referenced by a cluster, together with the ansible
+// inventory where its host is expected to be
+type clusterInstance struct {
+	CloudID      string
+	Role         string
+	InventoryDir string
+}
+
+// doctorLocation holds the running instances found at a cloud location
+type doctorLocation struct {
+	provider         cloud.CloudProvider
+	location         string
+	runningInstances []string
+}
+
+// clusterDoctor looks for inconsistencies between the local information of the clusters
+// and the real state of their instances
+type clusterDoctor struct {
+	clustersConfig   models.ClustersConfig
+	getCloudProvider cloudProviderGetter
+	checkSSH         func(host *models.Host) error
+	// cloud locations queried, by provider name and location
+	locations map[string]*doctorLocation
+	// firewall groups queried, by provider name, location and group
+	firewallGroups map[string]bool
+	issues         []clusterIssue
+}
+
+func newClusterDoctor(
+	clustersConfig models.ClustersConfig,
+	getCloudProvider cloudProviderGetter,
+	checkSSH func(host *models.Host) error,
+) *clusterDoctor {
+	return &clusterDoctor{
+		clustersConfig:   clustersConfig,
+		getCloudProvider: getCloudProvider,
+		checkSSH:         checkSSH,
+		locations:        map[string]*doctorLocation{},
+		firewallGroups:   map[string]bool{},
+	}
+}
+
+func doctor(_ *cobra.Command, args []string) error {
+	clustersConfig := models.ClustersConfig{}
+	if app.ClustersConfigExists() {
+		var err error
+		clustersConfig, err = app.LoadClustersConfig()
+		if err != nil {
+			return err
+		}
+	}
+	clusterNames := maps.Keys(clustersConfig.Clusters)
+	sort.Strings(clusterNames)
+	if len(args) == 1 {
+		if _, ok := clustersConfig.Clusters[args[0]]; !ok {
+			return fmt.Errorf("cluster %q does not exist", args[0])
+		}
+		clusterNames = args
+	}
+	issues, err := newClusterDoctor(clustersConfig, newCloudProviderGetter(true), checkSSHReachable).diagnose(clusterNames)
+	if err != nil {
+		if isExpiredCredentialError(err) {
+			ux.Logger.PrintToUser("")
+			printExpiredCredentialsOutput(awsProfile)
+		}
+		return err
+	}
+	return reportClusterIssues(issues, doctorFix)
+}
+
+// reportClusterIssues prints [issues], fixing them if [fix] is set. Returns an
+// error if any issue is left unsolved
+func reportClusterIssues(issues []clusterIssue, fix bool) error {
+	if len(issues) == 0 {
+		ux.Logger.GreenCheckmarkToUser("No issues found")
+		return nil
+	}
+	pending := 0
+	for _, issue := range issues {
+		description := issue.Description
+		if issue.ClusterName != "" {
+			description = fmt.Sprintf("[%s] %s", issue.ClusterName, description)
+		}
+		ux.Logger.RedXToUser(description)
+		switch {
+		case issue.Fix == nil:
+			ux.Logger.PrintToUser("  Manual action required: %s", issue.Hint)
+			pending++
+		case !fix:
+			ux.Logger.PrintToUser("  Can be fixed with --fix")
+			pending++
+		default:
+			if err := issue.Fix(); err != nil {
+				ux.Logger.PrintToUser("  Failed to fix: %s", err)
+				pending++
+				continue
+			}
+			ux.Logger.GreenCheckmarkToUser("  Fixed")
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d of %d issue(s) found are not solved", pending, len(issues))
+	}
+	return nil
+}
+
+// diagnose looks for the issues of [clusterNames], and of the local and cloud
+// state not bound to any cluster
+func (d *clusterDoctor) diagnose(clusterNames []string) ([]clusterIssue, error) {
+	for _, clusterName := range clusterNames {
+		ux.Logger.PrintToUser("Checking cluster %s...", clusterName)
+		if err := d.diagnoseCluster(clusterName); err != nil {
+			return nil, err
+		}
+	}
+	d.diagnoseUnknownInstances()
+	if err := d.diagnoseUnusedNodeConfigs(); err != nil {
+		return nil, err
+	}
+	return d.issues, nil
+}
+
+func (d *clusterDoctor) addIssue(clusterName string, fix func() error, hint string, format string, args ...interface{}) {
+	d.issues = append(d.issues, clusterIssue{
+		ClusterName: clusterName,
+		Description: fmt.Sprintf(format, args...),
+		Fix:         fix,
+		Hint:        hint,
+	})
+}
+
+// getClusterInstances returns the node, monitoring and load test instances of [clusterConfig]
+func getClusterInstances(clusterName string, clusterConfig models.ClusterConfig) []clusterInstance {
+	instances := []clusterInstance{}
+	for _, cloudID := range clusterConfig.Nodes {
+		role := "node"
+		if clusterConfig.IsAPIHost(cloudID) {
+			role = "API node"
+		}
+		instances = append(instances, clusterInstance{
+			CloudID:      cloudID,
+			Role:         role,
+			InventoryDir: app.GetAnsibleInventoryDirPath(clusterName),
+		})
+	}
+	if clusterConfig.MonitoringInstance != "" {
+		instances = append(instances, clusterInstance{
+			CloudID:      clusterConfig.MonitoringInstance,
+			Role:         "monitoring instance",
+			InventoryDir: app.GetMonitoringInventoryDir(clusterName),
+		})
+	}
+	loadTestNames := maps.Keys(clusterConfig.LoadTestInstance)
+	sort.Strings(loadTestNames)
+	for _, loadTestName := range loadTestNames {
+		instances = append(instances, clusterInstance{
+			CloudID:      clusterConfig.LoadTestInstance[loadTestName],
+			Role:         fmt.Sprintf("load test %s instance", loadTestName),
+			InventoryDir: app.GetLoadTestInventoryDir(clusterName),
+		})
+	}
+	return instances
+}
+
+// loadInventoryByCloudID returns the hosts of the inventory at [inventoryDir] by cloud ID.
+// A missing inventory is considered empty
+func loadInventoryByCloudID(inventoryDir string) (map[string]*models.Host, error) {
+	inventory := map[string]*models.Host{}
+	if !utils.FileExists(filepath.Join(inventoryDir, constants.AnsibleHostInventoryFileName)) {
+		return inventory, nil
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(inventoryDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		inventory[host.GetCloudID()] = host
+	}
+	return inventory, nil
+}
+
+func (d *clusterDoctor) diagnoseCluster(clusterName string) error {
+	clusterConfig, ok := d.clustersConfig.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %q does not exist", clusterName)
+	}
+	instances := getClusterInstances(clusterName, clusterConfig)
+	inventoryDirs := []string{
+		app.GetAnsibleInventoryDirPath(clusterName),
+		app.GetMonitoringInventoryDir(clusterName),
+		app.GetLoadTestInventoryDir(clusterName),
+	}
+	inventories := map[string]map[string]*models.Host{}
+	for _, inventoryDir := range inventoryDirs {
+		inventory, err := loadInventoryByCloudID(inventoryDir)
+		if err != nil {
+			return err
+		}
+		inventories[inventoryDir] = inventory
+	}
+	for _, instance := range instances {
+		if err := d.diagnoseInstance(clusterName, instance, inventories[instance.InventoryDir][instance.CloudID]); err != nil {
+			return err
+		}
+	}
+	for _, inventoryDir := range inventoryDirs {
+		inventoryDir := inventoryDir
+		cloudIDs := maps.Keys(inventories[inventoryDir])
+		sort.Strings(cloudIDs)
+		for _, cloudID := range cloudIDs {
+			if slices.ContainsFunc(instances, func(i clusterInstance) bool {
+				return i.CloudID == cloudID && i.InventoryDir == inventoryDir
+			}) {
+				continue
+			}
+			ansibleHostID := inventories[inventoryDir][cloudID].NodeID
+			d.addIssue(
+				clusterName,
+				func() error { return ansible.RemoveInventoryHosts(inventoryDir, []string{ansibleHostID}) },
+				"",
+				"inventory %s has host %s, which is not part of the cluster",
+				inventoryDir,
+				ansibleHostID,
+			)
+		}
+	}
+	return nil
+}
+
+func (d *clusterDoctor) diagnoseInstance(clusterName string, instance clusterInstance, host *models.Host) error {
+	nodeConfig, err := app.LoadClusterNodeConfig(instance.CloudID)
+	if err != nil {
+		d.addIssue(
+			clusterName,
+			nil,
+			fmt.Sprintf("restore %s, or remove the cluster with avalanche node destroy %s", app.GetNodeConfigPath(instance.CloudID), clusterName),
+			"%s %s has no valid node config: %s",
+			instance.Role,
+			instance.CloudID,
+			err,
+		)
+		return nil
+	}
+	isCloudInstance := nodeConfig.CloudService != constants.ExistingHostsService && nodeConfig.CloudService != constants.E2EDocker
+	publicIP := nodeConfig.ElasticIP
+	if isCloudInstance {
+		provider, err := d.getCloudProvider(nodeConfig)
+		if err != nil {
+			return err
+		}
+		runningInstances, err := d.getRunningInstances(provider, nodeConfig.Region)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(runningInstances, nodeConfig.NodeID) {
+			d.addIssue(
+				clusterName,
+				func() error { return removeClusterInstance(clusterName, instance) },
+				"",
+				"%s %s is not running at %s %s (terminated or stopped). Fixing removes it from the cluster, keeping its node config and keys at %s",
+				instance.Role,
+				instance.CloudID,
+				provider.Name(),
+				nodeConfig.Region,
+				app.GetNodeInstanceDirPath(instance.CloudID),
+			)
+			return nil
+		}
+		publicIPs, err := provider.GetInstancePublicIPs(nodeConfig.Region, []string{nodeConfig.NodeID})
+		if err != nil {
+			return err
+		}
+		if cloudIP := publicIPs[nodeConfig.NodeID]; cloudIP != "" {
+			publicIP = cloudIP
+		}
+		if nodeConfig.SecurityGroup != "" {
+			exists, err := d.firewallGroupExists(provider, nodeConfig.Region, nodeConfig.SecurityGroup)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				d.addIssue(
+					clusterName,
+					nil,
+					"recreate it with the rules needed by the node, or destroy and recreate the node",
+					"firewall group %s of %s %s does not exist at %s %s",
+					nodeConfig.SecurityGroup,
+					instance.Role,
+					instance.CloudID,
+					provider.Name(),
+					nodeConfig.Region,
+				)
+			}
+		}
+	}
+	if host == nil {
+		var fix func() error
+		hint := ""
+		if isCloudInstance {
+			fix = func() error {
+				return ansible.CreateAnsibleHostInventory(
+					instance.InventoryDir,
+					nodeConfig.CertPath,
+					nodeConfig.CloudService,
+					map[string]string{instance.CloudID: publicIP},
+					nil,
+				)
+			}
+		} else {
+			hint = fmt.Sprintf("add it back to %s", instance.InventoryDir)
+		}
+		d.addIssue(clusterName, fix, hint, "%s %s is missing from inventory %s", instance.Role, instance.CloudID, instance.InventoryDir)
+		return nil
+	}
+	if nodeConfig.ElasticIP != publicIP || host.IP != publicIP {
+		d.addIssue(
+			clusterName,
+			func() error { return updateClusterInstanceIP(instance, publicIP) },
+			"",
+			"public IP of %s %s is %s, but %s is recorded on its node config and %s on the inventory",
+			instance.Role,
+			instance.CloudID,
+			publicIP,
+			nodeConfig.ElasticIP,
+			host.IP,
+		)
+	}
+	sshHost := *host
+	sshHost.IP = publicIP
+	sshHost.Connection = nil
+	if err := d.checkSSH(&sshHost); err != nil {
+		d.addIssue(
+			clusterName,
+			nil,
+			fmt.Sprintf("check that the instance is healthy. If its host key changed legitimately, run avalanche node ssh %s --reset-host-key", clusterName),
+			"%s %s is not reachable over SSH at %s: %s",
+			instance.Role,
+			instance.CloudID,
+			publicIP,
+			err,
+		)
+	}
+	return nil
+}
+
+// getRunningInstances returns the running instances created by the CLI at [location]
+func (d *clusterDoctor) getRunningInstances(provider cloud.CloudProvider, location string) ([]string, error) {
+	key := provider.Name() + "/" + location
+	if l, ok := d.locations[key]; ok {
+		return l.runningInstances, nil
+	}
+	runningInstances, err := provider.ListInstances(location)
+	if err != nil {
+		return nil, err
+	}
+	d.locations[key] = &doctorLocation{provider: provider, location: location, runningInstances: runningInstances}
+	return runningInstances, nil
+}
+
+func (d *clusterDoctor) firewallGroupExists(provider cloud.CloudProvider, location string, group string) (bool, error) {
+	key := provider.Name() + "/" + location + "/" + group
+	if exists, ok := d.firewallGroups[key]; ok {
+		return exists, nil
+	}
+	exists, err := provider.CheckFirewallGroupExists(group)
+	if err != nil {
+		return false, err
+	}
+	d.firewallGroups[key] = exists
+	return exists, nil
+}
+
+// getKnownInstances returns the instances referenced by any cluster
+func (d *clusterDoctor) getKnownInstances() []string {
+	knownInstances := []string{}
+	for clusterName, clusterConfig := range d.clustersConfig.Clusters {
+		for _, instance := range getClusterInstances(clusterName, clusterConfig) {
+			knownInstances = append(knownInstances, instance.CloudID)
+		}
+	}
+	return knownInstances
+}
+
+// diagnoseUnknownInstances reports the running instances created by the CLI that are not part of
+// any cluster, at the cloud locations used by the diagnosed clusters
+func (d *clusterDoctor) diagnoseUnknownInstances() {
+	knownInstances := d.getKnownInstances()
+	locationKeys := maps.Keys(d.locations)
+	sort.Strings(locationKeys)
+	for _, locationKey := range locationKeys {
+		l := d.locations[locationKey]
+		for _, instanceID := range l.runningInstances {
+			if slices.Contains(knownInstances, instanceID) {
+				continue
+			}
+			d.addIssue(
+				"",
+				nil,
+				"if it is not managed from another machine, terminate it from the cloud console",
+				"instance %s at %s %s was created by the CLI but is not part of any cluster",
+				instanceID,
+				l.provider.Name(),
+				l.location,
+			)
+		}
+	}
+}
+
+// diagnoseUnusedNodeConfigs reports the node configs that are not used by any cluster
+func (d *clusterDoctor) diagnoseUnusedNodeConfigs() error {
+	entries, err := os.ReadDir(app.GetNodesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	knownInstances := d.getKnownInstances()
+	for _, entry := range entries {
+		if !entry.IsDir() || slices.Contains(knownInstances, entry.Name()) || !utils.FileExists(app.GetNodeConfigPath(entry.Name())) {
+			continue
+		}
+		d.addIssue(
+			"",
+			nil,
+			fmt.Sprintf("if the instance no longer exists, remove %s. It holds the node staking keys", app.GetNodeInstanceDirPath(entry.Name())),
+			"node config of instance %s is not used by any cluster",
+			entry.Name(),
+		)
+	}
+	return nil
+}
+
+// removeClusterInstance removes [instance] from its cluster config and inventory
+func removeClusterInstance(clusterName string, instance clusterInstance) error {
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig, ok := clustersConfig.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %q does not exist", clusterName)
+	}
+	clusterConfig.Nodes = utils.Filter(clusterConfig.Nodes, func(cloudID string) bool { return cloudID != instance.CloudID })
+	clusterConfig.APINodes = utils.Filter(clusterConfig.APINodes, func(cloudID string) bool { return cloudID != instance.CloudID })
+	if clusterConfig.MonitoringInstance == instance.CloudID {
+		clusterConfig.MonitoringInstance = ""
+	}
+	for loadTestName, cloudID := range clusterConfig.LoadTestInstance {
+		if cloudID == instance.CloudID {
+			delete(clusterConfig.LoadTestInstance, loadTestName)
+		}
+	}
+	clustersConfig.Clusters[clusterName] = clusterConfig
+	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
+		return err
+	}
+	inventory, err := loadInventoryByCloudID(instance.InventoryDir)
+	if err != nil {
+		return err
+	}
+	if host, ok := inventory[instance.CloudID]; ok {
+		return ansible.RemoveInventoryHosts(instance.InventoryDir, []string{host.NodeID})
+	}
+	return nil
+}
+
+// updateClusterInstanceIP sets [publicIP] as the IP of [instance] on its node config and inventory
+func updateClusterInstanceIP(instance clusterInstance, publicIP string) error {
+	nodeConfig, err := app.LoadClusterNodeConfig(instance.CloudID)
+	if err != nil {
+		return err
+	}
+	if nodeConfig.ElasticIP != publicIP {
+		nodeConfig.ElasticIP = publicIP
+		if err := app.CreateNodeCloudConfigFile(instance.CloudID, &nodeConfig); err != nil {
+			return err
+		}
+	}
+	return ansible.UpdateInventoryHostPublicIP(instance.InventoryDir, map[string]string{instance.CloudID: publicIP})
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/cloud/fake"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+func getIssueDescriptions(issues []clusterIssue) []string {
+	descriptions := []string{}
+	for _, issue := range issues {
+		descriptions = append(descriptions, issue.Description)
+	}
+	return descriptions
+}
+
+func TestClusterDoctor(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	app = application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil, nil)
+	defer models.SetKnownHostsPath("")
+
+	const (
+		region      = "us-east-1"
+		sg          = "avalanche-cli-sg"
+		clusterName = "cluster"
+	)
+	provider := fake.New(constants.AWSCloudService, []string{region})
+	spec := cloud.InstanceSpec{Location: region, Count: 5, FirewallGroup: sg}
+	nodeIDs, err := provider.CreateInstances(spec)
+	require.NoError(err)
+	spec.Count = 1
+	spec.Prefix = "monitoring"
+	monitoringIDs, err := provider.CreateInstances(spec)
+	require.NoError(err)
+	spec.Prefix = "unknown"
+	unknownIDs, err := provider.CreateInstances(spec)
+	require.NoError(err)
+	allIDs := append(slices.Clone(nodeIDs), monitoringIDs...)
+	publicIPs, err := provider.GetInstancePublicIPs(region, allIDs)
+	require.NoError(err)
+	for _, instanceID := range allIDs {
+		nodeConfig := models.NodeConfig{
+			NodeID:        instanceID,
+			Region:        region,
+			SecurityGroup: sg,
+			ElasticIP:     publicIPs[instanceID],
+			CloudService:  constants.AWSCloudService,
+			IsMonitor:     instanceID == monitoringIDs[0],
+		}
+		require.NoError(app.CreateNodeCloudConfigFile(instanceID, &nodeConfig))
+	}
+	require.NoError(app.CreateNodeCloudConfigFile("unused", &models.NodeConfig{NodeID: "unused"}))
+	require.NoError(app.WriteClustersConfigFile(&models.ClustersConfig{
+		Clusters: map[string]models.ClusterConfig{
+			clusterName: {
+				Nodes:              nodeIDs,
+				APINodes:           nodeIDs[4:],
+				MonitoringInstance: monitoringIDs[0],
+			},
+		},
+	}))
+	// nodeIDs[3] is missing from the inventory, which has a host not in the cluster
+	inventoryIPs := map[string]string{"stale": "10.10.10.10"}
+	for _, instanceID := range []string{nodeIDs[0], nodeIDs[1], nodeIDs[2], nodeIDs[4]} {
+		inventoryIPs[instanceID] = publicIPs[instanceID]
+	}
+	require.NoError(ansible.CreateAnsibleHostInventory(app.GetAnsibleInventoryDirPath(clusterName), "", constants.AWSCloudService, inventoryIPs, nil))
+	require.NoError(ansible.CreateAnsibleHostInventory(
+		app.GetMonitoringInventoryDir(clusterName),
+		"",
+		constants.AWSCloudService,
+		map[string]string{monitoringIDs[0]: publicIPs[monitoringIDs[0]]},
+		nil,
+	))
+	// nodeIDs[1] got a new IP, nodeIDs[2] was terminated, and the monitoring instance stopped
+	newIP, err := provider.RotatePublicIP(nodeIDs[1])
+	require.NoError(err)
+	require.NoError(provider.DestroyNode(models.NodeConfig{NodeID: nodeIDs[2], Region: region}, clusterName))
+	require.NoError(provider.StopInstance(monitoringIDs[0]))
+	// nodeIDs[4] is not reachable
+	checkSSH := func(host *models.Host) error {
+		if host.GetCloudID() == nodeIDs[4] {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	getCloudProvider := func(models.NodeConfig) (cloud.CloudProvider, error) {
+		return provider, nil
+	}
+	diagnose := func() []clusterIssue {
+		clustersConfig, err := app.LoadClustersConfig()
+		require.NoError(err)
+		issues, err := newClusterDoctor(clustersConfig, getCloudProvider, checkSSH).diagnose([]string{clusterName})
+		require.NoError(err)
+		return issues
+	}
+
+	issues := diagnose()
+	descriptions := getIssueDescriptions(issues)
+	require.Len(issues, 8, descriptions)
+	require.Contains(descriptions[0], "public IP of node "+nodeIDs[1]+" is "+newIP)
+	require.Contains(descriptions[1], "node "+nodeIDs[2]+" is not running")
+	require.Contains(descriptions[2], "node "+nodeIDs[3]+" is missing from inventory")
+	require.Contains(descriptions[3], "API node "+nodeIDs[4]+" is not reachable over SSH")
+	require.Contains(descriptions[4], "monitoring instance "+monitoringIDs[0]+" is not running")
+	require.Contains(descriptions[5], "has host aws_node_stale, which is not part of the cluster")
+	require.Contains(descriptions[6], "instance "+unknownIDs[0]+" at "+constants.AWSCloudService+" us-east-1 was created by the CLI")
+	require.Contains(descriptions[7], "node config of instance unused is not used by any cluster")
+	for _, issue := range issues {
+		require.Equal(issue.Fix == nil, issue.Hint != "", issue.Description)
+	}
+	require.Error(reportClusterIssues(issues, false))
+	require.Error(reportClusterIssues(issues, true))
+
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	require.NoError(err)
+	require.Equal([]string{nodeIDs[0], nodeIDs[1], nodeIDs[3], nodeIDs[4]}, clusterConfig.Nodes)
+	require.Empty(clusterConfig.MonitoringInstance)
+	nodeConfig, err := app.LoadClusterNodeConfig(nodeIDs[1])
+	require.NoError(err)
+	require.Equal(newIP, nodeConfig.ElasticIP)
+	inventory, err := loadInventoryByCloudID(app.GetAnsibleInventoryDirPath(clusterName))
+	require.NoError(err)
+	require.Len(inventory, 4)
+	require.Equal(newIP, inventory[nodeIDs[1]].IP)
+	require.Equal(publicIPs[nodeIDs[3]], inventory[nodeIDs[3]].IP)
+	inventory, err = loadInventoryByCloudID(app.GetMonitoringInventoryDir(clusterName))
+	require.NoError(err)
+	require.Empty(inventory)
+
+	// fixed issues are gone. The node configs of the removed instances are kept
+	descriptions = getIssueDescriptions(diagnose())
+	require.Len(descriptions, 5, descriptions)
+	require.Contains(descriptions[0], "API node "+nodeIDs[4]+" is not reachable over SSH")
+	require.Contains(descriptions[1], "instance "+unknownIDs[0]+" at "+constants.AWSCloudService+" us-east-1 was created by the CLI")
+	require.Contains(descriptions[2], "node config of instance "+nodeIDs[2]+" is not used by any cluster")
+	require.Contains(descriptions[3], "node config of instance "+monitoringIDs[0]+" is not used by any cluster")
+	require.Contains(descriptions[4], "node config of instance unused is not used by any cluster")
+
+	provider.DeleteFirewallGroup(sg)
+	descriptions = getIssueDescriptions(diagnose())
+	require.Contains(descriptions[0], "firewall group "+sg+" of node "+nodeIDs[0]+" does not exist")
+}
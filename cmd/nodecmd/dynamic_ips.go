@@ -3,14 +3,7 @@
 package nodecmd
 
 import (
-	"context"
-	"fmt"
-
-	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
-	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
-
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
-	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -30,53 +23,21 @@ func getNodesWithDynamicIP(clusterNodes []string) ([]models.NodeConfig, error) {
 	return nodesWithDynamicIP, nil
 }
 
-func getPublicIPsForNodesWithDynamicIP(nodesWithDynamicIP []models.NodeConfig) (map[string]string, error) {
+func getPublicIPsForNodesWithDynamicIP(nodesWithDynamicIP []models.NodeConfig, getCloudProvider cloudProviderGetter) (map[string]string, error) {
 	publicIPMap := make(map[string]string)
-	var (
-		err        error
-		lastRegion string
-		ec2Svc     *awsAPI.AwsCloud
-		gcpCloud   *gcpAPI.GcpCloud
-	)
 	ux.Logger.PrintToUser("Getting Public IP(s) for node(s) with dynamic IP ...")
 	for _, node := range nodesWithDynamicIP {
-		if lastRegion == "" || node.Region != lastRegion {
-			if node.CloudService == "" || node.CloudService == constants.AWSCloudService {
-				ec2Svc, err = awsAPI.NewAwsCloud(awsProfile, node.Region)
-				if err != nil {
-					return nil, err
-				}
-			}
-			lastRegion = node.Region
+		provider, err := getCloudProvider(node)
+		if err != nil {
+			return nil, err
 		}
-		var publicIP map[string]string
-		if node.CloudService == constants.GCPCloudService {
-			if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.GCPCloudService) != nil) {
-				return nil, fmt.Errorf("cloud access is required")
-			}
-			if gcpCloud == nil {
-				gcpClient, projectName, _, err := getGCPCloudCredentials()
-				if err != nil {
-					return nil, err
-				}
-				gcpCloud, err = gcpAPI.NewGcpCloud(gcpClient, projectName, context.Background())
-				if err != nil {
-					return nil, err
-				}
-			}
-			publicIP, err = gcpCloud.GetInstancePublicIPs(node.Region, []string{node.NodeID})
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			publicIP, err = ec2Svc.GetInstancePublicIPs([]string{node.NodeID})
-			if err != nil {
-				if isExpiredCredentialError(err) {
-					ux.Logger.PrintToUser("")
-					printExpiredCredentialsOutput(awsProfile)
-				}
-				return nil, err
+		publicIP, err := provider.GetInstancePublicIPs(node.Region, []string{node.NodeID})
+		if err != nil {
+			if isExpiredCredentialError(err) {
+				ux.Logger.PrintToUser("")
+				printExpiredCredentialsOutput(awsProfile)
 			}
+			return nil, err
 		}
 		publicIPMap[node.NodeID] = publicIP[node.NodeID]
 	}
@@ -98,7 +59,7 @@ func updatePublicIPs(clusterName string) error {
 	if len(nodesWithDynamicIP) > 0 {
 		nodeIDs := utils.Map(nodesWithDynamicIP, func(c models.NodeConfig) string { return c.NodeID })
 		ux.Logger.PrintToUser("Nodes with dynamic IPs in cluster: %s", nodeIDs)
-		publicIPMap, err := getPublicIPsForNodesWithDynamicIP(nodesWithDynamicIP)
+		publicIPMap, err := getPublicIPsForNodesWithDynamicIP(nodesWithDynamicIP, newCloudProviderGetter(false))
 		if err != nil {
 			return err
 		}
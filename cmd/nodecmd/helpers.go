@@ -3,15 +3,14 @@
 package nodecmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
-	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/api/info"
 )
@@ -27,31 +26,26 @@ func (n NumNodes) All() int {
 }
 
 func getUnhealthyNodes(hosts []*models.Host) ([]string, error) {
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckHealthy(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if isHealthy, err := parseHealthyOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), isHealthy, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to get health status for node(s) %s", wgResults.GetErrorHostMap())
-	}
-	return utils.Filter(wgResults.GetNodeList(), func(nodeID string) bool {
-		return !wgResults.GetResultMap()[nodeID].(bool)
-	}), nil
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		resp, err := ssh.RunSSHCheckHealthy(host)
+		if err != nil {
+			return models.CommandOutput{}, err
+		}
+		if _, err := parseHealthyOutput(resp); err != nil {
+			return models.CommandOutput{}, err
+		}
+		return models.CommandOutput{Stdout: resp}, nil
+	})
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to get health status for node(s) %s", results.GetErrorHostMap())
+	}
+	unhealthyNodes := []string{}
+	for _, result := range results.GetResults() {
+		if isHealthy, _ := parseHealthyOutput([]byte(result.Stdout)); !isHealthy {
+			unhealthyNodes = append(unhealthyNodes, result.CloudID)
+		}
+	}
+	return unhealthyNodes, nil
 }
 
 func parseHealthyOutput(byteValue []byte) (bool, error) {
@@ -71,31 +65,26 @@ func parseHealthyOutput(byteValue []byte) (bool, error) {
 
 func getNotBootstrappedNodes(hosts []*models.Host) ([]string, error) {
 	ux.Logger.PrintToUser("Checking if node(s) are bootstrapped to Primary Network...")
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckBootstrapped(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if isBootstrapped, err := parseBootstrappedOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), isBootstrapped, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to get avalanchego bootrapp status for node(s) %s", wgResults.GetErrorHostMap())
-	}
-	return utils.Filter(wgResults.GetNodeList(), func(nodeID string) bool {
-		return !wgResults.GetResultMap()[nodeID].(bool)
-	}), nil
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		resp, err := ssh.RunSSHCheckBootstrapped(host)
+		if err != nil {
+			return models.CommandOutput{}, err
+		}
+		if _, err := parseBootstrappedOutput(resp); err != nil {
+			return models.CommandOutput{}, err
+		}
+		return models.CommandOutput{Stdout: resp}, nil
+	})
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to get avalanchego bootrapp status for node(s) %s", results.GetErrorHostMap())
+	}
+	notBootstrappedNodes := []string{}
+	for _, result := range results.GetResults() {
+		if isBootstrapped, _ := parseBootstrappedOutput([]byte(result.Stdout)); !isBootstrapped {
+			notBootstrappedNodes = append(notBootstrappedNodes, result.CloudID)
+		}
+	}
+	return notBootstrappedNodes, nil
 }
 
 func parseBootstrappedOutput(byteValue []byte) (bool, error) {
@@ -119,33 +108,23 @@ func getRPCIncompatibleNodes(hosts []*models.Host, subnetName string) ([]string,
 	if err != nil {
 		return nil, err
 	}
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if _, rpcVersion, err := parseAvalancheGoOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), rpcVersion, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to get rpc protocol version for node(s) %s", wgResults.GetErrorHostMap())
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+		if err != nil {
+			return models.CommandOutput{}, err
+		}
+		if _, _, err := parseAvalancheGoOutput(resp); err != nil {
+			return models.CommandOutput{}, err
+		}
+		return models.CommandOutput{Stdout: resp}, nil
+	})
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to get rpc protocol version for node(s) %s", results.GetErrorHostMap())
 	}
 	incompatibleNodes := []string{}
-	for nodeID, rpcVersionI := range wgResults.GetResultMap() {
-		rpcVersion := rpcVersionI.(uint32)
-		if rpcVersion != uint32(sc.RPCVersion) {
-			incompatibleNodes = append(incompatibleNodes, nodeID)
+	for _, result := range results.GetResults() {
+		if _, rpcVersion, _ := parseAvalancheGoOutput([]byte(result.Stdout)); rpcVersion != uint32(sc.RPCVersion) {
+			incompatibleNodes = append(incompatibleNodes, result.CloudID)
 		}
 	}
 	if len(incompatibleNodes) > 0 {
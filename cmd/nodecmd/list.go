@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/cost"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 
@@ -20,7 +22,8 @@ func newListCmd() *cobra.Command {
 		Short: "(ALPHA Warning) List all clusters together with their nodes",
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
-The node list command lists all clusters together with their nodes.`,
+The node list command lists all clusters together with their nodes, and the
+estimated cost of their cloud servers since they were created.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(0),
 		RunE:         list,
@@ -41,6 +44,11 @@ func list(_ *cobra.Command, _ []string) error {
 	if len(clustersConfig.Clusters) == 0 {
 		ux.Logger.PrintToUser("There are no clusters defined.")
 	}
+	priceTable, err := cost.LoadPriceTable(app.GetPriceTablePath())
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 	clusterNames := maps.Keys(clustersConfig.Clusters)
 	sort.Strings(clusterNames)
 	for _, clusterName := range clusterNames {
@@ -49,11 +57,20 @@ func list(_ *cobra.Command, _ []string) error {
 		if err := checkCluster(clusterName); err != nil {
 			return err
 		}
+		clusterCost := 0.0
+		numUnknownCostNodes := 0
 		for _, cloudID := range clusterConf.GetCloudIDs() {
 			nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
 			if err != nil {
 				return err
 			}
+			if isCloudNode(nodeConfig) {
+				if nodeCost, err := getNodeUptimeCost(priceTable, nodeConfig, now); err != nil {
+					numUnknownCostNodes++
+				} else {
+					clusterCost += nodeCost
+				}
+			}
 			nodeIDStr := "----------------------------------------"
 			if clusterConf.IsAvalancheGoHost(cloudID) {
 				nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
@@ -69,6 +86,11 @@ func list(_ *cobra.Command, _ []string) error {
 			}
 			ux.Logger.PrintToUser(fmt.Sprintf("  Node %s (%s) %s%s", cloudID, nodeIDStr, nodeConfig.ElasticIP, rolesStr))
 		}
+		unknownCostStr := ""
+		if numUnknownCostNodes > 0 {
+			unknownCostStr = fmt.Sprintf(" (cost of %d node(s) is unknown)", numUnknownCostNodes)
+		}
+		ux.Logger.PrintToUser("  Estimated cloud cost since creation: $%.2f%s", clusterCost, unknownCostStr)
 	}
 	return nil
 }
@@ -79,6 +79,7 @@ The command will then run the load test binary based on the provided load test r
 	cmd.Flags().StringVar(&loadTestCmd, "load-test-cmd", "", "command to run load test")
 	cmd.Flags().StringVar(&loadTestHostRegion, "region", "", "create load test node in a given region")
 	cmd.Flags().StringVar(&loadTestBranch, "load-test-branch", "", "load test branch or commit")
+	cmd.Flags().BoolVar(&ignoreBudget, ignoreBudgetFlag, false, "create the load test cloud server even if its estimated cost exceeds the configured budget")
 	return cmd
 }
 
@@ -189,6 +190,9 @@ func startLoadTest(_ *cobra.Command, args []string) error {
 				return err
 			}
 			separateHostRegion = loadTestHostRegion
+			if err := checkCloudCost(getLoadTestCloudResources(cloudService, separateHostRegion)); err != nil {
+				return err
+			}
 			loadTestEc2SvcMap[separateHostRegion] = ec2SvcMap[separateHostRegion]
 			loadTestCloudConfig, err = createAWSInstances(loadTestEc2SvcMap, nodeType, map[string]NumNodes{separateHostRegion: {1, 0}}, []string{separateHostRegion}, ami, true)
 			if err != nil {
@@ -207,7 +211,7 @@ func startLoadTest(_ *cobra.Command, args []string) error {
 		}
 		if !useStaticIP {
 			// get loadtest public
-			loadTestPublicIPMap, err := loadTestEc2SvcMap[separateHostRegion].GetInstancePublicIPs(loadTestNodeConfig.InstanceIDs)
+			loadTestPublicIPMap, err := loadTestEc2SvcMap[separateHostRegion].GetInstancePublicIPs(separateHostRegion, loadTestNodeConfig.InstanceIDs)
 			if err != nil {
 				return err
 			}
@@ -233,6 +237,9 @@ func startLoadTest(_ *cobra.Command, args []string) error {
 			}
 			regions := maps.Keys(gcpRegions)
 			separateHostRegion = regions[0]
+			if err := checkCloudCost(getLoadTestCloudResources(cloudService, separateHostRegion)); err != nil {
+				return err
+			}
 			loadTestCloudConfig, err = createGCPInstance(gcpClient, nodeType, map[string]NumNodes{separateHostRegion: {1, 0}}, imageID, clusterName, true)
 			if err != nil {
 				return err
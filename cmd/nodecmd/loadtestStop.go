@@ -15,7 +15,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 
-	awsAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/aws"
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
 	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/cloud/gcp"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
@@ -126,7 +126,7 @@ func stopLoadTest(_ *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			if err = destroyNode(existingSeparateInstance, clusterName, loadTestName, loadTestEc2SvcMap[separateHostRegion], nil); err != nil {
+			if err = destroyNode(existingSeparateInstance, clusterName, loadTestName, loadTestEc2SvcMap[separateHostRegion]); err != nil {
 				return err
 			}
 		case constants.GCPCloudService:
@@ -135,7 +135,7 @@ func stopLoadTest(_ *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			if err = destroyNode(existingSeparateInstance, clusterName, loadTestName, nil, gcpClient); err != nil {
+			if err = destroyNode(existingSeparateInstance, clusterName, loadTestName, gcpClient); err != nil {
 				return err
 			}
 		default:
@@ -171,37 +171,25 @@ func updateLoadTestInventory(separateHosts, removedLoadTestHosts []*models.Host,
 	return nil
 }
 
-func destroyNode(node, clusterName, loadTestName string, ec2Svc *awsAPI.AwsCloud, gcpClient *gcpAPI.GcpCloud) error {
+func destroyNode(node, clusterName, loadTestName string, provider cloud.CloudProvider) error {
 	nodeConfig, err := app.LoadClusterNodeConfig(node)
 	if err != nil {
 		ux.Logger.RedXToUser("Failed to destroy node %s", node)
 		return err
 	}
-	if nodeConfig.CloudService == "" || nodeConfig.CloudService == constants.AWSCloudService {
-		if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.AWSCloudService) != nil) {
-			return fmt.Errorf("cloud access is required")
-		}
-		if err = ec2Svc.DestroyAWSNode(nodeConfig, ""); err != nil {
-			if isExpiredCredentialError(err) {
-				ux.Logger.PrintToUser("")
-				printExpiredCredentialsOutput(awsProfile)
-				return nil
-			}
-			if !errors.Is(err, awsAPI.ErrNodeNotFoundToBeRunning) {
-				return err
-			}
-			ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
-		}
-	} else {
-		if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(constants.GCPCloudService) != nil) {
-			return fmt.Errorf("cloud access is required")
+	if !(authorizeAccess || authorizedAccessFromSettings()) && (requestCloudAuth(provider.Name()) != nil) {
+		return fmt.Errorf("cloud access is required")
+	}
+	if err = provider.DestroyNode(nodeConfig, ""); err != nil {
+		if isExpiredCredentialError(err) {
+			ux.Logger.PrintToUser("")
+			printExpiredCredentialsOutput(awsProfile)
+			return nil
 		}
-		if err = gcpClient.DestroyGCPNode(nodeConfig, ""); err != nil {
-			if !errors.Is(err, gcpAPI.ErrNodeNotFoundToBeRunning) {
-				return err
-			}
-			ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
+		if !errors.Is(err, cloud.ErrNodeNotFoundToBeRunning) {
+			return err
 		}
+		ux.Logger.PrintToUser("node %s is already destroyed", nodeConfig.NodeID)
 	}
 	ux.Logger.GreenCheckmarkToUser("Node instance %s successfully destroyed!", nodeConfig.NodeID)
 	if err := removeDeletedNodeDirectory(node); err != nil {
@@ -0,0 +1,291 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	migrateToRegion           string
+	migrateNodeType           string
+	migrateExistingHostsPath  string
+	migrateAvalancheGoVersion string
+	migrateSyncTimeout        time.Duration
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [clusterName] [node]",
+		Short: "(ALPHA Warning) Move a node of a cluster to a new host, keeping its NodeID",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node migrate command moves a node of a cluster, given by cloud ID, IP or NodeID,
+to a new host, keeping its NodeID, so validators can change region or instance type.
+
+The new host is created on the cloud of the node, on the region given by --to-region
+(by default, the node region), or set up from an existing SSH reachable host given by
+--existing-hosts, in the node create format. It is first set up as a non validating
+node with a temporary identity, tracking the Subnets deployed on the cluster, and the
+command waits for it to be bootstrapped and healthy.
+
+Then the original node is stopped, its staking certificate, key and BLS key are moved
+to the new host, which is restarted with them, and the original node instance is
+destroyed. Existing hosts are not destroyed, only removed from the cluster.
+
+The cluster config, the ansible inventory and the monitoring targets are updated
+accordingly. If the new host does not get healthy in time, it is removed, and the
+original node is left untouched.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         migrateNode,
+	}
+	cmd.Flags().StringVar(&migrateToRegion, "to-region", "", "create the new host on the given region. Defaults to the node region")
+	cmd.Flags().StringVar(&migrateNodeType, "node-type", constants.DefaultNodeType, "cloud instance type of the new host. Use 'default' to use recommended default instance type")
+	cmd.Flags().StringVar(&migrateExistingHostsPath, "existing-hosts", "", "move the node to the SSH reachable host listed in the given JSON file, instead of creating a cloud server")
+	cmd.Flags().StringVar(&migrateAvalancheGoVersion, "avalanchego-version", "", "install given avalanchego version on the new host. Defaults to the version the cluster nodes run")
+	cmd.Flags().DurationVar(&migrateSyncTimeout, "sync-timeout", 6*time.Hour, "time to wait for the new host to be bootstrapped and healthy")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create and destroy cloud resources")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&ignoreBudget, ignoreBudgetFlag, false, "create the new cloud server even if its estimated cost exceeds the configured budget")
+	return cmd
+}
+
+func migrateNode(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	node := args[1]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if migrateExistingHostsPath != "" && migrateToRegion != "" {
+		return fmt.Errorf("--to-region and --existing-hosts are mutually exclusive")
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	defer disconnectHosts(hosts)
+	nodeHosts, err := filterHosts(hosts, []string{node})
+	if err != nil {
+		return err
+	}
+	oldHost := nodeHosts[0]
+	oldCloudID := oldHost.GetCloudID()
+	oldNodeInstanceDir := app.GetNodeInstanceDirPath(oldCloudID)
+	nodeID, err := getNodeID(oldNodeInstanceDir)
+	if err != nil {
+		return err
+	}
+	if !utils.FileExists(filepath.Join(oldNodeInstanceDir, constants.BLSKeyFileName)) {
+		return fmt.Errorf("BLS key of node %s not found at %s", oldCloudID, oldNodeInstanceDir)
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	isAPI := clusterConfig.IsAPIHost(oldCloudID)
+	oldNodeConfig, err := app.LoadClusterNodeConfig(oldCloudID)
+	if err != nil {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Setting up the new host of node %s [%s]...", oldCloudID, nodeID)
+	if migrateExistingHostsPath != "" {
+		err = addClusterExistingHost(clusterName, clusterConfig.Network, migrateExistingHostsPath, isAPI)
+	} else {
+		region := migrateToRegion
+		if region == "" {
+			region = oldNodeConfig.Region
+		}
+		if oldNodeConfig.CloudService == constants.ExistingHostsService {
+			return fmt.Errorf("node %s is an existing host: please give the new host with --existing-hosts", oldCloudID)
+		}
+		numNodes := NumNodes{numValidators: 1}
+		if isAPI {
+			numNodes = NumNodes{numAPI: 1}
+		}
+		err = addClusterNodes(
+			clusterName,
+			clusterConfig.Network,
+			oldNodeConfig.CloudService,
+			oldNodeConfig.UseStaticIP,
+			migrateNodeType,
+			migrateAvalancheGoVersion,
+			map[string]NumNodes{region: numNodes},
+		)
+	}
+	if err != nil {
+		return err
+	}
+	newClusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	newCloudID, err := getAddedClusterNode(clusterConfig.Nodes, newClusterConfig.Nodes)
+	if err != nil {
+		return err
+	}
+	newHost, err := getHostWithCloudID(clusterName, newCloudID)
+	if err != nil {
+		return err
+	}
+	if newHost == nil {
+		return fmt.Errorf("node %s not found in the inventory of cluster %s", newCloudID, clusterName)
+	}
+	defer disconnectHosts([]*models.Host{newHost})
+
+	getCloudProvider := newCloudProviderGetter(true)
+	if err := syncMigrationHost(clusterName, newClusterConfig.Subnets, newHost); err != nil {
+		ux.Logger.RedXToUser("new host %s could not be synced: %s", newCloudID, err)
+		ux.Logger.PrintToUser("Removing new host %s. Node %s is left untouched", newCloudID, oldCloudID)
+		if removeErr := removeClusterNode(clusterName, newCloudID, getCloudProvider); removeErr != nil {
+			return fmt.Errorf("failed to remove new host %s: %w", newCloudID, removeErr)
+		}
+		return err
+	}
+
+	ux.Logger.PrintToUser("Moving the identity of node %s [%s] to the new host %s...", oldCloudID, nodeID, newCloudID)
+	if err := swapNodeIdentity(clusterName, oldHost, newHost, nodeID); err != nil {
+		return err
+	}
+	if err := removeClusterNode(clusterName, oldCloudID, getCloudProvider); err != nil {
+		return fmt.Errorf("node %s now runs on %s, but failed to remove the original host: %w", nodeID, newCloudID, err)
+	}
+	if err := updateClusterPrometheusTargets(clusterName); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Node %s migrated from %s to %s", nodeID, oldCloudID, newCloudID)
+	return nil
+}
+
+// addClusterExistingHost runs node create for [clusterName], setting up the only node host
+// of the existing hosts file at [existingHostsPath]
+func addClusterExistingHost(clusterName string, network models.Network, existingHostsPath string, isAPI bool) error {
+	existingHosts, err := loadExistingHosts(existingHostsPath)
+	if err != nil {
+		return err
+	}
+	if err := checkMigrationExistingHosts(existingHosts, isAPI); err != nil {
+		return err
+	}
+	avalancheGoVersion := migrateAvalancheGoVersion
+	if avalancheGoVersion == "" {
+		if avalancheGoVersion, err = getClusterAvalancheGoVersion(clusterName); err != nil {
+			return err
+		}
+	}
+	return runClusterNodeCreate(clusterName, network, map[string]string{
+		"existing-hosts":             existingHostsPath,
+		"custom-avalanchego-version": avalancheGoVersion,
+	})
+}
+
+// checkMigrationExistingHosts checks that [existingHosts] holds a single node host, with
+// the same role as the migrated node
+func checkMigrationExistingHosts(existingHosts []ExistingHost, isAPI bool) error {
+	if len(existingHosts) != 1 || existingHosts[0].Monitoring {
+		return fmt.Errorf("the existing hosts file must list only the new node host")
+	}
+	if existingHosts[0].API != isAPI {
+		return fmt.Errorf("the existing host must have api set to %t, as the migrated node", isAPI)
+	}
+	return nil
+}
+
+// getAddedClusterNode returns the only node of [after] that is not in [before]
+func getAddedClusterNode(before []string, after []string) (string, error) {
+	added := utils.Filter(after, func(cloudID string) bool { return !slices.Contains(before, cloudID) })
+	if len(added) != 1 {
+		return "", fmt.Errorf("expected one new cluster node, found %d", len(added))
+	}
+	return added[0], nil
+}
+
+// syncMigrationHost makes [host] track [subnetNames], and waits for it to be bootstrapped and healthy
+func syncMigrationHost(clusterName string, subnetNames []string, host *models.Host) error {
+	for _, subnetName := range subnetNames {
+		untrackedNodes, err := trackSubnet([]*models.Host{host}, clusterName, subnetName)
+		if err != nil {
+			return err
+		}
+		if len(untrackedNodes) > 0 {
+			return fmt.Errorf("failed to track subnet %s", subnetName)
+		}
+	}
+	spinSession := ux.NewUserSpinner()
+	defer spinSession.Stop()
+	spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Wait for node to be bootstrapped and healthy"))
+	startTime := time.Now()
+	for {
+		err := checkHostIsHealthy(host, ids.Empty)
+		if err == nil {
+			ux.SpinComplete(spinner)
+			return nil
+		}
+		if time.Since(startTime) > migrateSyncTimeout {
+			err = fmt.Errorf("node not ready after %s: %w", migrateSyncTimeout, err)
+			ux.SpinFailWithError(spinner, "", err)
+			return err
+		}
+		time.Sleep(healthCheckPoolTime)
+	}
+}
+
+// swapNodeIdentity stops [oldHost] and restarts [newHost] with its staking files, so it runs
+// as [nodeID]. The old host is stopped first, so that both never run with the same NodeID
+func swapNodeIdentity(clusterName string, oldHost *models.Host, newHost *models.Host, nodeID ids.NodeID) error {
+	oldNodeInstanceDir := app.GetNodeInstanceDirPath(oldHost.GetCloudID())
+	newNodeInstanceDir := app.GetNodeInstanceDirPath(newHost.GetCloudID())
+	if err := ssh.RunSSHStopNode(newHost); err != nil {
+		return err
+	}
+	if err := ssh.RunSSHStopNode(oldHost); err != nil {
+		return fmt.Errorf("failed to stop node %s, the new host %s is left stopped: %w", oldHost.GetCloudID(), newHost.GetCloudID(), err)
+	}
+	if err := ssh.RunSSHUploadStakingFiles(newHost, oldNodeInstanceDir); err != nil {
+		return err
+	}
+	for _, fileName := range []string{constants.StakerCertFileName, constants.StakerKeyFileName, constants.BLSKeyFileName} {
+		fileBytes, err := os.ReadFile(filepath.Join(oldNodeInstanceDir, fileName))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(newNodeInstanceDir, fileName), fileBytes, constants.WriteReadUserOnlyPerms); err != nil {
+			return err
+		}
+	}
+	if err := ssh.RunSSHStartNode(newHost); err != nil {
+		return err
+	}
+	return updateHostPromtailNodeID(clusterName, newHost, nodeID)
+}
+
+// updateHostPromtailNodeID sets [nodeID] as the NodeID [host] logs are labeled with, if the
+// cluster has a monitoring instance
+func updateHostPromtailNodeID(clusterName string, host *models.Host, nodeID ids.NodeID) error {
+	monitoringInventoryDir := app.GetMonitoringInventoryDir(clusterName)
+	if !utils.FileExists(monitoringInventoryDir) {
+		return nil
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryDir)
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) != 1 {
+		return fmt.Errorf("expected only one monitoring host, found %d", len(monitoringHosts))
+	}
+	return ssh.RunSSHUpdatePromtailConfig(host, monitoringHosts[0].IP, constants.AvalanchegoLokiPort, host.GetCloudID(), nodeID.String())
+}
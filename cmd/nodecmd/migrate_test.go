@@ -0,0 +1,29 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAddedClusterNode(t *testing.T) {
+	require := require.New(t)
+	cloudID, err := getAddedClusterNode([]string{"node1", "node2"}, []string{"node1", "node2", "node3"})
+	require.NoError(err)
+	require.Equal("node3", cloudID)
+	_, err = getAddedClusterNode([]string{"node1", "node2"}, []string{"node1", "node2"})
+	require.ErrorContains(err, "found 0")
+	_, err = getAddedClusterNode([]string{"node1"}, []string{"node1", "node2", "node3"})
+	require.ErrorContains(err, "found 2")
+}
+
+func TestCheckMigrationExistingHosts(t *testing.T) {
+	require := require.New(t)
+	require.NoError(checkMigrationExistingHosts([]ExistingHost{{IP: "1.2.3.4"}}, false))
+	require.NoError(checkMigrationExistingHosts([]ExistingHost{{IP: "1.2.3.4", API: true}}, true))
+	require.ErrorContains(checkMigrationExistingHosts([]ExistingHost{{IP: "1.2.3.4"}}, true), "api set to true")
+	require.Error(checkMigrationExistingHosts([]ExistingHost{{IP: "1.2.3.4"}, {IP: "1.2.3.5"}}, false))
+	require.Error(checkMigrationExistingHosts([]ExistingHost{{IP: "1.2.3.4", Monitoring: true}}, false))
+}
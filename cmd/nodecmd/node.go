@@ -54,5 +54,15 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 	cmd.AddCommand(newRefreshIPsCmd())
 	// node loadtest
 	cmd.AddCommand(NewLoadTestCmd())
+	// node doctor
+	cmd.AddCommand(newDoctorCmd())
+	// node scale
+	cmd.AddCommand(newScaleCmd())
+	// node backup
+	cmd.AddCommand(newBackupCmd())
+	// node restore
+	cmd.AddCommand(newRestoreCmd())
+	// node migrate
+	cmd.AddCommand(newMigrateCmd())
 	return cmd
 }
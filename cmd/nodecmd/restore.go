@@ -0,0 +1,227 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	restoreNodeCloudID    string
+	restoreHostCloudID    string
+	restorePassphraseFile string
+)
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [clusterName] [backupFile]",
+		Short: "(ALPHA Warning) Restore a backed up node on a host of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node restore command restores a node saved by node backup on a host of the
+cluster, so that the host runs with the same NodeID, BLS key and configs as the
+backed up node. If the backup includes the node database, it replaces the host one.
+
+By default the node is restored on the host it was backed up from. To replace a
+lost node, restore it on a new cluster host with --host, after removing the lost
+one from the cluster. Restoring a node on a different host while the original one
+is still part of the cluster is refused, as both would run with the same NodeID.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         restoreNode,
+	}
+	cmd.Flags().StringVar(&restoreNodeCloudID, "node", "", "restore the backed up node with the given cloud ID. Required if the backup has more than one node")
+	cmd.Flags().StringVar(&restoreHostCloudID, "host", "", "restore the node on the cluster host with the given cloud ID. Defaults to the host the node was backed up from")
+	cmd.Flags().StringVar(&restorePassphraseFile, "passphrase-file", "", "read the backup passphrase from the given file")
+	return cmd
+}
+
+func restoreNode(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	backupPath := args[1]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if !utils.FileExists(backupPath) {
+		return fmt.Errorf("backup file %s does not exist", backupPath)
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	passphrase, err := getBackupPassphrase(restorePassphraseFile, false)
+	if err != nil {
+		return err
+	}
+	manifest, err := backup.ReadManifest(backupPath, passphrase)
+	if err != nil {
+		return err
+	}
+	nodeBackup, err := getNodeBackupToRestore(manifest, restoreNodeCloudID)
+	if err != nil {
+		return err
+	}
+	targetCloudID := restoreHostCloudID
+	if targetCloudID == "" {
+		targetCloudID = nodeBackup.CloudID
+	}
+	if err := checkNodeRestoreTarget(clusterConfig, nodeBackup, targetCloudID); err != nil {
+		return err
+	}
+	inventory, err := loadInventoryByCloudID(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	host, ok := inventory[targetCloudID]
+	if !ok {
+		return fmt.Errorf("node %s not found in the inventory of cluster %s", targetCloudID, clusterName)
+	}
+	defer disconnectHosts([]*models.Host{host})
+
+	tmpDir, err := os.MkdirTemp("", "avalanche-cli-restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	nodeArchivePath := filepath.Join(tmpDir, nodeBackup.CloudID+".tar.gz")
+	if err := backup.ExtractNodeArchive(backupPath, passphrase, nodeBackup.CloudID, nodeArchivePath); err != nil {
+		return err
+	}
+	stakingDir := filepath.Join(tmpDir, "staking")
+	if err := backup.ExtractNodeArchiveFiles(nodeArchivePath, backupStakingFiles, stakingDir); err != nil {
+		return err
+	}
+	nodeID, err := getNodeID(stakingDir)
+	if err != nil {
+		return err
+	}
+	if nodeID.String() != nodeBackup.NodeID {
+		return fmt.Errorf("backup of node %s has NodeID %s, expected %s", nodeBackup.CloudID, nodeID, nodeBackup.NodeID)
+	}
+	configDir := filepath.Join(tmpDir, "configs")
+	err = backup.ExtractNodeArchiveFiles(nodeArchivePath, []string{"configs/" + constants.NodeFileName}, configDir)
+	hasNodeConfig := err == nil
+	if err != nil && !errors.Is(err, backup.ErrMissingNodeArchiveFiles) {
+		return err
+	}
+
+	ux.Logger.PrintToUser("Restoring node %s [%s] on host %s...", nodeBackup.CloudID, nodeBackup.NodeID, targetCloudID)
+	spinSession := ux.NewUserSpinner()
+	defer spinSession.Stop()
+	spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, "Restore Node"))
+	if err := restoreNodeOnHost(host, nodeArchivePath, hasNodeConfig, configDir); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		return err
+	}
+	ux.SpinComplete(spinner)
+	spinSession.Stop()
+
+	// keep the local files of the host in sync with the restored ones
+	nodeInstanceDir := app.GetNodeInstanceDirPath(targetCloudID)
+	if err := backup.ExtractNodeArchiveFiles(nodeArchivePath, backupStakingFiles, nodeInstanceDir); err != nil {
+		return err
+	}
+	if hasNodeConfig {
+		confBytes, err := os.ReadFile(filepath.Join(configDir, constants.NodeFileName))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(nodeInstanceDir, constants.NodeFileName), confBytes, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Node %s restored on host %s with NodeID %s", nodeBackup.CloudID, targetCloudID, nodeBackup.NodeID)
+	return nil
+}
+
+// getNodeBackupToRestore returns the backup of node [cloudID] in [manifest], or the
+// only node backup if [cloudID] is not given
+func getNodeBackupToRestore(manifest backup.Manifest, cloudID string) (backup.NodeBackup, error) {
+	if cloudID == "" {
+		if len(manifest.Nodes) != 1 {
+			return backup.NodeBackup{}, fmt.Errorf("backup has %d nodes: select the one to restore with --node", len(manifest.Nodes))
+		}
+		return manifest.Nodes[0], nil
+	}
+	nodeBackup, ok := manifest.GetNode(cloudID)
+	if !ok {
+		return backup.NodeBackup{}, fmt.Errorf("backup has no node %s", cloudID)
+	}
+	return nodeBackup, nil
+}
+
+// checkNodeRestoreTarget checks that [nodeBackup] can be restored on cluster host [targetCloudID]
+// without two cluster nodes ending up with the same NodeID
+func checkNodeRestoreTarget(clusterConfig models.ClusterConfig, nodeBackup backup.NodeBackup, targetCloudID string) error {
+	if !slices.Contains(clusterConfig.Nodes, targetCloudID) {
+		return fmt.Errorf("node %s is not part of the cluster", targetCloudID)
+	}
+	if targetCloudID != nodeBackup.CloudID && slices.Contains(clusterConfig.Nodes, nodeBackup.CloudID) {
+		return fmt.Errorf(
+			"node %s is still part of the cluster: remove it before restoring it on node %s, as both would have NodeID %s",
+			nodeBackup.CloudID,
+			targetCloudID,
+			nodeBackup.NodeID,
+		)
+	}
+	if clusterConfig.IsAPIHost(targetCloudID) != nodeBackup.IsAPI {
+		return fmt.Errorf("node %s and the backed up node %s don't have the same role", targetCloudID, nodeBackup.CloudID)
+	}
+	return nil
+}
+
+// restoreNodeOnHost stops avalanchego on [host], restores the node archive at [nodeArchivePath],
+// sets the host public IP on the restored node config if any, and starts avalanchego
+func restoreNodeOnHost(host *models.Host, nodeArchivePath string, hasNodeConfig bool, configDir string) error {
+	if err := ssh.RunSSHStopNode(host); err != nil {
+		return err
+	}
+	if err := ssh.RunSSHRestoreNode(host, nodeArchivePath); err != nil {
+		return err
+	}
+	if hasNodeConfig {
+		if err := setNodeConfigPublicIP(filepath.Join(configDir, constants.NodeFileName), host.IP); err != nil {
+			return err
+		}
+		if err := ssh.RunSSHUploadNodeConfig(host, configDir); err != nil {
+			return err
+		}
+	}
+	return ssh.RunSSHStartNode(host)
+}
+
+// setNodeConfigPublicIP updates the public IP of the avalanchego config at [nodeConfigPath],
+// if it sets one
+func setNodeConfigPublicIP(nodeConfigPath string, publicIP string) error {
+	confBytes, err := os.ReadFile(nodeConfigPath)
+	if err != nil {
+		return err
+	}
+	confMap := map[string]interface{}{}
+	if err := json.Unmarshal(confBytes, &confMap); err != nil {
+		return fmt.Errorf("invalid node config: %w", err)
+	}
+	if _, ok := confMap[config.PublicIPKey]; !ok {
+		return nil
+	}
+	confMap[config.PublicIPKey] = publicIP
+	confBytes, err = json.MarshalIndent(confMap, "", " ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(nodeConfigPath, confBytes, constants.WriteReadReadPerms)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNodeBackupToRestore(t *testing.T) {
+	require := require.New(t)
+	node1 := backup.NodeBackup{CloudID: "node1", NodeID: "NodeID-1"}
+	node2 := backup.NodeBackup{CloudID: "node2", NodeID: "NodeID-2", IsAPI: true}
+	nodeBackup, err := getNodeBackupToRestore(backup.Manifest{Nodes: []backup.NodeBackup{node1}}, "")
+	require.NoError(err)
+	require.Equal(node1, nodeBackup)
+	manifest := backup.Manifest{Nodes: []backup.NodeBackup{node1, node2}}
+	_, err = getNodeBackupToRestore(manifest, "")
+	require.ErrorContains(err, "--node")
+	nodeBackup, err = getNodeBackupToRestore(manifest, "node2")
+	require.NoError(err)
+	require.Equal(node2, nodeBackup)
+	_, err = getNodeBackupToRestore(manifest, "node3")
+	require.ErrorContains(err, "backup has no node node3")
+}
+
+func TestCheckNodeRestoreTarget(t *testing.T) {
+	require := require.New(t)
+	clusterConfig := models.ClusterConfig{
+		Nodes:    []string{"node1", "node2", "node3"},
+		APINodes: []string{"node3"},
+	}
+	node1 := backup.NodeBackup{CloudID: "node1", NodeID: "NodeID-1"}
+	require.NoError(checkNodeRestoreTarget(clusterConfig, node1, "node1"))
+	// the backed up node would share its NodeID with the target
+	require.ErrorContains(checkNodeRestoreTarget(clusterConfig, node1, "node2"), "still part of the cluster")
+	lost := backup.NodeBackup{CloudID: "lost", NodeID: "NodeID-lost"}
+	require.NoError(checkNodeRestoreTarget(clusterConfig, lost, "node2"))
+	require.ErrorContains(checkNodeRestoreTarget(clusterConfig, lost, "node3"), "same role")
+	require.ErrorContains(checkNodeRestoreTarget(clusterConfig, lost, "node4"), "not part of the cluster")
+}
+
+func TestSetNodeConfigPublicIP(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	nodeConfigPath := filepath.Join(dir, "node.json")
+	require.NoError(os.WriteFile(nodeConfigPath, []byte(`{"public-ip": "1.1.1.1", "network-id": "fuji"}`), 0o600))
+	require.NoError(setNodeConfigPublicIP(nodeConfigPath, "2.2.2.2"))
+	confBytes, err := os.ReadFile(nodeConfigPath)
+	require.NoError(err)
+	require.JSONEq(`{"public-ip": "2.2.2.2", "network-id": "fuji"}`, string(confBytes))
+
+	// configs without public IP, relying on dynamic resolution, are kept as they are
+	require.NoError(os.WriteFile(nodeConfigPath, []byte(`{"public-ip-resolution-service": "opendns"}`), 0o600))
+	require.NoError(setNodeConfigPublicIP(nodeConfigPath, "2.2.2.2"))
+	confBytes, err = os.ReadFile(nodeConfigPath)
+	require.NoError(err)
+	require.JSONEq(`{"public-ip-resolution-service": "opendns"}`, string(confBytes))
+}
@@ -0,0 +1,564 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	scaleValidators         []string
+	scaleAPIs               []string
+	scaleNodeType           string
+	scaleAvalancheGoVersion string
+	scaleForce              bool
+)
+
+func newScaleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale [clusterName]",
+		Short: "(ALPHA Warning) Add or remove nodes of a cluster per region",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node scale command sets the number of validator nodes and API nodes that
+a cluster has on each given region, as in
+
+avalanche node scale <clusterName> --region us-east-1=5 --apis eu-west-1=2
+
+Regions not given are left untouched. Missing nodes are created as in node create,
+using the avalanchego version the cluster nodes run, and join the cluster network.
+
+Before removing a node, the command drains it: it removes the node from the
+validator set of the Subnets it validates, as long as the given key holds enough
+Subnet control keys, stops avalanchego, and destroys the cloud instance. The most
+recently added nodes of a region are removed first. Nodes that can't be drained
+(Primary Network validators, or validators of Subnets controlled by other keys)
+are only removed with --force.
+
+The cluster config, the ansible inventory and the monitoring Prometheus targets
+are updated accordingly.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         scaleCluster,
+	}
+	cmd.Flags().StringSliceVar(&scaleValidators, "region", []string{}, "set the number of validator nodes of a region, as region=N. Use comma to separate multiple regions")
+	cmd.Flags().StringSliceVar(&scaleAPIs, "apis", []string{}, "set the number of API nodes of a region, as region=N. Use comma to separate multiple regions [devnet only]")
+	cmd.Flags().StringVar(&scaleNodeType, "node-type", constants.DefaultNodeType, "cloud instance type of the created nodes. Use 'default' to use recommended default instance type")
+	cmd.Flags().StringVar(&scaleAvalancheGoVersion, "avalanchego-version", "", "install given avalanchego version on the created nodes. Defaults to the version the cluster nodes run")
+	cmd.Flags().BoolVar(&scaleForce, "force", false, "remove nodes even if they can't be removed from the validator sets")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use to remove validators [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet only]")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create and destroy cloud resources")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&ignoreBudget, ignoreBudgetFlag, false, "create the cloud servers even if their estimated cost exceeds the configured budget")
+	return cmd
+}
+
+// clusterRegionNodes are the cloud IDs of the nodes of a cluster in a region,
+// in the order they were added to the cluster
+type clusterRegionNodes struct {
+	validators []string
+	apis       []string
+}
+
+// clusterScalePlan holds the number of nodes to create per region, and the cloud IDs
+// of the nodes to remove
+type clusterScalePlan struct {
+	add    map[string]NumNodes
+	remove []string
+}
+
+// subnetValidatorRemoval holds what is needed to remove validators of a Subnet
+type subnetValidatorRemoval struct {
+	subnetID                    ids.ID
+	transferSubnetOwnershipTxID ids.ID
+	controlKeys                 []string
+	subnetAuthKeys              []string
+}
+
+func scaleCluster(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	validatorTargets, err := parseRegionNodeCounts(scaleValidators)
+	if err != nil {
+		return err
+	}
+	apiTargets, err := parseRegionNodeCounts(scaleAPIs)
+	if err != nil {
+		return err
+	}
+	if len(validatorTargets) == 0 && len(apiTargets) == 0 {
+		return fmt.Errorf("number of nodes of at least one region must be given with --region or --apis")
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	network := clusterConfig.Network
+	if len(apiTargets) > 0 && network.Kind != models.Devnet {
+		return fmt.Errorf("API nodes can only be created in Devnet")
+	}
+	current := map[string]clusterRegionNodes{}
+	cloudService := ""
+	useStaticIP := false
+	for _, cloudID := range clusterConfig.Nodes {
+		nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+		if err != nil {
+			return err
+		}
+		cloudService = nodeConfig.CloudService
+		useStaticIP = nodeConfig.UseStaticIP
+		regionNodes := current[nodeConfig.Region]
+		if clusterConfig.IsAPIHost(cloudID) {
+			regionNodes.apis = append(regionNodes.apis, cloudID)
+		} else {
+			regionNodes.validators = append(regionNodes.validators, cloudID)
+		}
+		current[nodeConfig.Region] = regionNodes
+	}
+	plan := planClusterScale(current, validatorTargets, apiTargets)
+	if len(plan.add) == 0 && len(plan.remove) == 0 {
+		ux.Logger.PrintToUser("Cluster %s already has the requested number of nodes", clusterName)
+		return nil
+	}
+	if len(plan.add) == 0 && len(plan.remove) == len(clusterConfig.Nodes) {
+		return fmt.Errorf("scaling would remove all nodes of cluster %s. Please use node destroy instead", clusterName)
+	}
+	if len(plan.add) > 0 {
+		if err := addClusterNodes(clusterName, network, cloudService, useStaticIP, scaleNodeType, scaleAvalancheGoVersion, plan.add); err != nil {
+			return err
+		}
+	}
+	if len(plan.remove) > 0 {
+		if err := removeClusterNodes(clusterName, network, plan.remove); err != nil {
+			return err
+		}
+		if err := updateClusterPrometheusTargets(clusterName); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("Cluster %s successfully scaled", clusterName)
+	return nil
+}
+
+// parseRegionNodeCounts parses a list of region=N values into a map of number of nodes per region
+func parseRegionNodeCounts(values []string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, value := range values {
+		region, countStr, found := strings.Cut(value, "=")
+		region = strings.TrimSpace(region)
+		if !found || region == "" {
+			return nil, fmt.Errorf("invalid value %q: expected region=N", value)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid number of nodes for region %s: %q", region, countStr)
+		}
+		if _, ok := counts[region]; ok {
+			return nil, fmt.Errorf("region %s is given more than once", region)
+		}
+		counts[region] = count
+	}
+	return counts, nil
+}
+
+// planClusterScale compares the [current] nodes of a cluster per region with the target number
+// of validator and API nodes per region, returning the nodes to create and the nodes to remove.
+// The most recently added nodes of a region are removed first. Regions without target are kept
+func planClusterScale(current map[string]clusterRegionNodes, validatorTargets map[string]int, apiTargets map[string]int) clusterScalePlan {
+	plan := clusterScalePlan{add: map[string]NumNodes{}}
+	regions := utils.Unique(append(maps.Keys(validatorTargets), maps.Keys(apiTargets)...))
+	sort.Strings(regions)
+	for _, region := range regions {
+		toAdd := NumNodes{}
+		if target, ok := validatorTargets[region]; ok {
+			validators := current[region].validators
+			if target > len(validators) {
+				toAdd.numValidators = target - len(validators)
+			} else {
+				plan.remove = append(plan.remove, validators[target:]...)
+			}
+		}
+		if target, ok := apiTargets[region]; ok {
+			apis := current[region].apis
+			if target > len(apis) {
+				toAdd.numAPI = target - len(apis)
+			} else {
+				plan.remove = append(plan.remove, apis[target:]...)
+			}
+		}
+		if toAdd.All() > 0 {
+			plan.add[region] = toAdd
+		}
+	}
+	return plan
+}
+
+// addClusterNodes runs node create for [clusterName], creating the given number of nodes per region.
+// If [avalancheGoVersion] is not given, the nodes get the version the cluster nodes run
+func addClusterNodes(
+	clusterName string,
+	network models.Network,
+	cloudService string,
+	useStaticIP bool,
+	nodeType string,
+	avalancheGoVersion string,
+	add map[string]NumNodes,
+) error {
+	if cloudService != constants.AWSCloudService && cloudService != constants.GCPCloudService {
+		return fmt.Errorf("nodes of cluster %s can't be created on the cloud. Please use node create", clusterName)
+	}
+	if avalancheGoVersion == "" {
+		var err error
+		if avalancheGoVersion, err = getClusterAvalancheGoVersion(clusterName); err != nil {
+			return err
+		}
+	}
+	regions := maps.Keys(add)
+	sort.Strings(regions)
+	flagValues := map[string]string{
+		"region":                     strings.Join(regions, ","),
+		"num-validators":             strings.Join(utils.Map(regions, func(r string) string { return strconv.Itoa(add[r].numValidators) }), ","),
+		"node-type":                  nodeType,
+		"custom-avalanchego-version": avalancheGoVersion,
+		"use-static-ip":              strconv.FormatBool(useStaticIP),
+		"authorize-access":           strconv.FormatBool(authorizeAccess),
+		"aws-profile":                awsProfile,
+	}
+	if cloudService == constants.AWSCloudService {
+		flagValues["aws"] = "true"
+	} else {
+		flagValues["gcp"] = "true"
+	}
+	if network.Kind == models.Devnet {
+		flagValues["num-apis"] = strings.Join(utils.Map(regions, func(r string) string { return strconv.Itoa(add[r].numAPI) }), ",")
+	}
+	return runClusterNodeCreate(clusterName, network, flagValues)
+}
+
+// runClusterNodeCreate runs node create for [clusterName] with the given flag values, so the
+// created nodes join the cluster [network]
+func runClusterNodeCreate(clusterName string, network models.Network, flagValues map[string]string) error {
+	// an existing monitoring instance is used without asking
+	flagValues[enableMonitoringFlag] = "false"
+	flagValues[ignoreBudgetFlag] = strconv.FormatBool(ignoreBudget)
+	switch network.Kind {
+	case models.Devnet:
+		flagValues["devnet"] = "true"
+		flagValues["endpoint"] = network.Endpoint
+	case models.Fuji:
+		flagValues["fuji"] = "true"
+	default:
+		return fmt.Errorf("nodes can't be created on %s", network.Name())
+	}
+	// creating the command resets the create flags, so it goes after reading the shared ones
+	createCmd := newCreateCmd()
+	for name, value := range flagValues {
+		if err := createCmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	return createNodes(createCmd, []string{clusterName})
+}
+
+// getClusterAvalancheGoVersion returns the avalanchego version run by the nodes of [clusterName]
+func getClusterAvalancheGoVersion(clusterName string) (string, error) {
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return "", err
+	}
+	for _, host := range hosts {
+		resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+		if err != nil {
+			continue
+		}
+		if avalancheGoVersion, _, err := parseAvalancheGoOutput(resp); err == nil {
+			return avalancheGoVersion, nil
+		}
+	}
+	return "", fmt.Errorf("unable to get the avalanchego version of cluster %s. Please set it with --avalanchego-version", clusterName)
+}
+
+// removeClusterNodes drains and removes the nodes [cloudIDs] from [clusterName]. All of them
+// are checked to be drainable before removing any of them
+func removeClusterNodes(clusterName string, network models.Network, cloudIDs []string) error {
+	nodeIDs := map[string]ids.NodeID{}
+	for _, cloudID := range cloudIDs {
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+		if err != nil {
+			return err
+		}
+		nodeIDs[cloudID] = nodeID
+	}
+	validatedSubnets, blockReasons, err := getValidatedSubnets(network, cloudIDs, nodeIDs)
+	if err != nil {
+		return err
+	}
+	var deployer *subnet.PublicDeployer
+	removals := map[string]subnetValidatorRemoval{}
+	if len(validatedSubnets) > 0 {
+		numRemovals := 0
+		for _, subnetNames := range validatedSubnets {
+			numRemovals += len(subnetNames)
+		}
+		fee := network.GenesisParams().TxFee * uint64(numRemovals)
+		kc, err := keychain.GetKeychainFromCmdLineFlags(
+			app,
+			constants.PayTxsFeesMsg,
+			network,
+			keyName,
+			useEwoq,
+			useLedger,
+			ledgerAddresses,
+			fee,
+		)
+		if err != nil {
+			return err
+		}
+		removals, err = getSubnetValidatorRemovals(kc, network, validatedSubnets)
+		if err != nil {
+			return err
+		}
+		deployer = subnet.NewPublicDeployer(app, kc, network)
+	}
+	for _, cloudID := range cloudIDs {
+		for _, subnetName := range validatedSubnets[cloudID] {
+			if _, ok := removals[subnetName]; !ok {
+				blockReasons[cloudID] = append(blockReasons[cloudID], fmt.Sprintf("it validates Subnet %s, and the given key does not hold enough of its control keys", subnetName))
+			}
+		}
+	}
+	blockedNodes := utils.Filter(cloudIDs, func(cloudID string) bool { return len(blockReasons[cloudID]) > 0 })
+	for _, cloudID := range blockedNodes {
+		ux.Logger.RedXToUser("node %s can't be drained: %s", cloudID, strings.Join(blockReasons[cloudID], "; "))
+	}
+	if len(blockedNodes) > 0 && !scaleForce {
+		return fmt.Errorf("node(s) %s can't be drained. Please remove them from the validator sets, or use --force to remove them anyway", blockedNodes)
+	}
+	getCloudProvider := newCloudProviderGetter(true)
+	for _, cloudID := range cloudIDs {
+		if err := drainClusterNode(clusterName, cloudID, nodeIDs[cloudID], validatedSubnets[cloudID], removals, deployer, getCloudProvider); err != nil {
+			return fmt.Errorf("failed to remove node %s: %w", cloudID, err)
+		}
+		ux.Logger.GreenCheckmarkToUser("Node %s removed from cluster %s", cloudID, clusterName)
+	}
+	return nil
+}
+
+// getValidatedSubnets returns the names of the deployed Subnets each node validates. It also
+// returns why nodes can't be drained whatever the keys, as for Primary Network validators
+func getValidatedSubnets(network models.Network, cloudIDs []string, nodeIDs map[string]ids.NodeID) (map[string][]string, map[string][]string, error) {
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(subnetNames)
+	subnetIDs := map[string]ids.ID{}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if subnetID := sc.Networks[network.Name()].SubnetID; subnetID != ids.Empty {
+			subnetIDs[subnetName] = subnetID
+		}
+	}
+	validatedSubnets := map[string][]string{}
+	blockReasons := map[string][]string{}
+	for _, cloudID := range cloudIDs {
+		isValidator, err := checkNodeIsPrimaryNetworkValidator(nodeIDs[cloudID], network)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isValidator {
+			blockReasons[cloudID] = append(blockReasons[cloudID], "it is a Primary Network validator until the end of its staking period")
+		}
+		for _, subnetName := range subnetNames {
+			subnetID, ok := subnetIDs[subnetName]
+			if !ok {
+				continue
+			}
+			isValidator, err := subnet.IsSubnetValidator(subnetID, nodeIDs[cloudID], network)
+			if err != nil {
+				return nil, nil, err
+			}
+			if isValidator {
+				validatedSubnets[cloudID] = append(validatedSubnets[cloudID], subnetName)
+			}
+		}
+	}
+	return validatedSubnets, blockReasons, nil
+}
+
+// getSubnetValidatorRemovals returns the validator removal data of the Subnets in [validatedSubnets]
+// whose control keys are held by [kc] up to the Subnet threshold
+func getSubnetValidatorRemovals(kc *keychain.Keychain, network models.Network, validatedSubnets map[string][]string) (map[string]subnetValidatorRemoval, error) {
+	removals := map[string]subnetValidatorRemoval{}
+	for _, subnetNames := range validatedSubnets {
+		for _, subnetName := range subnetNames {
+			if _, ok := removals[subnetName]; ok {
+				continue
+			}
+			sc, err := app.LoadSidecar(subnetName)
+			if err != nil {
+				return nil, err
+			}
+			subnetID := sc.Networks[network.Name()].SubnetID
+			transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
+			controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+			if err != nil {
+				return nil, err
+			}
+			// add control keys to the keychain whenever possible
+			if err := kc.AddAddresses(controlKeys); err != nil {
+				return nil, err
+			}
+			kcKeys, err := kc.PChainFormattedStrAddresses()
+			if err != nil {
+				return nil, err
+			}
+			subnetAuthKeys, ok := getOwnedSubnetAuthKeys(kcKeys, controlKeys, threshold)
+			if !ok {
+				continue
+			}
+			removals[subnetName] = subnetValidatorRemoval{
+				subnetID:                    subnetID,
+				transferSubnetOwnershipTxID: transferSubnetOwnershipTxID,
+				controlKeys:                 controlKeys,
+				subnetAuthKeys:              subnetAuthKeys,
+			}
+		}
+	}
+	return removals, nil
+}
+
+// getOwnedSubnetAuthKeys returns [threshold] of the Subnet [controlKeys] that are in [kcKeys],
+// or false if there are not enough of them
+func getOwnedSubnetAuthKeys(kcKeys []string, controlKeys []string, threshold uint32) ([]string, bool) {
+	subnetAuthKeys := utils.Filter(controlKeys, func(controlKey string) bool { return slices.Contains(kcKeys, controlKey) })
+	if len(subnetAuthKeys) < int(threshold) {
+		return nil, false
+	}
+	return subnetAuthKeys[:threshold], true
+}
+
+// drainClusterNode removes [cloudID] from the validator set of the Subnets it validates when
+// possible, and then removes it from the cluster
+func drainClusterNode(
+	clusterName string,
+	cloudID string,
+	nodeID ids.NodeID,
+	subnetNames []string,
+	removals map[string]subnetValidatorRemoval,
+	deployer *subnet.PublicDeployer,
+	getCloudProvider cloudProviderGetter,
+) error {
+	for _, subnetName := range subnetNames {
+		removal, ok := removals[subnetName]
+		if !ok {
+			continue
+		}
+		ux.Logger.PrintToUser("Removing node %s from the validator set of Subnet %s...", cloudID, subnetName)
+		isFullySigned, _, _, err := deployer.RemoveValidator(
+			removal.controlKeys,
+			removal.subnetAuthKeys,
+			removal.subnetID,
+			removal.transferSubnetOwnershipTxID,
+			nodeID,
+		)
+		if err != nil {
+			return err
+		}
+		if !isFullySigned {
+			return fmt.Errorf("removal of node %s from Subnet %s is not fully signed", cloudID, subnetName)
+		}
+	}
+	return removeClusterNode(clusterName, cloudID, getCloudProvider)
+}
+
+// removeClusterNode stops avalanchego on [cloudID], destroys its instance unless it is an
+// existing host, and removes it from the cluster
+func removeClusterNode(clusterName string, cloudID string, getCloudProvider cloudProviderGetter) error {
+	nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+	if err != nil {
+		return err
+	}
+	inventoryDir := app.GetAnsibleInventoryDirPath(clusterName)
+	inventory, err := loadInventoryByCloudID(inventoryDir)
+	if err != nil {
+		return err
+	}
+	if host, ok := inventory[cloudID]; ok {
+		if err := ssh.RunSSHStopNode(host); err != nil {
+			ux.Logger.RedXToUser("unable to stop avalanchego on node %s: %s", cloudID, err)
+		}
+	}
+	if nodeConfig.CloudService == constants.ExistingHostsService {
+		ux.Logger.PrintToUser("Node %s is an existing host: removing it from cluster %s without destroying it", cloudID, clusterName)
+	} else {
+		provider, err := getCloudProvider(nodeConfig)
+		if err != nil {
+			return err
+		}
+		if err := destroyCloudNode(provider, nodeConfig, clusterName); err != nil {
+			return err
+		}
+	}
+	if err := removeClusterInstance(clusterName, clusterInstance{CloudID: cloudID, InventoryDir: inventoryDir}); err != nil {
+		return err
+	}
+	return removeDeletedNodeDirectory(cloudID)
+}
+
+// updateClusterPrometheusTargets sets the cluster nodes as the Prometheus targets of the
+// monitoring instance of [clusterName], if any
+func updateClusterPrometheusTargets(clusterName string) error {
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if clusterConfig.MonitoringInstance == "" {
+		return nil
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetMonitoringInventoryDir(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) != 1 {
+		return fmt.Errorf("expected only one monitoring host, found %d", len(monitoringHosts))
+	}
+	avalancheGoPorts, machinePorts, ltPorts, err := getPrometheusTargets(clusterName)
+	if err != nil {
+		return err
+	}
+	spinSession := ux.NewUserSpinner()
+	defer spinSession.Stop()
+	spinner := spinSession.SpinToUser(utils.ScriptLog(monitoringHosts[0].NodeID, "Update Monitoring Targets"))
+	if err := ssh.RunSSHUpdatePrometheusConfig(monitoringHosts[0], avalancheGoPorts, machinePorts, ltPorts); err != nil {
+		ux.SpinFailWithError(spinner, "", err)
+		return err
+	}
+	ux.SpinComplete(spinner)
+	return nil
+}
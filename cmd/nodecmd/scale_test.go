@@ -0,0 +1,68 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegionNodeCounts(t *testing.T) {
+	require := require.New(t)
+	counts, err := parseRegionNodeCounts([]string{"us-east-1=5", " eu-west-1 = 0"})
+	require.NoError(err)
+	require.Equal(map[string]int{"us-east-1": 5, "eu-west-1": 0}, counts)
+	counts, err = parseRegionNodeCounts(nil)
+	require.NoError(err)
+	require.Empty(counts)
+	for _, values := range [][]string{
+		{"us-east-1"},
+		{"=2"},
+		{"us-east-1=two"},
+		{"us-east-1=-1"},
+		{"us-east-1=1", "us-east-1=2"},
+	} {
+		_, err := parseRegionNodeCounts(values)
+		require.Error(err, values)
+	}
+}
+
+func TestPlanClusterScale(t *testing.T) {
+	require := require.New(t)
+	current := map[string]clusterRegionNodes{
+		"us-east-1": {validators: []string{"v1", "v2", "v3"}, apis: []string{"a1"}},
+		"eu-west-1": {validators: []string{"v4"}},
+	}
+
+	// the latest nodes of a region are removed first, and regions without target are kept
+	plan := planClusterScale(current, map[string]int{"us-east-1": 1}, nil)
+	require.Empty(plan.add)
+	require.Equal([]string{"v2", "v3"}, plan.remove)
+
+	plan = planClusterScale(current, map[string]int{"us-east-1": 5, "eu-west-1": 0}, map[string]int{"eu-west-1": 2, "us-east-1": 0})
+	require.Equal(map[string]NumNodes{"us-east-1": {2, 0}, "eu-west-1": {0, 2}}, plan.add)
+	require.Equal([]string{"v4", "a1"}, plan.remove)
+
+	// new regions only get nodes
+	plan = planClusterScale(current, map[string]int{"ap-south-1": 2}, map[string]int{"ap-south-1": 1})
+	require.Equal(map[string]NumNodes{"ap-south-1": {2, 1}}, plan.add)
+	require.Empty(plan.remove)
+
+	plan = planClusterScale(current, map[string]int{"us-east-1": 3, "ap-south-1": 0}, map[string]int{"us-east-1": 1})
+	require.Empty(plan.add)
+	require.Empty(plan.remove)
+}
+
+func TestGetOwnedSubnetAuthKeys(t *testing.T) {
+	require := require.New(t)
+	controlKeys := []string{"P-key1", "P-key2", "P-key3"}
+	authKeys, ok := getOwnedSubnetAuthKeys([]string{"P-key3", "P-other", "P-key1"}, controlKeys, 2)
+	require.True(ok)
+	require.Equal([]string{"P-key1", "P-key3"}, authKeys)
+	authKeys, ok = getOwnedSubnetAuthKeys([]string{"P-key3", "P-key1", "P-key2"}, controlKeys, 1)
+	require.True(ok)
+	require.Equal([]string{"P-key1"}, authKeys)
+	_, ok = getOwnedSubnetAuthKeys([]string{"P-key2", "P-other"}, controlKeys, 2)
+	require.False(ok)
+}
@@ -3,23 +3,35 @@
 package nodecmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/spf13/cobra"
 )
 
-var isParallel bool
+var (
+	isParallel     bool
+	resetHostKey   bool
+	sshConcurrency int
+	sshTimeout     time.Duration
+	sshRetries     int
+	sshJSONOutput  bool
+)
 
 func newSSHCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -31,12 +43,25 @@ The node ssh command execute a given command [cmd] using ssh on all nodes in the
 If no command is given, just prints the ssh command to be used to connect to each node in the cluster.
 For provided NodeID or InstanceID or IP, the command [cmd] will be executed on that node.
 If no [cmd] is provided for the node, it will open ssh shell there.
+
+With --parallel, the command is executed on up to --concurrency nodes at the same
+time, and the output of each node is printed once all of them are done. Use --json
+to print instead the stdout, stderr, exit code and duration of the command on each node.
+
+The host key of each node is pinned on the first connection, and verified on every
+following one. If a node was legitimately re-provisioned, use --reset-host-key to pin
+its new host key.
 `,
 		SilenceUsage: true,
 		Args:         cobra.MinimumNArgs(0),
 		RunE:         sshNode,
 	}
 	cmd.Flags().BoolVar(&isParallel, "parallel", false, "run ssh command on all nodes in parallel")
+	cmd.Flags().BoolVar(&resetHostKey, "reset-host-key", false, "forget the pinned host key of the node(s) and pin the current one")
+	cmd.Flags().IntVar(&sshConcurrency, "concurrency", constants.SSHDefaultConcurrency, "maximum number of nodes to run ssh command on at the same time when in parallel")
+	cmd.Flags().DurationVar(&sshTimeout, "timeout", 0, "stop ssh command on a node after the given duration. No timeout by default")
+	cmd.Flags().IntVar(&sshRetries, "retries", 0, "number of times to run ssh command again on a node when it fails")
+	cmd.Flags().BoolVar(&sshJSONOutput, "json", false, "print the result of ssh command on each node as JSON")
 	return cmd
 }
 
@@ -67,23 +92,29 @@ func sshNode(_ *cobra.Command, args []string) error {
 		cmd := strings.Join(args[1:], " ")
 		if err := checkCluster(clusterNameOrNodeID); err == nil {
 			// clusterName detected
-			if len(args[1:]) == 0 {
+			if len(args[1:]) == 0 && !resetHostKey {
 				return printClusterConnectionString(clusterNameOrNodeID, clustersConfig.Clusters[clusterNameOrNodeID].Network.Kind.String())
-			} else {
-				clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID))
+			}
+			clusterHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID))
+			if err != nil {
+				return err
+			}
+			monitoringInventoryPath := filepath.Join(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID), constants.MonitoringDir)
+			if utils.DirectoryExists(monitoringInventoryPath) {
+				monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
 				if err != nil {
 					return err
 				}
-				monitoringInventoryPath := filepath.Join(app.GetAnsibleInventoryDirPath(clusterNameOrNodeID), constants.MonitoringDir)
-				if utils.DirectoryExists(monitoringInventoryPath) {
-					monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryPath)
-					if err != nil {
-						return err
-					}
-					clusterHosts = append(clusterHosts, monitoringHosts...)
+				clusterHosts = append(clusterHosts, monitoringHosts...)
+			}
+			if len(args[1:]) == 0 {
+				// only resetting the host keys
+				if err := resetHostKeys(clusterHosts); err != nil {
+					return err
 				}
-				return sshHosts(clusterHosts, cmd, clustersConfig.Clusters[clusterNameOrNodeID])
+				return printClusterConnectionString(clusterNameOrNodeID, clustersConfig.Clusters[clusterNameOrNodeID].Network.Kind.String())
 			}
+			return sshHosts(clusterHosts, cmd, clustersConfig.Clusters[clusterNameOrNodeID])
 		} else {
 			// try to detect nodeID
 			for clusterName := range clustersConfig.Clusters {
@@ -141,54 +172,49 @@ func printNodeInfo(host *models.Host, clusterConf models.ClusterConfig, result s
 }
 
 func sshHosts(hosts []*models.Host, cmd string, clusterConf models.ClusterConfig) error {
+	if resetHostKey {
+		if err := resetHostKeys(hosts); err != nil {
+			return err
+		}
+	} else if err := checkHostKeys(hosts); err != nil {
+		return err
+	}
 	if cmd != "" {
 		// execute cmd
-		wg := sync.WaitGroup{}
-		nowExecutingMutex := sync.Mutex{}
-		wgResults := models.NodeResults{}
-		for _, host := range hosts {
-			wg.Add(1)
-			go func(nodeResults *models.NodeResults, host *models.Host) {
-				if !isParallel {
-					nowExecutingMutex.Lock()
-					defer nowExecutingMutex.Unlock()
-					if err := printNodeInfo(host, clusterConf, ""); err != nil {
-						ux.Logger.RedXToUser("Error getting node %s info due to : %s", host.GetCloudID(), err)
-					}
-				}
-				defer wg.Done()
-				splitCmdLine := strings.Split(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath), " ")
-				splitCmdLine = append(splitCmdLine, cmd)
-				cmd := exec.Command(splitCmdLine[0], splitCmdLine[1:]...)
-				cmd.Env = os.Environ()
-				outBuf, errBuf := utils.SetupRealtimeCLIOutput(cmd, false, false)
-				if !isParallel {
-					_, _ = utils.SetupRealtimeCLIOutput(cmd, true, true)
-				}
-				if _, err := outBuf.ReadFrom(errBuf); err != nil {
-					nodeResults.AddResult(host.NodeID, outBuf, err)
-				}
-				if err := cmd.Run(); err != nil {
-					nodeResults.AddResult(host.NodeID, outBuf, err)
-				} else {
-					nodeResults.AddResult(host.NodeID, outBuf, nil)
-				}
-			}(&wgResults, host)
-		}
-		wg.Wait()
-		if wgResults.HasErrors() {
-			return fmt.Errorf("failed to ssh node(s) %s", wgResults.GetErrorHostMap())
+		executor := &ssh.Executor{
+			Concurrency: 1,
+			Timeout:     sshTimeout,
+			Retries:     sshRetries,
+			RetryDelay:  constants.SSHSleepBetweenChecks,
 		}
 		if isParallel {
-			for hostID, result := range wgResults.GetResultMap() {
-				for _, host := range hosts {
-					if host.GetCloudID() == hostID {
-						if err := printNodeInfo(host, clusterConf, fmt.Sprintf("%v", result)); err != nil {
-							ux.Logger.RedXToUser("Error getting node %s info due to : %s", host.GetCloudID(), err)
-						}
-					}
+			executor.Concurrency = sshConcurrency
+		}
+		// output is streamed only when the command runs on one node at a time
+		streamOutput := !isParallel && !sshJSONOutput
+		results := executor.Run(context.Background(), hosts, func(ctx context.Context, host *models.Host) (models.CommandOutput, error) {
+			if streamOutput {
+				if err := printNodeInfo(host, clusterConf, ""); err != nil {
+					ux.Logger.RedXToUser("Error getting node %s info due to : %s", host.GetCloudID(), err)
 				}
 			}
+			return runSSHCommandLine(ctx, host, cmd, streamOutput)
+		})
+		if sshJSONOutput {
+			resultsJSON, err := results.JSON()
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("%s", resultsJSON)
+		} else if isParallel {
+			for i, result := range results.GetResults() {
+				if err := printNodeInfo(hosts[i], clusterConf, result.Stdout+result.Stderr); err != nil {
+					ux.Logger.RedXToUser("Error getting node %s info due to : %s", result.CloudID, err)
+				}
+			}
+		}
+		if results.HasErrors() {
+			return fmt.Errorf("failed to ssh node(s) %s", results.GetErrorHostMap())
 		}
 	} else {
 		// open shell
@@ -199,7 +225,7 @@ func sshHosts(hosts []*models.Host, cmd string, clusterConf models.ClusterConfig
 			return fmt.Errorf("no nodes found")
 		default:
 			selectedHost := hosts[0]
-			splitCmdLine := strings.Split(utils.GetSSHConnectionString(selectedHost.IP, selectedHost.SSHPrivateKeyPath), " ")
+			splitCmdLine := getSSHCommandLine(selectedHost)
 			cmd := exec.Command(splitCmdLine[0], splitCmdLine[1:]...)
 			cmd.Env = os.Environ()
 			cmd.Stdin = os.Stdin
@@ -230,8 +256,91 @@ func printClusterConnectionString(clusterName string, networkName string) error
 		clusterHosts = append(clusterHosts, monitoringHosts...)
 	}
 	for _, host := range clusterHosts {
-		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.IP, host.SSHPrivateKeyPath))
+		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.SSHUser, host.IP, host.SSHPort, host.SSHPrivateKeyPath))
 	}
 	ux.Logger.PrintToUser("")
 	return nil
 }
+
+// getSSHCommandLine returns the ssh command line to connect to [host], verifying its
+// host key against the one pinned by the CLI
+func getSSHCommandLine(host *models.Host) []string {
+	splitCmdLine := strings.Split(utils.GetSSHConnectionString(host.SSHUser, host.IP, host.SSHPort, host.SSHPrivateKeyPath), " ")
+	// ssh uses the first value given for each option, so these take precedence over
+	// the ones at the connection string
+	knownHostsArgs := []string{
+		"-o", fmt.Sprintf("UserKnownHostsFile=\"%s\"", app.GetKnownHostsPath()),
+		"-o", "StrictHostKeyChecking=yes",
+	}
+	return append(append([]string{splitCmdLine[0]}, knownHostsArgs...), splitCmdLine[1:]...)
+}
+
+// runSSHCommandLine executes [command] on [host] with the ssh command line, also
+// writing its output to the user if [streamOutput]
+func runSSHCommandLine(ctx context.Context, host *models.Host, command string, streamOutput bool) (models.CommandOutput, error) {
+	splitCmdLine := append(getSSHCommandLine(host), command)
+	cmd := exec.CommandContext(ctx, splitCmdLine[0], splitCmdLine[1:]...)
+	cmd.Env = os.Environ()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if streamOutput {
+		cmd.Stdout = io.MultiWriter(&stdout, os.Stdout)
+		cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
+	}
+	err := cmd.Run()
+	output := models.CommandOutput{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		output.ExitCode = exitErr.ExitCode()
+	}
+	return output, err
+}
+
+// checkHostKeys connects to [hosts] to pin their host keys if not yet known, and
+// to verify them otherwise
+func checkHostKeys(hosts []*models.Host) error {
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, ssh.FuncTask(checkSSHReachable))
+	if results.HasErrors() {
+		for cloudID, err := range results.GetErrorHostMap() {
+			ux.Logger.RedXToUser("Unable to verify the host key of node %s: %s", cloudID, err)
+		}
+		return fmt.Errorf("failed to verify the host key of node(s) %s", results.GetErrorHosts())
+	}
+	return nil
+}
+
+// checkSSHReachable opens and closes an ssh connection to [host], pinning its
+// host key if not yet known, and verifying it otherwise
+func checkSSHReachable(host *models.Host) error {
+	if err := host.Connect(0); err != nil {
+		return err
+	}
+	_ = host.Disconnect()
+	host.Connection = nil
+	return nil
+}
+
+// forgetHostKeys removes the pinned host keys of [hosts]
+func forgetHostKeys(hosts []*models.Host) error {
+	for _, host := range hosts {
+		if _, err := host.ResetHostKey(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetHostKeys forgets the pinned host keys of [hosts], and pins their current ones
+func resetHostKeys(hosts []*models.Host) error {
+	if err := forgetHostKeys(hosts); err != nil {
+		return err
+	}
+	if err := checkHostKeys(hosts); err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		ux.Logger.GreenCheckmarkToUser("Pinned the current host key of node %s (%s)", host.GetCloudID(), host.IP)
+	}
+	return nil
+}
@@ -3,13 +3,14 @@
 package nodecmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -25,6 +26,27 @@ import (
 
 var subnetName string
 
+// nodeStatus is the structured output of node status for a cluster host
+type nodeStatus struct {
+	CloudID            string   `json:"cloudID"`
+	NodeID             string   `json:"nodeID,omitempty"`
+	IP                 string   `json:"ip"`
+	Network            string   `json:"network"`
+	Roles              []string `json:"roles"`
+	AvalancheGoHost    bool     `json:"avalancheGoHost"`
+	AvalancheGoVersion string   `json:"avalancheGoVersion,omitempty"`
+	Bootstrapped       bool     `json:"bootstrapped"`
+	Healthy            bool     `json:"healthy"`
+	SubnetSyncStatus   string   `json:"subnetSyncStatus,omitempty"`
+}
+
+// clusterStatus is the structured output of node status
+type clusterStatus struct {
+	Cluster string       `json:"cluster"`
+	Subnet  string       `json:"subnet,omitempty"`
+	Nodes   []nodeStatus `json:"nodes"`
+}
+
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status [clusterName]",
@@ -38,6 +60,7 @@ To get the bootstrap status of a node with a Subnet, use --subnet flag`,
 		SilenceUsage: true,
 		Args:         cobra.MinimumNArgs(0),
 		RunE:         statusNode,
+		Annotations:  ux.StructuredOutputAnnotations(),
 	}
 	cmd.Flags().StringVar(&subnetName, "subnet", "", "specify the subnet the node is syncing with")
 
@@ -46,6 +69,9 @@ To get the bootstrap status of a node with a Subnet, use --subnet flag`,
 
 func statusNode(_ *cobra.Command, args []string) error {
 	if len(args) == 0 {
+		if ux.IsStructuredOutput() {
+			return fmt.Errorf("a cluster name is required for --%s %s", constants.OutputFormatFlag, ux.GetOutputFormat())
+		}
 		return list(nil, nil)
 	}
 	clusterName := args[0]
@@ -101,31 +127,20 @@ func statusNode(_ *cobra.Command, args []string) error {
 
 	ux.Logger.PrintToUser("Getting avalanchego version of node(s)...")
 
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if avalancheGoVersion, _, err := parseAvalancheGoOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), avalancheGoVersion, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return fmt.Errorf("failed to get avalanchego version for node(s) %s", wgResults.GetErrorHostMap())
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+		if err != nil {
+			return models.CommandOutput{}, err
+		}
+		_, _, err = parseAvalancheGoOutput(resp)
+		return models.CommandOutput{Stdout: resp}, err
+	})
+	if results.HasErrors() {
+		return fmt.Errorf("failed to get avalanchego version for node(s) %s", results.GetErrorHostMap())
 	}
 	avagoVersions := map[string]string{}
-	for nodeID, avalanchegoVersion := range wgResults.GetResultMap() {
-		avagoVersions[nodeID] = fmt.Sprintf("%v", avalanchegoVersion)
+	for _, result := range results.GetResults() {
+		avagoVersions[result.CloudID], _, _ = parseAvalancheGoOutput([]byte(result.Stdout))
 	}
 
 	notSyncedNodes := []string{}
@@ -143,30 +158,20 @@ func statusNode(_ *cobra.Command, args []string) error {
 		if len(hostsToCheckSyncStatus) != 0 {
 			ux.Logger.PrintToUser("Getting subnet sync status of node(s)")
 			hostsToCheck := utils.Filter(hosts, func(h *models.Host) bool { return slices.Contains(hostsToCheckSyncStatus, h.GetCloudID()) })
-			wg := sync.WaitGroup{}
-			wgResults := models.NodeResults{}
-			for _, host := range hostsToCheck {
-				wg.Add(1)
-				go func(nodeResults *models.NodeResults, host *models.Host) {
-					defer wg.Done()
-					if syncstatus, err := ssh.RunSSHSubnetSyncStatus(host, blockchainID.String()); err != nil {
-						nodeResults.AddResult(host.GetCloudID(), nil, err)
-						return
-					} else {
-						if subnetSyncStatus, err := parseSubnetSyncOutput(syncstatus); err != nil {
-							nodeResults.AddResult(host.GetCloudID(), nil, err)
-							return
-						} else {
-							nodeResults.AddResult(host.GetCloudID(), subnetSyncStatus, err)
-						}
-					}
-				}(&wgResults, host)
-			}
-			wg.Wait()
-			if wgResults.HasErrors() {
-				return fmt.Errorf("failed to check sync status for node(s) %s", wgResults.GetErrorHostMap())
+			results := ssh.NewExecutor("").Run(context.Background(), hostsToCheck, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+				syncStatus, err := ssh.RunSSHSubnetSyncStatus(host, blockchainID.String())
+				if err != nil {
+					return models.CommandOutput{}, err
+				}
+				_, err = parseSubnetSyncOutput(syncStatus)
+				return models.CommandOutput{Stdout: syncStatus}, err
+			})
+			if results.HasErrors() {
+				return fmt.Errorf("failed to check sync status for node(s) %s", results.GetErrorHostMap())
 			}
-			for nodeID, subnetSyncStatus := range wgResults.GetResultMap() {
+			for _, result := range results.GetResults() {
+				nodeID := result.CloudID
+				subnetSyncStatus, _ := parseSubnetSyncOutput([]byte(result.Stdout))
 				switch subnetSyncStatus {
 				case status.Syncing.String():
 					subnetSyncedNodes = append(subnetSyncedNodes, nodeID)
@@ -190,6 +195,21 @@ func statusNode(_ *cobra.Command, args []string) error {
 		}
 		nodeConfigs = append(nodeConfigs, nodeConfig)
 	}
+	if ux.IsStructuredOutput() {
+		return ux.PrintStructured("ClusterStatus", buildClusterStatus(
+			clusterConf,
+			hostIDs,
+			nodeIDs,
+			avagoVersions,
+			unhealthyNodes,
+			notBootstrappedNodes,
+			subnetSyncedNodes,
+			subnetValidatingNodes,
+			clusterName,
+			subnetName,
+			nodeConfigs,
+		))
+	}
 	printOutput(
 		clusterConf,
 		hostIDs,
@@ -290,6 +310,52 @@ func printOutput(
 	table.Render()
 }
 
+func buildClusterStatus(
+	clusterConf models.ClusterConfig,
+	cloudIDs []string,
+	nodeIDs []string,
+	avagoVersions map[string]string,
+	unhealthyHosts []string,
+	notBootstrappedHosts []string,
+	subnetSyncedHosts []string,
+	subnetValidatingHosts []string,
+	clusterName string,
+	subnetName string,
+	nodeConfigs []models.NodeConfig,
+) clusterStatus {
+	output := clusterStatus{
+		Cluster: clusterName,
+		Subnet:  subnetName,
+		Nodes:   []nodeStatus{},
+	}
+	for i, cloudID := range cloudIDs {
+		hostStatus := nodeStatus{
+			CloudID: cloudID,
+			IP:      nodeConfigs[i].ElasticIP,
+			Network: clusterConf.Network.Kind.String(),
+			Roles:   clusterConf.GetHostRoles(nodeConfigs[i]),
+		}
+		if clusterConf.IsAvalancheGoHost(cloudID) {
+			hostStatus.AvalancheGoHost = true
+			hostStatus.NodeID = nodeIDs[i]
+			hostStatus.AvalancheGoVersion = avagoVersions[cloudID]
+			hostStatus.Bootstrapped = !slices.Contains(notBootstrappedHosts, cloudID)
+			hostStatus.Healthy = !slices.Contains(unhealthyHosts, cloudID)
+			if subnetName != "" {
+				hostStatus.SubnetSyncStatus = "NOT_BOOTSTRAPPED"
+				if slices.Contains(subnetSyncedHosts, cloudID) {
+					hostStatus.SubnetSyncStatus = "SYNCED"
+				}
+				if slices.Contains(subnetValidatingHosts, cloudID) {
+					hostStatus.SubnetSyncStatus = "VALIDATING"
+				}
+			}
+		}
+		output.Nodes = append(output.Nodes, hostStatus)
+	}
+	return output
+}
+
 func removeColors(s string) string {
 	bs, err := ansi.Strip([]byte(s))
 	if err != nil {
@@ -0,0 +1,123 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+func TestBuildClusterStatus(t *testing.T) {
+	require := require.New(t)
+	clusterConf := models.ClusterConfig{
+		Nodes:              []string{"i-1", "i-2", "i-3"},
+		APINodes:           []string{"i-3"},
+		Network:            models.NewFujiNetwork(),
+		MonitoringInstance: "i-4",
+	}
+	cloudIDs := []string{"i-1", "i-2", "i-3", "i-4"}
+	nodeIDs := []string{"NodeID-1", "NodeID-2", "NodeID-3", ""}
+	nodeConfigs := []models.NodeConfig{
+		{NodeID: "i-1", ElasticIP: "1.1.1.1"},
+		{NodeID: "i-2", ElasticIP: "2.2.2.2"},
+		{NodeID: "i-3", ElasticIP: "3.3.3.3"},
+		{NodeID: "i-4", ElasticIP: "4.4.4.4", IsMonitor: true},
+	}
+	status := buildClusterStatus(
+		clusterConf,
+		cloudIDs,
+		nodeIDs,
+		map[string]string{"i-1": "v1.11.1", "i-2": "v1.11.1", "i-3": "v1.11.0"},
+		[]string{"i-2"},
+		[]string{"i-3"},
+		[]string{"i-1", "i-2"},
+		[]string{"i-1"},
+		"mycluster",
+		"mysubnet",
+		nodeConfigs,
+	)
+	require.Equal("mycluster", status.Cluster)
+	require.Equal("mysubnet", status.Subnet)
+	require.Len(status.Nodes, 4)
+
+	// sync status: validating takes precedence over synced
+	require.Equal("VALIDATING", status.Nodes[0].SubnetSyncStatus)
+	require.Equal("SYNCED", status.Nodes[1].SubnetSyncStatus)
+	require.Equal("NOT_BOOTSTRAPPED", status.Nodes[2].SubnetSyncStatus)
+	require.Equal("", status.Nodes[3].SubnetSyncStatus)
+
+	require.True(status.Nodes[0].Healthy)
+	require.False(status.Nodes[1].Healthy)
+	require.True(status.Nodes[1].Bootstrapped)
+	require.False(status.Nodes[2].Bootstrapped)
+	require.Equal([]string{constants.ValidatorRole}, status.Nodes[0].Roles)
+	require.Equal([]string{constants.APIRole}, status.Nodes[2].Roles)
+
+	// monitoring host has no avalanchego information
+	require.Equal(nodeStatus{
+		CloudID: "i-4",
+		IP:      "4.4.4.4",
+		Network: models.Fuji.String(),
+		Roles:   []string{constants.MonitorRole},
+	}, status.Nodes[3])
+}
+
+func TestClusterStatusJSONSchema(t *testing.T) {
+	require := require.New(t)
+	status := clusterStatus{
+		Cluster: "mycluster",
+		Subnet:  "mysubnet",
+		Nodes: []nodeStatus{
+			{
+				CloudID:            "i-1",
+				NodeID:             "NodeID-1",
+				IP:                 "1.1.1.1",
+				Network:            models.Fuji.String(),
+				Roles:              []string{constants.ValidatorRole},
+				AvalancheGoHost:    true,
+				AvalancheGoVersion: "v1.11.1",
+				Bootstrapped:       true,
+				Healthy:            true,
+				SubnetSyncStatus:   "SYNCED",
+			},
+			{
+				CloudID: "i-2",
+				IP:      "2.2.2.2",
+				Network: models.Fuji.String(),
+				Roles:   []string{constants.MonitorRole},
+			},
+		},
+	}
+	bs, err := json.Marshal(status)
+	require.NoError(err)
+	var generic map[string]interface{}
+	require.NoError(json.Unmarshal(bs, &generic))
+	require.ElementsMatch([]string{"cluster", "subnet", "nodes"}, maps.Keys(generic))
+	nodes := generic["nodes"].([]interface{})
+	require.ElementsMatch([]string{
+		"cloudID",
+		"nodeID",
+		"ip",
+		"network",
+		"roles",
+		"avalancheGoHost",
+		"avalancheGoVersion",
+		"bootstrapped",
+		"healthy",
+		"subnetSyncStatus",
+	}, maps.Keys(nodes[0].(map[string]interface{})))
+	require.ElementsMatch([]string{
+		"cloudID",
+		"ip",
+		"network",
+		"roles",
+		"avalancheGoHost",
+		"bootstrapped",
+		"healthy",
+	}, maps.Keys(nodes[1].(map[string]interface{})))
+}
@@ -3,9 +3,9 @@
 package nodecmd
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
-	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 
@@ -93,29 +93,18 @@ func trackSubnet(
 	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath); err != nil {
 		return nil, err
 	}
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			subnetExportPath := filepath.Join("/tmp", filepath.Base(subnetPath))
-			if err := ssh.RunSSHExportSubnet(host, subnetPath, subnetExportPath); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			}
-			if err := ssh.RunSSHUploadClustersConfig(host, app.GetClustersConfigPath()); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-			}
-			if err := ssh.RunSSHTrackSubnet(host, subnetName, subnetExportPath, networkFlag); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to track subnet for node(s) %s", wgResults.GetErrorHostMap())
+	results := ssh.NewExecutor("Track Subnet").Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		subnetExportPath := filepath.Join("/tmp", filepath.Base(subnetPath))
+		if err := ssh.RunSSHExportSubnet(host, subnetPath, subnetExportPath); err != nil {
+			return err
+		}
+		if err := ssh.RunSSHUploadClustersConfig(host, app.GetClustersConfigPath()); err != nil {
+			return err
+		}
+		return ssh.RunSSHTrackSubnet(host, subnetName, subnetExportPath, networkFlag)
+	}))
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to track subnet for node(s) %s", results.GetErrorHostMap())
 	}
-	return wgResults.GetErrorHosts(), nil
+	return results.GetErrorHosts(), nil
 }
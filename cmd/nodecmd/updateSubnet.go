@@ -3,9 +3,9 @@
 package nodecmd
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
-	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
@@ -79,29 +79,18 @@ func doUpdateSubnet(
 	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath); err != nil {
 		return nil, err
 	}
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			subnetExportPath := filepath.Join("/tmp", filepath.Base(subnetPath))
-			if err := ssh.RunSSHExportSubnet(host, subnetPath, subnetExportPath); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			}
-			if err := ssh.RunSSHUploadClustersConfig(host, app.GetClustersConfigPath()); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-			}
-			if err := ssh.RunSSHUpdateSubnet(host, subnetName, subnetExportPath); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			}
-		}(&wgResults, host)
+	results := ssh.NewExecutor("Update Subnet").Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		subnetExportPath := filepath.Join("/tmp", filepath.Base(subnetPath))
+		if err := ssh.RunSSHExportSubnet(host, subnetPath, subnetExportPath); err != nil {
+			return err
+		}
+		if err := ssh.RunSSHUploadClustersConfig(host, app.GetClustersConfigPath()); err != nil {
+			return err
+		}
+		return ssh.RunSSHUpdateSubnet(host, subnetName, subnetExportPath)
+	}))
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to update subnet for node(s) %s", results.GetErrorHostMap())
 	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to update subnet for node(s) %s", wgResults.GetErrorHostMap())
-	}
-	return wgResults.GetErrorHosts(), nil
+	return results.GetErrorHosts(), nil
 }
@@ -3,30 +3,59 @@
 package nodecmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
+var (
+	upgradeBatchSize      int
+	upgradeMinStakeOnline float64
+	upgradeHealthTimeout  time.Duration
+)
+
+// errNodeBackup signals that the node binaries could not be saved before the upgrade,
+// so the node was left untouched and there is nothing to roll back
+var errNodeBackup = errors.New("failed to backup node binaries")
+
 type nodeUpgradeInfo struct {
 	AvalancheGoVersion    string   // avalanche go version to update to on cloud server
 	SubnetEVMVersion      string   // subnet EVM version to update to on cloud server
 	SubnetEVMIDsToUpgrade []string // list of ID of Subnet EVM to be upgraded to subnet EVM version to update to
 }
 
+// binaryPaths returns the paths of the node binaries replaced by the upgrade
+func (i nodeUpgradeInfo) binaryPaths() []string {
+	paths := []string{}
+	if i.AvalancheGoVersion != "" {
+		paths = append(paths, constants.CloudNodeAvalancheGoBinPath)
+	}
+	if i.SubnetEVMVersion != "" {
+		for _, vmID := range i.SubnetEVMIDsToUpgrade {
+			paths = append(paths, fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID))
+		}
+	}
+	return paths
+}
+
 func newUpgradeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "upgrade",
@@ -36,20 +65,57 @@ func newUpgradeCmd() *cobra.Command {
 The node update command suite provides a collection of commands for nodes to update
 their avalanchego or VM version.
 
+Nodes are upgraded in rolling batches of --batch-size nodes. After each batch, the command
+waits for the upgraded nodes to be bootstrapped and healthy, and if --subnet is given, to be
+synced to the subnet, before moving to the next batch. The binaries of a node that fails to
+upgrade or to get healthy in time are rolled back, and the rollout is stopped.
+
+With --min-stake-online, batches are planned so that the subnet stake of the nodes being
+upgraded at the same time never takes the online stake of the subnet below the given percentage.
+
 You can check the status after upgrade by calling avalanche node status`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         upgrade,
 	}
+	cmd.Flags().IntVar(&upgradeBatchSize, "batch-size", 1, "number of nodes to upgrade at a time")
+	cmd.Flags().StringVar(&subnetName, "subnet", "", "wait for upgraded nodes to be synced to this subnet before upgrading the next ones")
+	cmd.Flags().Float64Var(&upgradeMinStakeOnline, "min-stake-online", 0, "minimum percentage of the --subnet stake to keep online during the upgrade")
+	cmd.Flags().DurationVar(&upgradeHealthTimeout, "health-timeout", 5*time.Minute, "time to wait for upgraded nodes to be healthy before rolling them back")
 
 	return cmd
 }
 
 func upgrade(_ *cobra.Command, args []string) error {
 	clusterName := args[0]
+	if upgradeBatchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	if upgradeMinStakeOnline < 0 || upgradeMinStakeOnline >= 100 {
+		return fmt.Errorf("--min-stake-online must be a percentage in [0, 100)")
+	}
+	if upgradeMinStakeOnline > 0 && subnetName == "" {
+		return fmt.Errorf("--min-stake-online requires --subnet")
+	}
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	var subnetID, blockchainID ids.ID
+	if subnetName != "" {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return err
+		}
+		subnetID = sc.Networks[clusterConfig.Network.Name()].SubnetID
+		blockchainID = sc.Networks[clusterConfig.Network.Name()].BlockchainID
+		if blockchainID == ids.Empty {
+			return ErrNoBlockchainID
+		}
+	}
 	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
 	if err != nil {
 		return err
@@ -59,44 +125,242 @@ func upgrade(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	spinSession := ux.NewUserSpinner()
-	for host, upgradeInfo := range toUpgradeNodesMap {
-		if upgradeInfo.AvalancheGoVersion != "" {
-			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading avalanchego to version %s...", upgradeInfo.AvalancheGoVersion)))
-			if err := upgradeAvalancheGo(host, upgradeInfo.AvalancheGoVersion); err != nil {
-				ux.SpinFailWithError(spinner, "", err)
-				return err
-			}
-			ux.SpinComplete(spinner)
-		}
-		if upgradeInfo.SubnetEVMVersion != "" {
-			subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(upgradeInfo.SubnetEVMVersion, "v")
-			subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
-			subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, upgradeInfo.SubnetEVMVersion, subnetEVMArchive)
-			spinner := spinSession.SpinToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading SubnetEVM to version %s...", upgradeInfo.SubnetEVMVersion)))
-			if err := getNewSubnetEVMRelease(host, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
-				ux.SpinFailWithError(spinner, "", err)
-				return err
-			}
-			if err := ssh.RunSSHStopNode(host); err != nil {
-				ux.SpinFailWithError(spinner, "", err)
+	hostsToUpgrade := utils.Filter(hosts, func(h *models.Host) bool {
+		upgradeInfo, ok := toUpgradeNodesMap[h]
+		return ok && len(upgradeInfo.binaryPaths()) > 0
+	})
+	if len(hostsToUpgrade) == 0 {
+		ux.Logger.PrintToUser("All nodes in cluster %s are up to date", clusterName)
+		return nil
+	}
+	weights := map[string]uint64{}
+	totalStake := uint64(0)
+	if subnetName != "" {
+		weights, totalStake, err = getSubnetStake(clusterConfig.Network, subnetID, hostsToUpgrade)
+		if err != nil {
+			return err
+		}
+	}
+	hostIDs := utils.Map(hostsToUpgrade, func(h *models.Host) string { return h.NodeID })
+	batches, err := planUpgradeBatches(hostIDs, weights, totalStake, upgradeBatchSize, upgradeMinStakeOnline)
+	if err != nil {
+		return err
+	}
+	for i, batch := range batches {
+		ux.Logger.PrintToUser("Upgrading batch %d/%d: node(s) %s", i+1, len(batches), batch)
+		batchHosts := utils.Filter(hostsToUpgrade, func(h *models.Host) bool { return slices.Contains(batch, h.NodeID) })
+		if err := upgradeBatch(batchHosts, toUpgradeNodesMap, blockchainID); err != nil {
+			return err
+		}
+	}
+	ux.Logger.GreenCheckmarkToUser("All nodes in cluster %s were upgraded", clusterName)
+	return nil
+}
+
+// getSubnetStake returns the subnet stake of each of [hosts], by host node ID,
+// together with the total stake of the subnet
+func getSubnetStake(network models.Network, subnetID ids.ID, hosts []*models.Host) (map[string]uint64, uint64, error) {
+	subnetValidators, err := subnet.GetPublicSubnetValidators(subnetID, network)
+	if err != nil {
+		return nil, 0, err
+	}
+	validatorWeights := map[ids.NodeID]uint64{}
+	totalStake := uint64(0)
+	for _, validator := range subnetValidators {
+		validatorWeights[validator.NodeID] = validator.Weight
+		totalStake += validator.Weight
+	}
+	weights := map[string]uint64{}
+	for _, host := range hosts {
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(host.GetCloudID()))
+		if err != nil {
+			return nil, 0, err
+		}
+		weights[host.NodeID] = validatorWeights[nodeID]
+	}
+	return weights, totalStake, nil
+}
+
+// planUpgradeBatches splits [hostIDs] into batches of at most [batchSize] hosts to be upgraded
+// at the same time, so that the stake of a batch never takes the online stake below [minStakeOnline]
+// percent of [totalStake]. Hosts missing from [weights] have no stake
+func planUpgradeBatches(
+	hostIDs []string,
+	weights map[string]uint64,
+	totalStake uint64,
+	batchSize int,
+	minStakeOnline float64,
+) ([][]string, error) {
+	maxOfflineStake := uint64(float64(totalStake) * (100 - minStakeOnline) / 100)
+	batches := [][]string{}
+	batch := []string{}
+	batchStake := uint64(0)
+	for _, hostID := range hostIDs {
+		weight := weights[hostID]
+		if weight > maxOfflineStake {
+			return nil, fmt.Errorf(
+				"upgrading node %s takes %.2f%% of the subnet stake offline, keeping %.2f%% online is not possible",
+				hostID,
+				float64(weight)*100/float64(totalStake),
+				minStakeOnline,
+			)
+		}
+		if len(batch) == batchSize || batchStake+weight > maxOfflineStake {
+			batches = append(batches, batch)
+			batch = []string{}
+			batchStake = 0
+		}
+		batch = append(batch, hostID)
+		batchStake += weight
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+// upgradeBatch upgrades [hosts] in parallel and waits for them to be healthy and synced to
+// [blockchainID], if given. The hosts that fail to do so are rolled back, and an error is returned
+func upgradeBatch(hosts []*models.Host, toUpgradeNodesMap map[*models.Host]nodeUpgradeInfo, blockchainID ids.ID) error {
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		return upgradeNode(host, toUpgradeNodesMap[host])
+	}))
+	failedNodes := results.GetErrorNodeIDMap()
+	upgradedHosts := utils.Filter(hosts, func(h *models.Host) bool {
+		_, failed := failedNodes[h.NodeID]
+		return !failed
+	})
+	for nodeID, err := range waitForUpgradedHosts(upgradedHosts, blockchainID, upgradeHealthTimeout) {
+		failedNodes[nodeID] = err
+	}
+	for _, host := range hosts {
+		err, failed := failedNodes[host.NodeID]
+		if !failed {
+			ux.Logger.GreenCheckmarkToUser("Node %s upgraded", host.NodeID)
+			continue
+		}
+		ux.Logger.RedXToUser("Node %s failed to upgrade: %s", host.NodeID, err)
+		if errors.Is(err, errNodeBackup) {
+			continue
+		}
+		ux.Logger.PrintToUser("Rolling back node %s...", host.NodeID)
+		if err := rollbackNode(host, toUpgradeNodesMap[host]); err != nil {
+			ux.Logger.RedXToUser("Failed to roll back node %s: %s, please check it manually", host.NodeID, err)
+			continue
+		}
+		ux.Logger.PrintToUser("Node %s rolled back", host.NodeID)
+	}
+	if len(failedNodes) > 0 {
+		return fmt.Errorf("upgrade stopped: node(s) %s failed to upgrade", maps.Keys(failedNodes))
+	}
+	return nil
+}
+
+// upgradeNode saves the binaries of [host] for a possible rollback, and then
+// upgrades them according to [upgradeInfo]
+func upgradeNode(host *models.Host, upgradeInfo nodeUpgradeInfo) error {
+	if err := ssh.RunSSHBackupNodeBinaries(host, upgradeInfo.binaryPaths()); err != nil {
+		return fmt.Errorf("%w: %s", errNodeBackup, err)
+	}
+	if upgradeInfo.AvalancheGoVersion != "" {
+		ux.Logger.PrintToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading avalanchego to version %s...", upgradeInfo.AvalancheGoVersion)))
+		if err := upgradeAvalancheGo(host, upgradeInfo.AvalancheGoVersion); err != nil {
+			return err
+		}
+	}
+	if upgradeInfo.SubnetEVMVersion != "" {
+		subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(upgradeInfo.SubnetEVMVersion, "v")
+		subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
+		subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, upgradeInfo.SubnetEVMVersion, subnetEVMArchive)
+		ux.Logger.PrintToUser(utils.ScriptLog(host.NodeID, fmt.Sprintf("Upgrading SubnetEVM to version %s...", upgradeInfo.SubnetEVMVersion)))
+		if err := getNewSubnetEVMRelease(host, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
+			return err
+		}
+		if err := ssh.RunSSHStopNode(host); err != nil {
+			return err
+		}
+		for _, vmID := range upgradeInfo.SubnetEVMIDsToUpgrade {
+			subnetEVMBinaryPath := fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID)
+			if err := upgradeSubnetEVM(host, subnetEVMBinaryPath); err != nil {
 				return err
 			}
-			for _, vmID := range upgradeInfo.SubnetEVMIDsToUpgrade {
-				subnetEVMBinaryPath := fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID)
-				if err := upgradeSubnetEVM(host, subnetEVMBinaryPath); err != nil {
-					ux.SpinFailWithError(spinner, "", err)
-					return err
-				}
-			}
-			if err := ssh.RunSSHStartNode(host); err != nil {
-				ux.SpinFailWithError(spinner, "", err)
+		}
+		if err := ssh.RunSSHStartNode(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackNode restores the binaries of [host] saved before its upgrade
+func rollbackNode(host *models.Host, upgradeInfo nodeUpgradeInfo) error {
+	if err := ssh.RunSSHStopNode(host); err != nil {
+		return err
+	}
+	if err := ssh.RunSSHRestoreNodeBinaries(host, upgradeInfo.binaryPaths()); err != nil {
+		return err
+	}
+	return ssh.RunSSHStartNode(host)
+}
+
+// waitForUpgradedHosts waits up to [timeout] for [hosts] to be bootstrapped, healthy and, if
+// [blockchainID] is given, synced to it. Returns the errors of the hosts that did not get there
+func waitForUpgradedHosts(hosts []*models.Host, blockchainID ids.ID, timeout time.Duration) map[string]error {
+	if len(hosts) == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("Waiting for upgraded node(s) to be healthy...")
+	executor := ssh.NewExecutor("")
+	// hosts are all waited for at the same time, so that each of them gets the whole timeout
+	executor.Concurrency = 0
+	results := executor.Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		startTime := time.Now()
+		for {
+			err := checkHostIsHealthy(host, blockchainID)
+			if err == nil || time.Since(startTime) > timeout {
 				return err
 			}
-			ux.SpinComplete(spinner)
+			time.Sleep(healthCheckPoolTime)
 		}
+	}))
+	return results.GetErrorNodeIDMap()
+}
+
+// checkHostIsHealthy returns an error if [host] is not bootstrapped, healthy, or
+// synced to [blockchainID], if given
+func checkHostIsHealthy(host *models.Host, blockchainID ids.ID) error {
+	resp, err := ssh.RunSSHCheckBootstrapped(host)
+	if err != nil {
+		return err
+	}
+	if isBootstrapped, err := parseBootstrappedOutput(resp); err != nil {
+		return err
+	} else if !isBootstrapped {
+		return errors.New("node is not bootstrapped")
+	}
+	resp, err = ssh.RunSSHCheckHealthy(host)
+	if err != nil {
+		return err
+	}
+	if isHealthy, err := parseHealthyOutput(resp); err != nil {
+		return err
+	} else if !isHealthy {
+		return errors.New("node is not healthy")
+	}
+	if blockchainID == ids.Empty {
+		return nil
+	}
+	resp, err = ssh.RunSSHSubnetSyncStatus(host, blockchainID.String())
+	if err != nil {
+		return err
+	}
+	subnetSyncStatus, err := parseSubnetSyncOutput(resp)
+	if err != nil {
+		return err
+	}
+	if subnetSyncStatus != status.Syncing.String() && subnetSyncStatus != status.Validating.String() {
+		return fmt.Errorf("node is not synced to blockchain %s, status is %s", blockchainID, subnetSyncStatus)
 	}
-	spinSession.Stop()
 	return nil
 }
 
@@ -126,27 +390,16 @@ func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo
 	nodeErrors := map[string]error{}
 	nodesToUpgrade := make(map[*models.Host]nodeUpgradeInfo)
 
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			} else {
-				if vmVersions, err := parseNodeVersionOutput(resp); err != nil {
-					nodeResults.AddResult(host.NodeID, nil, err)
-				} else {
-					nodeResults.AddResult(host.NodeID, vmVersions, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return nil, fmt.Errorf("failed to get avalanchego version for node(s) %s", wgResults.GetErrorHostMap())
+	results := ssh.NewExecutor("").Run(context.Background(), hosts, func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		resp, err := ssh.RunSSHCheckAvalancheGoVersion(host)
+		if err != nil {
+			return models.CommandOutput{}, err
+		}
+		_, err = parseNodeVersionOutput(resp)
+		return models.CommandOutput{Stdout: resp}, err
+	})
+	if results.HasErrors() {
+		return nil, fmt.Errorf("failed to get avalanchego version for node(s) %s", results.GetErrorHostMap())
 	}
 
 	nodeIDToHost := map[string]*models.Host{}
@@ -154,8 +407,9 @@ func getNodesUpgradeInfo(hosts []*models.Host) (map[*models.Host]nodeUpgradeInfo
 		nodeIDToHost[host.NodeID] = host
 	}
 
-	for hostID, vmVersionsInterface := range wgResults.GetResultMap() {
-		vmVersions, err := utils.ConvertInterfaceToMap(vmVersionsInterface)
+	for _, result := range results.GetResults() {
+		hostID := result.NodeID
+		vmVersions, err := parseNodeVersionOutput([]byte(result.Stdout))
 		if err != nil {
 			return nil, err
 		}
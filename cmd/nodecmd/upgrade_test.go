@@ -0,0 +1,59 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanUpgradeBatches(t *testing.T) {
+	require := require.New(t)
+	hostIDs := []string{"node1", "node2", "node3", "node4", "api1"}
+
+	// no subnet stake: batches are only bounded by size
+	batches, err := planUpgradeBatches(hostIDs, nil, 0, 2, 0)
+	require.NoError(err)
+	require.Equal([][]string{{"node1", "node2"}, {"node3", "node4"}, {"api1"}}, batches)
+	batches, err = planUpgradeBatches(hostIDs, nil, 0, 10, 0)
+	require.NoError(err)
+	require.Equal([][]string{hostIDs}, batches)
+
+	// 4 validators with 25% of the stake each, keeping 50% online allows 2 at a time
+	weights := map[string]uint64{"node1": 100, "node2": 100, "node3": 100, "node4": 100}
+	batches, err = planUpgradeBatches(hostIDs, weights, 400, 3, 50)
+	require.NoError(err)
+	require.Equal([][]string{{"node1", "node2"}, {"node3", "node4", "api1"}}, batches)
+	// keeping 60% online allows only one at a time
+	batches, err = planUpgradeBatches(hostIDs, weights, 400, 3, 60)
+	require.NoError(err)
+	require.Equal([][]string{{"node1"}, {"node2"}, {"node3"}, {"node4", "api1"}}, batches)
+	// stake of validators outside the cluster counts for the total
+	batches, err = planUpgradeBatches(hostIDs, weights, 1000, 5, 70)
+	require.NoError(err)
+	require.Equal([][]string{{"node1", "node2", "node3"}, {"node4", "api1"}}, batches)
+
+	// a single validator over the allowed offline stake can't be upgraded
+	_, err = planUpgradeBatches(hostIDs, weights, 400, 1, 80)
+	require.ErrorContains(err, "node1")
+}
+
+func TestNodeUpgradeInfoBinaryPaths(t *testing.T) {
+	require := require.New(t)
+	require.Empty(nodeUpgradeInfo{}.binaryPaths())
+	require.Equal([]string{constants.CloudNodeAvalancheGoBinPath}, nodeUpgradeInfo{AvalancheGoVersion: "v1.11.1"}.binaryPaths())
+	require.Equal(
+		[]string{
+			constants.CloudNodeAvalancheGoBinPath,
+			"/home/ubuntu/.avalanchego/plugins/vm1",
+			"/home/ubuntu/.avalanchego/plugins/vm2",
+		},
+		nodeUpgradeInfo{
+			AvalancheGoVersion:    "v1.11.1",
+			SubnetEVMVersion:      "v0.6.1",
+			SubnetEVMIDsToUpgrade: []string{"vm1", "vm2"},
+		}.binaryPaths(),
+	)
+}
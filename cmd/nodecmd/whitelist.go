@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
@@ -139,6 +138,10 @@ func whitelist(_ *cobra.Command, args []string) error {
 				gcpSGFound = true
 				continue
 			}
+			if cloudSecurityGroup.cloud == constants.ExistingHostsService {
+				ux.Logger.RedXToUser("IP whitelisting is not supported for existing hosts. Please update their firewall rules manually")
+				continue
+			}
 			ux.Logger.GreenCheckmarkToUser("Whitelisting IP %s in %s cloud region %s", userIPAddress, cloudSecurityGroup.cloud, cloudSecurityGroup.region)
 			if cloudSecurityGroup.cloud == "" || cloudSecurityGroup.cloud == constants.AWSCloudService {
 				if cloudSecurityGroup.cloud == "" || cloudSecurityGroup.cloud == constants.AWSCloudService {
@@ -221,23 +224,12 @@ func whitelistSSHPubKey(clusterName string, pubkey string) error {
 		return err
 	}
 	ux.Logger.PrintToUser("Whitelisting SSH public key on all nodes in cluster: %s", logging.LightBlue.Wrap(clusterName))
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if err := ssh.RunSSHWhitelistPubKey(host, sshPubKey); err != nil {
-				nodeResults.AddResult(host.NodeID, nil, err)
-				return
-			}
-			ux.Logger.GreenCheckmarkToUser(utils.ScriptLog(host.NodeID, "Whitelisted SSH public key"))
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		ux.Logger.RedXToUser("Failed to whitelist SSH public key for node(s) %s", wgResults.GetErrorHostMap())
-		return fmt.Errorf("failed to whitelist SSH public key for node(s) %s", wgResults.GetErrorHostMap())
+	results := ssh.NewExecutor("Whitelist SSH public key").Run(context.Background(), hosts, ssh.FuncTask(func(host *models.Host) error {
+		return ssh.RunSSHWhitelistPubKey(host, sshPubKey)
+	}))
+	if results.HasErrors() {
+		ux.Logger.RedXToUser("Failed to whitelist SSH public key for node(s) %s", results.GetErrorHostMap())
+		return fmt.Errorf("failed to whitelist SSH public key for node(s) %s", results.GetErrorHostMap())
 	}
 	return nil
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -39,11 +40,12 @@ import (
 )
 
 var (
-	app       *application.Avalanche
-	logLevel  string
-	Version   = ""
-	cfgFile   string
-	skipCheck bool
+	app          *application.Avalanche
+	logLevel     string
+	Version      = ""
+	cfgFile      string
+	skipCheck    bool
+	outputFormat string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -67,6 +69,7 @@ in with avalanche subnet create myNewSubnet.`,
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli/config.json)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
 	rootCmd.PersistentFlags().BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, constants.OutputFormatFlag, string(ux.TableOutputFormat), "output format for command results (table, json, yaml)")
 
 	// add sub commands
 	rootCmd.AddCommand(subnetcmd.NewCmd(app))
@@ -96,6 +99,14 @@ in with avalanche subnet create myNewSubnet.`,
 }
 
 func createApp(cmd *cobra.Command, _ []string) error {
+	format, err := ux.ParseOutputFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	if err := checkOutputFormatSupport(cmd, format); err != nil {
+		return err
+	}
+	ux.SetOutputFormat(format)
 	baseDir, err := setupEnv()
 	if err != nil {
 		return err
@@ -106,6 +117,7 @@ func createApp(cmd *cobra.Command, _ []string) error {
 	}
 	cf := config.New()
 	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	key.SetPassphraseFunc(key.NewPassphraseFunc(promptKeyPassphrase))
 
 	initConfig()
 
@@ -125,6 +137,24 @@ func createApp(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+// promptKeyPassphrase asks the user for the passphrase of the encrypted key at [keyPath]
+func promptKeyPassphrase(keyPath string) (string, error) {
+	keyName := strings.TrimSuffix(filepath.Base(keyPath), constants.KeySuffix)
+	return app.Prompt.CapturePassword(fmt.Sprintf("Enter passphrase for key %s", keyName))
+}
+
+// checkOutputFormatSupport fails if a structured [format] is requested for
+// a command that can only print tables.
+// Note that the local --output flag of key export and subnet export (a file
+// path) shadows the global one, so those commands never get here with
+// a structured format
+func checkOutputFormatSupport(cmd *cobra.Command, format ux.OutputFormat) error {
+	if format == ux.TableOutputFormat || ux.SupportsStructuredOutput(cmd.Annotations) {
+		return nil
+	}
+	return fmt.Errorf("--%s %s is not supported by %q", constants.OutputFormatFlag, format, cmd.CommandPath())
+}
+
 // checkForUpdates evaluates first if the user is maybe wanting to skip the update check
 // if there's no skip, it runs the update check
 func checkForUpdates(cmd *cobra.Command, app *application.Avalanche) error {
@@ -285,7 +315,12 @@ func setupLogging(baseDir string) (logging.Logger, error) {
 		return nil, fmt.Errorf("failed setting up logging, exiting: %w", err)
 	}
 	// create the user facing logger as a global var
-	ux.NewUserLog(log, os.Stdout)
+	// on structured output, stdout is reserved for the command result
+	userWriter := os.Stdout
+	if ux.IsStructuredOutput() {
+		userWriter = os.Stderr
+	}
+	ux.NewUserLog(log, userWriter)
 	return log, nil
 }
 
@@ -0,0 +1,51 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/stretchr/testify/require"
+)
+
+// key export and subnet export have a local --output flag for a file path,
+// that shadows the global output format flag
+func TestOutputFlagShadowedOnExport(t *testing.T) {
+	require := require.New(t)
+	rootCmd := NewRootCmd()
+	for _, path := range [][]string{{"key", "export"}, {"subnet", "export"}} {
+		outputFormat = string(ux.TableOutputFormat)
+		cmd, _, err := rootCmd.Find(path)
+		require.NoError(err)
+		require.NoError(cmd.ParseFlags([]string{"--" + constants.OutputFormatFlag, "json"}))
+		flag := cmd.Flags().Lookup(constants.OutputFormatFlag)
+		require.NotNil(flag)
+		require.Equal("o", flag.Shorthand)
+		require.Equal("json", flag.Value.String())
+		require.Equal(string(ux.TableOutputFormat), outputFormat)
+	}
+}
+
+func TestCheckOutputFormatSupport(t *testing.T) {
+	require := require.New(t)
+	rootCmd := NewRootCmd()
+	for _, path := range [][]string{
+		{"subnet", "describe"},
+		{"subnet", "list"},
+		{"subnet", "stats"},
+		{"subnet", "apply"},
+		{"node", "status"},
+	} {
+		cmd, _, err := rootCmd.Find(path)
+		require.NoError(err)
+		require.NoError(checkOutputFormatSupport(cmd, ux.JSONOutputFormat))
+		require.NoError(checkOutputFormatSupport(cmd, ux.YAMLOutputFormat))
+	}
+	cmd, _, err := rootCmd.Find([]string{"subnet", "deploy"})
+	require.NoError(err)
+	require.NoError(checkOutputFormatSupport(cmd, ux.TableOutputFormat))
+	require.Error(checkOutputFormatSupport(cmd, ux.JSONOutputFormat))
+	require.Error(checkOutputFormatSupport(cmd, ux.YAMLOutputFormat))
+}
@@ -0,0 +1,327 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/manifest"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestFile string
+	forceApply   bool
+	onlyDiff     bool
+
+	errManifestDrift = errors.New("subnet configuration differs from manifest. Use --" + forceFlag + " to recreate it")
+)
+
+// avalanche subnet apply
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create and deploy a subnet from a manifest file",
+		Long: `The subnet apply command creates and deploys a Subnet-EVM subnet from a declarative,
+versioned yaml manifest, without any interactive prompt. Sections omitted on the manifest
+are not left untouched: a missing fee config means the default one, and missing airdrops
+mean an empty genesis allocation.
+
+If the subnet configuration does not exist yet, it is created from the manifest. If it exists,
+the command reports any drift between the manifest and the stored configuration, and fails
+unless --force is given, in which case the configuration is recreated from the manifest.
+
+Afterwards, the subnet is deployed to each network listed on the manifest deploy section
+where it is not deployed yet. Running apply again with the same manifest is a no-op.`,
+		SilenceUsage:      true,
+		RunE:              applyManifest,
+		PersistentPostRun: handlePostRun,
+		Args:              cobra.ExactArgs(0),
+		Annotations:       ux.StructuredOutputAnnotations(),
+	}
+	cmd.Flags().StringVarP(&manifestFile, "file", "f", "", "subnet manifest file")
+	cmd.Flags().BoolVar(&forceApply, forceFlag, false, "recreate the subnet configuration if it differs from the manifest")
+	cmd.Flags().BoolVar(&onlyDiff, "diff", false, "only report differences between the manifest and the subnet configuration")
+	return cmd
+}
+
+func applyManifest(cmd *cobra.Command, _ []string) error {
+	if manifestFile == "" {
+		return errors.New("a manifest file must be given with --file")
+	}
+	m, err := manifest.Load(manifestFile)
+	if err != nil {
+		return err
+	}
+	if err := checkInvalidSubnetNames(m.Name); err != nil {
+		return fmt.Errorf("subnet name %q is invalid: %w", m.Name, err)
+	}
+	create := true
+	var prevSidecar models.Sidecar
+	if app.GenesisExists(m.Name) && app.SidecarExists(m.Name) {
+		drifts, err := getManifestDrift(m)
+		if err != nil {
+			return err
+		}
+		if err := printManifestDrift(m.Name, drifts); err != nil {
+			return err
+		}
+		if onlyDiff {
+			return nil
+		}
+		if len(drifts) > 0 && !forceApply {
+			return errManifestDrift
+		}
+		create = len(drifts) > 0
+		if create {
+			prevSidecar, err = app.LoadSidecar(m.Name)
+			if err != nil {
+				return err
+			}
+			if err := checkManifestSubnetNotPubliclyDeployed(prevSidecar); err != nil {
+				return err
+			}
+		}
+	} else if onlyDiff {
+		return fmt.Errorf("subnet %s does not exist", m.Name)
+	}
+	if create {
+		if err := createSubnetConfigFromManifest(m, prevSidecar); err != nil {
+			return err
+		}
+		ux.Logger.GreenCheckmarkToUser("Successfully created subnet configuration")
+	}
+	return deployManifest(cmd, m)
+}
+
+// checkManifestSubnetNotPubliclyDeployed fails if [sc] has a deployment on a
+// public network, as recreating its configuration would make apply deploy
+// a second subnet there
+func checkManifestSubnetNotPubliclyDeployed(sc models.Sidecar) error {
+	for networkName, networkData := range sc.Networks {
+		if networkName == models.NewLocalNetwork().Name() {
+			continue
+		}
+		if networkData.SubnetID != ids.Empty || networkData.BlockchainID != ids.Empty {
+			return fmt.Errorf("subnet %s is already deployed to %s, so its configuration can't be recreated from the manifest", sc.Name, networkName)
+		}
+	}
+	return nil
+}
+
+// createSubnetConfigFromManifest creates the subnet genesis and sidecar from [m].
+// Deploy information of [prevSidecar], if any, is kept
+func createSubnetConfigFromManifest(m *manifest.SubnetManifest, prevSidecar models.Sidecar) error {
+	genesisBytes, sc, err := vm.CreateEvmSubnetConfig(
+		app,
+		m.Name,
+		"",
+		m.VM.Version,
+		true,
+		m.ChainID,
+		m.Token,
+		true,
+		m.Teleporter,
+	)
+	if err != nil {
+		return err
+	}
+	genesis, err := app.LoadEvmGenesisFromJSON(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if err := m.ApplyToEvmGenesis(&genesis, nil); err != nil {
+		return err
+	}
+	genesisBytes, err = genesis.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	genesisBytes, err = indentJSON(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if m.Teleporter {
+		genesisBytes, err = setupTeleporterReadySubnet(genesisBytes, sc)
+		if err != nil {
+			return err
+		}
+	}
+	sc.TokenName = m.Token
+	if err := app.WriteGenesisFile(m.Name, genesisBytes); err != nil {
+		return err
+	}
+	sc.ImportedFromAPM = false
+	sc.Networks = prevSidecar.Networks
+	sc.ElasticSubnet = prevSidecar.ElasticSubnet
+	sc.SubnetEVMMainnetChainID = prevSidecar.SubnetEVMMainnetChainID
+	if _, ok := sc.Networks[models.NewLocalNetwork().Name()]; ok {
+		ux.Logger.PrintToUser("Local deployment of subnet %s keeps using the previous configuration until avalanche network clean is called", m.Name)
+	}
+	return app.CreateSidecar(sc)
+}
+
+func indentJSON(bs []byte) ([]byte, error) {
+	var generic interface{}
+	if err := json.Unmarshal(bs, &generic); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(generic, "", "    ")
+}
+
+func getManifestDrift(m *manifest.SubnetManifest) ([]manifest.Drift, error) {
+	sc, err := app.LoadSidecar(m.Name)
+	if err != nil {
+		return nil, err
+	}
+	if sc.VM != models.SubnetEvm {
+		return []manifest.Drift{{Field: "vm.type", Manifest: m.VM.Type, Stored: string(sc.VM)}}, nil
+	}
+	genesis, err := app.LoadEvmGenesis(m.Name)
+	if err != nil {
+		return nil, err
+	}
+	ignoreAlloc := []common.Address{}
+	if sc.TeleporterReady && sc.TeleporterKey != "" {
+		keyPath := app.GetKeyPath(sc.TeleporterKey)
+		if utils.FileExists(keyPath) {
+			k, err := key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			ignoreAlloc = append(ignoreAlloc, common.HexToAddress(k.C()))
+		}
+	}
+	return m.ComputeDrift(sc, genesis, ignoreAlloc), nil
+}
+
+func printManifestDrift(subnetName string, drifts []manifest.Drift) error {
+	if ux.IsStructuredOutput() {
+		return ux.PrintStructured("SubnetManifestDrift", drifts)
+	}
+	if len(drifts) == 0 {
+		ux.Logger.PrintToUser("Subnet %s configuration is up to date with the manifest", subnetName)
+		return nil
+	}
+	ux.Logger.PrintToUser("Subnet %s configuration differs from the manifest:", subnetName)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Field", "Manifest", "Stored"})
+	table.SetRowLine(true)
+	for _, drift := range drifts {
+		table.Append([]string{drift.Field, drift.Manifest, drift.Stored})
+	}
+	table.Render()
+	return nil
+}
+
+// deployManifest deploys the subnet to every manifest network where
+// it is not yet deployed, setting the deploy flags so that no prompt is needed
+func deployManifest(cmd *cobra.Command, m *manifest.SubnetManifest) error {
+	networksFlags := []networkoptions.NetworkFlags{}
+	for _, network := range m.Deploy.Networks {
+		switch network {
+		case manifest.LocalNetwork:
+			networksFlags = append(networksFlags, networkoptions.NetworkFlags{UseLocal: true})
+		case manifest.DevnetNetwork:
+			networksFlags = append(networksFlags, networkoptions.NetworkFlags{UseDevnet: true, Endpoint: m.Deploy.Endpoint})
+		case manifest.FujiNetwork:
+			networksFlags = append(networksFlags, networkoptions.NetworkFlags{UseFuji: true})
+		case manifest.MainnetNetwork:
+			networksFlags = append(networksFlags, networkoptions.NetworkFlags{UseMainnet: true})
+		}
+	}
+	if m.Deploy.Cluster != "" {
+		networksFlags = append(networksFlags, networkoptions.NetworkFlags{ClusterName: m.Deploy.Cluster})
+	}
+	for _, networkFlags := range networksFlags {
+		network, err := networkoptions.GetNetworkFromCmdLineFlags(
+			app,
+			networkFlags,
+			true,
+			deploySupportedNetworkOptions,
+			"",
+		)
+		if err != nil {
+			return err
+		}
+		isDeployed, err := isManifestSubnetDeployed(m.Name, network)
+		if err != nil {
+			return err
+		}
+		if isDeployed {
+			ux.Logger.PrintToUser("Subnet %s is already deployed to %s", m.Name, network.Name())
+			continue
+		}
+		globalNetworkFlags = networkFlags
+		keyName, useLedger, err = getManifestKeySource(m, network)
+		if err != nil {
+			return err
+		}
+		useEwoq = false
+		ledgerAddresses = m.Deploy.LedgerAddrs
+		sameControlKey = m.Deploy.SameControlKey
+		controlKeys = m.Deploy.ControlKeys
+		threshold = m.Deploy.Threshold
+		if sameControlKey {
+			threshold = 1
+		}
+		subnetAuthKeys = m.Deploy.SubnetAuthKeys
+		// range checked on manifest validation
+		mainnetChainID = uint32(m.Deploy.MainnetChainID)
+		userProvidedAvagoVersion = latest
+		if m.Deploy.AvagoVersion != "" {
+			userProvidedAvagoVersion = m.Deploy.AvagoVersion
+		}
+		skipCreatePrompt = true
+		if err := deploySubnet(cmd, []string{m.Name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getManifestKeySource selects the fee paying key source for [network].
+// Devnets use the ewoq key, and mainnet always requires the ledger
+func getManifestKeySource(m *manifest.SubnetManifest, network models.Network) (string, bool, error) {
+	switch network.Kind {
+	case models.Local, models.Devnet:
+		return "", false, nil
+	case models.Mainnet:
+		return "", true, nil
+	default:
+		if m.Deploy.Key == "" && !m.Deploy.UsesLedger() {
+			return "", false, fmt.Errorf("manifest key or ledger is required to deploy to %s", network.Name())
+		}
+		return m.Deploy.Key, m.Deploy.UsesLedger(), nil
+	}
+}
+
+func isManifestSubnetDeployed(subnetName string, network models.Network) (bool, error) {
+	if network.Kind == models.Local {
+		deployedNames, err := subnet.GetLocallyDeployedSubnets()
+		if err != nil {
+			// local network not running, so nothing is deployed there
+			return false, nil
+		}
+		_, ok := deployedNames[subnetName]
+		return ok, nil
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return false, err
+	}
+	return sc.Networks[network.Name()].BlockchainID != ids.Empty, nil
+}
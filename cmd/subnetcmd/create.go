@@ -251,38 +251,10 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 
 	if teleporterReady {
 		if isSubnetEVMGenesis := jsonIsSubnetEVMGenesis(genesisBytes); isSubnetEVMGenesis {
-			keyPath := app.GetKeyPath(constants.TeleporterKeyName)
-			var k *key.SoftKey
-			if utils.FileExists(keyPath) {
-				ux.Logger.PrintToUser("loading stored key %q for teleporter deploys", constants.TeleporterKeyName)
-				k, err = key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
-				if err != nil {
-					return err
-				}
-			} else {
-				ux.Logger.PrintToUser("generating stored key %q for teleporter deploys", constants.TeleporterKeyName)
-				k, err = key.NewSoft(0)
-				if err != nil {
-					return err
-				}
-				if err := k.Save(keyPath); err != nil {
-					return err
-				}
-			}
-			ux.Logger.PrintToUser("  (evm address, genesis balance) = (%s, %v)", k.C(), teleporter.TeleporterPrefundedAddressBalance)
-			genesisBytes, err = addSubnetEVMGenesisPrefundedAddress(genesisBytes, k.C(), teleporter.TeleporterPrefundedAddressBalance.String())
-			if err != nil {
-				return err
-			}
-			// let's use latest versions for teleporter contract
-			teleporterVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.TeleporterRepoName))
+			genesisBytes, err = setupTeleporterReadySubnet(genesisBytes, sc)
 			if err != nil {
 				return err
 			}
-			ux.Logger.PrintToUser("using latest teleporter version (%s)", teleporterVersion)
-			sc.TeleporterReady = true
-			sc.TeleporterKey = constants.TeleporterKeyName
-			sc.TeleporterVersion = teleporterVersion
 		}
 	}
 
@@ -304,6 +276,49 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadOrCreateTeleporterKey loads the stored key used for teleporter deploys,
+// generating it if it does not exist yet
+func loadOrCreateTeleporterKey() (*key.SoftKey, error) {
+	keyPath := app.GetKeyPath(constants.TeleporterKeyName)
+	if utils.FileExists(keyPath) {
+		ux.Logger.PrintToUser("loading stored key %q for teleporter deploys", constants.TeleporterKeyName)
+		return key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+	}
+	ux.Logger.PrintToUser("generating stored key %q for teleporter deploys", constants.TeleporterKeyName)
+	k, err := key.NewSoft(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.Save(keyPath); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// setupTeleporterReadySubnet prefunds the teleporter key on the subnet evm
+// [genesisBytes], and sets the teleporter fields of [sc]
+func setupTeleporterReadySubnet(genesisBytes []byte, sc *models.Sidecar) ([]byte, error) {
+	k, err := loadOrCreateTeleporterKey()
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("  (evm address, genesis balance) = (%s, %v)", k.C(), teleporter.TeleporterPrefundedAddressBalance)
+	genesisBytes, err = addSubnetEVMGenesisPrefundedAddress(genesisBytes, k.C(), teleporter.TeleporterPrefundedAddressBalance.String())
+	if err != nil {
+		return nil, err
+	}
+	// let's use latest versions for teleporter contract
+	teleporterVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.TeleporterRepoName))
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("using latest teleporter version (%s)", teleporterVersion)
+	sc.TeleporterReady = true
+	sc.TeleporterKey = constants.TeleporterKeyName
+	sc.TeleporterVersion = teleporterVersion
+	return genesisBytes, nil
+}
+
 func addSubnetEVMGenesisPrefundedAddress(genesisBytes []byte, address string, balance string) ([]byte, error) {
 	var genesisMap map[string]interface{}
 	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/commontype"
 	"github.com/ava-labs/subnet-evm/core"
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
@@ -31,6 +32,35 @@ import (
 
 var printGenesisOnly bool
 
+// subnetNetworkDescription is the structured output of subnet describe
+// for a network the subnet is deployed to
+type subnetNetworkDescription struct {
+	SubnetID                   string `json:"subnetID,omitempty"`
+	BlockchainID               string `json:"blockchainID,omitempty"`
+	BlockchainIDHex            string `json:"blockchainIDHex,omitempty"`
+	TeleporterMessengerAddress string `json:"teleporterMessengerAddress,omitempty"`
+	TeleporterRegistryAddress  string `json:"teleporterRegistryAddress,omitempty"`
+}
+
+// subnetDescription is the structured output of subnet describe
+type subnetDescription struct {
+	Subnet            string                              `json:"subnet"`
+	Chain             string                              `json:"chain"`
+	VM                string                              `json:"vm"`
+	VMVersion         string                              `json:"vmVersion"`
+	RPCVersion        int                                 `json:"rpcVersion"`
+	VMID              string                              `json:"vmID"`
+	TokenName         string                              `json:"tokenName"`
+	ChainID           string                              `json:"chainID,omitempty"`
+	MainnetChainID    uint                                `json:"mainnetChainID,omitempty"`
+	TeleporterReady   bool                                `json:"teleporterReady"`
+	TeleporterVersion string                              `json:"teleporterVersion,omitempty"`
+	Networks          map[string]subnetNetworkDescription `json:"networks"`
+	FeeConfig         *commontype.FeeConfig               `json:"feeConfig,omitempty"`
+	Airdrops          map[string]string                   `json:"airdrops,omitempty"`
+	Precompiles       params.Precompiles                  `json:"precompiles,omitempty"`
+}
+
 // avalanche subnet describe
 func newDescribeCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -39,8 +69,9 @@ func newDescribeCmd() *cobra.Command {
 		Long: `The subnet describe command prints the details of a Subnet configuration to the console.
 By default, the command prints a summary of the configuration. By providing the --genesis
 flag, the command instead prints out the raw genesis file.`,
-		RunE: readGenesis,
-		Args: cobra.ExactArgs(1),
+		RunE:        readGenesis,
+		Args:        cobra.ExactArgs(1),
+		Annotations: ux.StructuredOutputAnnotations(),
 	}
 	cmd.Flags().BoolVarP(
 		&printGenesisOnly,
@@ -87,16 +118,7 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) {
 	table.Append([]string{"Mainnet ChainID", fmt.Sprint(sc.SubnetEVMMainnetChainID)})
 	table.Append([]string{"Token Name", app.GetTokenName(sc.Subnet)})
 	table.Append([]string{"VM Version", sc.VMVersion})
-	if sc.ImportedVMID != "" {
-		table.Append([]string{"VM ID", sc.ImportedVMID})
-	} else {
-		id := constants.NotAvailableLabel
-		vmID, err := utils.VMID(sc.Name)
-		if err == nil {
-			id = vmID.String()
-		}
-		table.Append([]string{"VM ID", id})
-	}
+	table.Append([]string{"VM ID", getDescribeVMID(sc)})
 
 	for net, data := range sc.Networks {
 		if data.SubnetID != ids.Empty {
@@ -117,6 +139,71 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) {
 	table.Render()
 }
 
+func getDescribeVMID(sc models.Sidecar) string {
+	if sc.ImportedVMID != "" {
+		return sc.ImportedVMID
+	}
+	vmID, err := utils.VMID(sc.Name)
+	if err != nil {
+		return constants.NotAvailableLabel
+	}
+	return vmID.String()
+}
+
+// buildSubnetDescription gathers the sidecar information and, if given,
+// the most relevant parts of the subnet evm genesis
+func buildSubnetDescription(sc models.Sidecar, genesis *core.Genesis) subnetDescription {
+	desc := subnetDescription{
+		Subnet:            sc.Subnet,
+		Chain:             sc.Name,
+		VM:                string(sc.VM),
+		VMVersion:         sc.VMVersion,
+		RPCVersion:        sc.RPCVersion,
+		VMID:              getDescribeVMID(sc),
+		TokenName:         sc.TokenName,
+		MainnetChainID:    sc.SubnetEVMMainnetChainID,
+		TeleporterReady:   sc.TeleporterReady,
+		TeleporterVersion: sc.TeleporterVersion,
+		Networks:          buildSubnetNetworkDescriptions(sc.Networks),
+	}
+	if genesis != nil && genesis.Config != nil {
+		if genesis.Config.ChainID != nil {
+			desc.ChainID = genesis.Config.ChainID.String()
+		}
+		feeConfig := genesis.Config.FeeConfig
+		desc.FeeConfig = &feeConfig
+		desc.Airdrops = map[string]string{}
+		for address, account := range genesis.Alloc {
+			if account.Balance != nil {
+				desc.Airdrops[address.Hex()] = account.Balance.String()
+			}
+		}
+		desc.Precompiles = genesis.Config.GenesisPrecompiles
+	}
+	return desc
+}
+
+// buildSubnetNetworkDescriptions gives the structured output of the
+// sidecar deploy information for each network
+func buildSubnetNetworkDescriptions(networks map[string]models.NetworkData) map[string]subnetNetworkDescription {
+	descs := map[string]subnetNetworkDescription{}
+	for net, data := range networks {
+		desc := subnetNetworkDescription{
+			TeleporterMessengerAddress: data.TeleporterMessengerAddress,
+			TeleporterRegistryAddress:  data.TeleporterRegistryAddress,
+		}
+		if data.SubnetID != ids.Empty {
+			desc.SubnetID = data.SubnetID.String()
+		}
+		if data.BlockchainID != ids.Empty {
+			desc.BlockchainID = data.BlockchainID.String()
+			desc.BlockchainIDHex = "0x" + hex.EncodeToString(data.BlockchainID[:])
+		}
+		descs[net] = desc
+	}
+	return descs
+}
+
 func printGasTable(genesis core.Genesis) {
 	// Generated here with BIG font
 	// https://patorjk.com/software/taag/#p=display&f=Big&t=Precompiles
@@ -290,6 +377,9 @@ func describeSubnetEvmGenesis(sc models.Sidecar) error {
 func readGenesis(_ *cobra.Command, args []string) error {
 	subnetName := args[0]
 	if !app.GenesisExists(subnetName) {
+		if ux.IsStructuredOutput() {
+			return fmt.Errorf("the provided subnet name %q does not exist", subnetName)
+		}
 		ux.Logger.PrintToUser("The provided subnet name %q does not exist", subnetName)
 		return nil
 	}
@@ -305,6 +395,17 @@ func readGenesis(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if ux.IsStructuredOutput() {
+		var genesis *core.Genesis
+		if isEVM {
+			evmGenesis, err := app.LoadEvmGenesis(subnetName)
+			if err != nil {
+				return err
+			}
+			genesis = &evmGenesis
+		}
+		return ux.PrintStructured("SubnetDescription", buildSubnetDescription(sc, genesis))
+	}
 	if isEVM {
 		return describeSubnetEvmGenesis(sc)
 	}
@@ -0,0 +1,96 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+func TestSubnetDescriptionSchema(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	blockchainID := ids.GenerateTestID()
+	sc := models.Sidecar{
+		Name:              "mysubnet",
+		Subnet:            "mysubnet",
+		VM:                models.SubnetEvm,
+		VMVersion:         "v0.6.1",
+		RPCVersion:        33,
+		TokenName:         "TST",
+		TeleporterReady:   true,
+		TeleporterVersion: "v1.0.0",
+		Networks: map[string]models.NetworkData{
+			models.NewFujiNetwork().Name(): {
+				SubnetID:     subnetID,
+				BlockchainID: blockchainID,
+			},
+		},
+	}
+	conf := *params.SubnetEVMDefaultChainConfig
+	conf.ChainID = big.NewInt(1234)
+	conf.FeeConfig = vm.StarterFeeConfig
+	address := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	genesis := core.Genesis{
+		Config: &conf,
+		Alloc: core.GenesisAlloc{
+			address: {Balance: big.NewInt(100)},
+		},
+	}
+
+	desc := buildSubnetDescription(sc, &genesis)
+	require.Equal("1234", desc.ChainID)
+	require.Equal(map[string]string{address.Hex(): "100"}, desc.Airdrops)
+	require.Equal(subnetNetworkDescription{
+		SubnetID:        subnetID.String(),
+		BlockchainID:    blockchainID.String(),
+		BlockchainIDHex: "0x" + hex.EncodeToString(blockchainID[:]),
+	}, desc.Networks[models.NewFujiNetwork().Name()])
+
+	bs, err := json.Marshal(desc)
+	require.NoError(err)
+	var generic map[string]interface{}
+	require.NoError(json.Unmarshal(bs, &generic))
+	require.ElementsMatch([]string{
+		"subnet",
+		"chain",
+		"vm",
+		"vmVersion",
+		"rpcVersion",
+		"vmID",
+		"tokenName",
+		"chainID",
+		"teleporterReady",
+		"teleporterVersion",
+		"networks",
+		"feeConfig",
+		"airdrops",
+	}, maps.Keys(generic))
+	networks := generic["networks"].(map[string]interface{})
+	require.ElementsMatch([]string{
+		"subnetID",
+		"blockchainID",
+		"blockchainIDHex",
+	}, maps.Keys(networks[models.NewFujiNetwork().Name()].(map[string]interface{})))
+
+	// without genesis, only the sidecar information is given
+	desc = buildSubnetDescription(sc, nil)
+	bs, err = json.Marshal(desc)
+	require.NoError(err)
+	generic = map[string]interface{}{}
+	require.NoError(json.Unmarshal(bs, &generic))
+	require.NotContains(generic, "chainID")
+	require.NotContains(generic, "feeConfig")
+	require.NotContains(generic, "airdrops")
+}
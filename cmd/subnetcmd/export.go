@@ -41,7 +41,7 @@ the --output flag.`,
 		"output",
 		"o",
 		"",
-		"write the export data to the provided file path",
+		"write the export data to the provided file path (this flag shadows the global --output format flag)",
 	)
 	cmd.Flags().StringVar(&customVMRepoURL, "custom-vm-repo-url", "", "custom vm repository url")
 	cmd.Flags().StringVar(&customVMBranch, "custom-vm-branch", "", "custom vm branch")
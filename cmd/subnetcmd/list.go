@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/utils"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/olekukonko/tablewriter"
@@ -21,6 +22,26 @@ import (
 
 var deployed bool
 
+// subnetListEntry is the structured output of subnet list for a subnet
+type subnetListEntry struct {
+	Subnet    string `json:"subnet"`
+	Chain     string `json:"chain"`
+	ChainID   string `json:"chainID"`
+	VMID      string `json:"vmID"`
+	Type      string `json:"type"`
+	VMVersion string `json:"vmVersion"`
+	FromRepo  bool   `json:"fromRepo"`
+}
+
+// subnetDeployInfoEntry is the structured output of subnet list --deployed for a subnet
+type subnetDeployInfoEntry struct {
+	Subnet          string                              `json:"subnet"`
+	Chain           string                              `json:"chain"`
+	VMID            string                              `json:"vmID"`
+	DeployedLocally bool                                `json:"deployedLocally"`
+	Networks        map[string]subnetNetworkDescription `json:"networks"`
+}
+
 // avalanche subnet list
 func newListCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -31,6 +52,7 @@ it prints some general, static information about the Subnet. With the --deployed
 shows additional information including the VMID, BlockchainID and SubnetID.`,
 		RunE:         listSubnets,
 		SilenceUsage: true,
+		Annotations:  ux.StructuredOutputAnnotations(),
 	}
 	cmd.Flags().BoolVar(&deployed, "deployed", false, "show additional deploy information")
 	return cmd
@@ -63,6 +85,7 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 	table.SetRowLine(true)
 
 	rows := subnetMatrix{}
+	entries := []subnetListEntry{}
 
 	cars, err := getSidecars(app)
 	if err != nil {
@@ -98,6 +121,19 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 			sc.VMVersion,
 			strconv.FormatBool(sc.ImportedFromAPM),
 		})
+		entries = append(entries, subnetListEntry{
+			Subnet:    sc.Subnet,
+			Chain:     sc.Name,
+			ChainID:   chainID,
+			VMID:      vmID,
+			Type:      string(sc.VM),
+			VMVersion: sc.VMVersion,
+			FromRepo:  sc.ImportedFromAPM,
+		})
+	}
+	if ux.IsStructuredOutput() {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Subnet < entries[j].Subnet })
+		return ux.PrintStructured("SubnetList", entries)
 	}
 	sort.Sort(rows)
 	for _, row := range rows {
@@ -148,6 +184,7 @@ func listDeployInfo(*cobra.Command, []string) error {
 	table.SetRowLine(true)
 
 	rows := subnetMatrix{}
+	entries := []subnetDeployInfoEntry{}
 
 	deployedNames, err := subnet.GetLocallyDeployedSubnets()
 	if err != nil {
@@ -203,6 +240,14 @@ func listDeployInfo(*cobra.Command, []string) error {
 			}
 		}
 
+		entries = append(entries, subnetDeployInfoEntry{
+			Subnet:          sc.Subnet,
+			Chain:           sc.Name,
+			VMID:            vmID,
+			DeployedLocally: deployedLocal == constants.YesLabel,
+			Networks:        buildSubnetNetworkDescriptions(sc.Networks),
+		})
+
 		rows = append(rows, []string{
 			sc.Subnet,
 			sc.Name,
@@ -224,6 +269,11 @@ func listDeployInfo(*cobra.Command, []string) error {
 		}
 	}
 
+	if ux.IsStructuredOutput() {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Subnet < entries[j].Subnet })
+		return ux.PrintStructured("SubnetDeployInfoList", entries)
+	}
+
 	sort.Sort(rows)
 	for _, row := range rows {
 		table.Append(row)
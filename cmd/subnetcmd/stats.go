@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 )
 
 var statsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Fuji, networkoptions.Mainnet}
@@ -33,11 +35,43 @@ func newStatsCmd() *cobra.Command {
 		Args:         cobra.ExactArgs(1),
 		RunE:         stats,
 		SilenceUsage: true,
+		Annotations:  ux.StructuredOutputAnnotations(),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, statsSupportedNetworkOptions)
 	return cmd
 }
 
+// currentValidatorStats is the structured output of subnet stats for a current validator
+// VMVersions is only available for the local node
+type currentValidatorStats struct {
+	NodeID     string            `json:"nodeID"`
+	Connected  *bool             `json:"connected"`
+	Weight     uint64            `json:"weight"`
+	StartTime  int64             `json:"startTime"`
+	EndTime    int64             `json:"endTime"`
+	Remaining  string            `json:"remaining"`
+	VMVersions map[string]string `json:"vmVersions,omitempty"`
+}
+
+// pendingValidatorStats is the structured output of subnet stats for a pending validator
+// VMVersions is only available for the local node
+type pendingValidatorStats struct {
+	NodeID     string            `json:"nodeID"`
+	Weight     uint64            `json:"weight"`
+	StartTime  int64             `json:"startTime"`
+	EndTime    int64             `json:"endTime"`
+	VMVersions map[string]string `json:"vmVersions,omitempty"`
+}
+
+// subnetStats is the structured output of subnet stats
+type subnetStats struct {
+	Subnet            string                  `json:"subnet"`
+	SubnetID          string                  `json:"subnetID"`
+	Network           string                  `json:"network"`
+	CurrentValidators []currentValidatorStats `json:"currentValidators"`
+	PendingValidators []pendingValidatorStats `json:"pendingValidators"`
+}
+
 func stats(_ *cobra.Command, args []string) error {
 	network, err := networkoptions.GetNetworkFromCmdLineFlags(
 		app,
@@ -71,6 +105,24 @@ func stats(_ *cobra.Command, args []string) error {
 		return errors.New("failed to create a client to an API endpoint")
 	}
 
+	if ux.IsStructuredOutput() {
+		current, err := getCurrentValidatorStats(pClient, infoClient, subnetID)
+		if err != nil {
+			return err
+		}
+		pending, err := getPendingValidatorStats(pClient, infoClient, subnetID)
+		if err != nil {
+			return err
+		}
+		return ux.PrintStructured("SubnetStats", subnetStats{
+			Subnet:            subnetName,
+			SubnetID:          subnetID.String(),
+			Network:           network.Name(),
+			CurrentValidators: current,
+			PendingValidators: pending,
+		})
+	}
+
 	table := tablewriter.NewWriter(os.Stdout)
 	rows, err := buildCurrentValidatorStats(pClient, infoClient, table, subnetID)
 	if err != nil {
@@ -97,7 +149,33 @@ func stats(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func buildPendingValidatorStats(pClient platformvm.Client, infoClient info.Client, table *tablewriter.Table, subnetID ids.ID) ([][]string, error) {
+// getLocalVMVersions tries querying the local node for its node ID and vm versions
+func getLocalVMVersions(ctx context.Context, infoClient info.Client) (ids.NodeID, map[string]string) {
+	var (
+		localNodeID     ids.NodeID
+		localVMVersions map[string]string
+	)
+	reply, err := infoClient.GetNodeVersion(ctx)
+	if err == nil {
+		// we can ignore err here; if it worked, we have a non-zero node ID
+		localNodeID, _, _ = infoClient.GetNodeID(ctx)
+		localVMVersions = reply.VMVersions
+	}
+	return localNodeID, localVMVersions
+}
+
+// formatVMVersions renders one "vm: version" line per vm, sorted by vm
+func formatVMVersions(vmVersions map[string]string) string {
+	vmNames := maps.Keys(vmVersions)
+	sort.Strings(vmNames)
+	versionStr := ""
+	for _, vmName := range vmNames {
+		versionStr += fmt.Sprintf("%s: %s\n", vmName, vmVersions[vmName])
+	}
+	return versionStr
+}
+
+func getPendingValidatorStats(pClient platformvm.Client, infoClient info.Client, subnetID ids.ID) ([]pendingValidatorStats, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -123,9 +201,47 @@ func buildPendingValidatorStats(pClient platformvm.Client, infoClient info.Clien
 		}
 	}
 
+	validatorStats := []pendingValidatorStats{}
+	if len(pendingValidators) == 0 {
+		return validatorStats, nil
+	}
+
+	localNodeID, localVMVersions := getLocalVMVersions(ctx, infoClient)
+
+	for _, v := range pendingValidators {
+		uint64Weight := uint64(v.Weight)
+		for _, d := range pendingDelegators {
+			uint64Weight += uint64(d.Weight)
+		}
+
+		var vmVersions map[string]string
+		// if retrieval of localNodeID failed, it will be empty,
+		// and this comparison fails
+		if v.NodeID == localNodeID {
+			vmVersions = localVMVersions
+		}
+		// query peers for IP address of this NodeID...
+		validatorStats = append(validatorStats, pendingValidatorStats{
+			NodeID:     v.NodeID.String(),
+			Weight:     uint64Weight,
+			StartTime:  int64(v.StartTime),
+			EndTime:    int64(v.EndTime),
+			VMVersions: vmVersions,
+		})
+	}
+
+	return validatorStats, nil
+}
+
+func buildPendingValidatorStats(pClient platformvm.Client, infoClient info.Client, table *tablewriter.Table, subnetID ids.ID) ([][]string, error) {
+	validatorStats, err := getPendingValidatorStats(pClient, infoClient, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
 	rows := [][]string{}
 
-	if len(pendingValidators) == 0 {
+	if len(validatorStats) == 0 {
 		ux.Logger.PrintToUser("No pending validators found.")
 		return rows, nil
 	}
@@ -139,58 +255,72 @@ func buildPendingValidatorStats(pClient platformvm.Client, infoClient info.Clien
 	table.SetAutoMergeCells(true)
 	table.SetRowLine(true)
 
-	var (
-		startTime, endTime          time.Time
-		localNodeID                 ids.NodeID
-		weight                      string
-		localVersionStr, versionStr string
-	)
+	for _, v := range validatorStats {
+		rows = append(rows, []string{
+			v.NodeID,
+			strconv.FormatUint(v.Weight, 10),
+			time.Unix(v.StartTime, 0).Local().String(),
+			time.Unix(v.EndTime, 0).Local().String(),
+			formatVMVersions(v.VMVersions),
+		})
+	}
 
-	// try querying the local node for its node version
-	reply, err := infoClient.GetNodeVersion(ctx)
-	if err == nil {
-		// we can ignore err here; if it worked, we have a non-zero node ID
-		localNodeID, _, _ = infoClient.GetNodeID(ctx)
-		for k, v := range reply.VMVersions {
-			localVersionStr = fmt.Sprintf("%s: %s\n", k, v)
-		}
+	return rows, nil
+}
+
+func getCurrentValidatorStats(pClient platformvm.Client, infoClient info.Client, subnetID ids.ID) ([]currentValidatorStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	currValidators, err := pClient.GetCurrentValidators(ctx, subnetID, []ids.NodeID{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the API endpoint for the current validators: %w", err)
 	}
 
-	for _, v := range pendingValidators {
-		startTime = time.Unix(int64(v.StartTime), 0)
-		endTime = time.Unix(int64(v.EndTime), 0)
+	localNodeID, localVMVersions := getLocalVMVersions(ctx, infoClient)
+
+	validatorStats := []currentValidatorStats{}
+	now := time.Now()
+	for _, v := range currValidators {
+		startTime := time.Unix(int64(v.StartTime), 0)
+		endTime := time.Unix(int64(v.EndTime), 0)
 
 		uint64Weight := v.Weight
-		for _, d := range pendingDelegators {
+		delegators := v.Delegators
+		for _, d := range delegators {
 			uint64Weight += d.Weight
 		}
-		weight = strconv.FormatUint(uint64(uint64Weight), 10)
 
+		remaining := endTime.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		var vmVersions map[string]string
 		// if retrieval of localNodeID failed, it will be empty,
 		// and this comparison fails
 		if v.NodeID == localNodeID {
-			versionStr = localVersionStr
+			vmVersions = localVMVersions
 		}
 		// query peers for IP address of this NodeID...
-		rows = append(rows, []string{
-			v.NodeID.String(),
-			weight,
-			startTime.Local().String(),
-			endTime.Local().String(),
-			versionStr,
+		validatorStats = append(validatorStats, currentValidatorStats{
+			NodeID:     v.NodeID.String(),
+			Connected:  v.Connected,
+			Weight:     uint64Weight,
+			StartTime:  startTime.Unix(),
+			EndTime:    endTime.Unix(),
+			Remaining:  ux.FormatDuration(remaining),
+			VMVersions: vmVersions,
 		})
 	}
 
-	return rows, nil
+	return validatorStats, nil
 }
 
 func buildCurrentValidatorStats(pClient platformvm.Client, infoClient info.Client, table *tablewriter.Table, subnetID ids.ID) ([][]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	currValidators, err := pClient.GetCurrentValidators(ctx, subnetID, []ids.NodeID{})
+	validatorStats, err := getCurrentValidatorStats(pClient, infoClient, subnetID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query the API endpoint for the current validators: %w", err)
+		return nil, err
 	}
 
 	ux.Logger.PrintToUser("Current validators (already validating the subnet)")
@@ -203,55 +333,19 @@ func buildCurrentValidatorStats(pClient platformvm.Client, infoClient info.Clien
 	table.SetRowLine(true)
 	rows := [][]string{}
 
-	var (
-		startTime, endTime           time.Time
-		localNodeID                  ids.NodeID
-		remaining, connected, weight string
-		localVersionStr, versionStr  string
-	)
-
-	// try querying the local node for its node version
-	reply, err := infoClient.GetNodeVersion(ctx)
-	if err == nil {
-		// we can ignore err here; if it worked, we have a non-zero node ID
-		localNodeID, _, _ = infoClient.GetNodeID(ctx)
-		for k, v := range reply.VMVersions {
-			localVersionStr = fmt.Sprintf("%s: %s\n", k, v)
-		}
-	}
-
-	for _, v := range currValidators {
-		startTime = time.Unix(int64(v.StartTime), 0)
-		endTime = time.Unix(int64(v.EndTime), 0)
-		remaining = ux.FormatDuration(endTime.Sub(startTime))
-
+	for _, v := range validatorStats {
 		// some members of the returned object are pointers
 		// so we need to check the pointer is actually valid
+		connected := constants.NotAvailableLabel
 		if v.Connected != nil {
 			connected = strconv.FormatBool(*v.Connected)
-		} else {
-			connected = constants.NotAvailableLabel
-		}
-
-		uint64Weight := v.Weight
-		delegators := v.Delegators
-		for _, d := range delegators {
-			uint64Weight += d.Weight
 		}
-		weight = strconv.FormatUint(uint64Weight, 10)
-
-		// if retrieval of localNodeID failed, it will be empty,
-		// and this comparison fails
-		if v.NodeID == localNodeID {
-			versionStr = localVersionStr
-		}
-		// query peers for IP address of this NodeID...
 		rows = append(rows, []string{
-			v.NodeID.String(),
+			v.NodeID,
 			connected,
-			weight,
-			remaining,
-			versionStr,
+			strconv.FormatUint(v.Weight, 10),
+			v.Remaining,
+			formatVMVersions(v.VMVersions),
 		})
 	}
 
@@ -3,6 +3,7 @@
 package subnetcmd
 
 import (
+	encjson "encoding/json"
 	"io"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 )
 
 func TestStats(t *testing.T) {
@@ -36,7 +38,8 @@ func TestStats(t *testing.T) {
 	weight := uint64(42)
 	conn := true
 
-	remaining := ux.FormatDuration(endTime.Sub(startTime))
+	// remaining time is computed from now, and validation already ended
+	remaining := ux.FormatDuration(0)
 
 	reply := []platformvm.ClientPermissionlessValidator{
 		{
@@ -108,3 +111,70 @@ func TestStats(t *testing.T) {
 	require.Equal(controlEndTime.Local().String(), rows[0][3])
 	require.Equal(expectedVerStr, rows[0][4])
 }
+
+func TestCurrentValidatorStatsSchema(t *testing.T) {
+	require := require.New(t)
+
+	pClient := &mocks.PClient{}
+	iClient := &mocks.InfoClient{}
+
+	localNodeID := ids.GenerateTestNodeID()
+	otherNodeID := ids.GenerateTestNodeID()
+	subnetID := ids.GenerateTestID()
+
+	startTime := time.Now().Add(-24 * time.Hour)
+	endTime := time.Now().Add(48 * time.Hour)
+	conn := true
+	reply := []platformvm.ClientPermissionlessValidator{}
+	for _, nodeID := range []ids.NodeID{localNodeID, otherNodeID} {
+		reply = append(reply, platformvm.ClientPermissionlessValidator{
+			ClientStaker: platformvm.ClientStaker{
+				StartTime: uint64(startTime.Unix()),
+				EndTime:   uint64(endTime.Unix()),
+				NodeID:    nodeID,
+				Weight:    42,
+			},
+			Connected: &conn,
+		})
+	}
+	vmVersions := map[string]string{
+		subnetID.String(): "0.1.23",
+		"avm":             "v1.11.1",
+	}
+	pClient.On("GetCurrentValidators", mock.Anything, mock.Anything, mock.Anything).Return(reply, nil)
+	iClient.On("GetNodeID", mock.Anything).Return(localNodeID, nil, nil)
+	iClient.On("GetNodeVersion", mock.Anything).Return(&info.GetNodeVersionReply{
+		VMVersions: vmVersions,
+	}, nil)
+
+	stats, err := getCurrentValidatorStats(pClient, iClient, subnetID)
+	require.NoError(err)
+	require.Len(stats, 2)
+
+	// vm versions are only known for the local node
+	require.Equal(vmVersions, stats[0].VMVersions)
+	require.Nil(stats[1].VMVersions)
+	expectedVerStr := "avm: v1.11.1\n" + subnetID.String() + ": 0.1.23\n"
+	if subnetID.String() < "avm" {
+		expectedVerStr = subnetID.String() + ": 0.1.23\navm: v1.11.1\n"
+	}
+	require.Equal(expectedVerStr, formatVMVersions(stats[0].VMVersions))
+
+	// remaining time goes from now to the end time, not from the start time
+	require.Contains(stats[0].Remaining, "1 days 23 hours")
+
+	bs, err := encjson.Marshal(stats)
+	require.NoError(err)
+	var generic []map[string]interface{}
+	require.NoError(encjson.Unmarshal(bs, &generic))
+	require.ElementsMatch([]string{
+		"nodeID",
+		"connected",
+		"weight",
+		"startTime",
+		"endTime",
+		"remaining",
+		"vmVersions",
+	}, maps.Keys(generic[0]))
+	require.NotContains(generic[1], "vmVersions")
+}
@@ -34,6 +34,8 @@ manage your Subnet configurations and live deployments.`,
 	app = injectedApp
 	// subnet create
 	cmd.AddCommand(newCreateCmd())
+	// subnet apply
+	cmd.AddCommand(newApplyCmd())
 	// subnet delete
 	cmd.AddCommand(newDeleteCmd())
 	// subnet deploy
@@ -144,41 +144,13 @@ func applyLocalNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar)
 		return err
 	}
 
-	// first let's get the status
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-	status, err := cli.Status(ctx)
+	blockchainID, err := getLocallyDeployedBlockchainID(cli, networkKey, sc)
 	if err != nil {
-		if server.IsServerError(err, server.ErrNotBootstrapped) {
-			ux.Logger.PrintToUser(ErrNetworkNotStartedOutput)
-			return err
-		}
 		return err
 	}
 
-	// confirm in the status that the subnet actually is deployed and running
-	deployed := false
-	subnets := status.ClusterInfo.GetSubnets()
-	for s := range subnets {
-		if s == sc.Networks[networkKey].SubnetID.String() {
-			deployed = true
-			break
-		}
-	}
-
-	if !deployed {
-		return subnetNotYetDeployed()
-	}
-
-	// get the blockchainID from the sidecar
-	blockchainID := sc.Networks[networkKey].BlockchainID
-	if blockchainID == ids.Empty {
-		return errors.New(
-			"failed to find deployment information about this subnet in state - aborting")
-	}
-
 	// into ANR network ops
-	ctx, cancel = utils.GetANRContext()
+	ctx, cancel := utils.GetANRContext()
 	defer cancel()
 
 	// save a temporary snapshot
@@ -224,6 +196,44 @@ func applyLocalNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar)
 	return errors.New("unexpected network size of zero nodes")
 }
 
+// getLocallyDeployedBlockchainID checks that the subnet is deployed and running on the
+// local network, and returns its blockchain ID
+func getLocallyDeployedBlockchainID(cli ANRclient.Client, networkKey string, sc *models.Sidecar) (ids.ID, error) {
+	// first let's get the status
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			ux.Logger.PrintToUser(ErrNetworkNotStartedOutput)
+			return ids.Empty, err
+		}
+		return ids.Empty, err
+	}
+
+	// confirm in the status that the subnet actually is deployed and running
+	deployed := false
+	subnets := status.ClusterInfo.GetSubnets()
+	for s := range subnets {
+		if s == sc.Networks[networkKey].SubnetID.String() {
+			deployed = true
+			break
+		}
+	}
+
+	if !deployed {
+		return ids.Empty, subnetNotYetDeployed()
+	}
+
+	// get the blockchainID from the sidecar
+	blockchainID := sc.Networks[networkKey].BlockchainID
+	if blockchainID == ids.Empty {
+		return ids.Empty, errors.New(
+			"failed to find deployment information about this subnet in state - aborting")
+	}
+	return blockchainID, nil
+}
+
 // applyPublicNetworkUpgrade applies an upgrade file to a locally running validator
 // for public networks (fuji, main)
 // the validation of the upgrade file has many things to consider:
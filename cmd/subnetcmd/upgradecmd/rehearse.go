@@ -0,0 +1,576 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	ANRclient "github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	rehearsalSnapshotInfix = "-rehearsal-"
+	// time given to the network to restart before the first rehearsed activation
+	rehearsalStartDelay    = 2 * time.Minute
+	activationPollInterval = 2 * time.Second
+)
+
+var (
+	rehearsalChecksPath       string
+	rehearsalInterval         time.Duration
+	rehearsalActivationWait   time.Duration
+	rehearsalBlockProducerKey string
+
+	errRehearsalFailed = errors.New("upgrade rehearsal failed")
+)
+
+// rehearsalStep groups the upgrades that activate at the same timestamp
+type rehearsalStep struct {
+	OriginalTimestamp uint64
+	Timestamp         uint64
+	// precompile keys enabled and disabled at this step
+	Enabled  []string
+	Disabled []string
+	// number of state upgrades at this step
+	StateUpgrades int
+}
+
+// rehearsalCheck is a smoke check run after each activation. It is either a JSON-RPC
+// call to the subnet, or a command run through the shell
+type rehearsalCheck struct {
+	Name string `json:"name"`
+	RPC  *struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	} `json:"rpc,omitempty"`
+	// substring expected in the JSON encoded RPC result
+	Expect  string `json:"expect,omitempty"`
+	Command string `json:"command,omitempty"`
+	// working dir for the command
+	Dir string `json:"dir,omitempty"`
+	// steps (starting at 1) after which to run the check. All of them if empty
+	Steps []int `json:"steps,omitempty"`
+}
+
+type rehearsalChecks struct {
+	Checks []rehearsalCheck `json:"checks"`
+}
+
+type rehearsalResult struct {
+	step         rehearsalStep
+	activated    bool
+	failedChecks []string
+	numChecks    int
+}
+
+// avalanche subnet upgrade rehearse
+func newUpgradeRehearseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rehearse [subnetName]",
+		Short: "Rehearse the upcoming upgrades of a subnet on the local network",
+		Long: `The subnet upgrade rehearse command activates the upcoming upgrades of the subnet upgrade file
+on the local network, without waiting for their real activation timestamps.
+
+The subnet must be deployed on the running local network. The command saves the local network into
+a temporary snapshot, and restarts it with the upgrades rescheduled to activate in order, one every
+--interval, starting a couple of minutes after the restart. Upgrades with timestamps in the past are
+kept as they are, as the chain may have already activated them.
+
+After each activation, the command checks that the enabled and disabled precompiles are active or
+inactive, and runs the smoke checks given with --checks. The checks file has the format:
+
+  {"checks": [
+    {"name": "fee config", "rpc": {"method": "eth_feeConfig", "params": []}, "expect": "gasLimit"},
+    {"name": "allow list", "command": "npx hardhat run scripts/check.ts --network local", "dir": "contracts", "steps": [2]}
+  ]}
+
+Commands pass if they exit with code 0, and get the env vars AVALANCHE_RPC_URL, AVALANCHE_BLOCKCHAIN_ID,
+AVALANCHE_UPGRADE_STEP and AVALANCHE_UPGRADE_TIMESTAMP. RPC checks pass if the call succeeds and the
+result contains the expected string, if any.
+
+Once done, the local network is restored to the state it had before the rehearsal.`,
+		RunE:         rehearseCmd,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&rehearsalChecksPath, "checks", "", "path to the smoke checks file")
+	cmd.Flags().DurationVar(&rehearsalInterval, "interval", 30*time.Second, "time between rehearsed activations")
+	cmd.Flags().DurationVar(&rehearsalActivationWait, "activation-timeout", time.Minute, "time to wait for each activation to be observed on chain")
+	cmd.Flags().StringVar(&rehearsalBlockProducerKey, "key", "", "key used to issue the txs that produce blocks after each activation (default: ewoq)")
+	return cmd
+}
+
+func rehearseCmd(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.SubnetConfigExists(subnetName) {
+		return errors.New("subnet does not exist")
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return errors.New("upgrade rehearsals are only supported for Subnet-EVM subnets")
+	}
+	networkKey := models.Local.String()
+	if sc.Networks[networkKey] == (models.NetworkData{}) {
+		return subnetNotYetDeployed()
+	}
+	upgradeBytes, err := app.ReadUpgradeFile(subnetName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			ux.Logger.PrintToUser("No file with upgrade specs for the given subnet has been found")
+			ux.Logger.PrintToUser("You may need to first create it with the `avalanche subnet upgrade generate` command or import it")
+		}
+		return err
+	}
+	checks := []rehearsalCheck{}
+	if rehearsalChecksPath != "" {
+		checks, err = loadRehearsalChecks(rehearsalChecksPath)
+		if err != nil {
+			return err
+		}
+	}
+	blockProducerKey, err := getBlockProducerKey()
+	if err != nil {
+		return err
+	}
+
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		ux.Logger.PrintToUser(ErrNetworkNotStartedOutput)
+		return err
+	}
+	blockchainID, err := getLocallyDeployedBlockchainID(cli, networkKey, &sc)
+	if err != nil {
+		return err
+	}
+
+	// the start delay also covers saving the network and loading it back
+	rehearsalUpgradeBytes, steps, err := rescheduleUpgrades(upgradeBytes, time.Now().Add(rehearsalStartDelay), rehearsalInterval)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Rehearsing %d upgrade activations, one every %s", len(steps), rehearsalInterval)
+
+	// save the current state, to rehearse on a copy of it
+	snapName := subnetName + rehearsalSnapshotInfix + time.Now().Format(timestampFormat)
+	app.Log.Debug("saving snapshot for upgrade rehearsal", zap.String("snapshot-name", snapName))
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	if _, err := cli.SaveSnapshot(ctx, snapName); err != nil {
+		return err
+	}
+
+	results, rehearsalErr := rehearseUpgrades(cli, snapName, blockchainID, rehearsalUpgradeBytes, steps, checks, blockProducerKey)
+	if err := restoreRehearsalSnapshot(cli, snapName); err != nil {
+		return fmt.Errorf("failure restoring the local network after the rehearsal: %w", err)
+	}
+	if len(results) > 0 {
+		printRehearsalResults(results)
+	}
+	if rehearsalErr != nil {
+		return rehearsalErr
+	}
+	for _, result := range results {
+		if !result.activated || len(result.failedChecks) > 0 {
+			return errRehearsalFailed
+		}
+	}
+	ux.Logger.PrintToUser("All rehearsed upgrades were activated and passed their checks")
+	return nil
+}
+
+// rehearseUpgrades loads [snapName] with the rescheduled upgrades and walks through the activations
+func rehearseUpgrades(
+	cli ANRclient.Client,
+	snapName string,
+	blockchainID ids.ID,
+	rehearsalUpgradeBytes []byte,
+	steps []rehearsalStep,
+	checks []rehearsalCheck,
+	blockProducerKey *key.SoftKey,
+) ([]rehearsalResult, error) {
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	netUpgradeConfs := map[string]string{
+		blockchainID.String(): string(rehearsalUpgradeBytes),
+	}
+	if _, err := cli.LoadSnapshot(ctx, snapName, ANRclient.WithUpgradeConfigs(netUpgradeConfs)); err != nil {
+		return nil, err
+	}
+	clusterInfo, err := subnet.WaitForHealthy(ctx, cli)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+	if len(clusterInfo.NodeNames) == 0 {
+		return nil, errors.New("unexpected network size of zero nodes")
+	}
+	nodeInfo := clusterInfo.NodeInfos[clusterInfo.NodeNames[0]]
+	rpcURL := fmt.Sprintf("%s/ext/bc/%s/rpc", nodeInfo.GetUri(), blockchainID)
+
+	results := []rehearsalResult{}
+	for i, step := range steps {
+		stepNumber := i + 1
+		activationTime := time.Unix(int64(step.Timestamp), 0)
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(
+			"Step %d: waiting for activation at %s (originally %s)",
+			stepNumber,
+			activationTime.Local().Format(constants.TimeParseLayout),
+			time.Unix(int64(step.OriginalTimestamp), 0).Local().Format(constants.TimeParseLayout),
+		)
+		time.Sleep(time.Until(activationTime))
+		result := rehearsalResult{step: step}
+		result.activated = waitForActivation(rpcURL, step, blockProducerKey)
+		if !result.activated {
+			ux.Logger.PrintToUser("Step %d: upgrades not observed on chain after %s", stepNumber, rehearsalActivationWait)
+		}
+		for _, check := range checks {
+			if !check.appliesTo(stepNumber) {
+				continue
+			}
+			result.numChecks++
+			if err := runRehearsalCheck(check, rpcURL, blockchainID, stepNumber, step.Timestamp); err != nil {
+				ux.Logger.PrintToUser("Step %d: check %q failed: %s", stepNumber, check.Name, err)
+				result.failedChecks = append(result.failedChecks, check.Name)
+			} else {
+				ux.Logger.PrintToUser("Step %d: check %q passed", stepNumber, check.Name)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// restoreRehearsalSnapshot brings back the local network to the state saved before the rehearsal
+func restoreRehearsalSnapshot(cli ANRclient.Client, snapName string) error {
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Restoring the local network...")
+	if _, err := cli.Stop(ctx); err != nil {
+		app.Log.Debug("failed stopping rehearsal network", zap.Error(err))
+	}
+	if _, err := cli.LoadSnapshot(ctx, snapName); err != nil {
+		return err
+	}
+	if _, err := subnet.WaitForHealthy(ctx, cli); err != nil {
+		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+	if _, err := cli.RemoveSnapshot(ctx, snapName); err != nil {
+		app.Log.Debug("failed removing rehearsal snapshot", zap.String("snapshot-name", snapName), zap.Error(err))
+	}
+	return nil
+}
+
+// waitForActivation issues txs to produce blocks until the chain head is past the step timestamp,
+// and the precompiles of [step] are observed as enabled or disabled, or the activation timeout expires
+func waitForActivation(rpcURL string, step rehearsalStep, blockProducerKey *key.SoftKey) bool {
+	deadline := time.Now().Add(rehearsalActivationWait)
+	for time.Now().Before(deadline) {
+		if err := produceBlock(rpcURL, blockProducerKey); err != nil {
+			app.Log.Debug("failed producing block", zap.Error(err))
+		}
+		activated, err := isStepActive(rpcURL, step)
+		if err != nil {
+			app.Log.Debug("failed checking activation", zap.Error(err))
+		}
+		if activated {
+			return true
+		}
+		time.Sleep(activationPollInterval)
+	}
+	return false
+}
+
+func isStepActive(rpcURL string, step rehearsalStep) (bool, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if head.Time < step.Timestamp {
+		return false, nil
+	}
+	activePrecompiles, err := evm.GetActivePrecompiles(rpcURL)
+	if err != nil {
+		return false, err
+	}
+	return step.isActive(activePrecompiles), nil
+}
+
+// produceBlock issues a zero value self transfer, as subnet-evm only builds blocks on txs
+func produceBlock(rpcURL string, k *key.SoftKey) error {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return evm.FundAddress(client, hex.EncodeToString(k.Raw()), k.C(), big.NewInt(0))
+}
+
+func getBlockProducerKey() (*key.SoftKey, error) {
+	if rehearsalBlockProducerKey == "" {
+		return key.LoadEwoq(constants.LocalNetworkID)
+	}
+	return key.LoadSoft(constants.LocalNetworkID, app.GetKeyPath(rehearsalBlockProducerKey))
+}
+
+func runRehearsalCheck(check rehearsalCheck, rpcURL string, blockchainID ids.ID, stepNumber int, timestamp uint64) error {
+	if check.RPC != nil {
+		result, err := evm.CallRPC(rpcURL, check.RPC.Method, check.RPC.Params...)
+		if err != nil {
+			return err
+		}
+		if check.Expect != "" && !strings.Contains(string(result), check.Expect) {
+			return fmt.Errorf("result %s does not contain %q", string(result), check.Expect)
+		}
+		return nil
+	}
+	cmd := exec.Command("sh", "-c", check.Command) //nolint:gosec
+	cmd.Dir = check.Dir
+	cmd.Env = append(
+		os.Environ(),
+		"AVALANCHE_RPC_URL="+rpcURL,
+		"AVALANCHE_BLOCKCHAIN_ID="+blockchainID.String(),
+		"AVALANCHE_UPGRADE_STEP="+strconv.Itoa(stepNumber),
+		"AVALANCHE_UPGRADE_TIMESTAMP="+strconv.FormatUint(timestamp, 10),
+	)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
+
+func printRehearsalResults(results []rehearsalResult) {
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"step", "original timestamp", "upgrades", "activated", "checks", "result"})
+	table.SetRowLine(true)
+	for i, result := range results {
+		upgrades := []string{}
+		for _, precompile := range result.step.Enabled {
+			upgrades = append(upgrades, "enable "+precompile)
+		}
+		for _, precompile := range result.step.Disabled {
+			upgrades = append(upgrades, "disable "+precompile)
+		}
+		if result.step.StateUpgrades > 0 {
+			upgrades = append(upgrades, fmt.Sprintf("%d state upgrades", result.step.StateUpgrades))
+		}
+		checks := fmt.Sprintf("%d/%d passed", result.numChecks-len(result.failedChecks), result.numChecks)
+		if len(result.failedChecks) > 0 {
+			checks += "\nfailed: " + strings.Join(result.failedChecks, ", ")
+		}
+		outcome := "PASS"
+		if !result.activated || len(result.failedChecks) > 0 {
+			outcome = "FAIL"
+		}
+		table.Append([]string{
+			strconv.Itoa(i + 1),
+			time.Unix(int64(result.step.OriginalTimestamp), 0).Local().Format(constants.TimeParseLayout),
+			strings.Join(upgrades, "\n"),
+			strconv.FormatBool(result.activated),
+			checks,
+			outcome,
+		})
+	}
+	table.Render()
+}
+
+func loadRehearsalChecks(checksPath string) ([]rehearsalCheck, error) {
+	checksBytes, err := os.ReadFile(checksPath)
+	if err != nil {
+		return nil, err
+	}
+	var checks rehearsalChecks
+	if err := json.Unmarshal(checksBytes, &checks); err != nil {
+		return nil, fmt.Errorf("invalid checks file %s: %w", checksPath, err)
+	}
+	for i, check := range checks.Checks {
+		if check.Name == "" {
+			checks.Checks[i].Name = fmt.Sprintf("check %d", i+1)
+		}
+		if (check.RPC == nil) == (check.Command == "") {
+			return nil, fmt.Errorf("invalid check %q: exactly one of rpc or command must be given", checks.Checks[i].Name)
+		}
+		if check.RPC != nil && check.RPC.Method == "" {
+			return nil, fmt.Errorf("invalid check %q: missing rpc method", checks.Checks[i].Name)
+		}
+	}
+	return checks.Checks, nil
+}
+
+func (check rehearsalCheck) appliesTo(stepNumber int) bool {
+	if len(check.Steps) == 0 {
+		return true
+	}
+	for _, step := range check.Steps {
+		if step == stepNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// isActive returns true if the precompiles enabled at the step are active, and the disabled ones are not
+func (step rehearsalStep) isActive(activePrecompiles []string) bool {
+	active := map[string]bool{}
+	for _, precompile := range activePrecompiles {
+		active[precompile] = true
+	}
+	for _, precompile := range step.Enabled {
+		if !active[precompile] {
+			return false
+		}
+	}
+	for _, precompile := range step.Disabled {
+		if active[precompile] {
+			return false
+		}
+	}
+	return true
+}
+
+// rescheduleUpgrades moves the precompile and state upgrades of [upgradeBytes] with timestamps after
+// [start] so that they activate in order, the first one at [start], and the next ones every [interval].
+// Upgrades at the same timestamp keep activating together. Upgrades in the past, and any other
+// content of the file, are left unchanged
+func rescheduleUpgrades(upgradeBytes []byte, start time.Time, interval time.Duration) ([]byte, []rehearsalStep, error) {
+	var upgradeConfig map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(upgradeBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&upgradeConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed parsing upgrade file: %w - %w", err, errInvalidPrecompiles)
+	}
+	now := uint64(time.Now().Unix())
+
+	// collect the upgrades to be rescheduled, by timestamp
+	stepsByTimestamp := map[uint64]*rehearsalStep{}
+	getStep := func(timestamp uint64) *rehearsalStep {
+		if _, ok := stepsByTimestamp[timestamp]; !ok {
+			stepsByTimestamp[timestamp] = &rehearsalStep{OriginalTimestamp: timestamp}
+		}
+		return stepsByTimestamp[timestamp]
+	}
+	toReschedule := []map[string]interface{}{}
+
+	precompileUpgrades, _ := upgradeConfig["precompileUpgrades"].([]interface{})
+	for _, precompileUpgrade := range precompileUpgrades {
+		upgrade, ok := precompileUpgrade.(map[string]interface{})
+		if !ok {
+			return nil, nil, errInvalidPrecompiles
+		}
+		for precompileKey, precompileConfig := range upgrade {
+			precompileConfigMap, ok := precompileConfig.(map[string]interface{})
+			if !ok {
+				return nil, nil, errInvalidPrecompiles
+			}
+			timestamp, err := getUpgradeTimestamp(precompileConfigMap)
+			if err != nil {
+				return nil, nil, err
+			}
+			if timestamp <= now {
+				continue
+			}
+			step := getStep(timestamp)
+			if disable, _ := precompileConfigMap["disable"].(bool); disable {
+				step.Disabled = append(step.Disabled, precompileKey)
+			} else {
+				step.Enabled = append(step.Enabled, precompileKey)
+			}
+			toReschedule = append(toReschedule, precompileConfigMap)
+		}
+	}
+	stateUpgrades, _ := upgradeConfig["stateUpgrades"].([]interface{})
+	for _, stateUpgrade := range stateUpgrades {
+		stateUpgradeMap, ok := stateUpgrade.(map[string]interface{})
+		if !ok {
+			return nil, nil, errInvalidPrecompiles
+		}
+		timestamp, err := getUpgradeTimestamp(stateUpgradeMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		if timestamp <= now {
+			continue
+		}
+		getStep(timestamp).StateUpgrades++
+		toReschedule = append(toReschedule, stateUpgradeMap)
+	}
+	if len(stepsByTimestamp) == 0 {
+		return nil, nil, errNoUpcomingUpgrades
+	}
+
+	// assign the new timestamps, in the original order
+	originalTimestamps := make([]uint64, 0, len(stepsByTimestamp))
+	for timestamp := range stepsByTimestamp {
+		originalTimestamps = append(originalTimestamps, timestamp)
+	}
+	sort.Slice(originalTimestamps, func(i, j int) bool { return originalTimestamps[i] < originalTimestamps[j] })
+	steps := make([]rehearsalStep, 0, len(originalTimestamps))
+	for i, timestamp := range originalTimestamps {
+		step := stepsByTimestamp[timestamp]
+		step.Timestamp = uint64(start.Add(time.Duration(i) * interval).Unix())
+		sort.Strings(step.Enabled)
+		sort.Strings(step.Disabled)
+		steps = append(steps, *step)
+	}
+	for _, upgrade := range toReschedule {
+		timestamp, err := getUpgradeTimestamp(upgrade)
+		if err != nil {
+			return nil, nil, err
+		}
+		upgrade["blockTimestamp"] = stepsByTimestamp[timestamp].Timestamp
+	}
+	rescheduledBytes, err := json.Marshal(upgradeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rescheduledBytes, steps, nil
+}
+
+func getUpgradeTimestamp(upgrade map[string]interface{}) (uint64, error) {
+	timestampNumber, ok := upgrade["blockTimestamp"].(json.Number)
+	if !ok {
+		return 0, errNoBlockTimestamp
+	}
+	timestamp, err := strconv.ParseUint(timestampNumber.String(), 10, 64)
+	if err != nil {
+		return 0, errBlockTimestampInvalid
+	}
+	return timestamp, nil
+}
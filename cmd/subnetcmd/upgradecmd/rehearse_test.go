@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRescheduleUpgrades(t *testing.T) {
+	require := require.New(t)
+
+	past := time.Now().Add(-time.Hour).Unix()
+	first := time.Now().Add(24 * time.Hour).Unix()
+	second := time.Now().Add(48 * time.Hour).Unix()
+	upgradesFile := []byte(fmt.Sprintf(`
+{"precompileUpgrades":[
+{"feeManagerConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d}},
+{"txAllowListConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d}},
+{"contractNativeMinterConfig":{"adminAddresses":["0xb794F5eA0ba39494cE839613fffBA74279579268"],"blockTimestamp":%d}},
+{"feeManagerConfig":{"blockTimestamp":%d,"disable":true}}
+],
+"stateUpgrades":[{"blockTimestamp":%d,"accounts":{"0xb794F5eA0ba39494cE839613fffBA74279579268":{"balanceChange":"0x10000000000000000000000000000"}}}]}`,
+		past, first, first, second, second,
+	))
+
+	start := time.Now().Add(time.Minute)
+	rescheduled, steps, err := rescheduleUpgrades(upgradesFile, start, 30*time.Second)
+	require.NoError(err)
+	require.Len(steps, 2)
+	require.Equal(uint64(first), steps[0].OriginalTimestamp)
+	require.Equal(uint64(start.Unix()), steps[0].Timestamp)
+	require.Equal([]string{"contractNativeMinterConfig", "txAllowListConfig"}, steps[0].Enabled)
+	require.Empty(steps[0].Disabled)
+	require.Equal(uint64(second), steps[1].OriginalTimestamp)
+	require.Equal(uint64(start.Add(30*time.Second).Unix()), steps[1].Timestamp)
+	require.Equal([]string{"feeManagerConfig"}, steps[1].Disabled)
+	require.Equal(1, steps[1].StateUpgrades)
+
+	var upgrades struct {
+		PrecompileUpgrades []map[string]map[string]interface{} `json:"precompileUpgrades"`
+		StateUpgrades      []map[string]interface{}            `json:"stateUpgrades"`
+	}
+	require.NoError(json.Unmarshal(rescheduled, &upgrades))
+	// past upgrades are kept
+	require.Equal(float64(past), upgrades.PrecompileUpgrades[0]["feeManagerConfig"]["blockTimestamp"])
+	require.Equal(float64(steps[0].Timestamp), upgrades.PrecompileUpgrades[1]["txAllowListConfig"]["blockTimestamp"])
+	require.Equal(float64(steps[0].Timestamp), upgrades.PrecompileUpgrades[2]["contractNativeMinterConfig"]["blockTimestamp"])
+	require.Equal(float64(steps[1].Timestamp), upgrades.PrecompileUpgrades[3]["feeManagerConfig"]["blockTimestamp"])
+	require.Equal(float64(steps[1].Timestamp), upgrades.StateUpgrades[0]["blockTimestamp"])
+	// other contents are left unchanged
+	require.Contains(string(rescheduled), `"balanceChange":"0x10000000000000000000000000000"`)
+
+	require.True(steps[0].isActive([]string{"contractNativeMinterConfig", "txAllowListConfig"}))
+	require.False(steps[0].isActive([]string{"txAllowListConfig"}))
+	require.True(steps[1].isActive([]string{"txAllowListConfig"}))
+	require.False(steps[1].isActive([]string{"feeManagerConfig"}))
+
+	// nothing upcoming
+	_, _, err = rescheduleUpgrades([]byte(fmt.Sprintf(`{"precompileUpgrades":[{"feeManagerConfig":{"blockTimestamp":%d}}]}`, past)), start, time.Second)
+	require.ErrorIs(err, errNoUpcomingUpgrades)
+
+	_, _, err = rescheduleUpgrades([]byte(`{"precompileUpgrades":[{"feeManagerConfig":{}}]}`), start, time.Second)
+	require.ErrorIs(err, errNoBlockTimestamp)
+}
+
+func TestLoadRehearsalChecks(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	checksPath := filepath.Join(dir, "checks.json")
+	require.NoError(os.WriteFile(checksPath, []byte(`{"checks":[
+{"name":"fee config","rpc":{"method":"eth_feeConfig","params":[]},"expect":"gasLimit"},
+{"command":"true","steps":[2]}
+]}`), 0o600))
+	checks, err := loadRehearsalChecks(checksPath)
+	require.NoError(err)
+	require.Len(checks, 2)
+	require.Equal("eth_feeConfig", checks[0].RPC.Method)
+	require.True(checks[0].appliesTo(1))
+	require.Equal("check 2", checks[1].Name)
+	require.False(checks[1].appliesTo(1))
+	require.True(checks[1].appliesTo(2))
+
+	require.NoError(os.WriteFile(checksPath, []byte(`{"checks":[{"name":"both","rpc":{"method":"eth_chainId"},"command":"true"}]}`), 0o600))
+	_, err = loadRehearsalChecks(checksPath)
+	require.Error(err)
+
+	require.NoError(os.WriteFile(checksPath, []byte(`{"checks":[{"name":"none"}]}`), 0o600))
+	_, err = loadRehearsalChecks(checksPath)
+	require.Error(err)
+}
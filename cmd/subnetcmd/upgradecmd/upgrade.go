@@ -38,5 +38,7 @@ updating your developmental and deployed Subnets.`,
 	cmd.AddCommand(newUpgradePrintCmd())
 	// subnet upgrade apply
 	cmd.AddCommand(newUpgradeApplyCmd())
+	// subnet upgrade rehearse
+	cmd.AddCommand(newUpgradeRehearseCmd())
 	return cmd
 }
@@ -5,44 +5,114 @@ package teleportercmd
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
 	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
 	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	payloadFormatString = "string"
+	payloadFormatHex    = "hex"
+	payloadFormatABI    = "abi"
+
+	msgDefaultRequiredGasLimit = 100_000
+	msgDeliveryCheckInterval   = time.Second
+)
+
 var (
 	msgSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Cluster, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Devnet}
 	globalNetworkFlags         networkoptions.NetworkFlags
+	msgDestinationAddress      string
+	msgPayloadFormat           string
+	msgABITypes                []string
+	msgFeeTokenAddress         string
+	msgFeeAmount               string
+	msgAllowedRelayers         []string
+	msgRequiredGasLimit        uint64
+	msgKeyName                 string
+	msgWait                    bool
+	msgWaitTimeout             time.Duration
 )
 
 // avalanche teleporter msg
 func newMsgCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "msg [sourceSubnetName] [destinationSubnetName] [messageContent]",
-		Short:        "Verifies exchange of teleporter message between two subnets",
-		Long:         `Sends and wait reception for a teleporter msg between two subnets (Currently only for local network).`,
+		Use:   "msg [sourceSubnetName] [destinationSubnetName] [messageContent...]",
+		Short: "Sends a teleporter message between two subnets",
+		Long: `Sends a teleporter message from the source subnet to the destination subnet, and
+waits for it to be delivered. Use c-chain as subnet name for the C-Chain.
+
+By default the message content is sent as a string to the sender address. Use
+--destination-address to send it to a contract, and --payload-format to send it
+as hex encoded bytes, or ABI encoded from one value argument per type given in
+--abi-types. For example:
+
+  avalanche teleporter msg mysubnet c-chain --payload-format abi --abi-types address,uint256 \
+    --destination-address 0x... 0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC 1000
+
+The message is sent with the teleporter key of the source subnet, or with the ewoq key
+on the C-Chain. Use --key to send it with another stored key.
+
+Set a relayer fee with --fee-token and --fee-amount: the messenger is approved to
+transfer the fee amount of the token before the message is sent.`,
 		SilenceUsage: true,
 		RunE:         msg,
-		Args:         cobra.ExactArgs(3),
+		Args:         cobra.MinimumNArgs(3),
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, msgSupportedNetworkOptions)
+	cmd.Flags().StringVar(&msgDestinationAddress, "destination-address", "", "address the message is sent to on the destination subnet. Defaults to the sender address")
+	cmd.Flags().StringVar(&msgPayloadFormat, "payload-format", payloadFormatString, fmt.Sprintf("format of the message content: %s, %s or %s", payloadFormatString, payloadFormatHex, payloadFormatABI))
+	cmd.Flags().StringSliceVar(&msgABITypes, "abi-types", nil, "solidity types of the message content values, for the abi payload format")
+	cmd.Flags().StringVar(&msgFeeTokenAddress, "fee-token", "", "address of the ERC-20 token the relayer fee is paid with")
+	cmd.Flags().StringVar(&msgFeeAmount, "fee-amount", "0", "relayer fee amount, in the fee token smallest unit")
+	cmd.Flags().StringSliceVar(&msgAllowedRelayers, "allowed-relayers", nil, "addresses or relayer instance names of the relayers allowed to deliver the message. Defaults to any relayer")
+	cmd.Flags().Uint64Var(&msgRequiredGasLimit, "gas-limit", msgDefaultRequiredGasLimit, "gas limit required to execute the message at the destination address")
+	cmd.Flags().StringVarP(&msgKeyName, "key", "k", "", "send the message with the given stored key")
+	cmd.Flags().BoolVar(&msgWait, "wait", true, "wait for the message to be delivered and print the destination receipt")
+	cmd.Flags().DurationVar(&msgWaitTimeout, "wait-timeout", 2*time.Minute, "how long to wait for the message to be delivered")
 	return cmd
 }
 
 func msg(_ *cobra.Command, args []string) error {
 	sourceSubnetName := args[0]
 	destSubnetName := args[1]
-	message := args[2]
+	payload, err := getMsgPayload(msgPayloadFormat, msgABITypes, args[2:])
+	if err != nil {
+		return err
+	}
+	feeAmount, ok := new(big.Int).SetString(msgFeeAmount, 10)
+	if !ok || feeAmount.Sign() < 0 {
+		return fmt.Errorf("invalid fee amount %q", msgFeeAmount)
+	}
+	if feeAmount.Sign() > 0 && msgFeeTokenAddress == "" {
+		return errors.New("a fee token is required to pay a fee: set it with --fee-token")
+	}
+	for _, addr := range []string{msgDestinationAddress, msgFeeTokenAddress} {
+		if addr != "" && !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address %q", addr)
+		}
+	}
+	allowedRelayers, err := getAllowedRelayers(msgAllowedRelayers)
+	if err != nil {
+		return err
+	}
 
 	subnetNameToGetNetworkFrom := ""
 	if !isCChain(sourceSubnetName) {
@@ -70,14 +140,13 @@ func msg(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-
-	if sourceMessengerAddress != destMessengerAddress {
-		return fmt.Errorf("different teleporter messenger addresses among subnets: %s vs %s", sourceMessengerAddress, destMessengerAddress)
+	if msgKeyName != "" {
+		sourceKey, err = key.LoadSoft(network.ID, app.GetKeyPath(msgKeyName))
+		if err != nil {
+			return err
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
 	// get clients + messengers
 	sourceClient, err := evm.GetClient(network.BlockchainEndpoint(sourceChainID.String()))
 	if err != nil {
@@ -87,41 +156,54 @@ func msg(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	destWebSocketClient, err := evm.GetClient(network.BlockchainWSEndpoint(destChainID.String()))
+	destClient, err := evm.GetClient(network.BlockchainEndpoint(destChainID.String()))
 	if err != nil {
 		return err
 	}
-	destMessenger, err := teleportermessenger.NewTeleporterMessenger(common.HexToAddress(destMessengerAddress), destWebSocketClient)
+	destMessenger, err := teleportermessenger.NewTeleporterMessenger(common.HexToAddress(destMessengerAddress), destClient)
 	if err != nil {
 		return err
 	}
 
-	// subscribe to get new heads from destination
-	destHeadsCh := make(chan *types.Header, 10)
-	destHeadsSubscription, err := destWebSocketClient.SubscribeNewHead(ctx, destHeadsCh)
+	// the delivery is searched from the destination height previous to sending the message
+	destStartBlock, err := getBlockNumber(destClient)
 	if err != nil {
 		return err
 	}
-	defer destHeadsSubscription.Unsubscribe()
 
+	sourcePrivateKey := hex.EncodeToString(sourceKey.Raw())
+	sourceAddress := common.HexToAddress(sourceKey.C())
+	destAddress := sourceAddress
+	if msgDestinationAddress != "" {
+		destAddress = common.HexToAddress(msgDestinationAddress)
+	}
+	feeTokenAddress := sourceAddress
+	if msgFeeTokenAddress != "" {
+		feeTokenAddress = common.HexToAddress(msgFeeTokenAddress)
+	}
+	if feeAmount.Sign() > 0 {
+		ux.Logger.PrintToUser("Approving the teleporter messenger to transfer a fee of %s of token %s", feeAmount, feeTokenAddress)
+		if err := evm.ApproveERC20(sourceClient, sourcePrivateKey, feeTokenAddress.Hex(), sourceMessengerAddress, feeAmount); err != nil {
+			return err
+		}
+	}
 	// send tx to the teleporter contract at the source
-	sourceSigner, err := evm.GetSigner(sourceClient, hex.EncodeToString(sourceKey.Raw()))
+	sourceSigner, err := evm.GetSigner(sourceClient, sourcePrivateKey)
 	if err != nil {
 		return err
 	}
-	sourceAddress := common.HexToAddress(sourceKey.C())
 	msgInput := teleportermessenger.TeleporterMessageInput{
 		DestinationBlockchainID: destChainID,
-		DestinationAddress:      sourceAddress,
+		DestinationAddress:      destAddress,
 		FeeInfo: teleportermessenger.TeleporterFeeInfo{
-			FeeTokenAddress: sourceAddress,
-			Amount:          big.NewInt(0),
+			FeeTokenAddress: feeTokenAddress,
+			Amount:          feeAmount,
 		},
-		RequiredGasLimit:        big.NewInt(1),
-		AllowedRelayerAddresses: []common.Address{},
-		Message:                 []byte(message),
+		RequiredGasLimit:        new(big.Int).SetUint64(msgRequiredGasLimit),
+		AllowedRelayerAddresses: allowedRelayers,
+		Message:                 payload,
 	}
-	ux.Logger.PrintToUser("Delivering message %q from source subnet %q (%s)", message, sourceSubnetName, sourceChainID)
+	ux.Logger.PrintToUser("Delivering message %s from source subnet %q (%s) to %s on destination subnet %q (%s)", hexutil.Encode(payload), sourceSubnetName, sourceChainID, destAddress, destSubnetName, destChainID)
 	tx, err := sourceMessenger.SendCrossChainMessage(sourceSigner, msgInput)
 	if err != nil {
 		return err
@@ -131,18 +213,8 @@ func msg(_ *cobra.Command, args []string) error {
 		return err
 	}
 	if !b {
-		txHash := tx.Hash().String()
-		ux.Logger.PrintToUser("error: source receipt status for tx %s is not ReceiptStatusSuccessful", txHash)
-		trace, err := evm.GetTrace(network.BlockchainEndpoint(sourceChainID.String()), txHash)
-		if err != nil {
-			ux.Logger.PrintToUser("error obtaining tx trace: %s", err)
-			ux.Logger.PrintToUser("")
-		} else {
-			ux.Logger.PrintToUser("")
-			ux.Logger.PrintToUser("trace: %#v", trace)
-			ux.Logger.PrintToUser("")
-		}
-		return fmt.Errorf("source receipt status for tx %s is not ReceiptStatusSuccessful", txHash)
+		printTxTrace(network.BlockchainEndpoint(sourceChainID.String()), "source", tx.Hash().String())
+		return fmt.Errorf("source receipt status for tx %s is not ReceiptStatusSuccessful", tx.Hash())
 	}
 	sourceEvent, err := evm.GetEventFromLogs(sourceReceipt.Logs, sourceMessenger.ParseSendCrossChainMessage)
 	if err != nil {
@@ -152,68 +224,176 @@ func msg(_ *cobra.Command, args []string) error {
 	if destChainID != ids.ID(sourceEvent.DestinationBlockchainID[:]) {
 		return fmt.Errorf("invalid destination blockchain id at source event, expected %s, got %s", destChainID, ids.ID(sourceEvent.DestinationBlockchainID[:]))
 	}
-	if message != string(sourceEvent.Message.Message) {
-		return fmt.Errorf("invalid message content at source event, expected %s, got %s", message, string(sourceEvent.Message.Message))
+	if string(payload) != string(sourceEvent.Message.Message) {
+		return fmt.Errorf("invalid message content at source event, expected %s, got %s", hexutil.Encode(payload), hexutil.Encode(sourceEvent.Message.Message))
+	}
+	messageID := common.Hash(sourceEvent.MessageID)
+	ux.Logger.PrintToUser("Message %s sent at tx %s", messageID, tx.Hash())
+	if !msgWait {
+		ux.Logger.PrintToUser("Follow its delivery with avalanche teleporter trace %s %s", sourceSubnetName, tx.Hash())
+		return nil
 	}
 
-	// receive and process head from destination
+	// wait for the message to be received at destination
 	ux.Logger.PrintToUser("Waiting for message to be received on destination subnet %q (%s)", destSubnetName, destChainID)
-	var head *types.Header
-	select {
-	case head = <-destHeadsCh:
-	case <-ctx.Done():
-		return ctx.Err()
-	}
-	if sourceChainID == destChainID {
-		// we have another block
-		select {
-		case head = <-destHeadsCh:
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), msgWaitTimeout)
+	defer cancel()
+	if err := waitForMessageReceived(ctx, destMessenger, sourceEvent.MessageID); err != nil {
+		return fmt.Errorf("message %s not received on destination subnet: %w", messageID, err)
 	}
-	blockNumber := head.Number
-	block, err := destWebSocketClient.BlockByNumber(ctx, blockNumber)
+	destEvent, err := getReceiveEvent(ctx, destMessenger, sourceEvent.MessageID, sourceChainID, destStartBlock)
 	if err != nil {
 		return err
 	}
-	if len(block.Transactions()) != 1 {
-		return fmt.Errorf("expected to have only one transaction on new block at destination")
-	}
-	destReceipt, err := destWebSocketClient.TransactionReceipt(ctx, block.Transactions()[0].Hash())
+	destReceipt, err := destClient.TransactionReceipt(ctx, destEvent.Raw.TxHash)
 	if err != nil {
 		return err
 	}
 	if destReceipt.Status != types.ReceiptStatusSuccessful {
-		txHash := block.Transactions()[0].Hash().String()
-		ux.Logger.PrintToUser("error: dest receipt status for tx %s is not ReceiptStatusSuccessful", txHash)
-		trace, err := evm.GetTrace(network.BlockchainEndpoint(destChainID.String()), txHash)
-		if err != nil {
-			ux.Logger.PrintToUser("error obtaining tx trace: %s", err)
-			ux.Logger.PrintToUser("")
-		} else {
-			ux.Logger.PrintToUser("")
-			ux.Logger.PrintToUser("trace: %#v", trace)
-			ux.Logger.PrintToUser("")
-		}
-		return fmt.Errorf("dest receipt status for tx %s is not ReceiptStatusSuccessful", txHash)
-	}
-	destEvent, err := evm.GetEventFromLogs(destReceipt.Logs, destMessenger.ParseReceiveCrossChainMessage)
-	if err != nil {
-		return err
+		printTxTrace(network.BlockchainEndpoint(destChainID.String()), "dest", destEvent.Raw.TxHash.String())
+		return fmt.Errorf("dest receipt status for tx %s is not ReceiptStatusSuccessful", destEvent.Raw.TxHash)
 	}
 
 	if sourceChainID != ids.ID(destEvent.SourceBlockchainID[:]) {
 		return fmt.Errorf("invalid source blockchain id at dest event, expected %s, got %s", sourceChainID, ids.ID(destEvent.SourceBlockchainID[:]))
 	}
-	if message != string(destEvent.Message.Message) {
-		return fmt.Errorf("invalid message content at source event, expected %s, got %s", message, string(destEvent.Message.Message))
-	}
-	if sourceEvent.MessageID != destEvent.MessageID {
-		return fmt.Errorf("unexpected difference between message ID at source and dest events: %s vs %s", hex.EncodeToString(sourceEvent.MessageID[:]), hex.EncodeToString(destEvent.MessageID[:]))
+	if string(payload) != string(destEvent.Message.Message) {
+		return fmt.Errorf("invalid message content at dest event, expected %s, got %s", hexutil.Encode(payload), hexutil.Encode(destEvent.Message.Message))
 	}
 
+	printDestinationReceipt(destMessenger, destReceipt, destEvent)
+
 	ux.Logger.PrintToUser("Message successfully Teleported!")
 
 	return nil
 }
+
+// getMsgPayload returns the message payload of [values], given in [payloadFormat]
+func getMsgPayload(payloadFormat string, abiTypes []string, values []string) ([]byte, error) {
+	if payloadFormat != payloadFormatABI && len(values) != 1 {
+		return nil, fmt.Errorf("expected one message content argument for the %s payload format, got %d", payloadFormat, len(values))
+	}
+	switch payloadFormat {
+	case payloadFormatString:
+		return []byte(values[0]), nil
+	case payloadFormatHex:
+		payload, err := hex.DecodeString(strings.TrimPrefix(values[0], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex message content: %w", err)
+		}
+		return payload, nil
+	case payloadFormatABI:
+		if len(abiTypes) == 0 {
+			return nil, fmt.Errorf("the %s payload format requires the solidity types of the values, set with --abi-types", payloadFormatABI)
+		}
+		return evm.PackABIValues(abiTypes, values)
+	default:
+		return nil, fmt.Errorf("invalid payload format %q: expected %s, %s or %s", payloadFormat, payloadFormatString, payloadFormatHex, payloadFormatABI)
+	}
+}
+
+func getBlockNumber(client ethclient.Client) (uint64, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return client.BlockNumber(ctx)
+}
+
+// waitForMessageReceived waits until [messenger] has received [messageID], or [ctx] is done
+func waitForMessageReceived(ctx context.Context, messenger *teleportermessenger.TeleporterMessenger, messageID [32]byte) error {
+	for {
+		received, err := messenger.MessageReceived(&bind.CallOpts{Context: ctx}, messageID)
+		if err != nil {
+			return err
+		}
+		if received {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(msgDeliveryCheckInterval):
+		}
+	}
+}
+
+// getReceiveEvent returns the ReceiveCrossChainMessage event of [messageID] on the destination
+// of [messenger], searching it from [startBlock]
+func getReceiveEvent(
+	ctx context.Context,
+	messenger *teleportermessenger.TeleporterMessenger,
+	messageID [32]byte,
+	sourceChainID ids.ID,
+	startBlock uint64,
+) (*teleportermessenger.TeleporterMessengerReceiveCrossChainMessage, error) {
+	it, err := messenger.FilterReceiveCrossChainMessage(&bind.FilterOpts{Start: startBlock, Context: ctx}, [][32]byte{messageID}, [][32]byte{sourceChainID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	if !it.Next() {
+		if it.Error() != nil {
+			return nil, it.Error()
+		}
+		return nil, fmt.Errorf("receive event of message %s not found on destination", common.Hash(messageID))
+	}
+	return it.Event, nil
+}
+
+func printDestinationReceipt(
+	messenger *teleportermessenger.TeleporterMessenger,
+	receipt *types.Receipt,
+	event *teleportermessenger.TeleporterMessengerReceiveCrossChainMessage,
+) {
+	ux.Logger.PrintToUser("Destination receipt:")
+	ux.Logger.PrintToUser("  Tx: %s", receipt.TxHash)
+	ux.Logger.PrintToUser("  Block: %s", receipt.BlockNumber)
+	ux.Logger.PrintToUser("  Gas used: %d", receipt.GasUsed)
+	ux.Logger.PrintToUser("  Deliverer: %s", event.Deliverer)
+	ux.Logger.PrintToUser("  Reward redeemer: %s", event.RewardRedeemer)
+	execution := "unknown"
+	if _, err := evm.GetEventFromLogs(receipt.Logs, messenger.ParseMessageExecuted); err == nil {
+		execution = "succeeded"
+	} else if _, err := evm.GetEventFromLogs(receipt.Logs, messenger.ParseMessageExecutionFailed); err == nil {
+		execution = "failed"
+	}
+	ux.Logger.PrintToUser("  Execution: %s", execution)
+	if execution == "failed" {
+		ux.Logger.PrintToUser("  Messages to addresses with no contract, or whose execution runs out of the required gas")
+		ux.Logger.PrintToUser("  limit, are stored on the destination messenger to be executed later with retryMessageExecution")
+	}
+}
+
+// printTxTrace prints the call trace of tx [txHash] of the [chainDesc] chain at [rpcURL]
+func printTxTrace(rpcURL string, chainDesc string, txHash string) {
+	ux.Logger.PrintToUser("error: %s receipt status for tx %s is not ReceiptStatusSuccessful", chainDesc, txHash)
+	trace, err := evm.GetTrace(rpcURL, txHash)
+	if err != nil {
+		ux.Logger.PrintToUser("error obtaining tx trace: %s", err)
+		ux.Logger.PrintToUser("")
+	} else {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("trace: %#v", trace)
+		ux.Logger.PrintToUser("")
+	}
+}
+
+// getAllowedRelayers returns the addresses of [relayers], given as addresses or as names
+// of relayer instances
+func getAllowedRelayers(relayers []string) ([]common.Address, error) {
+	allowedRelayers := []common.Address{}
+	for _, relayer := range relayers {
+		switch {
+		case common.IsHexAddress(relayer):
+			allowedRelayers = append(allowedRelayers, common.HexToAddress(relayer))
+		case app.RelayerExists(relayer):
+			relayerAddress, err := getRelayerAddress(relayer)
+			if err != nil {
+				return nil, err
+			}
+			allowedRelayers = append(allowedRelayers, common.HexToAddress(relayerAddress))
+		default:
+			return nil, fmt.Errorf("invalid allowed relayer %q: not an address nor a relayer instance", relayer)
+		}
+	}
+	return allowedRelayers, nil
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
 	"github.com/spf13/cobra"
 )
@@ -17,16 +18,22 @@ import (
 //go:embed awm-relayer.service
 var awmRelayerServiceTemplate []byte
 
+var prepareRelayerServiceName string
+
 // avalanche teleporter msg
 func newPrepareRelayerServiceCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "prepareService",
-		Short:        "Installs AWM relayer as a service",
-		Long:         `Installs AWM relayer as a service. Disabled by default.`,
+		Use:   "prepareService",
+		Short: "Installs AWM relayer as a service",
+		Long: `Installs AWM relayer as a service. Disabled by default.
+
+If --relayer is given, installs the service of the given relayer instance, so that
+more than one relayer instance can run on the same host.`,
 		SilenceUsage: true,
 		RunE:         prepareRelayerService,
 		Args:         cobra.ExactArgs(0),
 	}
+	cmd.Flags().StringVar(&prepareRelayerServiceName, "relayer", "", "install the service of the given relayer instance")
 	return cmd
 }
 
@@ -39,13 +46,22 @@ func prepareRelayerService(_ *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(app.GetAWMRelayerServiceDir(""), constants.DefaultPerms755); err != nil {
+	serviceDir := app.GetAWMRelayerServiceDir("")
+	serviceName := constants.AWMRelayerInstallDir
+	configPath := app.GetAWMRelayerServiceConfigPath("")
+	if prepareRelayerServiceName != "" {
+		relayer := models.Relayer{Name: prepareRelayerServiceName}
+		serviceName = relayer.ServiceName()
+		serviceDir = app.GetRelayerServiceDir("", serviceName)
+		configPath = app.GetRelayerServiceConfigPath("", serviceName)
+	}
+	if err := os.MkdirAll(serviceDir, constants.DefaultPerms755); err != nil {
 		return err
 	}
-	awmRelayerServicePath := filepath.Join(app.GetAWMRelayerServiceDir(""), "awm-relayer.service")
-	awmRelayerServiceConf := fmt.Sprintf(string(awmRelayerServiceTemplate), usr.Username, usr.HomeDir, relayerBin, app.GetAWMRelayerServiceConfigPath(""))
+	awmRelayerServicePath := filepath.Join(serviceDir, serviceName+".service")
+	awmRelayerServiceConf := fmt.Sprintf(string(awmRelayerServiceTemplate), usr.Username, usr.HomeDir, relayerBin, configPath)
 	if err := os.WriteFile(awmRelayerServicePath, []byte(awmRelayerServiceConf), constants.WriteReadReadPerms); err != nil {
 		return err
 	}
-	return os.RemoveAll(app.GetAWMRelayerServiceConfigPath(""))
+	return os.RemoveAll(configPath)
 }
@@ -12,9 +12,13 @@ import (
 func newRelayerCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "relayer",
-		Short: "Install and configure relayer on localhost",
-		Long: `The relayert command suite provides a collection of tools for installing
-and configuring an AWM relayer on localhost.`,
+		Short: "Install and configure relayers",
+		Long: `The relayer command suite provides a collection of tools for installing
+and configuring AWM relayers.
+
+Named relayer instances relay a chosen set of source to destination routes, each
+one with its own key and storage, either on localhost or as a service on a cluster
+node.`,
 		Run: func(cmd *cobra.Command, _ []string) {
 			err := cmd.Help()
 			if err != nil {
@@ -24,5 +28,21 @@ and configuring an AWM relayer on localhost.`,
 	}
 	cmd.AddCommand(newPrepareRelayerServiceCmd())
 	cmd.AddCommand(newAddSubnetToRelayerServiceCmd())
+	// teleporter relayer create
+	cmd.AddCommand(newRelayerCreateCmd())
+	// teleporter relayer add-route
+	cmd.AddCommand(newRelayerAddRouteCmd())
+	// teleporter relayer remove-route
+	cmd.AddCommand(newRelayerRemoveRouteCmd())
+	// teleporter relayer start
+	cmd.AddCommand(newRelayerStartCmd())
+	// teleporter relayer stop
+	cmd.AddCommand(newRelayerStopCmd())
+	// teleporter relayer logs
+	cmd.AddCommand(newRelayerLogsCmd())
+	// teleporter relayer status
+	cmd.AddCommand(newRelayerStatusCmd())
+	// teleporter relayer set-funding
+	cmd.AddCommand(newRelayerSetFundingCmd())
 	return cmd
 }
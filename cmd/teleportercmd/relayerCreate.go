@@ -0,0 +1,153 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+type RelayerCreateFlags struct {
+	Network             networkoptions.NetworkFlags
+	KeyName             string
+	RewardAddress       string
+	ProcessMissedBlocks bool
+	StorageDir          string
+	CloudNodeID         string
+	FundingKeyName      string
+	MinBalance          float64
+	TopUpBalance        float64
+}
+
+var (
+	relayerSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Cluster, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Devnet}
+	relayerCreateFlags             RelayerCreateFlags
+)
+
+// avalanche teleporter relayer create
+func newRelayerCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [relayerName]",
+		Short: "Creates a named relayer instance",
+		Long: `Creates a named AWM relayer instance on a network. Each instance relays its own set of
+source to destination routes, added with avalanche teleporter relayer add-route, and
+delivers the messages paying with its own key.
+
+By default the instance runs on localhost. With --cluster and --cloud-node-id, it runs
+as a service on the given cluster node, installed with prepareService.
+
+The instance address can be given to avalanche teleporter msg --allowed-relayers,
+so that only this instance delivers the message.
+
+The instance is reported as running dry on the destinations where its key balance is
+below --min-balance. With --funding-key, the key is topped up from the funding key on
+relayer start and status.`,
+		SilenceUsage: true,
+		RunE:         createRelayer,
+		Args:         cobra.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &relayerCreateFlags.Network, true, relayerSupportedNetworkOptions)
+	cmd.Flags().StringVarP(&relayerCreateFlags.KeyName, "key", "k", "", "stored key that pays for the deliveries. Defaults to a new key for the instance")
+	cmd.Flags().StringVar(&relayerCreateFlags.RewardAddress, "reward-address", "", "address that receives the teleporter fees. Defaults to the key address")
+	cmd.Flags().BoolVar(&relayerCreateFlags.ProcessMissedBlocks, "process-missed-blocks", false, "on start, relay the messages sent while the instance was stopped")
+	cmd.Flags().StringVar(&relayerCreateFlags.StorageDir, "storage-dir", "", "dir where the instance keeps its processed heights. Defaults to a dir of the instance")
+	cmd.Flags().StringVar(&relayerCreateFlags.CloudNodeID, "cloud-node-id", "", "run the instance as a service on the given cluster node")
+	addRelayerFundingFlags(cmd, &relayerCreateFlags.FundingKeyName, &relayerCreateFlags.MinBalance, &relayerCreateFlags.TopUpBalance)
+	return cmd
+}
+
+func createRelayer(_ *cobra.Command, args []string) error {
+	return CallCreateRelayer(args[0], relayerCreateFlags)
+}
+
+func addRelayerFundingFlags(cmd *cobra.Command, fundingKeyName *string, minBalance *float64, topUpBalance *float64) {
+	cmd.Flags().StringVar(fundingKeyName, "funding-key", "", "stored key that tops up the instance key on the destinations where it runs dry")
+	cmd.Flags().Float64Var(minBalance, "min-balance", defaultRelayerMinBalance, "balance, in native tokens, under which the instance key is considered to run dry")
+	cmd.Flags().Float64Var(topUpBalance, "top-up-balance", defaultRelayerTopUpBalance, "balance, in native tokens, the funding key tops the instance key up to")
+}
+
+func CallCreateRelayer(relayerName string, flags RelayerCreateFlags) error {
+	if !relayerNameRegexp.MatchString(relayerName) {
+		return fmt.Errorf("invalid relayer name %q: only letters, digits, '-' and '_' are allowed", relayerName)
+	}
+	if app.RelayerExists(relayerName) {
+		return fmt.Errorf("relayer %s already exists", relayerName)
+	}
+	if flags.RewardAddress != "" && !common.IsHexAddress(flags.RewardAddress) {
+		return fmt.Errorf("invalid reward address %q", flags.RewardAddress)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		flags.Network,
+		true,
+		relayerSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	if flags.CloudNodeID != "" && network.ClusterName == "" {
+		return errors.New("--cloud-node-id requires the relayer network to be a cluster: set it with --cluster")
+	}
+	keyName := flags.KeyName
+	if keyName == "" {
+		keyName = constants.AWMRelayerKeyName + "-" + relayerName
+	} else if !app.KeyExists(keyName) {
+		return fmt.Errorf("key %s not found", keyName)
+	}
+	// creates the instance key if needed
+	relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(keyName))
+	if err != nil {
+		return err
+	}
+	relayer := models.Relayer{
+		Name:                relayerName,
+		Network:             network,
+		KeyName:             keyName,
+		RewardAddress:       flags.RewardAddress,
+		ProcessMissedBlocks: flags.ProcessMissedBlocks,
+		StorageDir:          flags.StorageDir,
+		CloudNodeID:         flags.CloudNodeID,
+	}
+	if err := setRelayerFunding(&relayer, flags.FundingKeyName, flags.MinBalance, flags.TopUpBalance); err != nil {
+		return err
+	}
+	relayer.MetricsPort, err = getFreeRelayerMetricsPort(relayerName)
+	if err != nil {
+		return err
+	}
+	if relayer.StorageDir == "" {
+		relayer.StorageDir = app.GetRelayerStorageDir(relayerName)
+		if relayer.IsCloudService() {
+			relayer.StorageDir = app.GetRelayerServiceStorageDir(constants.CloudNodeCLIConfigBasePath, relayer.ServiceName())
+		}
+	}
+	if relayer.IsCloudService() {
+		host, err := getRelayerHost(relayer)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Installing relayer service %s on node %s", relayer.ServiceName(), relayer.CloudNodeID)
+		if err := ssh.RunSSHSetupAWMRelayerInstanceService(host, relayer.Name, relayer.ServiceName()); err != nil {
+			return err
+		}
+	}
+	if err := app.WriteRelayer(&relayer); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer %s created on %s", relayerName, network.Name())
+	ux.Logger.PrintToUser("Relayer address: %s (key %s)", relayerAddress, keyName)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Add routes with avalanche teleporter relayer add-route %s [source] [destination],", relayerName)
+	ux.Logger.PrintToUser("fund the relayer address on the destinations, and run it with avalanche teleporter relayer start %s", relayerName)
+	return nil
+}
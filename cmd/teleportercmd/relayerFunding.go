@@ -0,0 +1,66 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relayerFundingKeyName string
+	relayerMinBalance     float64
+	relayerTopUpBalance   float64
+	relayerDisableTopUp   bool
+)
+
+// avalanche teleporter relayer set-funding
+func newRelayerSetFundingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-funding [relayerName]",
+		Short: "Sets how a relayer instance key is checked and topped up",
+		Long: `Sets the balance under which a relayer instance key is reported as running dry on a
+destination, and the funding key that tops it up on relayer start and status.
+
+The current funding key is kept if --funding-key is not given. Use --disable-top-up to
+stop topping up the instance key.`,
+		SilenceUsage: true,
+		RunE:         setRelayerFundingCmd,
+		Args:         cobra.ExactArgs(1),
+	}
+	addRelayerFundingFlags(cmd, &relayerFundingKeyName, &relayerMinBalance, &relayerTopUpBalance)
+	cmd.Flags().BoolVar(&relayerDisableTopUp, "disable-top-up", false, "stop topping up the instance key")
+	return cmd
+}
+
+func setRelayerFundingCmd(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	switch {
+	case relayerDisableTopUp:
+		relayerFundingKeyName = ""
+	case relayerFundingKeyName == "":
+		relayerFundingKeyName = relayer.FundingKeyName
+	}
+	if err := setRelayerFunding(&relayer, relayerFundingKeyName, relayerMinBalance, relayerTopUpBalance); err != nil {
+		return err
+	}
+	if err := app.WriteRelayer(&relayer); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer %s runs dry below %s", relayer.Name, utils.FromTokenUnits(relayer.MinBalance, evmNativeTokenDecimals))
+	if relayer.HasTopUp() {
+		ux.Logger.PrintToUser(
+			"Relayer %s is topped up to %s from key %s",
+			relayer.Name,
+			utils.FromTokenUnits(relayer.TopUpBalance, evmNativeTokenDecimals),
+			relayer.FundingKeyName,
+		)
+	} else {
+		ux.Logger.PrintToUser("Relayer %s is not topped up", relayer.Name)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	evmNativeTokenDecimals = 18
+	// default balances, in native tokens, of the relayer key on the destinations
+	defaultRelayerMinBalance   = 10
+	defaultRelayerTopUpBalance = 100
+)
+
+var relayerNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func loadRelayer(relayerName string) (models.Relayer, error) {
+	if !app.RelayerExists(relayerName) {
+		return models.Relayer{}, fmt.Errorf("relayer %s not found. Create it with avalanche teleporter relayer create", relayerName)
+	}
+	return app.LoadRelayer(relayerName)
+}
+
+// getRelayerAddress returns the address the relayer instance [relayerName] delivers messages from
+func getRelayerAddress(relayerName string) (string, error) {
+	relayer, err := loadRelayer(relayerName)
+	if err != nil {
+		return "", err
+	}
+	relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(relayer.KeyName))
+	return relayerAddress, err
+}
+
+// getRelayerHost returns the cluster host a relayer instance runs on as a service
+func getRelayerHost(relayer models.Relayer) (*models.Host, error) {
+	clusterName := relayer.Network.ClusterName
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	monitoringInventoryFile := app.GetMonitoringInventoryDir(clusterName)
+	if utils.FileExists(monitoringInventoryFile) {
+		monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryFile)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, monitoringHosts...)
+	}
+	for _, host := range hosts {
+		if host.GetCloudID() == relayer.CloudNodeID {
+			return host, nil
+		}
+	}
+	return nil, fmt.Errorf("node %s of relayer %s not found in cluster %s", relayer.CloudNodeID, relayer.Name, clusterName)
+}
+
+// getRelayerChain returns the teleporter deployment of [chainName] on [network], with all the
+// messengers registered by the CLI, so that messages from previous versions are also relayed
+func getRelayerChain(network models.Network, chainName string) (teleporter.RelayerChain, error) {
+	subnetID, blockchainID, messengerAddress, registryAddress, _, err := getSubnetParams(network, chainName)
+	if err != nil {
+		return teleporter.RelayerChain{}, err
+	}
+	if blockchainID == ids.Empty || messengerAddress == "" {
+		return teleporter.RelayerChain{}, fmt.Errorf("teleporter is not deployed on %s at %s", chainName, network.Name())
+	}
+	messengerAddresses := []string{messengerAddress}
+	if !isCChain(chainName) {
+		sc, err := app.LoadSidecar(chainName)
+		if err != nil {
+			return teleporter.RelayerChain{}, err
+		}
+		for _, messenger := range sc.TeleporterMessengers[network.Name()] {
+			if !slices.Contains(messengerAddresses, messenger.Address) {
+				messengerAddresses = append(messengerAddresses, messenger.Address)
+			}
+		}
+	}
+	return teleporter.RelayerChain{
+		SubnetID:                     subnetID.String(),
+		BlockchainID:                 blockchainID.String(),
+		TeleporterMessengerAddresses: messengerAddresses,
+		TeleporterRegistryAddress:    registryAddress,
+	}, nil
+}
+
+func getRelayerRoutes(relayer models.Relayer) ([]teleporter.RelayerRoute, error) {
+	chains := map[string]teleporter.RelayerChain{}
+	getChain := func(chainName string) (teleporter.RelayerChain, error) {
+		if chain, ok := chains[chainName]; ok {
+			return chain, nil
+		}
+		chain, err := getRelayerChain(relayer.Network, chainName)
+		if err != nil {
+			return teleporter.RelayerChain{}, err
+		}
+		chains[chainName] = chain
+		return chain, nil
+	}
+	routes := []teleporter.RelayerRoute{}
+	for _, route := range relayer.Routes {
+		source, err := getChain(route.Source)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := getChain(route.Destination)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, teleporter.RelayerRoute{Source: source, Destination: destination})
+	}
+	return routes, nil
+}
+
+// getRelayerConfigPath returns the path of the awm-relayer config of [relayer]. For relayers
+// running as a service, it is the path the config is prepared at before uploading it to the node
+func getRelayerConfigPath(relayer models.Relayer) string {
+	if relayer.IsCloudService() {
+		return app.GetRelayerServiceConfigPath(app.GetNodeInstanceDirPath(relayer.CloudNodeID), relayer.ServiceName())
+	}
+	return app.GetRelayerConfigPath(relayer.Name)
+}
+
+// writeRelayerConfig writes the awm-relayer config that relays the [routes] of [relayer]
+func writeRelayerConfig(relayer models.Relayer, routes []teleporter.RelayerRoute) error {
+	relayerAddress, relayerPrivateKey, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(relayer.KeyName))
+	if err != nil {
+		return err
+	}
+	rewardAddress := relayer.RewardAddress
+	if rewardAddress == "" {
+		rewardAddress = relayerAddress
+	}
+	return teleporter.WriteRoutesRelayerConfig(
+		getRelayerConfigPath(relayer),
+		relayer.StorageDir,
+		relayer.ProcessMissedBlocks,
+		relayer.MetricsPort,
+		rewardAddress,
+		relayerPrivateKey,
+		relayer.Network,
+		routes,
+	)
+}
+
+// getFreeRelayerMetricsPort returns the first metrics port not used by other relayer than [relayerName]
+func getFreeRelayerMetricsPort(relayerName string) (uint16, error) {
+	relayerNames, err := app.GetRelayerNames()
+	if err != nil {
+		return 0, err
+	}
+	usedPorts := map[uint16]bool{}
+	for _, name := range relayerNames {
+		if name == relayerName {
+			continue
+		}
+		relayer, err := app.LoadRelayer(name)
+		if err != nil {
+			return 0, err
+		}
+		usedPorts[relayer.MetricsPort] = true
+	}
+	port := uint16(constants.AWMRelayerMetricsPort)
+	for usedPorts[port] {
+		port++
+	}
+	return port, nil
+}
+
+// setRelayerFunding sets the balance under which [relayer] is reported as running dry, and,
+// if [fundingKeyName] is given, the key that tops it up to [topUpBalance]. Balances are given
+// in native tokens
+func setRelayerFunding(relayer *models.Relayer, fundingKeyName string, minBalance float64, topUpBalance float64) error {
+	minBalanceWei, err := utils.ToTokenUnits(minBalance, evmNativeTokenDecimals)
+	if err != nil {
+		return err
+	}
+	var topUpBalanceWei *big.Int
+	if fundingKeyName != "" {
+		if !app.KeyExists(fundingKeyName) {
+			return fmt.Errorf("funding key %s not found", fundingKeyName)
+		}
+		topUpBalanceWei, err = utils.ToTokenUnits(topUpBalance, evmNativeTokenDecimals)
+		if err != nil {
+			return err
+		}
+		if topUpBalanceWei.Cmp(minBalanceWei) <= 0 {
+			return fmt.Errorf("top up balance %f must be greater than min balance %f", topUpBalance, minBalance)
+		}
+	}
+	relayer.MinBalance = minBalanceWei
+	relayer.FundingKeyName = fundingKeyName
+	relayer.TopUpBalance = topUpBalanceWei
+	return nil
+}
+
+// getRelayerMinBalance returns the balance, in wei, under which [relayer] is reported as running dry
+func getRelayerMinBalance(relayer models.Relayer) *big.Int {
+	if relayer.MinBalance != nil {
+		return relayer.MinBalance
+	}
+	return big.NewInt(0).Mul(big.NewInt(1e18), big.NewInt(defaultRelayerMinBalance))
+}
+
+// getRelayerFundingKey returns the private key, in hex, of the funding key of [relayer]
+func getRelayerFundingKey(relayer models.Relayer) (string, error) {
+	k, err := key.LoadSoft(relayer.Network.ID, app.GetKeyPath(relayer.FundingKeyName))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k.Raw()), nil
+}
+
+// topUpRelayer funds the key [relayerAddress] of [relayer] on [chainDesc] from the relayer funding
+// key, if its balance is below the relayer min balance
+func topUpRelayer(relayer models.Relayer, fundingKey string, rpcURL string, chainDesc string, relayerAddress string) error {
+	sent, err := teleporter.TopUpRelayer(
+		rpcURL,
+		fundingKey,
+		relayerAddress,
+		getRelayerMinBalance(relayer),
+		relayer.TopUpBalance,
+	)
+	if err != nil {
+		return fmt.Errorf("failure topping up relayer %s on %s: %w", relayer.Name, chainDesc, err)
+	}
+	if sent.Sign() > 0 {
+		ux.Logger.PrintToUser(
+			"Relayer %s topped up on %s with %s from key %s",
+			relayer.Name,
+			chainDesc,
+			utils.FromTokenUnits(sent, evmNativeTokenDecimals),
+			relayer.FundingKeyName,
+		)
+	}
+	return nil
+}
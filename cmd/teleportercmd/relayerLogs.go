@@ -0,0 +1,70 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var relayerLogsLines uint
+
+// avalanche teleporter relayer logs
+func newRelayerLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "logs [relayerName]",
+		Short:        "Shows the logs of a relayer instance",
+		Long:         `Shows the last log lines of a relayer instance, either local or running on a cluster node.`,
+		SilenceUsage: true,
+		RunE:         relayerLogs,
+		Args:         cobra.ExactArgs(1),
+	}
+	cmd.Flags().UintVar(&relayerLogsLines, "lines", 100, "number of log lines to show. 0 shows all of them")
+	return cmd
+}
+
+func relayerLogs(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	var logs string
+	if relayer.IsCloudService() {
+		host, err := getRelayerHost(relayer)
+		if err != nil {
+			return err
+		}
+		output, err := ssh.RunSSHGetAWMRelayerInstanceLogs(host, relayer.ServiceName(), relayerLogsLines)
+		if err != nil {
+			return err
+		}
+		logs = string(output)
+	} else {
+		logPath := app.GetRelayerLogPath(relayer.Name)
+		if !utils.FileExists(logPath) {
+			return fmt.Errorf("no logs found for relayer %s: it has not been started", relayer.Name)
+		}
+		bs, err := os.ReadFile(logPath)
+		if err != nil {
+			return err
+		}
+		logs = lastLines(string(bs), relayerLogsLines)
+	}
+	ux.Logger.PrintToUser("%s", strings.TrimRight(logs, "\n"))
+	return nil
+}
+
+// lastLines returns the last [n] lines of [s], or all of them if [n] is 0
+func lastLines(s string, n uint) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if n == 0 || uint(len(lines)) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[uint(len(lines))-n:], "\n")
+}
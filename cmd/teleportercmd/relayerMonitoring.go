@@ -0,0 +1,47 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// updateRelayerMonitoring makes the monitoring host of [clusterName], if any, scrape the
+// metrics of the relayer instances running on the cluster nodes
+func updateRelayerMonitoring(clusterName string) error {
+	monitoringInventoryFile := app.GetMonitoringInventoryDir(clusterName)
+	if !utils.FileExists(monitoringInventoryFile) {
+		return nil
+	}
+	monitoringHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(monitoringInventoryFile)
+	if err != nil {
+		return err
+	}
+	if len(monitoringHosts) == 0 {
+		return nil
+	}
+	relayerNames, err := app.GetRelayerNames()
+	if err != nil {
+		return err
+	}
+	relayerTargets := map[string]string{}
+	for _, relayerName := range relayerNames {
+		relayer, err := app.LoadRelayer(relayerName)
+		if err != nil {
+			return err
+		}
+		if !relayer.IsCloudService() || relayer.Network.ClusterName != clusterName || relayer.MetricsPort == 0 {
+			continue
+		}
+		host, err := getRelayerHost(relayer)
+		if err != nil {
+			return err
+		}
+		relayerTargets[relayer.Name] = fmt.Sprintf("%s:%d", host.IP, relayer.MetricsPort)
+	}
+	return ssh.RunSSHUpdateRelayerPrometheusTargets(monitoringHosts[0], relayerTargets)
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var relayerRouteBidirectional bool
+
+// avalanche teleporter relayer add-route
+func newRelayerAddRouteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-route [relayerName] [sourceSubnetName] [destinationSubnetName]",
+		Short: "Makes a relayer instance relay messages from a source to a destination",
+		Long: `Makes a relayer instance relay the teleporter messages sent from the source subnet
+to the destination subnet. Use c-chain for the C-Chain. Both subnets need teleporter
+deployed on the relayer network.
+
+Changes are applied on the next avalanche teleporter relayer start.`,
+		SilenceUsage: true,
+		RunE:         addRelayerRoute,
+		Args:         cobra.ExactArgs(3),
+	}
+	cmd.Flags().BoolVar(&relayerRouteBidirectional, "bidirectional", false, "also relay from the destination to the source")
+	return cmd
+}
+
+// avalanche teleporter relayer remove-route
+func newRelayerRemoveRouteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-route [relayerName] [sourceSubnetName] [destinationSubnetName]",
+		Short: "Stops a relayer instance from relaying messages from a source to a destination",
+		Long: `Stops a relayer instance from relaying the teleporter messages sent from the source
+subnet to the destination subnet.
+
+Changes are applied on the next avalanche teleporter relayer start.`,
+		SilenceUsage: true,
+		RunE:         removeRelayerRoute,
+		Args:         cobra.ExactArgs(3),
+	}
+	cmd.Flags().BoolVar(&relayerRouteBidirectional, "bidirectional", false, "also remove the route from the destination to the source")
+	return cmd
+}
+
+func getRouteEnds(source string, destination string) (string, string, error) {
+	// c-chain is given case insensitive
+	if isCChain(source) {
+		source = strings.ToLower(source)
+	}
+	if isCChain(destination) {
+		destination = strings.ToLower(destination)
+	}
+	if source == destination {
+		return "", "", errors.New("source and destination must be different")
+	}
+	return source, destination, nil
+}
+
+func addRelayerRoute(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	source, destination, err := getRouteEnds(args[1], args[2])
+	if err != nil {
+		return err
+	}
+	for _, chainName := range []string{source, destination} {
+		if _, err := getRelayerChain(relayer.Network, chainName); err != nil {
+			return err
+		}
+	}
+	routes := [][2]string{{source, destination}}
+	if relayerRouteBidirectional {
+		routes = append(routes, [2]string{destination, source})
+	}
+	for _, route := range routes {
+		if relayer.AddRoute(route[0], route[1]) {
+			ux.Logger.PrintToUser("Relayer %s now relays %s -> %s", relayer.Name, route[0], route[1])
+		} else {
+			ux.Logger.PrintToUser("Relayer %s already relays %s -> %s", relayer.Name, route[0], route[1])
+		}
+	}
+	if err := app.WriteRelayer(&relayer); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Run avalanche teleporter relayer start %s to apply the changes", relayer.Name)
+	return nil
+}
+
+func removeRelayerRoute(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	source, destination, err := getRouteEnds(args[1], args[2])
+	if err != nil {
+		return err
+	}
+	routes := [][2]string{{source, destination}}
+	if relayerRouteBidirectional {
+		routes = append(routes, [2]string{destination, source})
+	}
+	for _, route := range routes {
+		if !relayer.RemoveRoute(route[0], route[1]) {
+			return fmt.Errorf("relayer %s does not relay %s -> %s", relayer.Name, route[0], route[1])
+		}
+		ux.Logger.PrintToUser("Relayer %s no longer relays %s -> %s", relayer.Name, route[0], route[1])
+	}
+	if err := app.WriteRelayer(&relayer); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Run avalanche teleporter relayer start %s to apply the changes", relayer.Name)
+	return nil
+}
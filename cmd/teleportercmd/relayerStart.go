@@ -0,0 +1,188 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+// avalanche teleporter relayer start
+func newRelayerStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start [relayerName]",
+		Short: "Starts a relayer instance",
+		Long: `Generates the config of a relayer instance from its routes, and starts it, restarting
+it if already running. On the local network, the instance key is funded on the
+destinations. If the instance has a funding key, the instance key is topped up on the
+destinations where it runs dry.
+
+Instances running on a cluster with monitoring are scraped by its prometheus, as
+long as their metrics port is reachable from the monitoring host.`,
+		SilenceUsage: true,
+		RunE:         startRelayer,
+		Args:         cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+// avalanche teleporter relayer stop
+func newRelayerStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "stop [relayerName]",
+		Short:        "Stops a relayer instance",
+		Long:         `Stops a relayer instance, keeping its storage, so that it can resume later.`,
+		SilenceUsage: true,
+		RunE:         stopRelayer,
+		Args:         cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func startRelayer(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	if len(relayer.Routes) == 0 {
+		return fmt.Errorf("relayer %s has no routes. Add them with avalanche teleporter relayer add-route", relayer.Name)
+	}
+	routes, err := getRelayerRoutes(relayer)
+	if err != nil {
+		return err
+	}
+	// relayers created before metrics were exposed
+	if relayer.MetricsPort == 0 {
+		relayer.MetricsPort, err = getFreeRelayerMetricsPort(relayer.Name)
+		if err != nil {
+			return err
+		}
+		if err := app.WriteRelayer(&relayer); err != nil {
+			return err
+		}
+	}
+	if err := writeRelayerConfig(relayer, routes); err != nil {
+		return err
+	}
+	if relayer.Network.Kind == models.Local {
+		if err := fundLocalRelayer(relayer, routes); err != nil {
+			return err
+		}
+	}
+	if relayer.HasTopUp() {
+		if err := topUpRelayerRoutes(relayer, routes); err != nil {
+			return err
+		}
+	}
+	if relayer.IsCloudService() {
+		host, err := getRelayerHost(relayer)
+		if err != nil {
+			return err
+		}
+		if err := ssh.RunSSHUploadNodeAWMRelayerInstanceConfig(host, app.GetNodeInstanceDirPath(relayer.CloudNodeID), relayer.ServiceName()); err != nil {
+			return err
+		}
+		// restart, to apply config changes
+		if err := ssh.RunSSHStopAWMRelayerInstanceService(host, relayer.ServiceName()); err != nil {
+			return err
+		}
+		if err := ssh.RunSSHStartAWMRelayerInstanceService(host, relayer.ServiceName()); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Relayer %s started as service %s on node %s", relayer.Name, relayer.ServiceName(), relayer.CloudNodeID)
+		return updateRelayerMonitoring(relayer.Network.ClusterName)
+	}
+	if err := teleporter.StartRelayer(
+		app.GetAWMRelayerBinDir(),
+		getRelayerConfigPath(relayer),
+		app.GetRelayerLogPath(relayer.Name),
+		app.GetRelayerRunPath(relayer.Name),
+	); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer %s started. Logs at %s", relayer.Name, app.GetRelayerLogPath(relayer.Name))
+	return nil
+}
+
+// fundLocalRelayer funds the key of [relayer] on the destinations of [routes] with the ewoq key
+func fundLocalRelayer(relayer models.Relayer, routes []teleporter.RelayerRoute) error {
+	relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(relayer.KeyName))
+	if err != nil {
+		return err
+	}
+	k, err := key.LoadEwoq(relayer.Network.ID)
+	if err != nil {
+		return err
+	}
+	funded := map[string]bool{}
+	for _, route := range routes {
+		if funded[route.Destination.BlockchainID] {
+			continue
+		}
+		if err := teleporter.FundRelayer(
+			relayer.Network.BlockchainEndpoint(route.Destination.BlockchainID),
+			hex.EncodeToString(k.Raw()),
+			relayerAddress,
+		); err != nil {
+			return err
+		}
+		funded[route.Destination.BlockchainID] = true
+	}
+	return nil
+}
+
+// topUpRelayerRoutes tops up the key of [relayer] on the destinations of [routes] where it runs dry
+func topUpRelayerRoutes(relayer models.Relayer, routes []teleporter.RelayerRoute) error {
+	relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(relayer.KeyName))
+	if err != nil {
+		return err
+	}
+	fundingKey, err := getRelayerFundingKey(relayer)
+	if err != nil {
+		return err
+	}
+	toppedUp := map[string]bool{}
+	for i, route := range routes {
+		if toppedUp[route.Destination.BlockchainID] {
+			continue
+		}
+		if err := topUpRelayer(
+			relayer,
+			fundingKey,
+			relayer.Network.BlockchainEndpoint(route.Destination.BlockchainID),
+			relayer.Routes[i].Destination,
+			relayerAddress,
+		); err != nil {
+			return err
+		}
+		toppedUp[route.Destination.BlockchainID] = true
+	}
+	return nil
+}
+
+func stopRelayer(_ *cobra.Command, args []string) error {
+	relayer, err := loadRelayer(args[0])
+	if err != nil {
+		return err
+	}
+	if relayer.IsCloudService() {
+		host, err := getRelayerHost(relayer)
+		if err != nil {
+			return err
+		}
+		if err := ssh.RunSSHStopAWMRelayerInstanceService(host, relayer.ServiceName()); err != nil {
+			return err
+		}
+	} else if err := teleporter.StopRelayer(app.GetRelayerRunPath(relayer.Name)); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Relayer %s stopped", relayer.Name)
+	return nil
+}
@@ -0,0 +1,280 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relayerStatusMinBalance float64
+	relayerStatusSkipTopUp  bool
+)
+
+// avalanche teleporter relayer status
+func newRelayerStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [relayerName...]",
+		Short: "Shows the status of relayer instances",
+		Long: `Shows, for the given relayer instances, or for all of them if none is given, where
+they run, whether they are running, their address and their routes.
+
+For each started instance, it also shows, from its relayer config, the instance key
+balance on each destination, and, from the relayer storage, the last height processed
+on each source, compared to the source height. It warns about the destinations where
+the instance key runs dry, after topping it up if the instance has a funding key.`,
+		SilenceUsage: true,
+		RunE:         relayerStatus,
+	}
+	cmd.Flags().Float64Var(&relayerStatusMinBalance, "min-balance", 0, "warn below this balance, in native tokens, instead of the instance min balance")
+	cmd.Flags().BoolVar(&relayerStatusSkipTopUp, "skip-top-up", false, "do not top up the instance keys")
+	return cmd
+}
+
+func relayerStatus(_ *cobra.Command, args []string) error {
+	relayerNames := args
+	if len(relayerNames) == 0 {
+		var err error
+		relayerNames, err = app.GetRelayerNames()
+		if err != nil {
+			return err
+		}
+		if len(relayerNames) == 0 {
+			ux.Logger.PrintToUser("No relayer instances found. Create one with avalanche teleporter relayer create")
+			return nil
+		}
+	}
+	relayers := []models.Relayer{}
+	for _, relayerName := range relayerNames {
+		relayer, err := loadRelayer(relayerName)
+		if err != nil {
+			return err
+		}
+		if relayerStatusMinBalance != 0 {
+			relayer.MinBalance, err = utils.ToTokenUnits(relayerStatusMinBalance, evmNativeTokenDecimals)
+			if err != nil {
+				return err
+			}
+		}
+		relayers = append(relayers, relayer)
+	}
+	header := []string{"Relayer", "Network", "Runs On", "Status", "Address", "Routes"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	warnings := []string{}
+	for _, relayer := range relayers {
+		relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(relayer.KeyName))
+		if err != nil {
+			return err
+		}
+		runsOn := "localhost"
+		if relayer.IsCloudService() {
+			runsOn = relayer.CloudNodeID
+		}
+		routes := []string{}
+		for _, route := range relayer.Routes {
+			routes = append(routes, fmt.Sprintf("%s -> %s", route.Source, route.Destination))
+		}
+		runStatus := getRelayerRunStatus(relayer)
+		if runStatus != "running" {
+			warnings = append(warnings, fmt.Sprintf("relayer %s is %s", relayer.Name, runStatus))
+		}
+		table.Append([]string{
+			relayer.Name,
+			relayer.Network.Name(),
+			runsOn,
+			runStatus,
+			relayerAddress,
+			strings.Join(routes, "\n"),
+		})
+	}
+	table.Render()
+	for _, relayer := range relayers {
+		relayerWarnings, err := printRelayerChainsStatus(relayer)
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, relayerWarnings...)
+	}
+	if len(warnings) > 0 {
+		ux.Logger.PrintToUser("")
+		for _, warning := range warnings {
+			ux.Logger.RedXToUser("%s", warning)
+		}
+	}
+	return nil
+}
+
+// getRelayerRunStatus returns whether [relayer] is running, or the reason it could not be checked
+func getRelayerRunStatus(relayer models.Relayer) string {
+	var (
+		running bool
+		err     error
+	)
+	if relayer.IsCloudService() {
+		var host *models.Host
+		host, err = getRelayerHost(relayer)
+		if err == nil {
+			running, err = ssh.RunSSHCheckAWMRelayerInstanceService(host, relayer.ServiceName())
+		}
+	} else {
+		running, _, err = teleporter.IsRelayerRunning(app.GetRelayerRunPath(relayer.Name))
+	}
+	switch {
+	case err != nil:
+		return fmt.Sprintf("unknown: %s", err)
+	case running:
+		return "running"
+	default:
+		return "stopped"
+	}
+}
+
+// printRelayerChainsStatus prints, for the chains in the config of [relayer], its key balance
+// on the destinations, and its processed height on the sources. Tops up the destinations
+// where the key runs dry if the relayer has a funding key. Returns warnings about the chains
+func printRelayerChainsStatus(relayer models.Relayer) ([]string, error) {
+	configPath := getRelayerConfigPath(relayer)
+	if !utils.FileExists(configPath) {
+		return []string{fmt.Sprintf("relayer %s has not been started yet: no relayer config found", relayer.Name)}, nil
+	}
+	relayerConfig, err := teleporter.LoadRelayerConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	warnings := []string{}
+	chainNames := getRelayerChainNames(relayer)
+	getChainDesc := func(blockchainID string) string {
+		if chainName, ok := chainNames[blockchainID]; ok {
+			return chainName
+		}
+		return blockchainID
+	}
+	destinations := teleporter.GetRelayerDestinationsStatus(relayerConfig)
+	if relayer.HasTopUp() && !relayerStatusSkipTopUp {
+		fundingKey, err := getRelayerFundingKey(relayer)
+		if err != nil {
+			return nil, err
+		}
+		toppedUp := false
+		for _, destination := range destinations {
+			if destination.Err != nil || destination.Balance.Cmp(getRelayerMinBalance(relayer)) >= 0 {
+				continue
+			}
+			if err := topUpRelayer(relayer, fundingKey, destination.RPCEndpoint, getChainDesc(destination.BlockchainID), destination.Address); err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			toppedUp = true
+		}
+		if toppedUp {
+			destinations = teleporter.GetRelayerDestinationsStatus(relayerConfig)
+		}
+	}
+	processedHeights, err := getRelayerProcessedHeights(relayer)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("failure reading relayer %s storage: %s", relayer.Name, err))
+	}
+	sources := teleporter.GetRelayerSourcesStatus(relayerConfig, processedHeights)
+	// a row per chain, either source, destination or both
+	rows := map[string][]string{}
+	blockchainIDs := []string{}
+	getRow := func(blockchainID string) []string {
+		if _, ok := rows[blockchainID]; !ok {
+			rows[blockchainID] = []string{getChainDesc(blockchainID), "-", "-", "-"}
+			blockchainIDs = append(blockchainIDs, blockchainID)
+		}
+		return rows[blockchainID]
+	}
+	for _, source := range sources {
+		row := getRow(source.BlockchainID)
+		if source.Processed {
+			row[2] = fmt.Sprint(source.ProcessedHeight)
+		} else {
+			row[2] = "none yet"
+		}
+		if source.Err != nil {
+			row[3] = "unknown"
+			warnings = append(warnings, fmt.Sprintf("failure getting %s height: %s", getChainDesc(source.BlockchainID), source.Err))
+		} else {
+			row[3] = fmt.Sprint(source.Height)
+		}
+	}
+	for _, destination := range destinations {
+		row := getRow(destination.BlockchainID)
+		chainDesc := getChainDesc(destination.BlockchainID)
+		if destination.Err != nil {
+			row[1] = "unknown"
+			warnings = append(warnings, fmt.Sprintf("failure getting relayer %s balance on %s: %s", relayer.Name, chainDesc, destination.Err))
+			continue
+		}
+		row[1] = utils.FromTokenUnits(destination.Balance, evmNativeTokenDecimals)
+		if minBalance := getRelayerMinBalance(relayer); destination.Balance.Cmp(minBalance) < 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"relayer %s runs dry on %s: its address %s has %s, below %s",
+				relayer.Name,
+				chainDesc,
+				destination.Address,
+				utils.FromTokenUnits(destination.Balance, evmNativeTokenDecimals),
+				utils.FromTokenUnits(minBalance, evmNativeTokenDecimals),
+			))
+		}
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Relayer %s chains:", relayer.Name)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Chain", "Relayer Balance", "Processed Height", "Chain Height"})
+	table.SetRowLine(true)
+	for _, blockchainID := range blockchainIDs {
+		table.Append(rows[blockchainID])
+	}
+	table.Render()
+	return warnings, nil
+}
+
+// getRelayerChainNames returns the names of the chains of the routes of [relayer], indexed
+// by blockchain ID. Chains that can not be resolved are left out
+func getRelayerChainNames(relayer models.Relayer) map[string]string {
+	chainNames := map[string]string{}
+	resolved := map[string]bool{}
+	for _, route := range relayer.Routes {
+		for _, chainName := range []string{route.Source, route.Destination} {
+			if resolved[chainName] {
+				continue
+			}
+			if chain, err := getRelayerChain(relayer.Network, chainName); err == nil {
+				chainNames[chain.BlockchainID] = chainName
+			}
+			resolved[chainName] = true
+		}
+	}
+	return chainNames
+}
+
+// getRelayerProcessedHeights returns the last heights processed by [relayer], indexed by
+// source blockchain ID, read from its storage
+func getRelayerProcessedHeights(relayer models.Relayer) (map[string]uint64, error) {
+	if !relayer.IsCloudService() {
+		return teleporter.GetRelayerStorageHeights(relayer.StorageDir)
+	}
+	host, err := getRelayerHost(relayer)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ssh.RunSSHGetAWMRelayerInstanceStorage(host, relayer.StorageDir)
+	if err != nil {
+		return nil, err
+	}
+	return teleporter.ParseRelayerStorageHeights(files)
+}
@@ -28,9 +28,15 @@ with Teleporter-Enabled Subnets.`,
 	app = injectedApp
 	// teleporter msg
 	cmd.AddCommand(newMsgCmd())
+	// teleporter trace
+	cmd.AddCommand(newTraceCmd())
 	// teleporter deploy
 	cmd.AddCommand(newDeployCmd())
 	// teleporter relayer
 	cmd.AddCommand(newRelayerCmd())
+	// teleporter upgrade
+	cmd.AddCommand(newUpgradeCmd())
+	// teleporter versions
+	cmd.AddCommand(newVersionsCmd())
 	return cmd
 }
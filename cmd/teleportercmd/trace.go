@@ -0,0 +1,531 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	teleportermessenger "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/TeleporterMessenger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// messageStage is how far a teleporter message got on its way to the destination
+type messageStage string
+
+const (
+	// the message was sent on the source chain
+	stageSent messageStage = "sent"
+	// the source validators signed the warp message, so a relayer can deliver it
+	stageSigned messageStage = "signed"
+	// a relayer delivered the message on the destination chain
+	stageDelivered messageStage = "delivered"
+	// the message was delivered and executed by the destination contract
+	stageExecuted messageStage = "executed"
+	// the message was delivered but its execution failed. It can be retried
+	stageExecutionFailed messageStage = "execution-failed"
+
+	traceTimeout = 2 * time.Minute
+)
+
+var (
+	traceDestSubnetName    string
+	traceSourceStartBlock  uint64
+	traceDestStartBlock    uint64
+	traceJSONOutput        bool
+	errMessageSendNotFound = errors.New("no teleporter message sent")
+)
+
+// messageTrace is the state of a teleporter message on its source and destination chains
+type messageTrace struct {
+	MessageID               string       `json:"messageID"`
+	Stage                   messageStage `json:"stage"`
+	SourceSubnet            string       `json:"sourceSubnet"`
+	SourceBlockchainID      string       `json:"sourceBlockchainID"`
+	DestinationSubnet       string       `json:"destinationSubnet,omitempty"`
+	DestinationBlockchainID string       `json:"destinationBlockchainID"`
+	SendTxHash              string       `json:"sendTxHash"`
+	SendBlock               uint64       `json:"sendBlock"`
+	SendRetries             int          `json:"sendRetries"`
+	Nonce                   string       `json:"nonce"`
+	OriginSender            string       `json:"originSender"`
+	DestinationAddress      string       `json:"destinationAddress"`
+	RequiredGasLimit        string       `json:"requiredGasLimit"`
+	AllowedRelayers         []string     `json:"allowedRelayers"`
+	FeeTokenAddress         string       `json:"feeTokenAddress"`
+	InitialFee              string       `json:"initialFee"`
+	FeeAdditions            int          `json:"feeAdditions"`
+	CurrentFee              string       `json:"currentFee"`
+	WarpMessageID           string       `json:"warpMessageID,omitempty"`
+	Signed                  bool         `json:"signed"`
+	Delivered               bool         `json:"delivered"`
+	DeliveryTxHash          string       `json:"deliveryTxHash,omitempty"`
+	DeliveryBlock           uint64       `json:"deliveryBlock,omitempty"`
+	Deliverer               string       `json:"deliverer,omitempty"`
+	RewardRedeemer          string       `json:"rewardRedeemer,omitempty"`
+	ExecutionFailures       int          `json:"executionFailures"`
+	ExecutionTxHash         string       `json:"executionTxHash,omitempty"`
+	RetryPending            bool         `json:"retryPending"`
+	ReceiptReceived         bool         `json:"receiptReceived"`
+	ReceiptTxHash           string       `json:"receiptTxHash,omitempty"`
+	RelayerRewardAddress    string       `json:"relayerRewardAddress,omitempty"`
+	Notes                   []string     `json:"notes,omitempty"`
+}
+
+// avalanche teleporter trace
+func newTraceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace [sourceSubnetName] [txHash | messageID]",
+		Short: "Reports the delivery stage of a teleporter message",
+		Long: `Traces a teleporter message sent from the given source subnet, given the hash
+of the transaction that sent it or its teleporter message ID.
+
+The message is followed from its SendCrossChainMessage event to the destination
+chain, and its stage is reported: sent, signed (the source validators signed it,
+so a relayer can deliver it), delivered, executed or execution-failed. Fee
+additions, send and execution retries, and the receipt returned to the source
+are reported as well.
+
+The destination subnet is found among the local subnets by the destination
+blockchain ID of the message. Use --destination to set it otherwise.
+
+Events are searched from the first block of each chain. On RPCs that limit the
+block range of log queries, set the blocks to start from with --source-start-block
+and --destination-start-block.`,
+		SilenceUsage: true,
+		RunE:         traceMsg,
+		Args:         cobra.ExactArgs(2),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, msgSupportedNetworkOptions)
+	cmd.Flags().StringVar(&traceDestSubnetName, "destination", "", "destination subnet of the message (use c-chain for the C-Chain)")
+	cmd.Flags().Uint64Var(&traceSourceStartBlock, "source-start-block", 0, "search the message send from this block of the source chain, if given a message ID")
+	cmd.Flags().Uint64Var(&traceDestStartBlock, "destination-start-block", 0, "search the message delivery from this block of the destination chain")
+	cmd.Flags().BoolVar(&traceJSONOutput, "json", false, "print the trace as JSON")
+	return cmd
+}
+
+func traceMsg(_ *cobra.Command, args []string) error {
+	sourceSubnetName := args[0]
+	hash, err := parseHash(args[1])
+	if err != nil {
+		return err
+	}
+	subnetNameToGetNetworkFrom := ""
+	if !isCChain(sourceSubnetName) {
+		subnetNameToGetNetworkFrom = sourceSubnetName
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		msgSupportedNetworkOptions,
+		subnetNameToGetNetworkFrom,
+	)
+	if err != nil {
+		return err
+	}
+	_, sourceChainID, sourceMessengerAddress, _, _, err := getSubnetParams(network, sourceSubnetName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), traceTimeout)
+	defer cancel()
+
+	sourceEndpoint := network.BlockchainEndpoint(sourceChainID.String())
+	sourceClient, err := evm.GetClient(sourceEndpoint)
+	if err != nil {
+		return err
+	}
+	sourceMessenger, err := teleportermessenger.NewTeleporterMessenger(common.HexToAddress(sourceMessengerAddress), sourceClient)
+	if err != nil {
+		return err
+	}
+	sendEvent, sendReceipt, err := getSendEvent(ctx, sourceClient, sourceMessenger, hash, traceSourceStartBlock)
+	if err != nil {
+		return err
+	}
+	trace := newMessageTrace(sourceSubnetName, sourceChainID, sendEvent)
+	if err := traceSource(ctx, sourceMessenger, sendEvent, trace); err != nil {
+		return err
+	}
+	if warpMessageID, ok := getWarpMessageID(sendReceipt); ok {
+		trace.WarpMessageID = warpMessageID.String()
+		if _, err := evm.CallRPC(sourceEndpoint, "warp_getMessageSignature", warpMessageID.String()); err != nil {
+			trace.Notes = append(trace.Notes, fmt.Sprintf("could not get a validator signature of the warp message: %s", err))
+		} else {
+			trace.Signed = true
+		}
+	}
+
+	destChainID := ids.ID(sendEvent.DestinationBlockchainID)
+	destSubnetName := traceDestSubnetName
+	if destSubnetName == "" {
+		destSubnetName, err = findSubnetByBlockchainID(network, destChainID)
+		if err != nil {
+			return err
+		}
+	}
+	trace.DestinationSubnet = destSubnetName
+	_, chainID, destMessengerAddress, _, _, err := getSubnetParams(network, destSubnetName)
+	if err != nil {
+		return err
+	}
+	if chainID != destChainID {
+		return fmt.Errorf("subnet %s has blockchain ID %s, but the message destination is %s", destSubnetName, chainID, destChainID)
+	}
+	destClient, err := evm.GetClient(network.BlockchainEndpoint(destChainID.String()))
+	if err != nil {
+		return err
+	}
+	destMessenger, err := teleportermessenger.NewTeleporterMessenger(common.HexToAddress(destMessengerAddress), destClient)
+	if err != nil {
+		return err
+	}
+	if err := traceDestination(ctx, destMessenger, sendEvent.MessageID, sourceChainID, traceDestStartBlock, trace); err != nil {
+		return err
+	}
+	trace.Stage = getMessageStage(trace)
+
+	if traceJSONOutput {
+		traceBytes, err := json.MarshalIndent(trace, "", "  ")
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("%s", traceBytes)
+		return nil
+	}
+	printMessageTrace(trace)
+	return nil
+}
+
+// parseHash parses a 32 bytes hex string, as tx hashes and teleporter message IDs are
+func parseHash(hashStr string) (common.Hash, error) {
+	hashBytes, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(hashStr), "0x"))
+	if err != nil || len(hashBytes) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid tx hash or message ID %q: expected %d hex encoded bytes", hashStr, common.HashLength)
+	}
+	return common.BytesToHash(hashBytes), nil
+}
+
+// getSendEvent returns the SendCrossChainMessage event of the message, and the receipt of the tx
+// that sent it. [hash] is first taken as the hash of the tx, and otherwise as the message ID,
+// whose event is searched from [startBlock]
+func getSendEvent(
+	ctx context.Context,
+	client ethclient.Client,
+	messenger *teleportermessenger.TeleporterMessenger,
+	hash common.Hash,
+	startBlock uint64,
+) (*teleportermessenger.TeleporterMessengerSendCrossChainMessage, *types.Receipt, error) {
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	switch {
+	case err == nil:
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return nil, nil, fmt.Errorf("tx %s failed: %w", hash, errMessageSendNotFound)
+		}
+		event, err := evm.GetEventFromLogs(receipt.Logs, messenger.ParseSendCrossChainMessage)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tx %s: %w", hash, errMessageSendNotFound)
+		}
+		return event, receipt, nil
+	case !errors.Is(err, interfaces.NotFound):
+		return nil, nil, err
+	}
+	it, err := messenger.FilterSendCrossChainMessage(&bind.FilterOpts{Start: startBlock, Context: ctx}, [][32]byte{hash}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	if !it.Next() {
+		if it.Error() != nil {
+			return nil, nil, it.Error()
+		}
+		return nil, nil, fmt.Errorf("%s is not a tx hash or message ID: %w", hash, errMessageSendNotFound)
+	}
+	event := it.Event
+	receipt, err = client.TransactionReceipt(ctx, event.Raw.TxHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return event, receipt, nil
+}
+
+func newMessageTrace(
+	sourceSubnetName string,
+	sourceChainID ids.ID,
+	event *teleportermessenger.TeleporterMessengerSendCrossChainMessage,
+) *messageTrace {
+	allowedRelayers := []string{}
+	for _, relayer := range event.Message.AllowedRelayerAddresses {
+		allowedRelayers = append(allowedRelayers, relayer.Hex())
+	}
+	return &messageTrace{
+		MessageID:               common.Hash(event.MessageID).Hex(),
+		Stage:                   stageSent,
+		SourceSubnet:            sourceSubnetName,
+		SourceBlockchainID:      sourceChainID.String(),
+		DestinationBlockchainID: ids.ID(event.DestinationBlockchainID).String(),
+		SendTxHash:              event.Raw.TxHash.Hex(),
+		SendBlock:               event.Raw.BlockNumber,
+		Nonce:                   bigIntString(event.Message.MessageNonce),
+		OriginSender:            event.Message.OriginSenderAddress.Hex(),
+		DestinationAddress:      event.Message.DestinationAddress.Hex(),
+		RequiredGasLimit:        bigIntString(event.Message.RequiredGasLimit),
+		AllowedRelayers:         allowedRelayers,
+		FeeTokenAddress:         event.FeeInfo.FeeTokenAddress.Hex(),
+		InitialFee:              bigIntString(event.FeeInfo.Amount),
+	}
+}
+
+// traceSource sets on [trace] the send retries, fee additions and receipt of the message on
+// the source chain, searching them from the block the message was sent at
+func traceSource(
+	ctx context.Context,
+	messenger *teleportermessenger.TeleporterMessenger,
+	event *teleportermessenger.TeleporterMessengerSendCrossChainMessage,
+	trace *messageTrace,
+) error {
+	opts := &bind.FilterOpts{Start: event.Raw.BlockNumber, Context: ctx}
+	messageIDs := [][32]byte{event.MessageID}
+	sendIt, err := messenger.FilterSendCrossChainMessage(opts, messageIDs, nil)
+	if err != nil {
+		return err
+	}
+	defer sendIt.Close()
+	for sendIt.Next() {
+		if sendIt.Event.Raw.TxHash != event.Raw.TxHash {
+			trace.SendRetries++
+		}
+	}
+	if err := sendIt.Error(); err != nil {
+		return err
+	}
+	feeIt, err := messenger.FilterAddFeeAmount(opts, messageIDs)
+	if err != nil {
+		return err
+	}
+	defer feeIt.Close()
+	for feeIt.Next() {
+		trace.FeeAdditions++
+	}
+	if err := feeIt.Error(); err != nil {
+		return err
+	}
+	_, currentFee, err := messenger.GetFeeInfo(&bind.CallOpts{Context: ctx}, event.MessageID)
+	if err != nil {
+		return err
+	}
+	trace.CurrentFee = bigIntString(currentFee)
+	receiptIt, err := messenger.FilterReceiptReceived(opts, messageIDs, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer receiptIt.Close()
+	if receiptIt.Next() {
+		trace.ReceiptReceived = true
+		trace.ReceiptTxHash = receiptIt.Event.Raw.TxHash.Hex()
+		trace.RelayerRewardAddress = receiptIt.Event.RelayerRewardAddress.Hex()
+	}
+	return receiptIt.Error()
+}
+
+// traceDestination sets on [trace] the delivery and execution of message [messageID] on the
+// destination chain, searching their events from [startBlock]
+func traceDestination(
+	ctx context.Context,
+	messenger *teleportermessenger.TeleporterMessenger,
+	messageID [32]byte,
+	sourceChainID ids.ID,
+	startBlock uint64,
+	trace *messageTrace,
+) error {
+	callOpts := &bind.CallOpts{Context: ctx}
+	delivered, err := messenger.MessageReceived(callOpts, messageID)
+	if err != nil {
+		return err
+	}
+	if !delivered {
+		return nil
+	}
+	trace.Delivered = true
+	failedMessageHash, err := messenger.ReceivedFailedMessageHashes(callOpts, messageID)
+	if err != nil {
+		return err
+	}
+	trace.RetryPending = failedMessageHash != [32]byte{}
+	if relayerRewardAddress, err := messenger.GetRelayerRewardAddress(callOpts, messageID); err == nil && trace.RelayerRewardAddress == "" {
+		trace.RelayerRewardAddress = relayerRewardAddress.Hex()
+	}
+
+	opts := &bind.FilterOpts{Start: startBlock, Context: ctx}
+	messageIDs := [][32]byte{messageID}
+	sourceChainIDs := [][32]byte{sourceChainID}
+	if err := traceDestinationEvents(opts, messenger, messageIDs, sourceChainIDs, trace); err != nil {
+		// delivery was already confirmed by the messenger state, so only the event details are missing
+		trace.Notes = append(trace.Notes, fmt.Sprintf("could not search the delivery events on the destination: %s", err))
+	}
+	return nil
+}
+
+func traceDestinationEvents(
+	opts *bind.FilterOpts,
+	messenger *teleportermessenger.TeleporterMessenger,
+	messageIDs [][32]byte,
+	sourceChainIDs [][32]byte,
+	trace *messageTrace,
+) error {
+	receiveIt, err := messenger.FilterReceiveCrossChainMessage(opts, messageIDs, sourceChainIDs, nil)
+	if err != nil {
+		return err
+	}
+	defer receiveIt.Close()
+	if receiveIt.Next() {
+		trace.DeliveryTxHash = receiveIt.Event.Raw.TxHash.Hex()
+		trace.DeliveryBlock = receiveIt.Event.Raw.BlockNumber
+		trace.Deliverer = receiveIt.Event.Deliverer.Hex()
+		trace.RewardRedeemer = receiveIt.Event.RewardRedeemer.Hex()
+	}
+	if err := receiveIt.Error(); err != nil {
+		return err
+	}
+	failedIt, err := messenger.FilterMessageExecutionFailed(opts, messageIDs, sourceChainIDs)
+	if err != nil {
+		return err
+	}
+	defer failedIt.Close()
+	for failedIt.Next() {
+		trace.ExecutionFailures++
+	}
+	if err := failedIt.Error(); err != nil {
+		return err
+	}
+	executedIt, err := messenger.FilterMessageExecuted(opts, messageIDs, sourceChainIDs)
+	if err != nil {
+		return err
+	}
+	defer executedIt.Close()
+	if executedIt.Next() {
+		trace.ExecutionTxHash = executedIt.Event.Raw.TxHash.Hex()
+	}
+	return executedIt.Error()
+}
+
+// getMessageStage returns the furthest stage [trace] shows the message got to
+func getMessageStage(trace *messageTrace) messageStage {
+	switch {
+	case trace.ExecutionTxHash != "":
+		return stageExecuted
+	case trace.RetryPending:
+		return stageExecutionFailed
+	case trace.Delivered:
+		return stageDelivered
+	case trace.Signed:
+		return stageSigned
+	default:
+		return stageSent
+	}
+}
+
+// getWarpMessageID returns the ID of the warp message sent by the tx of [receipt]
+func getWarpMessageID(receipt *types.Receipt) (ids.ID, bool) {
+	sendWarpMessageEventID := warp.WarpABI.Events["SendWarpMessage"].ID
+	for _, log := range receipt.Logs {
+		if log.Address == warp.ContractAddress && len(log.Topics) > 2 && log.Topics[0] == sendWarpMessageEventID {
+			return ids.ID(log.Topics[2]), true
+		}
+	}
+	return ids.Empty, false
+}
+
+// findSubnetByBlockchainID returns the name of the local subnet, or C-Chain, with
+// blockchain ID [blockchainID] on [network]
+func findSubnetByBlockchainID(network models.Network, blockchainID ids.ID) (string, error) {
+	if cChainID, err := subnet.GetChainID(network, "C"); err == nil && cChainID == blockchainID {
+		return "c-chain", nil
+	}
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return "", err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return "", err
+		}
+		if sc.Networks[network.Name()].BlockchainID == blockchainID {
+			return subnetName, nil
+		}
+	}
+	return "", fmt.Errorf("no subnet found with blockchain ID %s on network %s: set it with --destination", blockchainID, network.Name())
+}
+
+func bigIntString(n *big.Int) string {
+	if n == nil {
+		return "0"
+	}
+	return n.String()
+}
+
+func printMessageTrace(trace *messageTrace) {
+	ux.Logger.PrintToUser("Message %s: %s", trace.MessageID, trace.Stage)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Source: %s (%s)", trace.SourceSubnet, trace.SourceBlockchainID)
+	ux.Logger.PrintToUser("  Sent at tx %s, block %d, nonce %s", trace.SendTxHash, trace.SendBlock, trace.Nonce)
+	ux.Logger.PrintToUser("  From %s to %s, required gas limit %s", trace.OriginSender, trace.DestinationAddress, trace.RequiredGasLimit)
+	if len(trace.AllowedRelayers) > 0 {
+		ux.Logger.PrintToUser("  Allowed relayers: %s", strings.Join(trace.AllowedRelayers, ", "))
+	}
+	ux.Logger.PrintToUser("  Fee: %s of token %s initially, %s now after %d addition(s)", trace.InitialFee, trace.FeeTokenAddress, trace.CurrentFee, trace.FeeAdditions)
+	if trace.SendRetries > 0 {
+		ux.Logger.PrintToUser("  Send retries: %d", trace.SendRetries)
+	}
+	if trace.WarpMessageID != "" {
+		ux.Logger.PrintToUser("  Warp message %s signed by validators: %t", trace.WarpMessageID, trace.Signed)
+	}
+	if trace.ReceiptReceived {
+		ux.Logger.PrintToUser("  Receipt received at tx %s, relayer reward address %s", trace.ReceiptTxHash, trace.RelayerRewardAddress)
+	} else {
+		ux.Logger.PrintToUser("  Receipt not received yet")
+	}
+	ux.Logger.PrintToUser("Destination: %s (%s)", trace.DestinationSubnet, trace.DestinationBlockchainID)
+	if !trace.Delivered {
+		ux.Logger.PrintToUser("  Not delivered yet")
+	} else {
+		if trace.DeliveryTxHash != "" {
+			ux.Logger.PrintToUser("  Delivered at tx %s, block %d, by %s", trace.DeliveryTxHash, trace.DeliveryBlock, trace.Deliverer)
+		} else {
+			ux.Logger.PrintToUser("  Delivered")
+		}
+		if trace.ExecutionFailures > 0 {
+			ux.Logger.PrintToUser("  Execution failures: %d", trace.ExecutionFailures)
+		}
+		switch {
+		case trace.ExecutionTxHash != "":
+			ux.Logger.PrintToUser("  Executed at tx %s", trace.ExecutionTxHash)
+		case trace.RetryPending:
+			ux.Logger.PrintToUser("  Execution failed: it can be retried with retryMessageExecution on the destination messenger")
+		}
+	}
+	for _, note := range trace.Notes {
+		ux.Logger.PrintToUser("Note: %s", note)
+	}
+}
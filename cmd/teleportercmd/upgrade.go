@@ -0,0 +1,280 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	ANRclient "github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	upgradeTmpSnapshotInfix = "-teleporter-upgrade-"
+	timestampFormat         = "20060102150405"
+)
+
+var (
+	upgradeSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Cluster, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Devnet}
+	upgradeNetworkFlags            networkoptions.NetworkFlags
+	upgradeVersion                 string
+	upgradeKeyName                 string
+)
+
+// avalanche teleporter upgrade
+func newUpgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade [subnetName]",
+		Short: "Upgrades the teleporter messenger of a subnet to a new version",
+		Long: `Deploys the teleporter messenger of the given release on the subnet, and registers
+it as the latest version on the subnet teleporter registry, so that teleporter apps
+using the registry move to it.
+
+The new messenger is registered through a warp off-chain message signed by the subnet
+validators, added to the subnet chain config. On the local network, the nodes are
+restarted with the new chain config, and the messenger is registered right away. On
+other networks, the message has to be added to the chain config of the validators:
+the command prints it, and registers the messenger once it is run again after the
+validators are restarted.
+
+The sidecar and the relayer configs are updated to use the new messenger, keeping
+the relay of the messages sent by the previous ones. Registered versions are shown
+with avalanche teleporter versions.`,
+		SilenceUsage: true,
+		RunE:         upgrade,
+		Args:         cobra.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &upgradeNetworkFlags, true, upgradeSupportedNetworkOptions)
+	cmd.Flags().StringVar(&upgradeVersion, "version", "", "teleporter release to upgrade to (e.g. v1.0.0)")
+	cmd.Flags().StringVarP(&upgradeKeyName, "key", "k", "", "pay the upgrade txs with the given stored key. Defaults to the subnet teleporter key")
+	return cmd
+}
+
+func upgrade(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if upgradeVersion == "" {
+		return errors.New("the teleporter release to upgrade to is required: set it with --version")
+	}
+	if isCChain(subnetName) {
+		return errors.New("upgrading the C-Chain teleporter messenger is not supported")
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		upgradeNetworkFlags,
+		true,
+		upgradeSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	subnetID, blockchainID, messengerAddress, registryAddress, k, err := getSubnetParams(network, subnetName)
+	if err != nil {
+		return err
+	}
+	if registryAddress == "" {
+		return fmt.Errorf("teleporter registry address for subnet %s not found on network %s", subnetName, network.Name())
+	}
+	if upgradeKeyName != "" {
+		k, err = key.LoadSoft(network.ID, app.GetKeyPath(upgradeKeyName))
+		if err != nil {
+			return err
+		}
+	}
+	privateKey := hex.EncodeToString(k.Raw())
+	rpcURL := network.BlockchainEndpoint(blockchainID.String())
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	entries, latestVersion, err := teleporter.GetRegistryEntries(client, registryAddress)
+	if err != nil {
+		return err
+	}
+	td := teleporter.Deployer{}
+	_, newMessengerAddress, err := td.DeployMessenger(app.GetTeleporterBinDir(), upgradeVersion, subnetName, rpcURL, privateKey)
+	if err != nil {
+		return err
+	}
+	registryVersion := latestVersion + 1
+	if i := slices.IndexFunc(entries, func(e teleporter.RegistryEntry) bool { return strings.EqualFold(e.Address, newMessengerAddress) }); i != -1 {
+		registryVersion = entries[i].Version
+		ux.Logger.PrintToUser("Teleporter Messenger %s is already registered as version %d", newMessengerAddress, registryVersion)
+	} else {
+		msg, err := teleporter.NewRegistryMessage(network.ID, blockchainID, registryAddress, registryVersion, newMessengerAddress)
+		if err != nil {
+			return err
+		}
+		if err := addRegistryMessageToChainConfig(subnetName, msg); err != nil {
+			return err
+		}
+		if network.Kind == models.Local {
+			if err := applyLocalChainConfig(subnetName, blockchainID); err != nil {
+				return err
+			}
+		}
+		signedMessage, err := teleporter.GetSignedRegistryMessage(network.Endpoint, subnetID, blockchainID, msg)
+		if err != nil {
+			if network.Kind == models.Local {
+				return fmt.Errorf("failure getting the validator signatures of the registry message: %w", err)
+			}
+			printRegistryMessageInstructions(subnetName, msg, err)
+			return nil
+		}
+		ux.Logger.PrintToUser("Registering Teleporter Messenger %s as version %d of the Teleporter Registry", newMessengerAddress, registryVersion)
+		registryVersion, err = teleporter.AddProtocolVersion(client, privateKey, registryAddress, signedMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	// update sidecar
+	networkInfo := sc.Networks[network.Name()]
+	networkInfo.TeleporterMessengerAddress = newMessengerAddress
+	sc.Networks[network.Name()] = networkInfo
+	if sc.TeleporterMessengers == nil {
+		sc.TeleporterMessengers = map[string][]models.TeleporterMessenger{}
+	}
+	messengers := sc.TeleporterMessengers[network.Name()]
+	if len(messengers) == 0 && messengerAddress != newMessengerAddress {
+		// the messenger deployed with the registry is its first version
+		messengers = append(messengers, models.TeleporterMessenger{Version: sc.TeleporterVersion, RegistryVersion: 1, Address: messengerAddress})
+	}
+	if !slices.ContainsFunc(messengers, func(m models.TeleporterMessenger) bool { return m.Address == newMessengerAddress }) {
+		messengers = append(messengers, models.TeleporterMessenger{Version: upgradeVersion, RegistryVersion: registryVersion, Address: newMessengerAddress})
+	}
+	sc.TeleporterMessengers[network.Name()] = messengers
+	sc.TeleporterVersion = upgradeVersion
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+
+	if err := updateRelayerConfigsMessenger(network, blockchainID, newMessengerAddress); err != nil {
+		return err
+	}
+	ux.Logger.GreenCheckmarkToUser("Subnet %s upgraded to Teleporter Messenger %s (%s), registry version %d", subnetName, upgradeVersion, newMessengerAddress, registryVersion)
+	ux.Logger.PrintToUser("")
+	return printRegisteredVersions(network, subnetName)
+}
+
+// addRegistryMessageToChainConfig adds [msg] to the warp off-chain messages of the chain config
+// of [subnetName], so that it is used by the next deploys and node syncs
+func addRegistryMessageToChainConfig(subnetName string, msg *avalancheWarp.UnsignedMessage) error {
+	chainConfigPath := app.GetChainConfigPath(subnetName)
+	var chainConfigBytes []byte
+	if utils.FileExists(chainConfigPath) {
+		var err error
+		chainConfigBytes, err = os.ReadFile(chainConfigPath)
+		if err != nil {
+			return err
+		}
+	}
+	chainConfigBytes, err := teleporter.AddOffChainMessageToChainConfig(chainConfigBytes, msg)
+	if err != nil {
+		return err
+	}
+	return app.WriteChainConfigFile(subnetName, chainConfigBytes)
+}
+
+// applyLocalChainConfig restarts the local network with the current chain config of [subnetName],
+// by saving a snapshot and loading it with the chain config
+func applyLocalChainConfig(subnetName string, blockchainID ids.ID) error {
+	chainConfigBytes, err := os.ReadFile(app.GetChainConfigPath(subnetName))
+	if err != nil {
+		return err
+	}
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	// save a temporary snapshot, stopping the network, to restart it with the chain config
+	snapName := subnetName + upgradeTmpSnapshotInfix + time.Now().Format(timestampFormat)
+	ux.Logger.PrintToUser("Restarting the local network with the registry message on the chain config of %s...", subnetName)
+	if _, err := cli.SaveSnapshot(ctx, snapName); err != nil {
+		return err
+	}
+	chainConfigs := map[string]string{
+		blockchainID.String(): string(chainConfigBytes),
+	}
+	if _, err := cli.LoadSnapshot(ctx, snapName, ANRclient.WithChainConfigs(chainConfigs)); err != nil {
+		return err
+	}
+	if _, err := subnet.WaitForHealthy(ctx, cli); err != nil {
+		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+	return nil
+}
+
+func printRegistryMessageInstructions(subnetName string, msg *avalancheWarp.UnsignedMessage, signatureErr error) {
+	ux.Logger.PrintToUser("The validator signatures of the registry message could not be obtained: %s", signatureErr)
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("To register the new messenger, add this message to the %q list of the", "warp-off-chain-messages")
+	ux.Logger.PrintToUser("chain config of every validator of %s, set %q to true, and restart them:", subnetName, "warp-api-enabled")
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("  %s", hexutil.Encode(msg.Bytes()))
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("The chain config at %s has been updated with it.", app.GetChainConfigPath(subnetName))
+	ux.Logger.PrintToUser("Then run this command again.")
+}
+
+// updateRelayerConfigsMessenger adds the messenger [messengerAddress] of [blockchainID] to the
+// relayer configs managed by the CLI, restarting the local relayer
+func updateRelayerConfigsMessenger(network models.Network, blockchainID ids.ID, messengerAddress string) error {
+	relayerAddress, _, err := teleporter.GetRelayerKeyInfo(app.GetKeyPath(constants.AWMRelayerKeyName))
+	if err != nil {
+		return err
+	}
+	if network.Kind == models.Local {
+		updated, err := teleporter.AddMessengerToRelayerConfig(app.GetAWMRelayerConfigPath(), blockchainID.String(), messengerAddress, relayerAddress)
+		if err != nil {
+			return err
+		}
+		if updated {
+			ux.Logger.PrintToUser("Restarting the local relayer with the new messenger...")
+			return teleporter.DeployRelayer(
+				app.GetAWMRelayerBinDir(),
+				app.GetAWMRelayerConfigPath(),
+				app.GetAWMRelayerLogPath(),
+				app.GetAWMRelayerRunPath(),
+				app.GetAWMRelayerStorageDir(),
+			)
+		}
+		return nil
+	}
+	configPath := app.GetAWMRelayerServiceConfigPath("")
+	updated, err := teleporter.AddMessengerToRelayerConfig(configPath, blockchainID.String(), messengerAddress, relayerAddress)
+	if err != nil {
+		return err
+	}
+	if updated {
+		ux.Logger.PrintToUser("Relayer service config %s updated with the new messenger. Restart the relayer service to use it", configPath)
+	}
+	return nil
+}
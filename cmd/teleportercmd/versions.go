@@ -0,0 +1,108 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleportercmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var versionsNetworkFlags networkoptions.NetworkFlags
+
+// avalanche teleporter versions
+func newVersionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "versions [subnetName...]",
+		Short: "Shows the teleporter messenger versions registered on subnets",
+		Long: `Shows, for each given subnet, the teleporter messengers registered on its
+teleporter registry, together with the teleporter release they were deployed from,
+when known. The latest registered version is the one used by teleporter apps.`,
+		SilenceUsage: true,
+		RunE:         versions,
+		Args:         cobra.MinimumNArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &versionsNetworkFlags, true, upgradeSupportedNetworkOptions)
+	return cmd
+}
+
+func versions(_ *cobra.Command, args []string) error {
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		versionsNetworkFlags,
+		true,
+		upgradeSupportedNetworkOptions,
+		"",
+	)
+	if err != nil {
+		return err
+	}
+	for _, subnetName := range args {
+		if err := printRegisteredVersions(network, subnetName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printRegisteredVersions prints the messengers registered on the teleporter registry of [subnetName]
+func printRegisteredVersions(network models.Network, subnetName string) error {
+	if isCChain(subnetName) {
+		return errors.New("teleporter versions of the C-Chain are not supported")
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	_, blockchainID, messengerAddress, registryAddress, _, err := getSubnetParams(network, subnetName)
+	if err != nil {
+		return err
+	}
+	if registryAddress == "" {
+		return fmt.Errorf("teleporter registry address for subnet %s not found on network %s", subnetName, network.Name())
+	}
+	client, err := evm.GetClient(network.BlockchainEndpoint(blockchainID.String()))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	entries, latestVersion, err := teleporter.GetRegistryEntries(client, registryAddress)
+	if err != nil {
+		return err
+	}
+	// releases are only known for the messengers deployed by the CLI
+	releases := map[string]string{}
+	for _, messenger := range sc.TeleporterMessengers[network.Name()] {
+		releases[strings.ToLower(messenger.Address)] = messenger.Version
+	}
+	if _, ok := releases[strings.ToLower(messengerAddress)]; !ok && sc.TeleporterVersion != "" {
+		releases[strings.ToLower(messengerAddress)] = sc.TeleporterVersion
+	}
+	ux.Logger.PrintToUser("Teleporter Registry %s of subnet %s on %s:", registryAddress, subnetName, network.Name())
+	header := []string{"Registry Version", "Messenger Address", "Release", "Latest"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	for _, entry := range entries {
+		release, ok := releases[strings.ToLower(entry.Address)]
+		if !ok {
+			release = "unknown"
+		}
+		latest := ""
+		if entry.Version == latestVersion {
+			latest = "yes"
+		}
+		table.Append([]string{fmt.Sprintf("%d", entry.Version), entry.Address, release, latest})
+	}
+	table.Render()
+	return nil
+}
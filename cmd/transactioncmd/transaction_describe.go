@@ -0,0 +1,168 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package transactioncmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// avalanche transaction describe
+func newTransactionDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [txPath]",
+		Short: "describe a transaction",
+		Long: `The transaction describe command decodes a transaction file, as created by the
+subnet multisig workflow, and prints its type, subnet, fee and type specific details.
+
+If the subnet control keys can be obtained from the network, it also shows which
+subnet auth keys have already signed the tx, and which ones still must.`,
+		RunE:         describeTx,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		Annotations:  ux.StructuredOutputAnnotations(),
+	}
+	return cmd
+}
+
+func describeTx(_ *cobra.Command, args []string) error {
+	tx, err := txutils.LoadFromDisk(args[0])
+	if err != nil {
+		return err
+	}
+	network, err := txutils.GetNetwork(tx)
+	if err != nil {
+		return err
+	}
+	subnetID, err := txutils.GetSubnetID(tx)
+	if err != nil {
+		return err
+	}
+	var (
+		controlKeys []string
+		warnings    []string
+	)
+	if subnetID != ids.Empty {
+		transferSubnetOwnershipTxID, err := getTransferSubnetOwnershipTxID(network, subnetID)
+		if err != nil {
+			return err
+		}
+		controlKeys, _, err = txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+		if err != nil {
+			controlKeys = nil
+			warnings = append(warnings, fmt.Sprintf("could not get the subnet control keys from %s, signers are not shown: %s", network.Name(), err))
+		}
+	}
+	desc, err := txutils.DescribeTx(tx, controlKeys, time.Now())
+	if err != nil {
+		return err
+	}
+	desc.Warnings = append(warnings, desc.Warnings...)
+
+	if ux.IsStructuredOutput() {
+		return ux.PrintStructured("TransactionDescription", desc)
+	}
+	printTxDescription(desc)
+	return nil
+}
+
+// getTransferSubnetOwnershipTxID looks for a local subnet deployed as [subnetID] on [network],
+// to know if its ownership was transferred
+func getTransferSubnetOwnershipTxID(network models.Network, subnetID ids.ID) (ids.ID, error) {
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return ids.Empty, err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return ids.Empty, err
+		}
+		if sc.Networks[network.Name()].SubnetID == subnetID {
+			return sc.Networks[network.Name()].TransferSubnetOwnershipTxID, nil
+		}
+	}
+	return ids.Empty, nil
+}
+
+func printTxDescription(desc *txutils.TxDescription) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Parameter", "Value"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	table.Append([]string{"Tx ID", desc.TxID})
+	table.Append([]string{"Type", desc.Type})
+	table.Append([]string{"Network", desc.Network})
+	if desc.SubnetID != "" {
+		table.Append([]string{"Subnet ID", desc.SubnetID})
+	}
+	table.Append([]string{"Fee", fmt.Sprintf("%d nAVAX", desc.Fee)})
+	if desc.Memo != "" {
+		table.Append([]string{"Memo", desc.Memo})
+	}
+	if desc.Chain != nil {
+		table.Append([]string{"Chain Name", desc.Chain.Name})
+		table.Append([]string{"VM ID", desc.Chain.VMID})
+		if len(desc.Chain.FxIDs) > 0 {
+			table.Append([]string{"Fx IDs", strings.Join(desc.Chain.FxIDs, "\n")})
+		}
+		table.Append([]string{"Genesis Size", fmt.Sprintf("%d bytes", desc.Chain.GenesisSize)})
+		table.Append([]string{"Genesis SHA256", desc.Chain.GenesisSHA256})
+	}
+	if desc.Validator != nil {
+		table.Append([]string{"Node ID", desc.Validator.NodeID})
+		if desc.Validator.Weight != 0 {
+			table.Append([]string{"Weight", fmt.Sprint(desc.Validator.Weight)})
+		}
+		if desc.Validator.StartTime != nil {
+			table.Append([]string{"Start Time", desc.Validator.StartTime.Format(constants.TimeParseLayout) + " UTC"})
+		}
+		if desc.Validator.EndTime != nil {
+			table.Append([]string{"End Time", desc.Validator.EndTime.Format(constants.TimeParseLayout) + " UTC"})
+		}
+	}
+	if desc.NewOwner != nil {
+		table.Append([]string{"New Control Keys", strings.Join(desc.NewOwner.Addresses, "\n")})
+		table.Append([]string{"New Threshold", fmt.Sprint(desc.NewOwner.Threshold)})
+	}
+	if desc.ElasticSubnet != nil {
+		es := desc.ElasticSubnet
+		table.Append([]string{"Asset ID", es.AssetID})
+		table.Append([]string{"Initial Supply", fmt.Sprint(es.InitialSupply)})
+		table.Append([]string{"Maximum Supply", fmt.Sprint(es.MaximumSupply)})
+		table.Append([]string{"Min Consumption Rate", fmt.Sprint(es.MinConsumptionRate)})
+		table.Append([]string{"Max Consumption Rate", fmt.Sprint(es.MaxConsumptionRate)})
+		table.Append([]string{"Min Validator Stake", fmt.Sprint(es.MinValidatorStake)})
+		table.Append([]string{"Max Validator Stake", fmt.Sprint(es.MaxValidatorStake)})
+		table.Append([]string{"Min Stake Duration", es.MinStakeDuration.String()})
+		table.Append([]string{"Max Stake Duration", es.MaxStakeDuration.String()})
+		table.Append([]string{"Min Delegation Fee", fmt.Sprint(es.MinDelegationFee)})
+		table.Append([]string{"Min Delegator Stake", fmt.Sprint(es.MinDelegatorStake)})
+		table.Append([]string{"Max Validator Weight Factor", fmt.Sprint(es.MaxValidatorWeightFactor)})
+		table.Append([]string{"Uptime Requirement", fmt.Sprint(es.UptimeRequirement)})
+	}
+	if len(desc.AuthSigners) > 0 {
+		table.Append([]string{"Subnet Auth Signers", strings.Join(desc.AuthSigners, "\n")})
+		table.Append([]string{"Signed By", strings.Join(desc.Signers, "\n")})
+		table.Append([]string{"Remaining Signers", strings.Join(desc.RemainingSigners, "\n")})
+	}
+	if len(desc.AuthSigners) > 0 || desc.FullySigned {
+		table.Append([]string{"Fully Signed", fmt.Sprint(desc.FullySigned)})
+	}
+	table.Render()
+
+	for _, warning := range desc.Warnings {
+		ux.Logger.PrintToUser("Warning: %s", warning)
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/slices"
 )
 
 // CreateAnsibleHostInventory creates inventory file for ansible
@@ -54,6 +56,26 @@ func CreateAnsibleHostInventory(inventoryDirPath, certFilePath, cloudService str
 	return nil
 }
 
+// CreateAnsibleHostInventoryFromHosts adds [hosts] to the inventory file for ansible, keeping
+// their own ssh user, port and key, as needed for hosts not created by the CLI
+func CreateAnsibleHostInventoryFromHosts(inventoryDirPath string, hosts []*models.Host) error {
+	if err := os.MkdirAll(inventoryDirPath, os.ModePerm); err != nil {
+		return err
+	}
+	inventoryHostsFilePath := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
+	inventoryFile, err := os.OpenFile(inventoryHostsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer inventoryFile.Close()
+	for _, host := range hosts {
+		if _, err := inventoryFile.WriteString(host.GetAnsibleInventoryRecord() + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func writeToInventoryFile(inventoryFile *os.File, ansibleInstanceID, publicIP, certFilePath string) error {
 	inventoryContent := ansibleInstanceID
 	inventoryContent += " ansible_host="
@@ -102,6 +124,13 @@ func GetInventoryFromAnsibleInventoryFile(inventoryDirPath string) ([]*models.Ho
 			SSHPrivateKeyPath: parsedHost["ansible_ssh_private_key_file"],
 			SSHCommonArgs:     parsedHost["ansible_ssh_common_args"],
 		}
+		if sshPort, ok := parsedHost["ansible_port"]; ok {
+			port, err := strconv.ParseUint(sshPort, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ansible_port for host %s: %w", host.NodeID, err)
+			}
+			host.SSHPort = uint(port)
+		}
 		inventory = append(inventory, host)
 	}
 	if err := scanner.Err(); err != nil {
@@ -174,3 +203,26 @@ func UpdateInventoryHostPublicIP(inventoryDirPath string, nodesWithDynamicIP map
 	}
 	return nil
 }
+
+// RemoveInventoryHosts regenerates the ansible inventory file without the hosts with the given [ansibleHostIDs]
+func RemoveInventoryHosts(inventoryDirPath string, ansibleHostIDs []string) error {
+	inventory, err := GetInventoryFromAnsibleInventoryFile(inventoryDirPath)
+	if err != nil {
+		return err
+	}
+	inventoryHostsFilePath := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
+	inventoryFile, err := os.Create(inventoryHostsFilePath)
+	if err != nil {
+		return err
+	}
+	defer inventoryFile.Close()
+	for _, host := range inventory {
+		if slices.Contains(ansibleHostIDs, host.NodeID) {
+			continue
+		}
+		if _, err = inventoryFile.WriteString(host.GetAnsibleInventoryRecord() + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -42,6 +42,7 @@ func (app *Avalanche) Setup(baseDir string, log logging.Logger, conf *config.Con
 	app.Conf = conf
 	app.Prompt = prompt
 	app.Downloader = downloader
+	models.SetKnownHostsPath(app.GetKnownHostsPath())
 }
 
 func (app *Avalanche) GetRunFile() string {
@@ -52,6 +53,16 @@ func (app *Avalanche) GetSnapshotsDir() string {
 	return filepath.Join(app.baseDir, constants.SnapshotsDirName)
 }
 
+// GetBackupsDir returns the default dir of the node backup archives
+func (app *Avalanche) GetBackupsDir() string {
+	return filepath.Join(app.baseDir, constants.BackupsDirName)
+}
+
+// GetPriceTablePath returns the path of the cloud price table overriding the bundled one
+func (app *Avalanche) GetPriceTablePath() string {
+	return filepath.Join(app.baseDir, constants.PriceTableFileName)
+}
+
 func (app *Avalanche) GetBaseDir() string {
 	return app.baseDir
 }
@@ -134,6 +145,10 @@ func (app *Avalanche) GetAWMRelayerSnapshotConfsDir() string {
 	return filepath.Join(app.GetSnapshotsDir(), constants.AWMRelayerSnapshotConfsDir)
 }
 
+func (app *Avalanche) GetAWMRelayerSnapshotConfPath(snapshotName string) string {
+	return filepath.Join(app.GetAWMRelayerSnapshotConfsDir(), snapshotName+constants.JSONSuffix)
+}
+
 func (app *Avalanche) GetAWMRelayerServiceDir(baseDir string) string {
 	return filepath.Join(app.GetServicesDir(baseDir), constants.AWMRelayerInstallDir)
 }
@@ -146,6 +161,47 @@ func (app *Avalanche) GetAWMRelayerServiceStorageDir(baseDir string) string {
 	return filepath.Join(app.GetAWMRelayerServiceDir(baseDir), constants.AWMRelayerStorageDir)
 }
 
+func (app *Avalanche) GetRelayersDir() string {
+	return filepath.Join(app.baseDir, constants.RelayersDir)
+}
+
+func (app *Avalanche) GetRelayerDir(relayerName string) string {
+	return filepath.Join(app.GetRelayersDir(), relayerName)
+}
+
+func (app *Avalanche) GetRelayerPath(relayerName string) string {
+	return filepath.Join(app.GetRelayerDir(relayerName), constants.AWMRelayerInstanceFilename)
+}
+
+func (app *Avalanche) GetRelayerConfigPath(relayerName string) string {
+	return filepath.Join(app.GetRelayerDir(relayerName), constants.AWMRelayerConfigFilename)
+}
+
+func (app *Avalanche) GetRelayerStorageDir(relayerName string) string {
+	return filepath.Join(app.GetRelayerDir(relayerName), constants.AWMRelayerStorageDir)
+}
+
+func (app *Avalanche) GetRelayerLogPath(relayerName string) string {
+	return filepath.Join(app.GetRelayerDir(relayerName), constants.AWMRelayerLogFilename)
+}
+
+func (app *Avalanche) GetRelayerRunPath(relayerName string) string {
+	return filepath.Join(app.GetRelayerDir(relayerName), constants.AWMRelayerRunFilename)
+}
+
+// GetRelayerServiceDir returns the dir of the relayer service [serviceName], based at [baseDir]
+func (app *Avalanche) GetRelayerServiceDir(baseDir string, serviceName string) string {
+	return filepath.Join(app.GetServicesDir(baseDir), serviceName)
+}
+
+func (app *Avalanche) GetRelayerServiceConfigPath(baseDir string, serviceName string) string {
+	return filepath.Join(app.GetRelayerServiceDir(baseDir, serviceName), constants.AWMRelayerConfigFilename)
+}
+
+func (app *Avalanche) GetRelayerServiceStorageDir(baseDir string, serviceName string) string {
+	return filepath.Join(app.GetRelayerServiceDir(baseDir, serviceName), constants.AWMRelayerStorageDir)
+}
+
 func (app *Avalanche) GetExtraLocalNetworkDataPath() string {
 	return filepath.Join(app.GetRunDir(), constants.ExtraLocalNetworkDataFilename)
 }
@@ -154,6 +210,14 @@ func (app *Avalanche) GetExtraLocalNetworkSnapshotsDir() string {
 	return filepath.Join(app.GetSnapshotsDir(), constants.ExtraLocalNetworkDataSnapshotsDir)
 }
 
+func (app *Avalanche) GetExtraLocalNetworkSnapshotPath(snapshotName string) string {
+	return filepath.Join(app.GetExtraLocalNetworkSnapshotsDir(), snapshotName+constants.JSONSuffix)
+}
+
+func (app *Avalanche) GetANRSnapshotPath(snapshotName string) string {
+	return filepath.Join(app.GetSnapshotsDir(), constants.ANRSnapshotPrefix+snapshotName)
+}
+
 func (app *Avalanche) GetSubnetEVMBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.SubnetEVMInstallDir)
 }
@@ -244,6 +308,11 @@ func (app *Avalanche) GetClustersConfigPath() string {
 	return filepath.Join(app.GetNodesDir(), constants.ClustersConfigFileName)
 }
 
+// GetKnownHostsPath returns the known_hosts file where the node host keys are pinned
+func (app *Avalanche) GetKnownHostsPath() string {
+	return filepath.Join(app.GetNodesDir(), constants.KnownHostsFileName)
+}
+
 func (app *Avalanche) GetNodeBLSSecretKeyPath(instanceID string) string {
 	return filepath.Join(app.GetNodeInstanceDirPath(instanceID), constants.BLSKeyFileName)
 }
@@ -680,6 +749,46 @@ func (app *Avalanche) LoadElasticSubnetConfig(subnetName string) (models.Elastic
 	return esc, err
 }
 
+func (app *Avalanche) RelayerExists(relayerName string) bool {
+	_, err := os.Stat(app.GetRelayerPath(relayerName))
+	return err == nil
+}
+
+func (app *Avalanche) WriteRelayer(relayer *models.Relayer) error {
+	relayerBytes, err := json.MarshalIndent(relayer, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFile(app.GetRelayerPath(relayer.Name), relayerBytes)
+}
+
+func (app *Avalanche) LoadRelayer(relayerName string) (models.Relayer, error) {
+	jsonBytes, err := os.ReadFile(app.GetRelayerPath(relayerName))
+	if err != nil {
+		return models.Relayer{}, err
+	}
+	var relayer models.Relayer
+	err = json.Unmarshal(jsonBytes, &relayer)
+	return relayer, err
+}
+
+func (app *Avalanche) GetRelayerNames() ([]string, error) {
+	matches, err := os.ReadDir(app.GetRelayersDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, m := range matches {
+		if m.IsDir() && app.RelayerExists(m.Name()) {
+			names = append(names, m.Name())
+		}
+	}
+	return names, nil
+}
+
 func (app *Avalanche) LoadClusterNodeConfig(nodeName string) (models.NodeConfig, error) {
 	nodeConfigPath := app.GetNodeConfigPath(nodeName)
 	jsonBytes, err := os.ReadFile(nodeConfigPath)
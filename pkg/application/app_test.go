@@ -3,6 +3,7 @@
 package application
 
 import (
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
@@ -275,6 +276,41 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func Test_writeLoadRelayer(t *testing.T) {
+	require := require.New(t)
+
+	ap := newTestApp(t)
+
+	names, err := ap.GetRelayerNames()
+	require.NoError(err)
+	require.Empty(names)
+	require.False(ap.RelayerExists("TEST_relayer"))
+
+	relayer := models.Relayer{
+		Name:                "TEST_relayer",
+		Network:             models.NewLocalNetwork(),
+		KeyName:             "TEST_key",
+		ProcessMissedBlocks: true,
+		StorageDir:          ap.GetRelayerStorageDir("TEST_relayer"),
+		Routes:              []models.RelayerRoute{{Source: subnetName1, Destination: "c-chain"}},
+		MetricsPort:         9091,
+		MinBalance:          big.NewInt(1e18),
+		FundingKeyName:      "TEST_funding_key",
+		TopUpBalance:        big.NewInt(0).Mul(big.NewInt(1e18), big.NewInt(10)),
+	}
+	err = ap.WriteRelayer(&relayer)
+	require.NoError(err)
+	require.True(ap.RelayerExists("TEST_relayer"))
+
+	control, err := ap.LoadRelayer("TEST_relayer")
+	require.NoError(err)
+	require.Equal(relayer, control)
+
+	names, err = ap.GetRelayerNames()
+	require.NoError(err)
+	require.Equal([]string{"TEST_relayer"}, names)
+}
+
 func newTestApp(t *testing.T) *Avalanche {
 	tempDir := t.TempDir()
 	return &Avalanche{
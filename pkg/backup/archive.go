@@ -0,0 +1,271 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/exp/slices"
+)
+
+// A backup archive is an encrypted tar holding a manifest, and a gzipped tar
+// per node, as archived on the node host
+const (
+	manifestFileName = "manifest.json"
+	nodesDir         = "nodes"
+)
+
+var (
+	ErrMissingNodeArchiveFiles = errors.New("node archive is missing files")
+
+	errStopWalk = errors.New("stop walk")
+)
+
+// NodeBackup describes the backup of a node
+type NodeBackup struct {
+	CloudID string
+	NodeID  string
+	IsAPI   bool
+	HasDB   bool
+}
+
+// Manifest describes the contents of a backup archive
+type Manifest struct {
+	ClusterName string
+	Network     string
+	CreatedAt   time.Time
+	Nodes       []NodeBackup
+}
+
+// GetNode returns the backup of node [cloudID], or false if the manifest doesn't have it
+func (m Manifest) GetNode(cloudID string) (NodeBackup, bool) {
+	i := slices.IndexFunc(m.Nodes, func(n NodeBackup) bool { return n.CloudID == cloudID })
+	if i == -1 {
+		return NodeBackup{}, false
+	}
+	return m.Nodes[i], true
+}
+
+// Write creates at [path] a backup archive encrypted with [passphrase], holding
+// [manifest] and the node archives at [nodeArchivePaths], by node cloud ID
+func Write(path string, passphrase string, manifest Manifest, nodeArchivePaths map[string]string) error {
+	for _, node := range manifest.Nodes {
+		if _, ok := nodeArchivePaths[node.CloudID]; !ok {
+			return fmt.Errorf("missing archive of node %s", node.CloudID)
+		}
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := writeArchive(tmpPath, passphrase, manifest, manifestBytes, nodeArchivePaths); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func writeArchive(path string, passphrase string, manifest Manifest, manifestBytes []byte, nodeArchivePaths map[string]string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	encWriter, err := NewEncryptWriter(f, passphrase)
+	if err != nil {
+		return err
+	}
+	tarWriter := tar.NewWriter(encWriter)
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    manifestFileName,
+		Mode:    int64(constants.WriteReadUserOnlyPerms),
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(manifestBytes); err != nil {
+		return err
+	}
+	for _, node := range manifest.Nodes {
+		if err := addFile(tarWriter, nodeArchivePaths[node.CloudID], nodeArchiveName(node.CloudID)); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := encWriter.Close(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func addFile(tarWriter *tar.Writer, path string, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    int64(constants.WriteReadUserOnlyPerms),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tarWriter, f)
+	return err
+}
+
+func nodeArchiveName(cloudID string) string {
+	return filepath.ToSlash(filepath.Join(nodesDir, cloudID+".tar.gz"))
+}
+
+// walk calls [f] on each file of the backup archive at [path], until [f] returns errStopWalk
+func walk(path string, passphrase string, f func(header *tar.Header, r io.Reader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decReader, err := NewDecryptReader(file, passphrase)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(decReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(header, tarReader); err != nil {
+			if errors.Is(err, errStopWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// ReadManifest returns the manifest of the backup archive at [path]
+func ReadManifest(path string, passphrase string) (Manifest, error) {
+	var manifest Manifest
+	found := false
+	if err := walk(path, passphrase, func(header *tar.Header, r io.Reader) error {
+		if header.Name != manifestFileName {
+			return nil
+		}
+		found = true
+		if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+			return fmt.Errorf("invalid backup manifest: %w", err)
+		}
+		return errStopWalk
+	}); err != nil {
+		return Manifest{}, err
+	}
+	if !found {
+		return Manifest{}, fmt.Errorf("backup manifest not found")
+	}
+	return manifest, nil
+}
+
+// ExtractNodeArchive writes to [outputPath] the archive of node [cloudID] held by
+// the backup archive at [path]
+func ExtractNodeArchive(path string, passphrase string, cloudID string, outputPath string) error {
+	found := false
+	if err := walk(path, passphrase, func(header *tar.Header, r io.Reader) error {
+		if header.Name != nodeArchiveName(cloudID) {
+			return nil
+		}
+		found = true
+		if err := writeFile(outputPath, r, header.Size); err != nil {
+			return err
+		}
+		return errStopWalk
+	}); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("backup has no archive for node %s", cloudID)
+	}
+	return nil
+}
+
+// ExtractNodeArchiveFiles writes into [outputDir] the files [names] of the node
+// archive at [nodeArchivePath], using their base names. Returns ErrMissingNodeArchiveFiles
+// if some of them are not found
+func ExtractNodeArchiveFiles(nodeArchivePath string, names []string, outputDir string) error {
+	f, err := os.Open(nodeArchivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	pending := slices.Clone(names)
+	for len(pending) > 0 {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(filepath.Clean(header.Name))
+		i := slices.Index(pending, name)
+		if i == -1 || header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := writeFile(filepath.Join(outputDir, filepath.Base(name)), tarReader, header.Size); err != nil {
+			return err
+		}
+		pending = slices.Delete(pending, i, i+1)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w %s", ErrMissingNodeArchiveFiles, pending)
+	}
+	return nil
+}
+
+// writeFile writes [size] bytes of [r] to [path]
+func writeFile(path string, r io.Reader, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, r, size); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
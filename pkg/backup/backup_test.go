@@ -0,0 +1,154 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(require *require.Assertions, plain []byte, passphrase string) []byte {
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, passphrase)
+	require.NoError(err)
+	// write in uneven pieces to cross chunk boundaries
+	for len(plain) > 0 {
+		n := min(len(plain), 1000)
+		_, err := w.Write(plain[:n])
+		require.NoError(err)
+		plain = plain[n:]
+	}
+	require.NoError(w.Close())
+	return buf.Bytes()
+}
+
+func decrypt(encrypted []byte, passphrase string) ([]byte, error) {
+	r, err := NewDecryptReader(bytes.NewReader(encrypted), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncryption(t *testing.T) {
+	require := require.New(t)
+	scryptN, scryptP = 2, 1
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize} {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		require.NoError(err)
+		encrypted := encrypt(require, plain, "passphrase")
+		decrypted, err := decrypt(encrypted, "passphrase")
+		require.NoError(err, size)
+		require.Equal(plain, append([]byte{}, decrypted...), size)
+
+		_, err = decrypt(encrypted, "other passphrase")
+		require.ErrorIs(err, ErrWrongPassphrase, size)
+	}
+
+	plain := make([]byte, 3*chunkSize)
+	encrypted := encrypt(require, plain, "passphrase")
+	// truncation at a chunk boundary is detected
+	sealedChunkSize := chunkSize + 16
+	headerSize := len(encrypted) - 3*sealedChunkSize
+	for _, numChunks := range []int{1, 2} {
+		_, err := decrypt(encrypted[:headerSize+numChunks*sealedChunkSize], "passphrase")
+		require.ErrorIs(err, ErrCorruptedBackup, numChunks)
+	}
+	// so is tampering
+	encrypted[len(encrypted)-1] ^= 1
+	_, err := decrypt(encrypted, "passphrase")
+	require.ErrorIs(err, ErrCorruptedBackup)
+
+	_, err = decrypt([]byte("not a backup"), "passphrase")
+	require.ErrorContains(err, "not a backup file")
+	_, err = NewEncryptWriter(io.Discard, "")
+	require.ErrorIs(err, ErrEmptyPassphrase)
+}
+
+func writeNodeArchive(require *require.Assertions, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(err)
+	defer f.Close()
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(tarWriter.WriteHeader(&tar.Header{Name: "staking/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for name, contents := range files {
+		require.NoError(tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(contents))}))
+		_, err := tarWriter.Write([]byte(contents))
+		require.NoError(err)
+	}
+	require.NoError(tarWriter.Close())
+	require.NoError(gzipWriter.Close())
+}
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+	scryptN, scryptP = 2, 1
+	dir := t.TempDir()
+	node1Archive := filepath.Join(dir, "node1.tar.gz")
+	writeNodeArchive(require, node1Archive, map[string]string{
+		"staking/staker.crt":   "cert1",
+		"staking/staker.key":   "key1",
+		"configs/node.json":    "{}",
+		"db/fuji/v1.4.5/0.log": "db",
+	})
+	node2Archive := filepath.Join(dir, "node2.tar.gz")
+	writeNodeArchive(require, node2Archive, map[string]string{
+		"staking/staker.crt": "cert2",
+	})
+	manifest := Manifest{
+		ClusterName: "cluster",
+		Network:     "Fuji",
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		Nodes: []NodeBackup{
+			{CloudID: "node1", NodeID: "NodeID-1", HasDB: true},
+			{CloudID: "node2", NodeID: "NodeID-2", IsAPI: true},
+		},
+	}
+	backupPath := filepath.Join(dir, "backups", "cluster.bak")
+	require.Error(Write(backupPath, "passphrase", manifest, map[string]string{"node1": node1Archive}))
+	require.NoError(Write(backupPath, "passphrase", manifest, map[string]string{"node1": node1Archive, "node2": node2Archive}))
+	info, err := os.Stat(backupPath)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	readManifest, err := ReadManifest(backupPath, "passphrase")
+	require.NoError(err)
+	require.Equal(manifest, readManifest)
+	node, ok := readManifest.GetNode("node2")
+	require.True(ok)
+	require.True(node.IsAPI)
+	_, ok = readManifest.GetNode("node3")
+	require.False(ok)
+	_, err = ReadManifest(backupPath, "other passphrase")
+	require.ErrorIs(err, ErrWrongPassphrase)
+
+	extractedArchive := filepath.Join(dir, "extracted", "node1.tar.gz")
+	require.NoError(ExtractNodeArchive(backupPath, "passphrase", "node1", extractedArchive))
+	originalBytes, err := os.ReadFile(node1Archive)
+	require.NoError(err)
+	extractedBytes, err := os.ReadFile(extractedArchive)
+	require.NoError(err)
+	require.Equal(originalBytes, extractedBytes)
+	require.ErrorContains(ExtractNodeArchive(backupPath, "passphrase", "node3", extractedArchive), "no archive for node node3")
+
+	stakingDir := filepath.Join(dir, "staking")
+	require.NoError(ExtractNodeArchiveFiles(extractedArchive, []string{"staking/staker.crt", "staking/staker.key"}, stakingDir))
+	certBytes, err := os.ReadFile(filepath.Join(stakingDir, "staker.crt"))
+	require.NoError(err)
+	require.Equal("cert1", string(certBytes))
+	err = ExtractNodeArchiveFiles(node2Archive, []string{"staking/staker.crt", "staking/staker.key"}, stakingDir)
+	require.ErrorIs(err, ErrMissingNodeArchiveFiles)
+	require.ErrorContains(err, "staking/staker.key")
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted stream layout: magic | scrypt N, r, p | salt | nonce prefix, followed by
+// AES-256-GCM sealed chunks of up to chunkSize plaintext bytes. The chunk nonce is the
+// nonce prefix, the chunk index, and a last chunk flag, so chunks can't be reordered,
+// dropped or truncated without failing authentication
+const (
+	chunkSize       = 64 * 1024
+	saltSize        = 32
+	noncePrefixSize = 7
+	keySize         = 32
+	scryptR         = 8
+	// bounds of the scrypt parameters read from backups
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+)
+
+var (
+	ErrWrongPassphrase = errors.New("could not decrypt backup: wrong passphrase")
+	ErrCorruptedBackup = errors.New("could not decrypt backup: file is corrupted")
+	ErrEmptyPassphrase = errors.New("passphrase can't be empty")
+
+	magic = []byte("avalanche-cli-backup\x00\x01")
+)
+
+// scrypt parameters used to encrypt backups. Variables so tests can use lighter ones
+var (
+	scryptN = 1 << 18
+	scryptP = 1
+)
+
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint32
+	buf     []byte
+}
+
+// NewEncryptWriter returns a writer that encrypts with [passphrase] what is written to it,
+// writing the result to [w]. Close must be called to write the last chunk
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, noncePrefixSize+5)
+	if _, err := rand.Read(nonce[:noncePrefixSize]); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte{}, magic...)
+	for _, param := range []int{scryptN, scryptR, scryptP} {
+		header = binary.BigEndian.AppendUint32(header, uint32(param))
+	}
+	header = append(header, salt...)
+	header = append(header, nonce[:noncePrefixSize]...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:     w,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		// a full chunk is only sealed when more data comes, as the last chunk is flagged
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return n, err
+			}
+		}
+		m := min(chunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:m]...)
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	if e.counter == math.MaxUint32 {
+		return fmt.Errorf("backup is too large")
+	}
+	setChunkNonce(e.nonce, e.counter, last)
+	if _, err := e.w.Write(e.aead.Seal(nil, e.nonce, e.buf, nil)); err != nil {
+		return err
+	}
+	e.buf = e.buf[:0]
+	e.counter++
+	return nil
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint32
+	chunk   []byte
+	plain   []byte
+	done    bool
+}
+
+// NewDecryptReader returns a reader of the contents of [r], decrypted with [passphrase]
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(magic)+12+saltSize+noncePrefixSize)
+	if _, err := io.ReadFull(br, header); err != nil || !bytes.Equal(header[:len(magic)], magic) {
+		return nil, fmt.Errorf("not a backup file")
+	}
+	params := header[len(magic):]
+	n := binary.BigEndian.Uint32(params[0:4])
+	r32 := binary.BigEndian.Uint32(params[4:8])
+	p := binary.BigEndian.Uint32(params[8:12])
+	if n > maxScryptN || r32 > maxScryptR || p > maxScryptP {
+		return nil, fmt.Errorf("unsupported backup encryption parameters")
+	}
+	salt := header[len(magic)+12 : len(magic)+12+saltSize]
+	nonce := make([]byte, noncePrefixSize+5)
+	copy(nonce, header[len(magic)+12+saltSize:])
+	aead, err := newAEAD(passphrase, salt, int(n), int(r32), int(p))
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:     br,
+		aead:  aead,
+		nonce: nonce,
+		chunk: make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	last := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case errors.Is(err, io.EOF):
+		return ErrCorruptedBackup
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	setChunkNonce(d.nonce, d.counter, last)
+	plain, err := d.aead.Open(nil, d.nonce, d.chunk[:n], nil)
+	if err != nil {
+		if d.counter > 0 {
+			return ErrCorruptedBackup
+		}
+		// a first chunk that opens with the other last chunk flag was truncated
+		setChunkNonce(d.nonce, d.counter, !last)
+		if _, err := d.aead.Open(nil, d.nonce, d.chunk[:n], nil); err == nil {
+			return ErrCorruptedBackup
+		}
+		return ErrWrongPassphrase
+	}
+	d.plain = plain
+	d.done = last
+	d.counter++
+	return nil
+}
+
+func newAEAD(passphrase string, salt []byte, n int, r int, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func setChunkNonce(nonce []byte, counter uint32, last bool) {
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], counter)
+	nonce[len(nonce)-1] = 0
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -26,12 +27,13 @@ import (
 var (
 	ErrNoInstanceState         = errors.New("unable to get instance state")
 	ErrNoAddressFound          = errors.New("unable to get public IP address info on AWS")
-	ErrNodeNotFoundToBeRunning = errors.New("node not found to be running")
+	ErrNodeNotFoundToBeRunning = cloud.ErrNodeNotFoundToBeRunning
 )
 
 type AwsCloud struct {
 	ec2Client *ec2.Client
 	ctx       context.Context
+	region    string
 }
 
 // NewAwsCloud creates an AWS cloud
@@ -61,6 +63,7 @@ func NewAwsCloud(awsProfile, region string) (*AwsCloud, error) {
 	return &AwsCloud{
 		ec2Client: ec2.NewFromConfig(cfg),
 		ctx:       ctx,
+		region:    region,
 	}, nil
 }
 
@@ -282,7 +285,10 @@ func (c *AwsCloud) WaitForEC2Instances(nodeIDs []string) error {
 }
 
 // GetInstancePublicIPs returns a map from instance ID to public IP
-func (c *AwsCloud) GetInstancePublicIPs(nodeIDs []string) (map[string]string, error) {
+func (c *AwsCloud) GetInstancePublicIPs(region string, nodeIDs []string) (map[string]string, error) {
+	if err := c.checkRegion(region); err != nil {
+		return nil, err
+	}
 	instanceInput := &ec2.DescribeInstancesInput{
 		InstanceIds: nodeIDs,
 	}
@@ -0,0 +1,201 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+var _ cloud.CloudProvider = (*AwsCloud)(nil)
+
+// Name returns the AWS cloud service name
+func (*AwsCloud) Name() string {
+	return constants.AWSCloudService
+}
+
+// checkRegion verifies that [region] is the one the client was created for.
+// An empty region is accepted as the client one
+func (c *AwsCloud) checkRegion(region string) error {
+	if region != "" && region != c.region {
+		return fmt.Errorf("AWS client for region %s can't operate on region %s", c.region, region)
+	}
+	return nil
+}
+
+// CreateInstances creates EC2 instances as described by [spec], associating to them
+// the elastic IPs at spec.StaticIPs, if given
+func (c *AwsCloud) CreateInstances(spec cloud.InstanceSpec) ([]string, error) {
+	if err := c.checkRegion(spec.Location); err != nil {
+		return nil, err
+	}
+	if len(spec.StaticIPs) > 0 && len(spec.StaticIPs) != spec.Count {
+		return nil, fmt.Errorf("expected %d static IPs, got %d", spec.Count, len(spec.StaticIPs))
+	}
+	sgExists, sg, err := c.CheckSecurityGroupExists(spec.FirewallGroup)
+	if err != nil {
+		return nil, err
+	}
+	if !sgExists {
+		return nil, fmt.Errorf("security group %s doesn't exist in region %s", spec.FirewallGroup, c.region)
+	}
+	instanceIDs, err := c.CreateEC2Instances(
+		spec.Name,
+		spec.Count,
+		spec.ImageID,
+		spec.InstanceType,
+		spec.KeyPair,
+		*sg.GroupId,
+		spec.ForMonitoring,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.StaticIPs) == 0 {
+		return instanceIDs, nil
+	}
+	// addresses can only be associated to running instances
+	if err := c.WaitForEC2Instances(instanceIDs); err != nil {
+		return instanceIDs, err
+	}
+	for i, instanceID := range instanceIDs {
+		allocationID, err := c.getAllocationID(spec.StaticIPs[i])
+		if err != nil {
+			return instanceIDs, err
+		}
+		if err := c.AssociateEIP(instanceID, allocationID); err != nil {
+			return instanceIDs, err
+		}
+	}
+	return instanceIDs, nil
+}
+
+// WaitForInstances waits for the EC2 instances to be running
+func (c *AwsCloud) WaitForInstances(region string, instanceIDs []string) error {
+	if err := c.checkRegion(region); err != nil {
+		return err
+	}
+	return c.WaitForEC2Instances(instanceIDs)
+}
+
+// ListInstances returns the IDs of the running EC2 instances managed by the CLI
+func (c *AwsCloud) ListInstances(region string) ([]string, error) {
+	if err := c.checkRegion(region); err != nil {
+		return nil, err
+	}
+	input := &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag:Managed-By"), Values: []string{"avalanche-cli"}},
+			{Name: aws.String("instance-state-name"), Values: []string{constants.AWSCloudServerRunningState}},
+		},
+	}
+	instanceIDs := []string{}
+	paginator := ec2.NewDescribeInstancesPaginator(c.ec2Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(c.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceIDs = append(instanceIDs, *instance.InstanceId)
+			}
+		}
+	}
+	return instanceIDs, nil
+}
+
+// DestroyNode terminates the EC2 instance of the node
+func (c *AwsCloud) DestroyNode(nodeConfig models.NodeConfig, clusterName string) error {
+	if err := c.checkRegion(nodeConfig.Region); err != nil {
+		return err
+	}
+	return c.DestroyAWSNode(nodeConfig, clusterName)
+}
+
+// CreateStaticIPs allocates [count] elastic IPs tagged with [prefix]
+func (c *AwsCloud) CreateStaticIPs(region, prefix string, count int) ([]string, error) {
+	if err := c.checkRegion(region); err != nil {
+		return nil, err
+	}
+	publicIPs := []string{}
+	for i := 0; i < count; i++ {
+		_, publicIP, err := c.CreateEIP(prefix)
+		if err != nil {
+			return publicIPs, err
+		}
+		publicIPs = append(publicIPs, publicIP)
+	}
+	return publicIPs, nil
+}
+
+// AddFirewallRule allows tcp ingress from [ip] to [port] on security group [securityGroupName]
+func (c *AwsCloud) AddFirewallRule(securityGroupName, ip string, port int32) error {
+	sg, err := c.getSecurityGroup(securityGroupName)
+	if err != nil {
+		return err
+	}
+	if CheckUserIPInSg(&sg, ip, port) {
+		return nil
+	}
+	return c.AddSecurityGroupRule(*sg.GroupId, "ingress", "tcp", ip, port)
+}
+
+// CheckFirewallGroupExists checks if the security group [securityGroupName] exists in the region
+func (c *AwsCloud) CheckFirewallGroupExists(securityGroupName string) (bool, error) {
+	sgExists, _, err := c.CheckSecurityGroupExists(securityGroupName)
+	return sgExists, err
+}
+
+// DeleteFirewallRule removes tcp ingress from [ip] to [port] on security group [securityGroupName]
+func (c *AwsCloud) DeleteFirewallRule(securityGroupName, ip string, port int32) error {
+	sg, err := c.getSecurityGroup(securityGroupName)
+	if err != nil {
+		return err
+	}
+	if !CheckUserIPInSg(&sg, ip, port) {
+		return nil
+	}
+	return c.DeleteSecurityGroupRule(*sg.GroupId, "ingress", "tcp", ip, port)
+}
+
+// CreateKeyPair creates a new key pair and downloads its private key to [privateKeyPath]
+func (c *AwsCloud) CreateKeyPair(name, privateKeyPath string) error {
+	return c.CreateAndDownloadKeyPair(name, privateKeyPath)
+}
+
+// getSecurityGroup returns the security group [securityGroupName], failing if it doesn't exist
+func (c *AwsCloud) getSecurityGroup(securityGroupName string) (types.SecurityGroup, error) {
+	sgExists, sg, err := c.CheckSecurityGroupExists(securityGroupName)
+	if err != nil {
+		return types.SecurityGroup{}, err
+	}
+	if !sgExists {
+		return types.SecurityGroup{}, fmt.Errorf("security group %s doesn't exist in region %s", securityGroupName, c.region)
+	}
+	return sg, nil
+}
+
+// getAllocationID returns the allocation ID of the elastic IP [publicIP]
+func (c *AwsCloud) getAllocationID(publicIP string) (string, error) {
+	addressOutput, err := c.ec2Client.DescribeAddresses(c.ctx, &ec2.DescribeAddressesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("public-ip"), Values: []string{publicIP}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(addressOutput.Addresses) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoAddressFound, publicIP)
+	}
+	return *addressOutput.Addresses[0].AllocationId, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cloud
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+var (
+	// ErrNodeNotFoundToBeRunning is returned by DestroyNode when the node instance is already gone
+	ErrNodeNotFoundToBeRunning = errors.New("node not found to be running")
+	// ErrNotSupported is returned by operations that do not apply to a given cloud
+	ErrNotSupported = errors.New("operation not supported by cloud provider")
+)
+
+// InstanceSpec describes a set of instances to be created on a cloud
+type InstanceSpec struct {
+	// region (AWS) or zone (GCP) to create the instances at
+	Location string
+	// name tag (AWS) or label (GCP) set on all instances
+	Name string
+	// instance name prefix (GCP), also used to name its static IPs
+	Prefix        string
+	Count         int
+	ImageID       string
+	InstanceType  string
+	ForMonitoring bool
+	// key pair authorized on the instances (AWS)
+	KeyPair string
+	// ssh public key authorized on the instances (GCP)
+	SSHPublicKey string
+	// security group (AWS) or network (GCP) the instances are attached to
+	FirewallGroup string
+	// static IPs, as returned by CreateStaticIPs, to be attached to the instances.
+	// If empty, the instances get dynamic IPs
+	StaticIPs []string
+}
+
+// CloudProvider is the set of cloud operations used to manage the nodes of a cluster.
+//
+// Locations are given in the same form as stored in models.NodeConfig.Region: regions
+// for AWS and zones for GCP. Firewall groups are given as stored in
+// models.NodeConfig.SecurityGroup: security group names for AWS and network names for GCP.
+type CloudProvider interface {
+	// Name returns the cloud service name, as stored in models.NodeConfig.CloudService
+	Name() string
+	// ListRegions returns the regions available to the account
+	ListRegions() ([]string, error)
+	// CreateInstances creates the instances described by [spec] and returns their IDs
+	CreateInstances(spec InstanceSpec) ([]string, error)
+	// WaitForInstances waits until all [instanceIDs] at [location] are running
+	WaitForInstances(location string, instanceIDs []string) error
+	// ListInstances returns the IDs of the running instances created by the CLI at [location]
+	ListInstances(location string) ([]string, error)
+	// GetInstancePublicIPs returns a map from instance ID to public IP for [instanceIDs] at [location]
+	GetInstancePublicIPs(location string, instanceIDs []string) (map[string]string, error)
+	// DestroyNode terminates the node instance, also releasing its static IP if any.
+	// Returns ErrNodeNotFoundToBeRunning if the instance is not running
+	DestroyNode(nodeConfig models.NodeConfig, clusterName string) error
+	// CreateStaticIPs reserves [count] static IPs at [location], to be used on CreateInstances
+	CreateStaticIPs(location, prefix string, count int) ([]string, error)
+	// AddFirewallRule allows tcp ingress from [ip] to [port] on [group]. It is a no-op if already allowed
+	AddFirewallRule(group, ip string, port int32) error
+	// DeleteFirewallRule removes the ingress rule added by AddFirewallRule. It is a no-op if not found
+	DeleteFirewallRule(group, ip string, port int32) error
+	// CheckFirewallGroupExists checks if the firewall [group] exists at the cloud
+	CheckFirewallGroupExists(group string) (bool, error)
+	// CheckKeyPairExists checks if the key pair [name] is registered at the cloud
+	CheckKeyPairExists(name string) (bool, error)
+	// CreateKeyPair registers a new key pair [name] at the cloud and saves its private key at [privateKeyPath]
+	CreateKeyPair(name, privateKeyPath string) error
+}
@@ -0,0 +1,379 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fake provides an in-memory cloud.CloudProvider, to test node
+// flows without any cloud account
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var _ cloud.CloudProvider = (*Cloud)(nil)
+
+// Instance is an instance created on the fake cloud
+type Instance struct {
+	ID            string
+	Location      string
+	Name          string
+	InstanceType  string
+	ImageID       string
+	KeyPair       string
+	FirewallGroup string
+	PublicIP      string
+	StaticIP      bool
+	Running       bool
+	ForMonitoring bool
+}
+
+// Cloud is an in-memory cloud.CloudProvider. It is safe for concurrent use
+type Cloud struct {
+	lock    sync.Mutex
+	name    string
+	regions []string
+	// instances by ID
+	instances map[string]*Instance
+	// reserved static IPs, mapped to the instance using them, if any
+	staticIPs map[string]string
+	// existing firewall group -> set of ip:port rules
+	firewallRules map[string]map[string]bool
+	keyPairs      map[string]bool
+	// operation name -> error to be returned by it
+	failures map[string]error
+	// counters used to build instance IDs and IPs
+	numInstances int
+	numIPs       int
+}
+
+// New creates an empty fake cloud named [name], with [regions] available
+func New(name string, regions []string) *Cloud {
+	return &Cloud{
+		name:          name,
+		regions:       regions,
+		instances:     map[string]*Instance{},
+		staticIPs:     map[string]string{},
+		firewallRules: map[string]map[string]bool{},
+		keyPairs:      map[string]bool{},
+		failures:      map[string]error{},
+	}
+}
+
+// FailOn makes the operation [op], given by its CloudProvider method name, return [err].
+// A nil [err] removes the failure
+func (c *Cloud) FailOn(op string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err == nil {
+		delete(c.failures, op)
+		return
+	}
+	c.failures[op] = err
+}
+
+// Instance returns a copy of the instance [instanceID], if it exists
+func (c *Cloud) Instance(instanceID string) (Instance, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		return Instance{}, false
+	}
+	return *instance, true
+}
+
+// StopInstance stops the instance [instanceID] without destroying it
+func (c *Cloud) StopInstance(instanceID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+	instance.Running = false
+	return nil
+}
+
+// RotatePublicIP assigns a new dynamic public IP to [instanceID], as a cloud does on restart.
+// Returns the new IP
+func (c *Cloud) RotatePublicIP(instanceID string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	instance, ok := c.instances[instanceID]
+	if !ok {
+		return "", fmt.Errorf("instance %s not found", instanceID)
+	}
+	if instance.StaticIP {
+		return "", fmt.Errorf("instance %s has a static IP", instanceID)
+	}
+	instance.PublicIP = c.newIP()
+	return instance.PublicIP, nil
+}
+
+// StaticIPs returns the reserved static IPs, sorted
+func (c *Cloud) StaticIPs() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ips := maps.Keys(c.staticIPs)
+	sort.Strings(ips)
+	return ips
+}
+
+// HasFirewallRule checks if ingress from [ip] to [port] is allowed on [group]
+func (c *Cloud) HasFirewallRule(group, ip string, port int32) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.firewallRules[group][firewallRule(ip, port)]
+}
+
+// DeleteFirewallGroup removes the firewall [group] together with its rules
+func (c *Cloud) DeleteFirewallGroup(group string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.firewallRules, group)
+}
+
+// Name returns the cloud service name given on New
+func (c *Cloud) Name() string {
+	return c.name
+}
+
+// ListRegions returns the regions given on New
+func (c *Cloud) ListRegions() ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["ListRegions"]; err != nil {
+		return nil, err
+	}
+	return slices.Clone(c.regions), nil
+}
+
+// CreateInstances creates running instances named <spec.Prefix>-<n>. If spec.StaticIPs
+// is not given, the instances get dynamic IPs
+func (c *Cloud) CreateInstances(spec cloud.InstanceSpec) ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["CreateInstances"]; err != nil {
+		return nil, err
+	}
+	if len(c.regions) > 0 && !slices.Contains(c.regions, spec.Location) {
+		return nil, fmt.Errorf("invalid location %s", spec.Location)
+	}
+	if spec.Count <= 0 {
+		return nil, fmt.Errorf("invalid number of instances %d", spec.Count)
+	}
+	if len(spec.StaticIPs) > 0 && len(spec.StaticIPs) != spec.Count {
+		return nil, fmt.Errorf("expected %d static IPs, got %d", spec.Count, len(spec.StaticIPs))
+	}
+	for _, ip := range spec.StaticIPs {
+		instanceID, ok := c.staticIPs[ip]
+		if !ok {
+			return nil, fmt.Errorf("static IP %s not found", ip)
+		}
+		if instanceID != "" {
+			return nil, fmt.Errorf("static IP %s already in use by %s", ip, instanceID)
+		}
+	}
+	if spec.KeyPair != "" && !c.keyPairs[spec.KeyPair] {
+		return nil, fmt.Errorf("key pair %s not found", spec.KeyPair)
+	}
+	if spec.FirewallGroup != "" && c.firewallRules[spec.FirewallGroup] == nil {
+		c.firewallRules[spec.FirewallGroup] = map[string]bool{}
+	}
+	prefix := spec.Prefix
+	if prefix == "" {
+		prefix = "i"
+	}
+	instanceIDs := []string{}
+	for i := 0; i < spec.Count; i++ {
+		c.numInstances++
+		instance := &Instance{
+			ID:            fmt.Sprintf("%s-%d", prefix, c.numInstances),
+			Location:      spec.Location,
+			Name:          spec.Name,
+			InstanceType:  spec.InstanceType,
+			ImageID:       spec.ImageID,
+			KeyPair:       spec.KeyPair,
+			FirewallGroup: spec.FirewallGroup,
+			Running:       true,
+			ForMonitoring: spec.ForMonitoring,
+		}
+		if len(spec.StaticIPs) > 0 {
+			instance.PublicIP = spec.StaticIPs[i]
+			instance.StaticIP = true
+			c.staticIPs[instance.PublicIP] = instance.ID
+		} else {
+			instance.PublicIP = c.newIP()
+		}
+		c.instances[instance.ID] = instance
+		instanceIDs = append(instanceIDs, instance.ID)
+	}
+	return instanceIDs, nil
+}
+
+// WaitForInstances checks that all [instanceIDs] at [location] are running
+func (c *Cloud) WaitForInstances(location string, instanceIDs []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["WaitForInstances"]; err != nil {
+		return err
+	}
+	for _, instanceID := range instanceIDs {
+		instance, ok := c.instances[instanceID]
+		if !ok || instance.Location != location {
+			return fmt.Errorf("instance %s not found at %s", instanceID, location)
+		}
+		if !instance.Running {
+			return fmt.Errorf("instance %s is not running", instanceID)
+		}
+	}
+	return nil
+}
+
+// ListInstances returns the IDs of the running instances at [location], sorted
+func (c *Cloud) ListInstances(location string) ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["ListInstances"]; err != nil {
+		return nil, err
+	}
+	instanceIDs := []string{}
+	for _, instance := range c.instances {
+		if instance.Location == location && instance.Running {
+			instanceIDs = append(instanceIDs, instance.ID)
+		}
+	}
+	sort.Strings(instanceIDs)
+	return instanceIDs, nil
+}
+
+// GetInstancePublicIPs returns the public IPs of the [instanceIDs] found at [location]
+func (c *Cloud) GetInstancePublicIPs(location string, instanceIDs []string) (map[string]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["GetInstancePublicIPs"]; err != nil {
+		return nil, err
+	}
+	publicIPs := map[string]string{}
+	for _, instanceID := range instanceIDs {
+		if instance, ok := c.instances[instanceID]; ok && instance.Location == location {
+			publicIPs[instanceID] = instance.PublicIP
+		}
+	}
+	return publicIPs, nil
+}
+
+// DestroyNode removes the node instance, releasing its static IP if nodeConfig.UseStaticIP is set
+func (c *Cloud) DestroyNode(nodeConfig models.NodeConfig, clusterName string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["DestroyNode"]; err != nil {
+		return err
+	}
+	instance, ok := c.instances[nodeConfig.NodeID]
+	if !ok || !instance.Running || instance.Location != nodeConfig.Region {
+		return fmt.Errorf("%w: instance %s, cluster %s", cloud.ErrNodeNotFoundToBeRunning, nodeConfig.NodeID, clusterName)
+	}
+	delete(c.instances, instance.ID)
+	if instance.StaticIP {
+		if nodeConfig.UseStaticIP {
+			delete(c.staticIPs, instance.PublicIP)
+		} else {
+			c.staticIPs[instance.PublicIP] = ""
+		}
+	}
+	return nil
+}
+
+// CreateStaticIPs reserves [count] new static IPs
+func (c *Cloud) CreateStaticIPs(_ string, _ string, count int) ([]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["CreateStaticIPs"]; err != nil {
+		return nil, err
+	}
+	ips := []string{}
+	for i := 0; i < count; i++ {
+		ip := c.newIP()
+		c.staticIPs[ip] = ""
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// AddFirewallRule allows ingress from [ip] to [port] on [group], creating [group] if needed
+func (c *Cloud) AddFirewallRule(group, ip string, port int32) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["AddFirewallRule"]; err != nil {
+		return err
+	}
+	if c.firewallRules[group] == nil {
+		c.firewallRules[group] = map[string]bool{}
+	}
+	c.firewallRules[group][firewallRule(ip, port)] = true
+	return nil
+}
+
+// DeleteFirewallRule removes the ingress rule from [ip] to [port] on [group], if present
+func (c *Cloud) DeleteFirewallRule(group, ip string, port int32) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["DeleteFirewallRule"]; err != nil {
+		return err
+	}
+	delete(c.firewallRules[group], firewallRule(ip, port))
+	return nil
+}
+
+// CheckFirewallGroupExists checks if the firewall [group] was created, either by
+// CreateInstances or AddFirewallRule
+func (c *Cloud) CheckFirewallGroupExists(group string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["CheckFirewallGroupExists"]; err != nil {
+		return false, err
+	}
+	_, ok := c.firewallRules[group]
+	return ok, nil
+}
+
+// CheckKeyPairExists checks if the key pair [name] was created
+func (c *Cloud) CheckKeyPairExists(name string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["CheckKeyPairExists"]; err != nil {
+		return false, err
+	}
+	return c.keyPairs[name], nil
+}
+
+// CreateKeyPair registers the key pair [name]. No private key is written
+func (c *Cloud) CreateKeyPair(name, _ string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.failures["CreateKeyPair"]; err != nil {
+		return err
+	}
+	if c.keyPairs[name] {
+		return fmt.Errorf("key pair %s already exists", name)
+	}
+	c.keyPairs[name] = true
+	return nil
+}
+
+// newIP returns a new unique IP. Must be called with the lock held
+func (c *Cloud) newIP() string {
+	c.numIPs++
+	return fmt.Sprintf("10.%d.%d.%d", (c.numIPs>>16)&0xff, (c.numIPs>>8)&0xff, c.numIPs&0xff)
+}
+
+func firewallRule(ip string, port int32) string {
+	return fmt.Sprintf("%s:%d", ip, port)
+}
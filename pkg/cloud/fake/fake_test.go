@@ -0,0 +1,110 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fake
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesLifecycle(t *testing.T) {
+	require := require.New(t)
+	c := New("fake", []string{"region-a", "region-b"})
+
+	require.NoError(c.CreateKeyPair("kp", ""))
+	exists, err := c.CheckKeyPairExists("kp")
+	require.NoError(err)
+	require.True(exists)
+	require.Error(c.CreateKeyPair("kp", ""))
+
+	staticIPs, err := c.CreateStaticIPs("region-a", "node", 2)
+	require.NoError(err)
+	require.Len(staticIPs, 2)
+	staticIDs, err := c.CreateInstances(cloud.InstanceSpec{
+		Location:  "region-a",
+		Prefix:    "node",
+		Count:     2,
+		KeyPair:   "kp",
+		StaticIPs: staticIPs,
+	})
+	require.NoError(err)
+	dynamicIDs, err := c.CreateInstances(cloud.InstanceSpec{Location: "region-b", Count: 1, KeyPair: "kp"})
+	require.NoError(err)
+	require.NoError(c.WaitForInstances("region-a", staticIDs))
+	require.Error(c.WaitForInstances("region-b", staticIDs))
+
+	_, err = c.CreateInstances(cloud.InstanceSpec{Location: "region-c", Count: 1})
+	require.Error(err)
+	_, err = c.CreateInstances(cloud.InstanceSpec{Location: "region-a", Count: 1, KeyPair: "missing"})
+	require.Error(err)
+	_, err = c.CreateInstances(cloud.InstanceSpec{Location: "region-a", Count: 1, StaticIPs: staticIPs[:1]})
+	require.Error(err)
+
+	listed, err := c.ListInstances("region-a")
+	require.NoError(err)
+	require.Equal(staticIDs, listed)
+
+	publicIPs, err := c.GetInstancePublicIPs("region-a", append(staticIDs, dynamicIDs...))
+	require.NoError(err)
+	require.Equal(map[string]string{staticIDs[0]: staticIPs[0], staticIDs[1]: staticIPs[1]}, publicIPs)
+	newIP, err := c.RotatePublicIP(dynamicIDs[0])
+	require.NoError(err)
+	publicIPs, err = c.GetInstancePublicIPs("region-b", dynamicIDs)
+	require.NoError(err)
+	require.Equal(newIP, publicIPs[dynamicIDs[0]])
+	_, err = c.RotatePublicIP(staticIDs[0])
+	require.Error(err)
+
+	exists, err = c.CheckFirewallGroupExists("sg")
+	require.NoError(err)
+	require.False(exists)
+	require.NoError(c.AddFirewallRule("sg", "1.2.3.4", 22))
+	require.True(c.HasFirewallRule("sg", "1.2.3.4", 22))
+	require.NoError(c.DeleteFirewallRule("sg", "1.2.3.4", 22))
+	require.False(c.HasFirewallRule("sg", "1.2.3.4", 22))
+	require.NoError(c.DeleteFirewallRule("sg", "1.2.3.4", 22))
+	exists, err = c.CheckFirewallGroupExists("sg")
+	require.NoError(err)
+	require.True(exists)
+	c.DeleteFirewallGroup("sg")
+	exists, err = c.CheckFirewallGroupExists("sg")
+	require.NoError(err)
+	require.False(exists)
+
+	require.NoError(c.DestroyNode(models.NodeConfig{NodeID: staticIDs[0], Region: "region-a", UseStaticIP: true}, "cluster"))
+	require.Equal([]string{staticIPs[1]}, c.StaticIPs())
+	err = c.DestroyNode(models.NodeConfig{NodeID: staticIDs[0], Region: "region-a"}, "cluster")
+	require.ErrorIs(err, cloud.ErrNodeNotFoundToBeRunning)
+	require.NoError(c.StopInstance(staticIDs[1]))
+	err = c.DestroyNode(models.NodeConfig{NodeID: staticIDs[1], Region: "region-a"}, "cluster")
+	require.ErrorIs(err, cloud.ErrNodeNotFoundToBeRunning)
+	listed, err = c.ListInstances("region-a")
+	require.NoError(err)
+	require.Empty(listed)
+}
+
+func TestFailOn(t *testing.T) {
+	require := require.New(t)
+	c := New("fake", nil)
+	errInjected := errors.New("injected")
+
+	c.FailOn("ListRegions", errInjected)
+	_, err := c.ListRegions()
+	require.ErrorIs(err, errInjected)
+	c.FailOn("ListRegions", nil)
+	_, err = c.ListRegions()
+	require.NoError(err)
+
+	c.FailOn("DestroyNode", errInjected)
+	ids, err := c.CreateInstances(cloud.InstanceSpec{Location: "anywhere", Count: 1})
+	require.NoError(err)
+	err = c.DestroyNode(models.NodeConfig{NodeID: ids[0], Region: "anywhere"}, "cluster")
+	require.ErrorIs(err, errInjected)
+	_, ok := c.Instance(ids[0])
+	require.True(ok)
+}
@@ -5,7 +5,6 @@ package gcp
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -18,6 +17,7 @@ import (
 
 	"google.golang.org/api/compute/v1"
 
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -30,7 +30,7 @@ const (
 	gcpRegionAPI  = "https://www.googleapis.com/compute/v1/projects/%s/regions/%s"
 )
 
-var ErrNodeNotFoundToBeRunning = errors.New("node not found to be running")
+var ErrNodeNotFoundToBeRunning = cloud.ErrNodeNotFoundToBeRunning
 
 type GcpCloud struct {
 	gcpClient *compute.Service
@@ -461,17 +461,17 @@ func (c *GcpCloud) AddFirewall(publicIP, networkName, projectName, firewallName
 }
 
 // ListRegions returns a list of regions for the GcpCloud instance.
-func (c *GcpCloud) ListRegions() []string {
+func (c *GcpCloud) ListRegions() ([]string, error) {
 	regionListCall := c.gcpClient.Regions.List(c.projectID)
 	regionList, err := regionListCall.Do()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	regions := []string{}
 	for _, region := range regionList.Items {
 		regions = append(regions, region.Name)
 	}
-	return regions
+	return regions, nil
 }
 
 // ListZonesInRegion returns a list of zones in a specific region for a given project ID.
@@ -0,0 +1,158 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/ava-labs/avalanche-cli/pkg/cloud"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+const gcpRunningStatus = "RUNNING"
+
+var _ cloud.CloudProvider = (*GcpCloud)(nil)
+
+// Name returns the GCP cloud service name
+func (*GcpCloud) Name() string {
+	return constants.GCPCloudService
+}
+
+// CreateInstances creates GCP instances as described by [spec]. Instance names are
+// built as <spec.Prefix>-<index>
+func (c *GcpCloud) CreateInstances(spec cloud.InstanceSpec) ([]string, error) {
+	instances, err := c.SetupInstances(
+		spec.Name,
+		spec.Location,
+		spec.FirewallGroup,
+		spec.SSHPublicKey,
+		spec.ImageID,
+		spec.Prefix,
+		spec.InstanceType,
+		spec.StaticIPs,
+		spec.Count,
+		spec.ForMonitoring,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return utils.Map(instances, func(instance *compute.Instance) string { return instance.Name }), nil
+}
+
+// WaitForInstances waits for the GCP instances in [zone] to be running
+func (c *GcpCloud) WaitForInstances(zone string, instanceIDs []string) error {
+	deadline := time.Now().Add(constants.CloudOperationTimeout)
+	for _, instanceID := range instanceIDs {
+		for {
+			instance, err := c.gcpClient.Instances.Get(c.projectID, zone, instanceID).Do()
+			if err != nil {
+				return err
+			}
+			if instance.Status == gcpRunningStatus {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("timeout waiting for instance %s to be running", instanceID)
+			}
+			select {
+			case <-c.ctx.Done():
+				return fmt.Errorf("operation canceled")
+			case <-time.After(1 * time.Second):
+			}
+		}
+	}
+	return nil
+}
+
+// ListInstances returns the names of the running GCP instances in [zone] managed by the CLI
+func (c *GcpCloud) ListInstances(zone string) ([]string, error) {
+	instanceIDs := []string{}
+	if err := c.gcpClient.Instances.List(c.projectID, zone).
+		Filter(`labels.managed-by = "avalanche-cli"`).
+		Pages(c.ctx, func(instances *compute.InstanceList) error {
+			for _, instance := range instances.Items {
+				if instance.Status == gcpRunningStatus {
+					instanceIDs = append(instanceIDs, instance.Name)
+				}
+			}
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	return instanceIDs, nil
+}
+
+// DestroyNode terminates the GCP instance of the node
+func (c *GcpCloud) DestroyNode(nodeConfig models.NodeConfig, clusterName string) error {
+	return c.DestroyGCPNode(nodeConfig, clusterName)
+}
+
+// CreateStaticIPs reserves [count] static IPs in the region of [zone]. [prefix] must
+// be the instance prefix later used on CreateInstances, so the IPs are released
+// when the instances are destroyed
+func (c *GcpCloud) CreateStaticIPs(zone, prefix string, count int) ([]string, error) {
+	return c.SetPublicIP(zone, prefix, count)
+}
+
+// AddFirewallRule allows tcp ingress from [ip] to [port] on network [networkName]
+func (c *GcpCloud) AddFirewallRule(networkName, ip string, port int32) error {
+	firewallName := getFirewallRuleName(networkName, ip, port)
+	firewallExists, err := c.CheckFirewallExists(firewallName, false)
+	if err != nil {
+		return err
+	}
+	if firewallExists {
+		return nil
+	}
+	_, err = c.SetFirewallRule(ip, firewallName, networkName, []string{strconv.Itoa(int(port))})
+	return err
+}
+
+// DeleteFirewallRule removes the firewall rule created by AddFirewallRule for [ip] and [port]
+func (c *GcpCloud) DeleteFirewallRule(networkName, ip string, port int32) error {
+	firewallName := getFirewallRuleName(networkName, ip, port)
+	firewallExists, err := c.CheckFirewallExists(firewallName, false)
+	if err != nil {
+		return err
+	}
+	if !firewallExists {
+		return nil
+	}
+	deleteOp, err := c.gcpClient.Firewalls.Delete(c.projectID, firewallName).Do()
+	if err != nil {
+		return fmt.Errorf("error deleting firewall rule %s: %w", firewallName, err)
+	}
+	return c.waitForOperation(deleteOp)
+}
+
+// CheckKeyPairExists is not supported: GCP ssh keys are set as instance metadata
+// on CreateInstances
+func (*GcpCloud) CheckKeyPairExists(string) (bool, error) {
+	return false, fmt.Errorf("%w: GCP key pairs", cloud.ErrNotSupported)
+}
+
+// CreateKeyPair is not supported: GCP ssh keys are set as instance metadata
+// on CreateInstances
+func (*GcpCloud) CreateKeyPair(string, string) error {
+	return fmt.Errorf("%w: GCP key pairs", cloud.ErrNotSupported)
+}
+
+// CheckFirewallGroupExists checks if the network [networkName] exists in the project
+func (c *GcpCloud) CheckFirewallGroupExists(networkName string) (bool, error) {
+	return c.CheckNetworkExists(networkName)
+}
+
+// getFirewallRuleName returns the name of the firewall rule for [ip] and [port] on [networkName]
+func getFirewallRuleName(networkName, ip string, port int32) string {
+	ip = strings.TrimSuffix(ip, constants.IPAddressSuffix)
+	ip = strings.NewReplacer(".", "", "/", "-").Replace(ip)
+	return fmt.Sprintf("%s-%s-%d", networkName, ip, port)
+}
@@ -72,6 +72,10 @@ func (*Config) GetConfigStringValue(key string) string {
 	return viper.GetString(key)
 }
 
+func (*Config) GetConfigFloat64Value(key string) float64 {
+	return viper.GetFloat64(key)
+}
+
 func (*Config) LoadNodeConfig() (string, error) {
 	globalConfigs := viper.GetStringMap(constants.ConfigNodeConfigKey)
 	if len(globalConfigs) == 0 {
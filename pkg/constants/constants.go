@@ -20,6 +20,7 @@ const (
 	AvalancheCliBinDir = "bin"
 	RunDir             = "runs"
 	ServicesDir        = "services"
+	RelayersDir        = "relayers"
 
 	SuffixSeparator              = "_"
 	SidecarFileName              = "sidecar.json"
@@ -36,6 +37,7 @@ const (
 	CreateAWSNode                = "create-aws-node"
 	GetAWSNodeIP                 = "get-aws-node-ip"
 	ClustersConfigFileName       = "cluster_config.json"
+	KnownHostsFileName           = "known_hosts"
 	ClustersConfigVersion        = "1"
 	StakerCertFileName           = "staker.crt"
 	StakerKeyFileName            = "staker.key"
@@ -60,6 +62,9 @@ const (
 	SSHSleepBetweenChecks = 1 * time.Second
 	SSHShell              = "/bin/bash"
 
+	SSHLongRunningScriptTimeout = 1 * time.Hour
+	SSHDefaultConcurrency       = 20
+
 	SimulatePublicNetwork = "SIMULATE_PUBLIC_NETWORK"
 
 	FujiAPIEndpoint    = "https://api.avax-test.network"
@@ -82,6 +87,21 @@ const (
 
 	DefaultSnapshotName = "default-1654102509"
 
+	BackupsDirName = "backups"
+
+	// price table of the cloud resources, overriding the one bundled with the CLI
+	PriceTableFileName = "prices.json"
+
+	// prefix of the snapshot dirs created by avalanche-network-runner
+	ANRSnapshotPrefix = "anr-snapshot-"
+
+	// number of nodes of the default local network
+	LocalNetworkNumNodes = 5
+	// layout of the local network node config overrides dir
+	LocalNodeFlagsFileName   = "flags.json"
+	LocalChainConfigsDirName = "chain-configs"
+	LocalStakingDirName      = "staking"
+
 	Cortina17Version = "v1.10.17"
 
 	BootstrapSnapshotRawBranch = "https://github.com/ava-labs/avalanche-cli/raw/main/"
@@ -122,6 +142,7 @@ const (
 	KeyDir                  = "key"
 	KeySuffix               = ".pk"
 	YAMLSuffix              = ".yml"
+	JSONSuffix              = ".json"
 
 	Enable = "enable"
 
@@ -182,17 +203,21 @@ const (
 	ConfigMetricsEnabledKey       = "MetricsEnabled"
 	ConfigAuthorizeCloudAccessKey = "AuthorizeCloudAccess"
 	ConfigSingleNodeEnabledKey    = "SingleNodeEnabled"
+	ConfigCloudMonthlyBudgetKey   = "CloudMonthlyBudget"
 	OldConfigFileName             = ".avalanche-cli.json"
 	OldMetricsConfigFileName      = ".avalanche-cli/config"
 	DefaultConfigFileName         = ".avalanche-cli/config.json"
 	DefaultNodeType               = "default"
 	AWSCloudService               = "Amazon Web Services"
 	GCPCloudService               = "Google Cloud Platform"
+	ExistingHostsService          = "Existing Hosts"
 	AWSDefaultInstanceType        = "c5.2xlarge"
 	GCPDefaultInstanceType        = "e2-standard-8"
 	AnsibleSSHUser                = "ubuntu"
 	AWSNodeAnsiblePrefix          = "aws_node"
 	GCPNodeAnsiblePrefix          = "gcp_node"
+	ExistingHostAnsiblePrefix     = "host_node"
+	ExistingHostsRegion           = "existing-hosts"
 	CustomVMDir                   = "vms"
 	ClusterYAMLFileName           = "clusterInfo.yaml"
 	GCPStaticIPPrefix             = "static-ip"
@@ -205,13 +230,16 @@ const (
 	SubnetEVMReleaseURL           = "https://github.com/ava-labs/subnet-evm/releases/download/%s/%s"
 	SubnetEVMArchive              = "subnet-evm_%s_linux_amd64.tar.gz"
 	CloudNodeConfigBasePath       = "/home/ubuntu/.avalanchego/"
+	CloudNodeAvalancheGoBinPath   = "/home/ubuntu/avalanche-node/avalanchego"
 	CloudNodeSubnetEvmBinaryPath  = "/home/ubuntu/.avalanchego/plugins/%s"
 	CloudNodeStakingPath          = "/home/ubuntu/.avalanchego/staking/"
 	CloudNodeConfigPath           = "/home/ubuntu/.avalanchego/configs/"
 	CloudNodePrometheusConfigPath = "/etc/prometheus/prometheus.yml"
 	CloudNodeCLIConfigBasePath    = "/home/ubuntu/.avalanche-cli/"
+	CloudNodeBackupPath           = "/home/ubuntu/avalanche-node-backup.tar.gz"
 	AvalanchegoMonitoringPort     = 9090
 	AvalanchegoMachineMetricsPort = 9100
+	AWMRelayerMetricsPort         = 9091
 	MonitoringDir                 = "monitoring"
 	LoadTestDir                   = "loadtest"
 	DashboardsDir                 = "dashboards"
@@ -226,6 +254,7 @@ const (
 	AWMRelayerStorageDir          = "awm-relayer-storage"
 	AWMRelayerLogFilename         = "awm-relayer.log"
 	AWMRelayerRunFilename         = "awm-relayer-process.json"
+	AWMRelayerInstanceFilename    = "relayer.json"
 
 	AWMRelayerSnapshotConfsDir = "relayer-confs"
 
@@ -244,6 +273,15 @@ const (
 	// #nosec G101
 	GithubAPITokenEnvVarName = "AVALANCHE_CLI_GITHUB_TOKEN"
 
+	// #nosec G101
+	KeyPassphraseEnvVarName = "AVALANCHE_CLI_KEY_PASSPHRASE"
+	// #nosec G101
+	KeyPassphraseFileEnvVarName = "AVALANCHE_CLI_KEY_PASSPHRASE_FILE"
+	// #nosec G101
+	BackupPassphraseEnvVarName = "AVALANCHE_CLI_BACKUP_PASSPHRASE"
+	// #nosec G101
+	BackupPassphraseFileEnvVarName = "AVALANCHE_CLI_BACKUP_PASSPHRASE_FILE"
+
 	ReposDir                   = "repos"
 	SubnetDir                  = "subnets"
 	NodesDir                   = "nodes"
@@ -286,6 +324,7 @@ const (
 	Network                      = "network"
 	MultiSig                     = "multi-sig"
 	SkipUpdateFlag               = "skip-update-check"
+	OutputFormatFlag             = "output"
 	LastFileName                 = ".last_actions.json"
 	APIRole                      = "API"
 	ValidatorRole                = "Validator"
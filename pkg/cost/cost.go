@@ -0,0 +1,214 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cost
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// HoursPerMonth is the average number of hours in a month, used by cloud providers for monthly prices
+const HoursPerMonth = 730
+
+// defaultRegion is the region key of the price used for regions with no price of their own
+const defaultRegion = "default"
+
+//go:embed prices.json
+var defaultPriceTableBytes []byte
+
+var ErrUnknownPrice = errors.New("unknown price")
+
+// CloudPrices holds the on-demand hourly prices, in USD, of the resources of a cloud
+type CloudPrices struct {
+	// Instances holds the price of each instance type by region. The price at the
+	// "default" region is used for the regions not listed
+	Instances map[string]map[string]float64 `json:"instances"`
+	StaticIP  float64                       `json:"staticIP"`
+}
+
+// PriceTable holds the prices of the cloud resources created by the CLI
+type PriceTable struct {
+	UpdatedAt string      `json:"updatedAt"`
+	AWS       CloudPrices `json:"aws"`
+	GCP       CloudPrices `json:"gcp"`
+}
+
+// DefaultPriceTable returns the price table bundled with the CLI
+func DefaultPriceTable() (PriceTable, error) {
+	return ParsePriceTable(defaultPriceTableBytes)
+}
+
+// ParsePriceTable parses and validates the price table at [priceTableBytes]
+func ParsePriceTable(priceTableBytes []byte) (PriceTable, error) {
+	var priceTable PriceTable
+	if err := json.Unmarshal(priceTableBytes, &priceTable); err != nil {
+		return PriceTable{}, fmt.Errorf("invalid price table: %w", err)
+	}
+	for _, cloudPrices := range []CloudPrices{priceTable.AWS, priceTable.GCP} {
+		if cloudPrices.StaticIP < 0 {
+			return PriceTable{}, errors.New("invalid price table: negative static IP price")
+		}
+		for instanceType, regionPrices := range cloudPrices.Instances {
+			for region, price := range regionPrices {
+				if price < 0 {
+					return PriceTable{}, fmt.Errorf("invalid price table: negative price for %s at %s", instanceType, region)
+				}
+			}
+		}
+	}
+	return priceTable, nil
+}
+
+// LoadPriceTable loads the price table at [priceTablePath] if it exists, or otherwise
+// the bundled one
+func LoadPriceTable(priceTablePath string) (PriceTable, error) {
+	if !utils.FileExists(priceTablePath) {
+		return DefaultPriceTable()
+	}
+	priceTableBytes, err := os.ReadFile(priceTablePath)
+	if err != nil {
+		return PriceTable{}, err
+	}
+	return ParsePriceTable(priceTableBytes)
+}
+
+func (pt PriceTable) getCloudPrices(cloudService string) (CloudPrices, error) {
+	switch cloudService {
+	case constants.AWSCloudService:
+		return pt.AWS, nil
+	case constants.GCPCloudService:
+		return pt.GCP, nil
+	default:
+		return CloudPrices{}, fmt.Errorf("%w: cloud %s is not supported", ErrUnknownPrice, cloudService)
+	}
+}
+
+// getPriceRegion returns the region [location] belongs to. GCP instances are located
+// at zones, named after their region
+func getPriceRegion(cloudService string, location string) string {
+	if cloudService == constants.GCPCloudService && strings.Count(location, "-") > 1 {
+		return location[:strings.LastIndex(location, "-")]
+	}
+	return location
+}
+
+// GetInstanceHourlyPrice returns the hourly price of an instance of type [instanceType]
+// at [location] on [cloudService]
+func (pt PriceTable) GetInstanceHourlyPrice(cloudService string, instanceType string, location string) (float64, error) {
+	cloudPrices, err := pt.getCloudPrices(cloudService)
+	if err != nil {
+		return 0, err
+	}
+	regionPrices, ok := cloudPrices.Instances[instanceType]
+	if !ok {
+		return 0, fmt.Errorf("%w: instance type %s", ErrUnknownPrice, instanceType)
+	}
+	if price, ok := regionPrices[getPriceRegion(cloudService, location)]; ok {
+		return price, nil
+	}
+	if price, ok := regionPrices[defaultRegion]; ok {
+		return price, nil
+	}
+	return 0, fmt.Errorf("%w: instance type %s at %s", ErrUnknownPrice, instanceType, location)
+}
+
+// GetStaticIPHourlyPrice returns the hourly price of a static IP on [cloudService]
+func (pt PriceTable) GetStaticIPHourlyPrice(cloudService string) (float64, error) {
+	cloudPrices, err := pt.getCloudPrices(cloudService)
+	if err != nil {
+		return 0, err
+	}
+	return cloudPrices.StaticIP, nil
+}
+
+// GetHostHourlyPrice returns the hourly price of an instance of type [instanceType] at
+// [location] on [cloudService], including its static IP if [useStaticIP]
+func (pt PriceTable) GetHostHourlyPrice(cloudService string, instanceType string, location string, useStaticIP bool) (float64, error) {
+	price, err := pt.GetInstanceHourlyPrice(cloudService, instanceType, location)
+	if err != nil {
+		return 0, err
+	}
+	if useStaticIP {
+		staticIPPrice, err := pt.GetStaticIPHourlyPrice(cloudService)
+		if err != nil {
+			return 0, err
+		}
+		price += staticIPPrice
+	}
+	return price, nil
+}
+
+// Resource is a group of cloud hosts of the same kind to be created
+type Resource struct {
+	Description  string
+	CloudService string
+	InstanceType string
+	Location     string
+	Count        int
+	UseStaticIP  bool
+}
+
+// EstimateItem is the estimated cost of a Resource
+type EstimateItem struct {
+	Resource
+	// HourlyPrice is the price of each host of the resource. Zero if unknown
+	HourlyPrice float64
+	Err         error
+}
+
+// Hourly returns the hourly cost of all the hosts of the resource
+func (ei EstimateItem) Hourly() float64 {
+	return ei.HourlyPrice * float64(ei.Count)
+}
+
+// Estimate is the estimated cost of a set of resources
+type Estimate struct {
+	Items []EstimateItem
+}
+
+// EstimateCost estimates the cost of [resources]. Resources with unknown prices are
+// included with an error, and not accounted for in the totals
+func (pt PriceTable) EstimateCost(resources []Resource) Estimate {
+	estimate := Estimate{}
+	for _, resource := range resources {
+		price, err := pt.GetHostHourlyPrice(resource.CloudService, resource.InstanceType, resource.Location, resource.UseStaticIP)
+		estimate.Items = append(estimate.Items, EstimateItem{Resource: resource, HourlyPrice: price, Err: err})
+	}
+	return estimate
+}
+
+// Hourly returns the hourly cost of the resources with known prices
+func (e Estimate) Hourly() float64 {
+	hourly := 0.0
+	for _, item := range e.Items {
+		hourly += item.Hourly()
+	}
+	return hourly
+}
+
+// Monthly returns the monthly cost of the resources with known prices
+func (e Estimate) Monthly() float64 {
+	return e.Hourly() * HoursPerMonth
+}
+
+// HasUnknownPrices returns true if the price of any of the resources is not known
+func (e Estimate) HasUnknownPrices() bool {
+	return utils.Any(e.Items, func(item EstimateItem) bool { return item.Err != nil })
+}
+
+// GetUptimeCost returns the cost of running a host with [hourlyPrice] since [createdAt] until [now]
+func GetUptimeCost(hourlyPrice float64, createdAt time.Time, now time.Time) float64 {
+	if createdAt.IsZero() || now.Before(createdAt) {
+		return 0
+	}
+	return hourlyPrice * now.Sub(createdAt).Hours()
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPriceTable(t *testing.T) {
+	require := require.New(t)
+	priceTable, err := DefaultPriceTable()
+	require.NoError(err)
+	_, err = priceTable.GetInstanceHourlyPrice(constants.AWSCloudService, constants.AWSDefaultInstanceType, "us-east-1")
+	require.NoError(err)
+	_, err = priceTable.GetInstanceHourlyPrice(constants.GCPCloudService, constants.GCPDefaultInstanceType, "us-east1-b")
+	require.NoError(err)
+}
+
+func TestPrices(t *testing.T) {
+	require := require.New(t)
+	priceTable, err := ParsePriceTable([]byte(`{
+		"aws": {"instances": {"c5.2xlarge": {"default": 0.4, "us-east-1": 0.34}}, "staticIP": 0.005},
+		"gcp": {"instances": {"e2-standard-8": {"us-east1": 0.268}}, "staticIP": 0.004}
+	}`))
+	require.NoError(err)
+
+	price, err := priceTable.GetInstanceHourlyPrice(constants.AWSCloudService, "c5.2xlarge", "us-east-1")
+	require.NoError(err)
+	require.Equal(0.34, price)
+	price, err = priceTable.GetInstanceHourlyPrice(constants.AWSCloudService, "c5.2xlarge", "eu-west-1")
+	require.NoError(err)
+	require.Equal(0.4, price)
+	_, err = priceTable.GetInstanceHourlyPrice(constants.AWSCloudService, "t3.micro", "us-east-1")
+	require.ErrorIs(err, ErrUnknownPrice)
+	// GCP zones use the price of their region
+	price, err = priceTable.GetInstanceHourlyPrice(constants.GCPCloudService, "e2-standard-8", "us-east1-b")
+	require.NoError(err)
+	require.Equal(0.268, price)
+	_, err = priceTable.GetInstanceHourlyPrice(constants.GCPCloudService, "e2-standard-8", "europe-west1-b")
+	require.ErrorIs(err, ErrUnknownPrice)
+	_, err = priceTable.GetInstanceHourlyPrice(constants.ExistingHostsService, "c5.2xlarge", "us-east-1")
+	require.ErrorIs(err, ErrUnknownPrice)
+	price, err = priceTable.GetHostHourlyPrice(constants.GCPCloudService, "e2-standard-8", "us-east1-b", true)
+	require.NoError(err)
+	require.InDelta(0.272, price, 1e-9)
+
+	_, err = ParsePriceTable([]byte(`{"aws": {"instances": {"c5.2xlarge": {"default": -1}}}}`))
+	require.ErrorContains(err, "negative price")
+	_, err = ParsePriceTable([]byte(`not json`))
+	require.ErrorContains(err, "invalid price table")
+}
+
+func TestEstimateCost(t *testing.T) {
+	require := require.New(t)
+	priceTable, err := ParsePriceTable([]byte(`{
+		"aws": {"instances": {"c5.2xlarge": {"default": 0.4}}, "staticIP": 0.1}
+	}`))
+	require.NoError(err)
+	estimate := priceTable.EstimateCost([]Resource{
+		{CloudService: constants.AWSCloudService, InstanceType: "c5.2xlarge", Location: "us-east-1", Count: 3, UseStaticIP: true},
+		{CloudService: constants.AWSCloudService, InstanceType: "c5.2xlarge", Location: "us-east-1", Count: 1},
+	})
+	require.False(estimate.HasUnknownPrices())
+	require.InDelta(1.9, estimate.Hourly(), 1e-9)
+	require.InDelta(1.9*HoursPerMonth, estimate.Monthly(), 1e-9)
+
+	estimate = priceTable.EstimateCost([]Resource{
+		{CloudService: constants.AWSCloudService, InstanceType: "c5.2xlarge", Location: "us-east-1", Count: 1},
+		{CloudService: constants.AWSCloudService, InstanceType: "t3.micro", Location: "us-east-1", Count: 1},
+	})
+	require.True(estimate.HasUnknownPrices())
+	require.InDelta(0.4, estimate.Hourly(), 1e-9)
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	require := require.New(t)
+	priceTablePath := filepath.Join(t.TempDir(), "prices.json")
+	priceTable, err := LoadPriceTable(priceTablePath)
+	require.NoError(err)
+	defaultPriceTable, err := DefaultPriceTable()
+	require.NoError(err)
+	require.Equal(defaultPriceTable, priceTable)
+
+	require.NoError(os.WriteFile(priceTablePath, []byte(`{"updatedAt": "2030-01-01"}`), 0o600))
+	priceTable, err = LoadPriceTable(priceTablePath)
+	require.NoError(err)
+	require.Equal("2030-01-01", priceTable.UpdatedAt)
+}
+
+func TestGetUptimeCost(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	require.InDelta(2.5, GetUptimeCost(0.5, now.Add(-5*time.Hour), now), 1e-9)
+	require.Zero(GetUptimeCost(0.5, time.Time{}, now))
+	require.Zero(GetUptimeCost(0.5, now.Add(time.Hour), now))
+}
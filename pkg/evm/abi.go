@@ -0,0 +1,93 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PackABIValues ABI encodes [values], each one given as a string, as the solidity
+// types [typeStrs]. Only address, bool, string, bytes, bytesN, intN and uintN types
+// are supported
+func PackABIValues(typeStrs []string, values []string) ([]byte, error) {
+	if len(typeStrs) != len(values) {
+		return nil, fmt.Errorf("got %d values for %d ABI types", len(values), len(typeStrs))
+	}
+	arguments := abi.Arguments{}
+	parsedValues := []interface{}{}
+	for i, typeStr := range typeStrs {
+		typ, err := abi.NewType(strings.TrimSpace(typeStr), "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI type %q: %w", typeStr, err)
+		}
+		value, err := parseABIValue(typ, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", typ, values[i], err)
+		}
+		arguments = append(arguments, abi.Argument{Type: typ})
+		parsedValues = append(parsedValues, value)
+	}
+	return arguments.Pack(parsedValues...)
+}
+
+// parseABIValue parses [value] into the go type used by the ABI encoder for [typ]
+func parseABIValue(typ abi.Type, value string) (interface{}, error) {
+	switch typ.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(value) {
+			return nil, errors.New("not an address")
+		}
+		return common.HexToAddress(value), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(value)
+	case abi.StringTy:
+		return value, nil
+	case abi.BytesTy:
+		return hexutil.Decode(value)
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != typ.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", typ.Size, len(b))
+		}
+		fixedBytes := reflect.New(typ.GetType()).Elem()
+		reflect.Copy(fixedBytes, reflect.ValueOf(b))
+		return fixedBytes.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return nil, errors.New("not an integer")
+		}
+		if typ.T == abi.UintTy && n.Sign() < 0 {
+			return nil, errors.New("negative value")
+		}
+		// small sizes are encoded from the go integer type of the same size
+		goType := typ.GetType()
+		if goType.Kind() == reflect.Ptr {
+			return n, nil
+		}
+		if typ.T == abi.UintTy {
+			if !n.IsUint64() || reflect.Zero(goType).OverflowUint(n.Uint64()) {
+				return nil, errors.New("out of range")
+			}
+			return reflect.ValueOf(n.Uint64()).Convert(goType).Interface(), nil
+		}
+		if !n.IsInt64() || reflect.Zero(goType).OverflowInt(n.Int64()) {
+			return nil, errors.New("out of range")
+		}
+		return reflect.ValueOf(n.Int64()).Convert(goType).Interface(), nil
+	default:
+		return nil, fmt.Errorf("ABI type %s is not supported", typ)
+	}
+}
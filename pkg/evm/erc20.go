@@ -0,0 +1,109 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package evm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	erc20 "github.com/ava-labs/teleporter/abi-bindings/go/Mocks/ExampleERC20"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetERC20Info returns the symbol and decimals of the ERC-20 token at [tokenAddressStr]
+func GetERC20Info(
+	client ethclient.Client,
+	tokenAddressStr string,
+) (string, uint8, error) {
+	token, err := erc20.NewExampleERC20Caller(common.HexToAddress(tokenAddressStr), client)
+	if err != nil {
+		return "", 0, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	symbol, err := token.Symbol(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return "", 0, fmt.Errorf("failure getting symbol of token %s: %w", tokenAddressStr, err)
+	}
+	decimals, err := token.Decimals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return "", 0, fmt.Errorf("failure getting decimals of token %s: %w", tokenAddressStr, err)
+	}
+	return symbol, decimals, nil
+}
+
+// GetERC20Balance returns the balance of [addressStr] on the ERC-20 token at [tokenAddressStr]
+func GetERC20Balance(
+	client ethclient.Client,
+	tokenAddressStr string,
+	addressStr string,
+) (*big.Int, error) {
+	token, err := erc20.NewExampleERC20Caller(common.HexToAddress(tokenAddressStr), client)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return token.BalanceOf(&bind.CallOpts{Context: ctx}, common.HexToAddress(addressStr))
+}
+
+// TransferERC20 transfers [amount] (in the token smallest unit) of the ERC-20
+// token at [tokenAddressStr] to [targetAddressStr]
+func TransferERC20(
+	client ethclient.Client,
+	sourceAddressPrivateKeyStr string,
+	tokenAddressStr string,
+	targetAddressStr string,
+	amount *big.Int,
+) error {
+	token, err := erc20.NewExampleERC20(common.HexToAddress(tokenAddressStr), client)
+	if err != nil {
+		return err
+	}
+	signer, err := GetSigner(client, sourceAddressPrivateKeyStr)
+	if err != nil {
+		return err
+	}
+	tx, err := token.Transfer(signer, common.HexToAddress(targetAddressStr), amount)
+	if err != nil {
+		return err
+	}
+	if _, b, err := WaitForTransaction(client, tx); err != nil {
+		return err
+	} else if !b {
+		return fmt.Errorf("failure transferring %s of token %s to %s", amount, tokenAddressStr, targetAddressStr)
+	}
+	return nil
+}
+
+// ApproveERC20 allows [spenderAddressStr] to spend [amount] (in the token smallest unit)
+// of the ERC-20 token at [tokenAddressStr] on behalf of the owner of [ownerPrivateKeyStr]
+func ApproveERC20(
+	client ethclient.Client,
+	ownerPrivateKeyStr string,
+	tokenAddressStr string,
+	spenderAddressStr string,
+	amount *big.Int,
+) error {
+	token, err := erc20.NewExampleERC20(common.HexToAddress(tokenAddressStr), client)
+	if err != nil {
+		return err
+	}
+	signer, err := GetSigner(client, ownerPrivateKeyStr)
+	if err != nil {
+		return err
+	}
+	tx, err := token.Approve(signer, common.HexToAddress(spenderAddressStr), amount)
+	if err != nil {
+		return err
+	}
+	if _, b, err := WaitForTransaction(client, tx); err != nil {
+		return err
+	} else if !b {
+		return fmt.Errorf("failure approving %s to spend %s of token %s", spenderAddressStr, amount, tokenAddressStr)
+	}
+	return nil
+}
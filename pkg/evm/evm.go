@@ -3,8 +3,10 @@
 package evm
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
 	"github.com/ava-labs/subnet-evm/core/types"
@@ -227,6 +229,39 @@ func GetTrace(rpcURL string, txID string) (map[string]interface{}, error) {
 	return result, err
 }
 
+// CallRPC issues the JSON-RPC request [method] with [params] to [rpcURL], and returns its raw result
+func CallRPC(rpcURL string, method string, params ...interface{}) (json.RawMessage, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	var result json.RawMessage
+	err = client.CallContext(ctx, &result, method, params...)
+	return result, err
+}
+
+// GetActivePrecompiles returns the keys of the precompiles active at the last block of
+// the subnet-evm chain at [rpcURL]
+func GetActivePrecompiles(rpcURL string) ([]string, error) {
+	result, err := CallRPC(rpcURL, "eth_getActivePrecompilesAt")
+	if err != nil {
+		return nil, err
+	}
+	var precompiles map[string]json.RawMessage
+	if err := json.Unmarshal(result, &precompiles); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(precompiles))
+	for key := range precompiles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
 func SetupProposerVM(
 	endpoint string,
 	privKeyStr string,
@@ -0,0 +1,94 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	eth_crypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoPassphrase    = errors.New("key file is encrypted and no passphrase source is available")
+	ErrWrongPassphrase = errors.New("could not decrypt key file: wrong passphrase")
+	ErrEmptyPassphrase = errors.New("passphrase can't be empty")
+)
+
+// scrypt parameters used to encrypt key files. Variables so tests can use lighter ones
+var (
+	scryptN = keystore.StandardScryptN
+	scryptP = keystore.StandardScryptP
+)
+
+// IsEncryptedKeyFile returns true if [kb] has the contents of an
+// encrypted key file (ethereum keystore v3 json)
+func IsEncryptedKeyFile(kb []byte) bool {
+	var keyJSON struct {
+		Crypto *keystore.CryptoJSON `json:"crypto"`
+	}
+	if err := json.Unmarshal(kb, &keyJSON); err != nil {
+		return false
+	}
+	return keyJSON.Crypto != nil
+}
+
+// IsEncryptedKeyPath returns true if the key file at [keyPath] is encrypted
+func IsEncryptedKeyPath(keyPath string) (bool, error) {
+	kb, err := os.ReadFile(keyPath)
+	if err != nil {
+		return false, err
+	}
+	return IsEncryptedKeyFile(kb), nil
+}
+
+// EncryptedBytes encrypts the private key with [passphrase], using the
+// ethereum keystore v3 json format (scrypt kdf + aes-128-ctr)
+func (m *SoftKey) EncryptedBytes(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	ecdsaPrv := m.privKey.ToECDSA()
+	return keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    eth_crypto.PubkeyToAddress(ecdsaPrv.PublicKey),
+		PrivateKey: ecdsaPrv,
+	}, passphrase, scryptN, scryptP)
+}
+
+// SaveEncrypted saves the private key to disk, encrypted with [passphrase]
+func (m *SoftKey) SaveEncrypted(p string, passphrase string) error {
+	kb, err := m.EncryptedBytes(passphrase)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, kb, constants.WriteReadUserOnlyPerms)
+}
+
+// LoadSoftFromEncryptedBytes decrypts the encrypted key file contents [kb]
+// with [passphrase] and creates the corresponding SoftKey
+func LoadSoftFromEncryptedBytes(networkID uint32, kb []byte, passphrase string) (*SoftKey, error) {
+	k, err := keystore.DecryptKey(kb, passphrase)
+	if err != nil {
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return nil, ErrWrongPassphrase
+		}
+		return nil, fmt.Errorf("invalid encrypted key file: %w", err)
+	}
+	privKey, err := secp256k1.ToPrivateKey(eth_crypto.FromECDSA(k.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewSoft(networkID, WithPrivateKey(privKey))
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/tyler-smith/go-bip32"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// BIP-44 coin types
+	AvalancheCoinType = 9000
+	EthereumCoinType  = 60
+
+	hdKeyFileType = "hd"
+)
+
+var (
+	ErrInvalidMnemonic    = errors.New("invalid BIP-39 mnemonic")
+	ErrInvalidMnemonicLen = errors.New("invalid mnemonic length (expect 12 or 24 words)")
+	ErrNoHDIndices        = errors.New("at least one account index must be given")
+)
+
+var mnemonicWordsToEntropyLen = map[int]int{12: 128, 24: 256}
+
+// HDKeyFile is the stored form of a BIP-39 mnemonic based key: the mnemonic,
+// in plaintext or encrypted, and the indices of the derived accounts to use
+type HDKeyFile struct {
+	Type     string               `json:"type"`
+	Mnemonic string               `json:"mnemonic,omitempty"`
+	Crypto   *keystore.CryptoJSON `json:"crypto,omitempty"`
+	Indices  []uint32             `json:"indices"`
+}
+
+// HDAccount holds the keys derived for an account index. The Avalanche key
+// (m/44'/9000'/0'/0/index) is used on the P-Chain and X-Chain, and the
+// EVM key (m/44'/60'/0'/0/index) on the C-Chain and subnet EVMs
+type HDAccount struct {
+	Index        uint32
+	AvalancheKey *SoftKey
+	EVMKey       *SoftKey
+}
+
+// NewMnemonic generates a new random BIP-39 mnemonic of [words] words (12 or 24)
+func NewMnemonic(words int) (string, error) {
+	entropyLen, ok := mnemonicWordsToEntropyLen[words]
+	if !ok {
+		return "", ErrInvalidMnemonicLen
+	}
+	entropy, err := bip39.NewEntropy(entropyLen)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// NormalizeMnemonic trims extra whitespace from [mnemonic] and validates it
+func NormalizeMnemonic(mnemonic string) (string, error) {
+	words := strings.Fields(mnemonic)
+	if _, ok := mnemonicWordsToEntropyLen[len(words)]; !ok {
+		return "", ErrInvalidMnemonicLen
+	}
+	mnemonic = strings.Join(words, " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return "", ErrInvalidMnemonic
+	}
+	return mnemonic, nil
+}
+
+// DerivationPath returns the BIP-44 derivation path for [coinType] and [index]
+func DerivationPath(coinType uint32, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index)
+}
+
+// DeriveSoft derives the BIP-44 key of [coinType] and [index] from [mnemonic]
+func DeriveSoft(networkID uint32, mnemonic string, coinType uint32, index uint32) (*SoftKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	k, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, childIdx := range []uint32{
+		bip32.FirstHardenedChild + 44,
+		bip32.FirstHardenedChild + coinType,
+		bip32.FirstHardenedChild,
+		0,
+		index,
+	} {
+		k, err = k.NewChildKey(childIdx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	privKey, err := secp256k1.ToPrivateKey(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	return NewSoft(networkID, WithPrivateKey(privKey))
+}
+
+// NewHDKeyFile creates the stored form of a mnemonic based key for account [indices]
+func NewHDKeyFile(mnemonic string, indices []uint32) (*HDKeyFile, error) {
+	mnemonic, err := NormalizeMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, ErrNoHDIndices
+	}
+	return &HDKeyFile{
+		Type:     hdKeyFileType,
+		Mnemonic: mnemonic,
+		Indices:  indices,
+	}, nil
+}
+
+// IsHDKeyFile returns true if [kb] has the contents of a mnemonic based key file
+func IsHDKeyFile(kb []byte) bool {
+	var f HDKeyFile
+	if err := json.Unmarshal(kb, &f); err != nil {
+		return false
+	}
+	return f.Type == hdKeyFileType
+}
+
+// Encrypted returns true if the mnemonic is stored encrypted
+func (f *HDKeyFile) Encrypted() bool {
+	return f.Crypto != nil
+}
+
+// Save writes the key file to [p]. If [passphrase] is not empty,
+// the mnemonic is stored encrypted with it
+func (f *HDKeyFile) Save(p string, passphrase string) error {
+	stored := HDKeyFile{
+		Type:    hdKeyFileType,
+		Indices: f.Indices,
+	}
+	if passphrase == "" {
+		stored.Mnemonic = f.Mnemonic
+	} else {
+		crypto, err := keystore.EncryptDataV3([]byte(f.Mnemonic), []byte(passphrase), scryptN, scryptP)
+		if err != nil {
+			return err
+		}
+		stored.Crypto = &crypto
+	}
+	kb, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, kb, constants.WriteReadUserOnlyPerms)
+}
+
+// ParseHDKeyFile parses the contents [kb] of a mnemonic based key file.
+// If the mnemonic is encrypted, it is left so, and must be decrypted with Decrypt
+func ParseHDKeyFile(kb []byte) (*HDKeyFile, error) {
+	var f HDKeyFile
+	if err := json.Unmarshal(kb, &f); err != nil {
+		return nil, err
+	}
+	if f.Type != hdKeyFileType {
+		return nil, fmt.Errorf("invalid key file type %q", f.Type)
+	}
+	if len(f.Indices) == 0 {
+		return nil, ErrNoHDIndices
+	}
+	if f.Mnemonic == "" && f.Crypto == nil {
+		return nil, errors.New("invalid key file: no mnemonic found")
+	}
+	return &f, nil
+}
+
+// Decrypt decrypts the stored mnemonic with [passphrase]
+func (f *HDKeyFile) Decrypt(passphrase string) error {
+	if f.Crypto == nil {
+		return nil
+	}
+	mnemonic, err := keystore.DecryptDataV3(*f.Crypto, passphrase)
+	if err != nil {
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return ErrWrongPassphrase
+		}
+		return err
+	}
+	f.Mnemonic = string(mnemonic)
+	f.Crypto = nil
+	return nil
+}
+
+// Accounts derives the keys of all stored account indices
+func (f *HDKeyFile) Accounts(networkID uint32) ([]HDAccount, error) {
+	if f.Encrypted() {
+		return nil, errors.New("mnemonic must be decrypted first")
+	}
+	accounts := make([]HDAccount, 0, len(f.Indices))
+	for _, index := range f.Indices {
+		avalancheKey, err := DeriveSoft(networkID, f.Mnemonic, AvalancheCoinType, index)
+		if err != nil {
+			return nil, err
+		}
+		evmKey, err := DeriveSoft(networkID, f.Mnemonic, EthereumCoinType, index)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, HDAccount{
+			Index:        index,
+			AvalancheKey: avalancheKey,
+			EVMKey:       evmKey,
+		})
+	}
+	return accounts, nil
+}
+
+// LoadHD loads the mnemonic based key file at [keyPath] and derives the keys
+// of all its account indices. An encrypted mnemonic is decrypted with the
+// passphrase given by the PassphraseFunc
+func LoadHD(networkID uint32, keyPath string) ([]HDAccount, error) {
+	kb, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseHDKeyFile(kb)
+	if err != nil {
+		return nil, err
+	}
+	if f.Encrypted() {
+		if passphraseFunc == nil {
+			return nil, ErrNoPassphrase
+		}
+		passphrase, err := passphraseFunc(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.Decrypt(passphrase); err != nil {
+			return nil, err
+		}
+	}
+	return f.Accounts(networkID)
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	// well known development mnemonic
+	testMnemonic = "test test test test test test test test test test test junk"
+	// its first EVM account
+	testMnemonicEVMAddr = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+)
+
+func TestNewMnemonic(t *testing.T) {
+	t.Parallel()
+
+	for _, words := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(words)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(strings.Fields(mnemonic)) != words {
+			t.Fatalf("unexpected mnemonic length %d, expected %d", len(strings.Fields(mnemonic)), words)
+		}
+		if _, err := NormalizeMnemonic(mnemonic); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := NewMnemonic(13); !errors.Is(err, ErrInvalidMnemonicLen) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidMnemonicLen)
+	}
+	if _, err := NormalizeMnemonic(strings.Replace(testMnemonic, "junk", "test", 1)); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrInvalidMnemonic)
+	}
+}
+
+func TestDeriveSoft(t *testing.T) {
+	t.Parallel()
+
+	evmKey, err := DeriveSoft(fallbackNetworkID, testMnemonic, EthereumCoinType, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evmKey.C() != testMnemonicEVMAddr {
+		t.Fatalf("unexpected C-Chain address %q, expected %q", evmKey.C(), testMnemonicEVMAddr)
+	}
+	avalancheKey, err := DeriveSoft(fallbackNetworkID, testMnemonic, AvalancheCoinType, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avalancheKey.P()[0] == evmKey.P()[0] {
+		t.Fatal("expected different keys for different coin types")
+	}
+	if DerivationPath(AvalancheCoinType, 3) != "m/44'/9000'/0'/0/3" {
+		t.Fatalf("unexpected derivation path %q", DerivationPath(AvalancheCoinType, 3))
+	}
+}
+
+func TestHDKeyFile(t *testing.T) {
+	scryptN, scryptP = 2, 1
+
+	f, err := NewHDKeyFile("  "+testMnemonic+"\n", []uint32{0, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHDKeyFile(testMnemonic, nil); !errors.Is(err, ErrNoHDIndices) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrNoHDIndices)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pk")
+	if err := f.Save(keyPath, ""); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := LoadHD(fallbackNetworkID, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0].Index != 0 || accounts[1].Index != 2 {
+		t.Fatalf("unexpected accounts %v", accounts)
+	}
+	if accounts[0].EVMKey.C() != testMnemonicEVMAddr {
+		t.Fatalf("unexpected C-Chain address %q, expected %q", accounts[0].EVMKey.C(), testMnemonicEVMAddr)
+	}
+	sk, err := LoadSoft(fallbackNetworkID, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk.P()[0] != accounts[0].AvalancheKey.P()[0] {
+		t.Fatalf("unexpected P-Chain address %q, expected %q", sk.P()[0], accounts[0].AvalancheKey.P()[0])
+	}
+
+	// encrypted mnemonic
+	if err := f.Save(keyPath, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := IsEncryptedKeyPath(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypted {
+		t.Fatal("expected key file to be encrypted")
+	}
+	SetPassphraseFunc(func(string) (string, error) {
+		return "passphrase", nil
+	})
+	defer SetPassphraseFunc(nil)
+	encryptedAccounts, err := LoadHD(fallbackNetworkID, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encryptedAccounts[1].AvalancheKey.P()[0] != accounts[1].AvalancheKey.P()[0] {
+		t.Fatal("unexpected key derived from encrypted mnemonic")
+	}
+}
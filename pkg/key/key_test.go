@@ -6,9 +6,12 @@ package key
 import (
 	"bytes"
 	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+
 	"github.com/ava-labs/avalanchego/utils/cb58"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 )
@@ -113,3 +116,66 @@ func TestNewKey(t *testing.T) {
 		}
 	}
 }
+
+func TestEncryptedKey(t *testing.T) {
+	scryptN, scryptP = 2, 1
+
+	m, err := NewSoft(fallbackNetworkID, WithPrivateKeyEncoded(EwoqPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pk")
+	if err := m.SaveEncrypted(keyPath, ""); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrEmptyPassphrase)
+	}
+	if err := m.SaveEncrypted(keyPath, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := IsEncryptedKeyPath(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypted {
+		t.Fatal("expected key file to be encrypted")
+	}
+
+	SetPassphraseFunc(nil)
+	if _, err := LoadSoft(fallbackNetworkID, keyPath); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrNoPassphrase)
+	}
+
+	prompts := 0
+	SetPassphraseFunc(NewPassphraseFunc(func(string) (string, error) {
+		prompts++
+		return "wrong", nil
+	}))
+	if _, err := LoadSoft(fallbackNetworkID, keyPath); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("unexpected error %v, expected %v", err, ErrWrongPassphrase)
+	}
+
+	t.Setenv(constants.KeyPassphraseEnvVarName, "passphrase")
+	m2, err := LoadSoft(fallbackNetworkID, keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.Raw(), m2.Raw()) {
+		t.Fatalf("loaded key unexpected %v, expected %v", m2.Raw(), m.Raw())
+	}
+	if m2.P()[0] != ewoqPChainAddr {
+		t.Fatalf("unexpected P-Chain address %q, expected %q", m2.P(), ewoqPChainAddr)
+	}
+	if prompts != 1 {
+		t.Fatalf("unexpected number of prompts %d, expected 1", prompts)
+	}
+
+	t.Setenv(constants.KeyPassphraseEnvVarName, "")
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("passphrase\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(constants.KeyPassphraseFileEnvVarName, passphraseFile)
+	if _, err := LoadSoft(fallbackNetworkID, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	SetPassphraseFunc(nil)
+}
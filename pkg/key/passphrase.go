@@ -0,0 +1,81 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package key
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// PassphraseFunc returns the passphrase to decrypt the key file at [keyPath]
+type PassphraseFunc func(keyPath string) (string, error)
+
+var passphraseFunc PassphraseFunc
+
+// SetPassphraseFunc sets how LoadSoft gets the passphrase of encrypted key files
+func SetPassphraseFunc(f PassphraseFunc) {
+	passphraseFunc = f
+}
+
+// GetPassphraseFromEnv returns the key passphrase set on the environment, either
+// directly or as a path to a file containing it. Returns false if none is set
+func GetPassphraseFromEnv() (string, bool, error) {
+	if passphrase := os.Getenv(constants.KeyPassphraseEnvVarName); passphrase != "" {
+		return passphrase, true, nil
+	}
+	passphraseFile := os.Getenv(constants.KeyPassphraseFileEnvVarName)
+	if passphraseFile == "" {
+		return "", false, nil
+	}
+	passphrase, err := ReadPassphraseFile(passphraseFile)
+	if err != nil {
+		return "", false, err
+	}
+	return passphrase, true, nil
+}
+
+// ReadPassphraseFile reads a passphrase from the first line of [passphraseFile]
+func ReadPassphraseFile(passphraseFile string) (string, error) {
+	bs, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	passphrase, _, _ := strings.Cut(string(bs), "\n")
+	passphrase = strings.TrimSuffix(passphrase, "\r")
+	if passphrase == "" {
+		return "", ErrEmptyPassphrase
+	}
+	return passphrase, nil
+}
+
+// NewPassphraseFunc creates a PassphraseFunc that first looks at the environment,
+// and otherwise calls [prompt]. Prompted passphrases are remembered by key path,
+// so each key is asked for at most once
+func NewPassphraseFunc(prompt func(keyPath string) (string, error)) PassphraseFunc {
+	var (
+		lock     sync.Mutex
+		prompted = map[string]string{}
+	)
+	return func(keyPath string) (string, error) {
+		passphrase, ok, err := GetPassphraseFromEnv()
+		if err != nil || ok {
+			return passphrase, err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if passphrase, ok := prompted[keyPath]; ok {
+			return passphrase, nil
+		}
+		passphrase, err = prompt(keyPath)
+		if err != nil {
+			return "", err
+		}
+		prompted[keyPath] = passphrase
+		return passphrase, nil
+	}
+}
@@ -147,11 +147,30 @@ func NewSoft(networkID uint32, opts ...SOpOption) (*SoftKey, error) {
 }
 
 // LoadSoft loads the private key from disk and creates the corresponding SoftKey.
+// Encrypted key files are decrypted with the passphrase given by the PassphraseFunc.
+// For mnemonic based key files, the Avalanche key of the first stored account is used
 func LoadSoft(networkID uint32, keyPath string) (*SoftKey, error) {
 	kb, err := os.ReadFile(keyPath)
 	if err != nil {
 		return nil, err
 	}
+	if IsHDKeyFile(kb) {
+		accounts, err := LoadHD(networkID, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return accounts[0].AvalancheKey, nil
+	}
+	if IsEncryptedKeyFile(kb) {
+		if passphraseFunc == nil {
+			return nil, ErrNoPassphrase
+		}
+		passphrase, err := passphraseFunc(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return LoadSoftFromEncryptedBytes(networkID, kb, passphrase)
+	}
 	return LoadSoftFromBytes(networkID, kb)
 }
 
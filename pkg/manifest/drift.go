@@ -0,0 +1,215 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package manifest
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const notSetLabel = "<not set>"
+
+// Drift is a difference between the manifest and the stored subnet configuration
+type Drift struct {
+	Field    string `json:"field"`
+	Manifest string `json:"manifest"`
+	Stored   string `json:"stored"`
+}
+
+// ComputeDrift compares the manifest against the stored sidecar and subnet evm genesis.
+// [ignoreAlloc] addresses (eg. the teleporter deployer) are not considered on
+// airdrop comparison
+func (m *SubnetManifest) ComputeDrift(sc models.Sidecar, genesis core.Genesis, ignoreAlloc []common.Address) []Drift {
+	drifts := []Drift{}
+	add := func(field string, manifestValue string, storedValue string) {
+		if manifestValue != storedValue {
+			drifts = append(drifts, Drift{Field: field, Manifest: manifestValue, Stored: storedValue})
+		}
+	}
+	add("vm.type", m.VM.Type, string(sc.VM))
+	add("vm.version", m.VM.Version, sc.VMVersion)
+	add("token", m.Token, sc.TokenName)
+	add("teleporter", fmt.Sprint(m.Teleporter), fmt.Sprint(sc.TeleporterReady))
+	if genesis.Config == nil {
+		add("chainID", fmt.Sprint(m.ChainID), notSetLabel)
+		return drifts
+	}
+	storedChainID := notSetLabel
+	if genesis.Config.ChainID != nil {
+		storedChainID = genesis.Config.ChainID.String()
+	}
+	add("chainID", fmt.Sprint(m.ChainID), storedChainID)
+	manifestFee := m.GetFeeConfig()
+	storedFee := genesis.Config.FeeConfig
+	add("feeConfig.gasLimit", bigString(manifestFee.GasLimit), bigString(storedFee.GasLimit))
+	add("feeConfig.targetBlockRate", fmt.Sprint(manifestFee.TargetBlockRate), fmt.Sprint(storedFee.TargetBlockRate))
+	add("feeConfig.minBaseFee", bigString(manifestFee.MinBaseFee), bigString(storedFee.MinBaseFee))
+	add("feeConfig.targetGas", bigString(manifestFee.TargetGas), bigString(storedFee.TargetGas))
+	add("feeConfig.baseFeeChangeDenominator", bigString(manifestFee.BaseFeeChangeDenominator), bigString(storedFee.BaseFeeChangeDenominator))
+	add("feeConfig.minBlockGasCost", bigString(manifestFee.MinBlockGasCost), bigString(storedFee.MinBlockGasCost))
+	add("feeConfig.maxBlockGasCost", bigString(manifestFee.MaxBlockGasCost), bigString(storedFee.MaxBlockGasCost))
+	add("feeConfig.blockGasCostStep", bigString(manifestFee.BlockGasCostStep), bigString(storedFee.BlockGasCostStep))
+	// validated on load
+	manifestAlloc, _ := m.GenesisAlloc()
+	addresses := map[common.Address]struct{}{}
+	for address := range manifestAlloc {
+		addresses[address] = struct{}{}
+	}
+	for address := range genesis.Alloc {
+		addresses[address] = struct{}{}
+	}
+	for _, address := range ignoreAlloc {
+		delete(addresses, address)
+	}
+	sortedAddresses := make([]common.Address, 0, len(addresses))
+	for address := range addresses {
+		sortedAddresses = append(sortedAddresses, address)
+	}
+	sort.Slice(sortedAddresses, func(i, j int) bool {
+		return sortedAddresses[i].Hex() < sortedAddresses[j].Hex()
+	})
+	for _, address := range sortedAddresses {
+		add("airdrops."+address.Hex(), allocString(manifestAlloc, address), allocString(genesis.Alloc, address))
+	}
+	drifts = append(drifts, precompilesDrift(m.Precompiles, genesis.Config.GenesisPrecompiles)...)
+	return drifts
+}
+
+func precompilesDrift(p Precompiles, stored params.Precompiles) []Drift {
+	drifts := []Drift{}
+	_, storedWarp := stored[warp.ConfigKey]
+	if p.Warp != storedWarp {
+		drifts = append(drifts, Drift{Field: "precompiles.warp", Manifest: fmt.Sprint(p.Warp), Stored: fmt.Sprint(storedWarp)})
+	}
+	storedAllowList := func(key string) *allowlist.AllowListConfig {
+		switch cfg := stored[key].(type) {
+		case *nativeminter.Config:
+			return &cfg.AllowListConfig
+		case *deployerallowlist.Config:
+			return &cfg.AllowListConfig
+		case *txallowlist.Config:
+			return &cfg.AllowListConfig
+		case *feemanager.Config:
+			return &cfg.AllowListConfig
+		case *rewardmanager.Config:
+			return &cfg.AllowListConfig
+		}
+		return nil
+	}
+	var rewardManagerAllowList *AllowList
+	if p.RewardManager != nil {
+		rewardManagerAllowList = &p.RewardManager.AllowList
+	}
+	for _, entry := range []struct {
+		field string
+		key   string
+		list  *AllowList
+	}{
+		{"precompiles.nativeMinter", nativeminter.ConfigKey, p.NativeMinter},
+		{"precompiles.contractDeployerAllowList", deployerallowlist.ConfigKey, p.ContractDeployerAllowList},
+		{"precompiles.txAllowList", txallowlist.ConfigKey, p.TxAllowList},
+		{"precompiles.feeManager", feemanager.ConfigKey, p.FeeManager},
+		{"precompiles.rewardManager", rewardmanager.ConfigKey, rewardManagerAllowList},
+	} {
+		storedList := storedAllowList(entry.key)
+		switch {
+		case entry.list == nil && storedList == nil:
+		case entry.list == nil:
+			drifts = append(drifts, Drift{Field: entry.field, Manifest: "disabled", Stored: "enabled"})
+		case storedList == nil:
+			drifts = append(drifts, Drift{Field: entry.field, Manifest: "enabled", Stored: "disabled"})
+		default:
+			for _, addresses := range []struct {
+				field    string
+				manifest []common.Address
+				stored   []common.Address
+			}{
+				{"adminAddresses", entry.list.AdminAddresses, storedList.AdminAddresses},
+				{"managerAddresses", entry.list.ManagerAddresses, storedList.ManagerAddresses},
+				{"enabledAddresses", entry.list.EnabledAddresses, storedList.EnabledAddresses},
+			} {
+				manifestValue := addressesString(addresses.manifest)
+				storedValue := addressesString(addresses.stored)
+				if manifestValue != storedValue {
+					drifts = append(drifts, Drift{Field: entry.field + "." + addresses.field, Manifest: manifestValue, Stored: storedValue})
+				}
+			}
+		}
+	}
+	drifts = append(drifts, rewardConfigDrift(p.RewardManager, stored)...)
+	return drifts
+}
+
+// rewardConfigDrift compares the initial reward config of the reward manager
+// precompile, when enabled on both the manifest and the stored genesis
+func rewardConfigDrift(rewardManager *RewardManager, stored params.Precompiles) []Drift {
+	storedConfig, ok := stored[rewardmanager.ConfigKey].(*rewardmanager.Config)
+	if rewardManager == nil || !ok {
+		return nil
+	}
+	manifestAllowFeeRecipients, manifestRewardAddress := rewardManager.AllowFeeRecipients, notSetLabel
+	if rewardManager.RewardAddress != nil && *rewardManager.RewardAddress != (common.Address{}) {
+		manifestRewardAddress = rewardManager.RewardAddress.Hex()
+	}
+	storedAllowFeeRecipients, storedRewardAddress := false, notSetLabel
+	if storedConfig.InitialRewardConfig != nil {
+		storedAllowFeeRecipients = storedConfig.InitialRewardConfig.AllowFeeRecipients
+		if storedConfig.InitialRewardConfig.RewardAddress != (common.Address{}) {
+			storedRewardAddress = storedConfig.InitialRewardConfig.RewardAddress.Hex()
+		}
+	}
+	drifts := []Drift{}
+	if manifestAllowFeeRecipients != storedAllowFeeRecipients {
+		drifts = append(drifts, Drift{
+			Field:    "precompiles.rewardManager.allowFeeRecipients",
+			Manifest: fmt.Sprint(manifestAllowFeeRecipients),
+			Stored:   fmt.Sprint(storedAllowFeeRecipients),
+		})
+	}
+	if manifestRewardAddress != storedRewardAddress {
+		drifts = append(drifts, Drift{
+			Field:    "precompiles.rewardManager.rewardAddress",
+			Manifest: manifestRewardAddress,
+			Stored:   storedRewardAddress,
+		})
+	}
+	return drifts
+}
+
+func bigString(n *big.Int) string {
+	if n == nil {
+		return notSetLabel
+	}
+	return n.String()
+}
+
+func allocString(alloc core.GenesisAlloc, address common.Address) string {
+	account, ok := alloc[address]
+	if !ok || account.Balance == nil {
+		return notSetLabel
+	}
+	return account.Balance.String()
+}
+
+func addressesString(addresses []common.Address) string {
+	strs := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		strs = append(strs, address.Hex())
+	}
+	sort.Strings(strs)
+	return "[" + strings.Join(strs, ",") + "]"
+}
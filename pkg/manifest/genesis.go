@@ -0,0 +1,161 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package manifest
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	"github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errTxAllowListAdminsNotFunded = errors.New("none of the addresses in the transaction allow list precompile have any tokens allocated to them")
+
+func (a *AllowList) toAllowListConfig() allowlist.AllowListConfig {
+	return allowlist.AllowListConfig{
+		AdminAddresses:   a.AdminAddresses,
+		ManagerAddresses: a.ManagerAddresses,
+		EnabledAddresses: a.EnabledAddresses,
+	}
+}
+
+func genesisUpgrade() precompileconfig.Upgrade {
+	return precompileconfig.Upgrade{
+		BlockTimestamp: utils.NewUint64(0),
+	}
+}
+
+// GenesisPrecompiles builds the subnet evm genesis precompile configs.
+// Warp keeps any activation timestamp already present on [current], as
+// the wizard sets it to the creation time
+func (p *Precompiles) GenesisPrecompiles(current params.Precompiles) params.Precompiles {
+	precompiles := params.Precompiles{}
+	if p.Warp {
+		if warpConfig, ok := current[warp.ConfigKey]; ok {
+			precompiles[warp.ConfigKey] = warpConfig
+		} else {
+			precompiles[warp.ConfigKey] = &warp.Config{
+				Upgrade:         genesisUpgrade(),
+				QuorumNumerator: warp.WarpDefaultQuorumNumerator,
+			}
+		}
+	}
+	if p.NativeMinter != nil {
+		precompiles[nativeminter.ConfigKey] = &nativeminter.Config{
+			AllowListConfig: p.NativeMinter.toAllowListConfig(),
+			Upgrade:         genesisUpgrade(),
+		}
+	}
+	if p.ContractDeployerAllowList != nil {
+		precompiles[deployerallowlist.ConfigKey] = &deployerallowlist.Config{
+			AllowListConfig: p.ContractDeployerAllowList.toAllowListConfig(),
+			Upgrade:         genesisUpgrade(),
+		}
+	}
+	if p.TxAllowList != nil {
+		precompiles[txallowlist.ConfigKey] = &txallowlist.Config{
+			AllowListConfig: p.TxAllowList.toAllowListConfig(),
+			Upgrade:         genesisUpgrade(),
+		}
+	}
+	if p.FeeManager != nil {
+		precompiles[feemanager.ConfigKey] = &feemanager.Config{
+			AllowListConfig: p.FeeManager.toAllowListConfig(),
+			Upgrade:         genesisUpgrade(),
+		}
+	}
+	if p.RewardManager != nil {
+		initialRewardConfig := &rewardmanager.InitialRewardConfig{
+			AllowFeeRecipients: p.RewardManager.AllowFeeRecipients,
+		}
+		if p.RewardManager.RewardAddress != nil {
+			initialRewardConfig.RewardAddress = *p.RewardManager.RewardAddress
+		}
+		precompiles[rewardmanager.ConfigKey] = &rewardmanager.Config{
+			AllowListConfig:     p.RewardManager.toAllowListConfig(),
+			Upgrade:             genesisUpgrade(),
+			InitialRewardConfig: initialRewardConfig,
+		}
+	}
+	return precompiles
+}
+
+// GenesisAlloc returns the airdrop allocations of the manifest
+func (m *SubnetManifest) GenesisAlloc() (core.GenesisAlloc, error) {
+	alloc := core.GenesisAlloc{}
+	for _, airdrop := range m.Airdrops {
+		balance, err := airdrop.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := alloc[airdrop.Address]; ok && prev.Balance != nil {
+			balance = new(big.Int).Add(balance, prev.Balance)
+		}
+		alloc[airdrop.Address] = core.GenesisAccount{
+			Balance: balance,
+		}
+	}
+	return alloc, nil
+}
+
+// GetFeeConfig returns the manifest fee config, or the CLI default one
+// (the same used by subnet create --evm-defaults) if none is given
+func (m *SubnetManifest) GetFeeConfig() commontype.FeeConfig {
+	if m.FeeConfig != nil {
+		return *m.FeeConfig
+	}
+	return vm.StarterFeeConfig
+}
+
+// ApplyToEvmGenesis overrides the chain ID, fee config, allocations and
+// precompiles of [genesis] with the ones specified on the manifest.
+// [keepAlloc] addresses (eg. the teleporter deployer) keep their balance
+func (m *SubnetManifest) ApplyToEvmGenesis(genesis *core.Genesis, keepAlloc []common.Address) error {
+	if genesis.Config == nil {
+		return errors.New("invalid subnet evm genesis format: config is nil")
+	}
+	genesis.Config.ChainID = new(big.Int).SetUint64(m.ChainID)
+	genesis.Config.FeeConfig = m.GetFeeConfig()
+	genesis.GasLimit = genesis.Config.FeeConfig.GasLimit.Uint64()
+	alloc, err := m.GenesisAlloc()
+	if err != nil {
+		return err
+	}
+	for _, address := range keepAlloc {
+		if account, ok := genesis.Alloc[address]; ok {
+			if _, ok := alloc[address]; !ok {
+				alloc[address] = account
+			}
+		}
+	}
+	genesis.Alloc = alloc
+	genesis.Config.GenesisPrecompiles = m.Precompiles.GenesisPrecompiles(genesis.Config.GenesisPrecompiles)
+	if m.Precompiles.TxAllowList != nil && len(m.Precompiles.TxAllowList.AdminAddresses) > 0 {
+		if !anyFunded(m.Precompiles.TxAllowList.AdminAddresses, genesis.Alloc) {
+			return errTxAllowListAdminsNotFunded
+		}
+	}
+	return genesis.Verify()
+}
+
+func anyFunded(addresses []common.Address, alloc core.GenesisAlloc) bool {
+	for _, address := range addresses {
+		if account, ok := alloc[address]; ok && account.Balance != nil && account.Balance.Sign() > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/slices"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the only manifest schema version currently understood
+const Version = 1
+
+const (
+	LocalNetwork   = "local"
+	DevnetNetwork  = "devnet"
+	FujiNetwork    = "fuji"
+	MainnetNetwork = "mainnet"
+)
+
+var (
+	errNoName           = errors.New("manifest is missing the subnet name")
+	errNoChainID        = errors.New("manifest is missing the evm chain ID")
+	errNoToken          = errors.New("manifest is missing the token symbol")
+	errUnsupportedVM    = fmt.Errorf("only %s manifests are currently supported", models.SubnetEvm)
+	errNoVMVersion      = errors.New("manifest is missing the vm version")
+	errInvalidThreshold = errors.New("manifest threshold must be between 1 and the number of control keys")
+
+	errNoControlKeys                = errors.New("manifest control keys (or sameControlKey) are required to deploy to public networks")
+	errSameControlKeyAndControlKeys = errors.New("manifest sameControlKey and controlKeys are mutually exclusive")
+	errInvalidSubnetAuthKeys        = errors.New("manifest subnet auth keys must be given when threshold is lower than the number of control keys, and its number must be equal to the threshold")
+	errNoFeePayingKey               = errors.New("manifest key or ledger is required to deploy to fuji")
+	errKeyAndLedger                 = errors.New("manifest key and ledger are mutually exclusive")
+	errNoMainnetChainID             = errors.New("manifest mainnet chain ID is required to deploy to mainnet")
+)
+
+// VMManifest selects the VM and version of the subnet
+type VMManifest struct {
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// Airdrop is a genesis allocation. Balance is given in wei
+type Airdrop struct {
+	Address common.Address `json:"address"`
+	Balance string         `json:"balance"`
+}
+
+// AllowList is the set of addresses allowed to use or manage a precompile
+type AllowList struct {
+	AdminAddresses   []common.Address `json:"adminAddresses,omitempty"`
+	ManagerAddresses []common.Address `json:"managerAddresses,omitempty"`
+	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"`
+}
+
+// RewardManager configures the reward manager precompile. If neither
+// AllowFeeRecipients nor RewardAddress are set, fees are burnt
+type RewardManager struct {
+	AllowList
+	AllowFeeRecipients bool            `json:"allowFeeRecipients,omitempty"`
+	RewardAddress      *common.Address `json:"rewardAddress,omitempty"`
+}
+
+// Precompiles lists the precompiles enabled at genesis. A nil entry
+// means the precompile is disabled
+type Precompiles struct {
+	Warp                      bool           `json:"warp,omitempty"`
+	NativeMinter              *AllowList     `json:"nativeMinter,omitempty"`
+	ContractDeployerAllowList *AllowList     `json:"contractDeployerAllowList,omitempty"`
+	TxAllowList               *AllowList     `json:"txAllowList,omitempty"`
+	FeeManager                *AllowList     `json:"feeManager,omitempty"`
+	RewardManager             *RewardManager `json:"rewardManager,omitempty"`
+}
+
+// DeployManifest describes where and how the subnet is deployed.
+// Fuji deploys pay fees with the stored Key, or with the ledger if no key is
+// given. Mainnet deploys always use the ledger, and devnet deploys the ewoq key
+type DeployManifest struct {
+	Networks       []string `json:"networks,omitempty"`
+	Cluster        string   `json:"cluster,omitempty"`
+	Endpoint       string   `json:"endpoint,omitempty"`
+	Key            string   `json:"key,omitempty"`
+	Ledger         bool     `json:"ledger,omitempty"`
+	LedgerAddrs    []string `json:"ledgerAddrs,omitempty"`
+	SameControlKey bool     `json:"sameControlKey,omitempty"`
+	ControlKeys    []string `json:"controlKeys,omitempty"`
+	Threshold      uint32   `json:"threshold,omitempty"`
+	SubnetAuthKeys []string `json:"subnetAuthKeys,omitempty"`
+	MainnetChainID uint64   `json:"mainnetChainID,omitempty"`
+	AvagoVersion   string   `json:"avalanchegoVersion,omitempty"`
+}
+
+// UsesLedger returns true if the ledger was selected as fee paying key source
+func (d *DeployManifest) UsesLedger() bool {
+	return d.Ledger || len(d.LedgerAddrs) > 0
+}
+
+// HasPublicTargets returns true if the subnet is deployed to any network
+// other than the local one
+func (d *DeployManifest) HasPublicTargets() bool {
+	if d.Cluster != "" {
+		return true
+	}
+	for _, network := range d.Networks {
+		if network != LocalNetwork {
+			return true
+		}
+	}
+	return false
+}
+
+// SubnetManifest is the declarative, versioned description of a subnet
+// used by subnet apply. An omitted FeeConfig means the CLI default fee
+// config, and omitted Airdrops mean an empty genesis allocation
+type SubnetManifest struct {
+	Version     int                   `json:"version"`
+	Name        string                `json:"name"`
+	VM          VMManifest            `json:"vm"`
+	ChainID     uint64                `json:"chainID"`
+	Token       string                `json:"token"`
+	Teleporter  bool                  `json:"teleporter"`
+	FeeConfig   *commontype.FeeConfig `json:"feeConfig,omitempty"`
+	Airdrops    []Airdrop             `json:"airdrops,omitempty"`
+	Precompiles Precompiles           `json:"precompiles"`
+	Deploy      DeployManifest        `json:"deploy"`
+}
+
+// Load reads a yaml (or json) manifest from [path]. yaml is first
+// converted to json so that the json tags of the manifest types (and of
+// the embedded subnet-evm types) are the single source of truth for the schema
+func Load(path string) (*SubnetManifest, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bs)
+}
+
+// Parse decodes and validates a yaml (or json) manifest
+func Parse(bs []byte) (*SubnetManifest, error) {
+	var generic interface{}
+	if err := yaml.Unmarshal(bs, &generic); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	jsonBytes, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	m := &SubnetManifest{}
+	if err := json.Unmarshal(jsonBytes, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate checks the manifest is complete and self consistent
+func (m *SubnetManifest) Validate() error {
+	if m.Version != Version {
+		return fmt.Errorf("unsupported manifest version %d. expected %d", m.Version, Version)
+	}
+	if m.Name == "" {
+		return errNoName
+	}
+	if m.VM.Type == "" {
+		m.VM.Type = models.SubnetEvm
+	}
+	if m.VM.Type != models.SubnetEvm {
+		return errUnsupportedVM
+	}
+	if m.VM.Version == "" {
+		return errNoVMVersion
+	}
+	if !semver.IsValid(m.VM.Version) {
+		return fmt.Errorf("invalid vm version %q, should be semantic version (ex: v1.1.1)", m.VM.Version)
+	}
+	if m.ChainID == 0 {
+		return errNoChainID
+	}
+	if m.Token == "" {
+		return errNoToken
+	}
+	if m.FeeConfig != nil {
+		if err := m.FeeConfig.Verify(); err != nil {
+			return fmt.Errorf("invalid fee config: %w", err)
+		}
+	}
+	for _, airdrop := range m.Airdrops {
+		if _, err := airdrop.GetBalance(); err != nil {
+			return err
+		}
+	}
+	if m.Teleporter && !m.Precompiles.Warp {
+		// teleporter requires warp
+		m.Precompiles.Warp = true
+	}
+	mainnet := false
+	for _, network := range m.Deploy.Networks {
+		switch network {
+		case LocalNetwork, DevnetNetwork, FujiNetwork:
+		case MainnetNetwork:
+			mainnet = true
+		default:
+			return fmt.Errorf("invalid deploy network %q. expected one of %s, %s, %s, %s",
+				network, LocalNetwork, DevnetNetwork, FujiNetwork, MainnetNetwork)
+		}
+	}
+	if m.Deploy.HasPublicTargets() {
+		if err := m.Deploy.validateOwners(); err != nil {
+			return err
+		}
+	}
+	if slices.Contains(m.Deploy.Networks, FujiNetwork) && m.Deploy.Key == "" && !m.Deploy.UsesLedger() {
+		return errNoFeePayingKey
+	}
+	if m.Deploy.Key != "" && m.Deploy.UsesLedger() {
+		return errKeyAndLedger
+	}
+	if mainnet {
+		if m.Deploy.MainnetChainID == 0 {
+			return errNoMainnetChainID
+		}
+		if m.Deploy.MainnetChainID > math.MaxUint32 {
+			return fmt.Errorf("mainnet chain ID %d is out of range", m.Deploy.MainnetChainID)
+		}
+	}
+	return nil
+}
+
+// validateOwners checks that control keys, threshold and subnet auth keys are
+// fully specified, so that deploy does not need to prompt for them
+func (d *DeployManifest) validateOwners() error {
+	if d.SameControlKey {
+		if len(d.ControlKeys) > 0 {
+			return errSameControlKeyAndControlKeys
+		}
+		return nil
+	}
+	if len(d.ControlKeys) == 0 {
+		return errNoControlKeys
+	}
+	if d.Threshold == 0 || d.Threshold > uint32(len(d.ControlKeys)) {
+		return errInvalidThreshold
+	}
+	if d.Threshold < uint32(len(d.ControlKeys)) || len(d.SubnetAuthKeys) > 0 {
+		if len(d.SubnetAuthKeys) != int(d.Threshold) {
+			return errInvalidSubnetAuthKeys
+		}
+		for _, subnetAuthKey := range d.SubnetAuthKeys {
+			if !slices.Contains(d.ControlKeys, subnetAuthKey) {
+				return fmt.Errorf("subnet auth key %s does not belong to control keys", subnetAuthKey)
+			}
+		}
+	}
+	return nil
+}
+
+// GetBalance returns the airdrop balance in wei
+func (a Airdrop) GetBalance() (*big.Int, error) {
+	balance, ok := new(big.Int).SetString(a.Balance, 10)
+	if !ok || balance.Sign() < 0 {
+		return nil, fmt.Errorf("invalid airdrop balance %q for address %s", a.Balance, a.Address.Hex())
+	}
+	return balance, nil
+}
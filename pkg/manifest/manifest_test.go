@@ -0,0 +1,211 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package manifest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testAddress1 = common.HexToAddress("0x098B69E43b1720Bd12378225519d74e5F3aD0eA5")
+	testAddress2 = common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+)
+
+const testManifest = `
+version: 1
+name: mysubnet
+vm:
+  version: v0.6.1
+chainID: 1234
+token: TST
+teleporter: true
+airdrops:
+  - address: "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5"
+    balance: "1000000000000000000000000"
+precompiles:
+  nativeMinter:
+    adminAddresses:
+      - "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5"
+deploy:
+  networks:
+    - local
+    - fuji
+  key: mykey
+  controlKeys:
+    - P-fuji1qkzwejxqkr7jwcrdudxuxm8uxuruevvw8ehhwd
+  threshold: 1
+`
+
+func newTestGenesis() *core.Genesis {
+	conf := *params.SubnetEVMDefaultChainConfig
+	conf.MandatoryNetworkUpgrades = params.GetMandatoryNetworkUpgrades(constants.LocalNetworkID)
+	conf.ChainID = big.NewInt(1)
+	conf.GenesisPrecompiles = params.Precompiles{}
+	return &core.Genesis{
+		Config:   &conf,
+		GasLimit: conf.FeeConfig.GasLimit.Uint64(),
+		Alloc: core.GenesisAlloc{
+			testAddress2: {Balance: big.NewInt(100)},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte(testManifest))
+	require.NoError(err)
+	require.Equal("mysubnet", m.Name)
+	require.Equal(models.SubnetEvm, m.VM.Type)
+	require.Equal(uint64(1234), m.ChainID)
+	require.True(m.Precompiles.Warp)
+	require.NotNil(m.Precompiles.NativeMinter)
+	require.Equal([]common.Address{testAddress1}, m.Precompiles.NativeMinter.AdminAddresses)
+	require.Equal([]string{LocalNetwork, FujiNetwork}, m.Deploy.Networks)
+	require.Equal(uint32(1), m.Deploy.Threshold)
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"bad version", "version: 2\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\n"},
+		{"no name", "version: 1\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\n"},
+		{"custom vm", "version: 1\nname: a\nvm:\n  type: Custom\n  version: v0.6.1\nchainID: 1\ntoken: T\n"},
+		{"bad vm version", "version: 1\nname: a\nvm:\n  version: latest\nchainID: 1\ntoken: T\n"},
+		{"no chain id", "version: 1\nname: a\nvm:\n  version: v0.6.1\ntoken: T\n"},
+		{"no token", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\n"},
+		{"bad balance", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\nairdrops:\n  - address: \"0x098B69E43b1720Bd12378225519d74e5F3aD0eA5\"\n    balance: \"-1\"\n"},
+		{"bad network", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [testnet]\n"},
+		{"bad threshold", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  key: k\n  controlKeys: [a]\n  threshold: 2\n"},
+		{"no control keys", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  key: k\n"},
+		{"no fuji key", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  sameControlKey: true\n"},
+		{"key and ledger", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  key: k\n  ledger: true\n  sameControlKey: true\n"},
+		{"no subnet auth keys", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  key: k\n  controlKeys: [a, b]\n  threshold: 1\n"},
+		{"bad subnet auth keys", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [fuji]\n  key: k\n  controlKeys: [a, b]\n  threshold: 1\n  subnetAuthKeys: [c]\n"},
+		{"no mainnet chain id", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [mainnet]\n  ledger: true\n  sameControlKey: true\n"},
+		{"mainnet chain id out of range", "version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\ndeploy:\n  networks: [mainnet]\n  ledger: true\n  sameControlKey: true\n  mainnetChainID: 4294967296\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestApplyToEvmGenesis(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte(testManifest))
+	require.NoError(err)
+	genesis := newTestGenesis()
+	require.NoError(m.ApplyToEvmGenesis(genesis, []common.Address{testAddress2}))
+	require.Equal(big.NewInt(1234), genesis.Config.ChainID)
+	require.Len(genesis.Alloc, 2)
+	expected, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	require.Equal(expected, genesis.Alloc[testAddress1].Balance)
+	require.Equal(big.NewInt(100), genesis.Alloc[testAddress2].Balance)
+	require.Contains(genesis.Config.GenesisPrecompiles, warp.ConfigKey)
+	require.Contains(genesis.Config.GenesisPrecompiles, nativeminter.ConfigKey)
+	require.NotContains(genesis.Config.GenesisPrecompiles, txallowlist.ConfigKey)
+}
+
+func TestApplyToEvmGenesisDefaults(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte("version: 1\nname: a\nvm:\n  version: v0.6.1\nchainID: 1\ntoken: T\n"))
+	require.NoError(err)
+	genesis := newTestGenesis()
+	require.NoError(m.ApplyToEvmGenesis(genesis, nil))
+	// omitted airdrops mean an empty allocation, and omitted fee config the default one
+	require.Empty(genesis.Alloc)
+	require.Equal(vm.StarterFeeConfig, genesis.Config.FeeConfig)
+	require.Empty(genesis.Config.GenesisPrecompiles)
+}
+
+func TestApplyToEvmGenesisUnfundedTxAllowListAdmin(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte(testManifest))
+	require.NoError(err)
+	m.Precompiles.TxAllowList = &AllowList{AdminAddresses: []common.Address{testAddress2}}
+	genesis := newTestGenesis()
+	err = m.ApplyToEvmGenesis(genesis, nil)
+	require.ErrorIs(err, errTxAllowListAdminsNotFunded)
+}
+
+func TestComputeDrift(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte(testManifest))
+	require.NoError(err)
+	genesis := newTestGenesis()
+	require.NoError(m.ApplyToEvmGenesis(genesis, []common.Address{testAddress2}))
+	sc := models.Sidecar{
+		VM:              models.SubnetEvm,
+		VMVersion:       "v0.6.1",
+		TokenName:       "TST",
+		TeleporterReady: true,
+	}
+	require.Empty(m.ComputeDrift(sc, *genesis, []common.Address{testAddress2}))
+
+	// the teleporter key allocation is reported unless ignored
+	require.Len(m.ComputeDrift(sc, *genesis, nil), 1)
+
+	// removing sections from the manifest is reported too
+	feeConfig := vm.StarterFeeConfig
+	feeConfig.MinBaseFee = big.NewInt(1)
+	genesis.Config.FeeConfig = feeConfig
+	drifts := m.ComputeDrift(sc, *genesis, []common.Address{testAddress2})
+	require.Equal([]Drift{
+		{Field: "feeConfig.minBaseFee", Manifest: vm.StarterFeeConfig.MinBaseFee.String(), Stored: "1"},
+	}, drifts)
+	genesis.Config.FeeConfig = vm.StarterFeeConfig
+	airdrops := m.Airdrops
+	m.Airdrops = nil
+	drifts = m.ComputeDrift(sc, *genesis, []common.Address{testAddress2})
+	require.Equal([]Drift{
+		{Field: "airdrops." + testAddress1.Hex(), Manifest: notSetLabel, Stored: "1000000000000000000000000"},
+	}, drifts)
+	m.Airdrops = airdrops
+
+	m.Token = "OTHER"
+	m.Precompiles.NativeMinter.AdminAddresses = []common.Address{testAddress2}
+	m.Precompiles.TxAllowList = &AllowList{}
+	drifts = m.ComputeDrift(sc, *genesis, []common.Address{testAddress2})
+	require.Equal([]Drift{
+		{Field: "token", Manifest: "OTHER", Stored: "TST"},
+		{Field: "precompiles.nativeMinter.adminAddresses", Manifest: "[" + testAddress2.Hex() + "]", Stored: "[" + testAddress1.Hex() + "]"},
+		{Field: "precompiles.txAllowList", Manifest: "enabled", Stored: "disabled"},
+	}, drifts)
+}
+
+func TestComputeDriftRewardManager(t *testing.T) {
+	require := require.New(t)
+	m, err := Parse([]byte(testManifest))
+	require.NoError(err)
+	m.Precompiles.RewardManager = &RewardManager{AllowFeeRecipients: true}
+	genesis := newTestGenesis()
+	require.NoError(m.ApplyToEvmGenesis(genesis, nil))
+	sc := models.Sidecar{
+		VM:              models.SubnetEvm,
+		VMVersion:       "v0.6.1",
+		TokenName:       "TST",
+		TeleporterReady: true,
+	}
+	require.Empty(m.ComputeDrift(sc, *genesis, nil))
+	m.Precompiles.RewardManager = &RewardManager{RewardAddress: &testAddress2}
+	require.Equal([]Drift{
+		{Field: "precompiles.rewardManager.allowFeeRecipients", Manifest: "false", Stored: "true"},
+		{Field: "precompiles.rewardManager.rewardAddress", Manifest: testAddress2.Hex(), Stored: notSetLabel},
+	}, m.ComputeDrift(sc, *genesis, nil))
+}
@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -32,6 +33,7 @@ type Host struct {
 	NodeID            string
 	IP                string
 	SSHUser           string
+	SSHPort           uint
 	SSHPrivateKeyPath string
 	SSHCommonArgs     string
 	Connection        *goph.Client
@@ -39,7 +41,7 @@ type Host struct {
 
 func NewHostConnection(h *Host, port uint) (*goph.Client, error) {
 	if port == 0 {
-		port = constants.SSHTCPPort
+		port = h.GetSSHPort()
 	}
 	var (
 		auth goph.Auth
@@ -55,13 +57,12 @@ func NewHostConnection(h *Host, port uint) (*goph.Client, error) {
 		return nil, err
 	}
 	cl, err := goph.NewConn(&goph.Config{
-		User:    h.SSHUser,
-		Addr:    h.IP,
-		Port:    port,
-		Auth:    auth,
-		Timeout: sshConnectionTimeout,
-		// #nosec G106
-		Callback: ssh.InsecureIgnoreHostKey(), // we don't verify host key ( similar to ansible)
+		User:     h.SSHUser,
+		Addr:     h.IP,
+		Port:     port,
+		Auth:     auth,
+		Timeout:  sshConnectionTimeout,
+		Callback: hostKeyCallback(),
 	})
 	if err != nil {
 		return nil, err
@@ -75,10 +76,18 @@ func (h *Host) GetCloudID() string {
 	return cloudID
 }
 
+// GetSSHPort returns the SSH port of the host, defaulting to the standard one
+func (h *Host) GetSSHPort() uint {
+	if h.SSHPort == 0 {
+		return constants.SSHTCPPort
+	}
+	return h.SSHPort
+}
+
 // Connect starts a new SSH connection with the provided private key.
 func (h *Host) Connect(port uint) error {
 	if port == 0 {
-		port = constants.SSHTCPPort
+		port = h.GetSSHPort()
 	}
 	if h.Connection != nil {
 		return nil
@@ -86,6 +95,9 @@ func (h *Host) Connect(port uint) error {
 	var err error
 	for i := 0; h.Connection == nil && i < sshConnectionRetries; i++ {
 		h.Connection, err = NewHostConnection(h, port)
+		if errors.Is(err, ErrHostKeyMismatch) {
+			break
+		}
 	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to host %s: %w", h.IP, err)
@@ -203,6 +215,46 @@ func (h *Host) Command(script string, env []string, timeout time.Duration) ([]by
 	return cmd.CombinedOutput()
 }
 
+// CommandOutput holds the output of a command run on a host
+type CommandOutput struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// CommandContext executes a shell command on the host until it finishes or [ctx] is done,
+// returning its stdout, stderr and exit code
+func (h *Host) CommandContext(ctx context.Context, script string, env []string) (CommandOutput, error) {
+	if !h.Connected() {
+		if err := h.Connect(0); err != nil {
+			return CommandOutput{}, err
+		}
+	}
+	cmd, err := h.Connection.CommandContext(ctx, "", script)
+	if err != nil {
+		return CommandOutput{}, err
+	}
+	if env != nil {
+		cmd.Env = env
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			// the session may still be writing its output
+			return CommandOutput{ExitCode: -1}, err
+		}
+		output := CommandOutput{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), ExitCode: -1}
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			output.ExitCode = exitErr.ExitStatus()
+		}
+		return output, err
+	}
+	return CommandOutput{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}, nil
+}
+
 // Forward forwards the TCP connection to a remote address.
 func (h *Host) Forward(httpRequest string, timeout time.Duration) ([]byte, error) {
 	if !h.Connected() {
@@ -268,13 +320,18 @@ func (h *Host) UntimedForward(httpRequest string) ([]byte, error) {
 }
 
 func (h *Host) GetAnsibleInventoryRecord() string {
-	return strings.Join([]string{
+	fields := []string{
 		h.NodeID,
 		fmt.Sprintf("ansible_host=%s", h.IP),
 		fmt.Sprintf("ansible_user=%s", h.SSHUser),
+	}
+	if h.SSHPort != 0 {
+		fields = append(fields, fmt.Sprintf("ansible_port=%d", h.SSHPort))
+	}
+	return strings.Join(append(fields,
 		fmt.Sprintf("ansible_ssh_private_key_file=%s", h.SSHPrivateKeyPath),
 		fmt.Sprintf("ansible_ssh_common_args='%s'", h.SSHCommonArgs),
-	}, " ")
+	), " ")
 }
 
 func HostCloudIDToAnsibleID(cloudService string, hostCloudID string) (string, error) {
@@ -285,6 +342,8 @@ func HostCloudIDToAnsibleID(cloudService string, hostCloudID string) (string, er
 		return fmt.Sprintf("%s_%s", constants.AWSNodeAnsiblePrefix, hostCloudID), nil
 	case constants.E2EDocker:
 		return fmt.Sprintf("%s_%s", constants.E2EDocker, hostCloudID), nil
+	case constants.ExistingHostsService:
+		return fmt.Sprintf("%s_%s", constants.ExistingHostAnsiblePrefix, hostCloudID), nil
 	}
 	return "", fmt.Errorf("unknown cloud service %s", cloudService)
 }
@@ -302,6 +361,9 @@ func HostAnsibleIDToCloudID(hostAnsibleID string) (string, string, error) {
 	case strings.HasPrefix(hostAnsibleID, constants.E2EDocker):
 		cloudService = constants.E2EDocker
 		cloudIDPrefix = strings.TrimPrefix(hostAnsibleID, constants.E2EDocker+"_")
+	case strings.HasPrefix(hostAnsibleID, constants.ExistingHostAnsiblePrefix):
+		cloudService = constants.ExistingHostsService
+		cloudIDPrefix = strings.TrimPrefix(hostAnsibleID, constants.ExistingHostAnsiblePrefix+"_")
 	default:
 		return "", "", fmt.Errorf("unknown cloud service prefix in %s", hostAnsibleID)
 	}
@@ -311,7 +373,7 @@ func HostAnsibleIDToCloudID(hostAnsibleID string) (string, string, error) {
 // WaitForSSHPort waits for the SSH port to become available on the host.
 func (h *Host) WaitForSSHPort(port uint, timeout time.Duration) error {
 	if port == 0 {
-		port = constants.SSHTCPPort
+		port = h.GetSSHPort()
 	}
 	start := time.Now()
 	deadline := start.Add(timeout)
@@ -332,7 +394,7 @@ func (h *Host) WaitForSSHShell(timeout time.Duration) error {
 		return fmt.Errorf("host IP is empty")
 	}
 	start := time.Now()
-	if err := h.WaitForSSHPort(constants.SSHTCPPort, timeout); err != nil {
+	if err := h.WaitForSSHPort(h.GetSSHPort(), timeout); err != nil {
 		return err
 	}
 
@@ -0,0 +1,154 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/exp/slices"
+)
+
+var ErrHostKeyMismatch = errors.New("host key mismatch")
+
+var (
+	// CLI managed known_hosts file where the host keys of the nodes are pinned
+	knownHostsPath string
+	// serializes the read-check-append cycles of parallel connections
+	knownHostsLock sync.Mutex
+)
+
+// SetKnownHostsPath sets the known_hosts file used to pin the node host keys on first
+// connection, and to verify them on the following ones. If not set, host keys are not verified
+func SetKnownHostsPath(path string) {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+	knownHostsPath = path
+}
+
+// GetKnownHostsPath returns the known_hosts file set with SetKnownHostsPath
+func GetKnownHostsPath() string {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+	return knownHostsPath
+}
+
+// hostKeyCallback returns the callback used to verify the host keys on ssh connections:
+// trust on first use, and fail on any change afterwards
+func hostKeyCallback() ssh.HostKeyCallback {
+	path := GetKnownHostsPath()
+	if path == "" {
+		return ssh.InsecureIgnoreHostKey() // #nosec G106
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		return checkKnownHost(path, hostname, remote, key)
+	}
+}
+
+// checkKnownHost verifies [key] against the one pinned for [hostname] at [path],
+// pinning it if [hostname] is not yet known
+func checkKnownHost(path, hostname string, remote net.Addr, key ssh.PublicKey) error {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+	if err := ensureKnownHostsFile(path); err != nil {
+		return err
+	}
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return fmt.Errorf("failed reading known hosts file %s: %w", path, err)
+	}
+	err = callback(hostname, remote, key)
+	var keyErr *knownhosts.KeyError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &keyErr) && len(keyErr.Want) == 0:
+		// first connection to the host: pin its key
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	case errors.As(err, &keyErr):
+		return fmt.Errorf(
+			"%w for %s: got %s key %s, but %s was pinned at %s:%d. "+
+				"The host may have been re-provisioned, or the connection intercepted. "+
+				"If the change is legitimate, run 'avalanche node ssh <node> --reset-host-key'",
+			ErrHostKeyMismatch,
+			hostname,
+			key.Type(),
+			ssh.FingerprintSHA256(key),
+			ssh.FingerprintSHA256(keyErr.Want[0].Key),
+			keyErr.Want[0].Filename,
+			keyErr.Want[0].Line,
+		)
+	default:
+		return err
+	}
+}
+
+func ensureKnownHostsFile(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, constants.WriteReadUserOnlyPerms)
+}
+
+// ResetHostKey removes the pinned host key of [h], so the next connection pins the
+// current one. To be used when the host was legitimately re-provisioned
+func (h *Host) ResetHostKey() (bool, error) {
+	knownHostsLock.Lock()
+	defer knownHostsLock.Unlock()
+	if knownHostsPath == "" {
+		return false, nil
+	}
+	return removeKnownHost(knownHostsPath, net.JoinHostPort(h.IP, fmt.Sprint(h.GetSSHPort())))
+}
+
+// removeKnownHost removes from [path] the lines for [address]. Returns true if any was found
+func removeKnownHost(path, address string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	normalized := knownhosts.Normalize(address)
+	keptLines := []string{}
+	removed := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) > 0 && slices.Contains(strings.Split(fields[0], ","), normalized) {
+			removed = true
+			continue
+		}
+		keptLines = append(keptLines, line+"\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	if !removed {
+		return false, nil
+	}
+	return true, os.WriteFile(path, []byte(strings.Join(keptLines, "")), constants.WriteReadUserOnlyPerms)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestHostKey(require *require.Assertions) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(err)
+	return key
+}
+
+func TestKnownHostsPinning(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "nodes", "known_hosts")
+	SetKnownHostsPath(path)
+	defer SetKnownHostsPath("")
+
+	key := newTestHostKey(require)
+	otherKey := newTestHostKey(require)
+	remote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+	otherRemote := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2222}
+	callback := hostKeyCallback()
+
+	// first use pins the key
+	require.NoError(callback("10.0.0.1:22", remote, key))
+	require.NoError(callback("10.0.0.1:22", remote, key))
+	require.ErrorIs(callback("10.0.0.1:22", remote, otherKey), ErrHostKeyMismatch)
+	// hosts are pinned by address and port
+	require.NoError(callback("10.0.0.1:2222", otherRemote, otherKey))
+	require.ErrorIs(callback("10.0.0.1:2222", otherRemote, key), ErrHostKeyMismatch)
+
+	removed, err := (&Host{IP: "10.0.0.1"}).ResetHostKey()
+	require.NoError(err)
+	require.True(removed)
+	removed, err = (&Host{IP: "10.0.0.1"}).ResetHostKey()
+	require.NoError(err)
+	require.False(removed)
+	// after a reset, the new key is pinned
+	require.NoError(callback("10.0.0.1:22", remote, otherKey))
+	require.ErrorIs(callback("10.0.0.1:22", remote, key), ErrHostKeyMismatch)
+	// other ports are untouched
+	require.ErrorIs(callback("10.0.0.1:2222", otherRemote, key), ErrHostKeyMismatch)
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0o600), info.Mode().Perm())
+}
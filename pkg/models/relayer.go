@@ -0,0 +1,87 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// RelayerRoute is a source to destination path relayed by a relayer instance. Both ends
+// are subnet names, or c-chain
+type RelayerRoute struct {
+	Source      string
+	Destination string
+}
+
+// Relayer is a named AWM relayer instance, relaying a set of routes of a network
+type Relayer struct {
+	Name    string
+	Network Network
+	// KeyName is the stored key that pays for the deliveries on the destinations
+	KeyName string
+	// RewardAddress receives the teleporter fees of the delivered messages
+	RewardAddress       string
+	ProcessMissedBlocks bool
+	StorageDir          string
+	// CloudNodeID is set when the relayer runs as a service on a node of the network cluster
+	CloudNodeID string
+	Routes      []RelayerRoute
+	// MetricsPort is where the relayer serves its prometheus metrics
+	MetricsPort uint16
+	// MinBalance is the relayer key balance, in wei, under which the relayer is reported as
+	// running dry on a destination
+	MinBalance *big.Int
+	// FundingKeyName is the stored key that tops up the relayer key up to TopUpBalance, in wei,
+	// on the destinations where it runs dry. Top ups are disabled if empty
+	FundingKeyName string
+	TopUpBalance   *big.Int
+}
+
+// IsCloudService returns true if the relayer runs as a service on a cluster node
+func (r *Relayer) IsCloudService() bool {
+	return r.CloudNodeID != ""
+}
+
+// ServiceName returns the name of the service that runs the relayer on a cluster node
+func (r *Relayer) ServiceName() string {
+	return constants.AWMRelayerInstallDir + "-" + r.Name
+}
+
+// HasTopUp returns true if the relayer key is topped up from a funding key
+func (r *Relayer) HasTopUp() bool {
+	return r.FundingKeyName != ""
+}
+
+// HasRoute returns true if the relayer relays from [source] to [destination]
+func (r *Relayer) HasRoute(source string, destination string) bool {
+	for _, route := range r.Routes {
+		if route.Source == source && route.Destination == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRoute makes the relayer relay from [source] to [destination]. Returns false if it
+// already did
+func (r *Relayer) AddRoute(source string, destination string) bool {
+	if r.HasRoute(source, destination) {
+		return false
+	}
+	r.Routes = append(r.Routes, RelayerRoute{Source: source, Destination: destination})
+	return true
+}
+
+// RemoveRoute stops the relayer from relaying from [source] to [destination]. Returns false
+// if it did not
+func (r *Relayer) RemoveRoute(source string, destination string) bool {
+	for i, route := range r.Routes {
+		if route.Source == source && route.Destination == destination {
+			r.Routes = append(r.Routes[:i], r.Routes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayerRoutes(t *testing.T) {
+	require := require.New(t)
+	r := Relayer{Name: "test"}
+
+	require.True(r.AddRoute("subnetA", "subnetB"))
+	require.True(r.AddRoute("subnetB", "subnetA"))
+	require.False(r.AddRoute("subnetA", "subnetB"))
+	require.Len(r.Routes, 2)
+	require.True(r.HasRoute("subnetA", "subnetB"))
+	require.True(r.HasRoute("subnetB", "subnetA"))
+	require.False(r.HasRoute("subnetA", "c-chain"))
+
+	require.True(r.RemoveRoute("subnetA", "subnetB"))
+	require.False(r.RemoveRoute("subnetA", "subnetB"))
+	require.False(r.HasRoute("subnetA", "subnetB"))
+	require.Equal([]RelayerRoute{{Source: "subnetB", Destination: "subnetA"}}, r.Routes)
+}
+
+func TestRelayerServiceName(t *testing.T) {
+	require := require.New(t)
+	r := Relayer{Name: "test"}
+	require.False(r.IsCloudService())
+	require.Equal("awm-relayer-test", r.ServiceName())
+	r.CloudNodeID = "i-123"
+	require.True(r.IsCloudService())
+}
+
+func TestRelayerHasTopUp(t *testing.T) {
+	require := require.New(t)
+	r := Relayer{Name: "test"}
+	require.False(r.HasTopUp())
+	r.FundingKeyName = "funding"
+	require.True(r.HasTopUp())
+}
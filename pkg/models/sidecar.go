@@ -16,6 +16,14 @@ type NetworkData struct {
 	TeleporterRegistryAddress   string
 }
 
+// TeleporterMessenger is a teleporter messenger registered on a teleporter registry
+type TeleporterMessenger struct {
+	// Version is the teleporter release of the messenger
+	Version         string
+	RegistryVersion uint64
+	Address         string
+}
+
 type PermissionlessValidators struct {
 	TxID ids.ID
 }
@@ -49,6 +57,9 @@ type Sidecar struct {
 	TeleporterReady   bool
 	TeleporterKey     string
 	TeleporterVersion string
+	// TeleporterMessengers holds, by network, the messengers registered on the teleporter
+	// registry by the CLI, the last one being the current one
+	TeleporterMessengers map[string][]TeleporterMessenger
 	// SubnetEVM based VM's only
 	SubnetEVMMainnetChainID uint
 }
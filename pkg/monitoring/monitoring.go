@@ -6,9 +6,11 @@ package monitoring
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -88,6 +90,34 @@ func WritePrometheusConfig(filePath string, avalancheGoPorts []string, machinePo
 	return os.WriteFile(filePath, []byte(config), constants.WriteReadReadPerms)
 }
 
+// prometheusTargetGroup is a group of targets of a prometheus file based service discovery
+type prometheusTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// WriteRelayerPrometheusTargets writes the targets prometheus scrapes for the awm-relayer job.
+// [relayerTargets] maps each relayer name to the host:port it serves its metrics at
+func WriteRelayerPrometheusTargets(filePath string, relayerTargets map[string]string) error {
+	relayerNames := make([]string, 0, len(relayerTargets))
+	for relayerName := range relayerTargets {
+		relayerNames = append(relayerNames, relayerName)
+	}
+	sort.Strings(relayerNames)
+	targetGroups := []prometheusTargetGroup{}
+	for _, relayerName := range relayerNames {
+		targetGroups = append(targetGroups, prometheusTargetGroup{
+			Targets: []string{relayerTargets[relayerName]},
+			Labels:  map[string]string{"relayer": relayerName},
+		})
+	}
+	bs, err := json.MarshalIndent(targetGroups, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, bs, constants.WriteReadReadPerms)
+}
+
 func WriteLokiConfig(filePath string, port string) error {
 	config, err := GenerateConfig("configs/loki.yml", "Loki Config", configInputs{
 		Port: port,
@@ -79,6 +79,7 @@ type Prompter interface {
 	CaptureList(promptStr string, options []string) (string, error)
 	CaptureListWithSize(promptStr string, options []string, size int) (string, error)
 	CaptureString(promptStr string) (string, error)
+	CapturePassword(promptStr string) (string, error)
 	CaptureValidatedString(promptStr string, validator func(string) error) (string, error)
 	CaptureURL(promptStr string, validateConnection bool) (string, error)
 	CaptureRepoBranch(promptStr string, repo string) (string, error)
@@ -620,6 +621,21 @@ func (*realPrompter) CaptureString(promptStr string) (string, error) {
 	return str, nil
 }
 
+func (*realPrompter) CapturePassword(promptStr string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:    promptStr,
+		Mask:     '*',
+		Validate: validateNonEmpty,
+	}
+
+	str, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return str, nil
+}
+
 func (*realPrompter) CaptureValidatedString(promptStr string, validator func(string) error) (string, error) {
 	prompt := promptui.Prompt{
 		Label:    promptStr,
@@ -822,6 +838,15 @@ func GetFujiKeyOrLedger(prompt Prompter, goal string, keyDir string) (bool, stri
 	return false, keyName, nil
 }
 
+// GetKeyName asks the user to choose one of the stored keys at [keyDir]
+func GetKeyName(prompt Prompter, goal string, keyDir string) (string, error) {
+	keyName, err := captureKeyName(prompt, goal, keyDir)
+	if errors.Is(err, errNoKeys) {
+		ux.Logger.PrintToUser("No private keys have been found. Create a new one with `avalanche key create`")
+	}
+	return keyName, err
+}
+
 func captureKeyName(prompt Prompter, goal string, keyDir string) (string, error) {
 	files, err := os.ReadDir(keyDir)
 	if err != nil {
@@ -0,0 +1,285 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/chelnak/ysmrr"
+)
+
+// Task is what an Executor runs on each host. Tasks should return when [ctx] is done;
+// the ones that don't are abandoned, and keep running in the background
+type Task func(ctx context.Context, host *models.Host) (models.CommandOutput, error)
+
+// FuncTask returns a Task that runs [f], which has no output
+func FuncTask(f func(host *models.Host) error) Task {
+	return func(_ context.Context, host *models.Host) (models.CommandOutput, error) {
+		return models.CommandOutput{}, f(host)
+	}
+}
+
+// CommandTask returns a Task that runs the shell command [command] on the host
+func CommandTask(command string) Task {
+	return func(ctx context.Context, host *models.Host) (models.CommandOutput, error) {
+		return host.CommandContext(ctx, command, nil)
+	}
+}
+
+// Executor runs a task on many hosts at the same time
+type Executor struct {
+	// Concurrency is the maximum number of hosts the task runs on at the same time.
+	// Zero or less means no limit
+	Concurrency int
+	// Timeout bounds each run of the task on a host. Zero means no timeout
+	Timeout time.Duration
+	// Retries is the number of times the task is run again on a host after failing
+	Retries int
+	// RetryDelay is the time to wait before running the task again on a host
+	RetryDelay time.Duration
+	// Description is shown as live progress for each host. No progress is shown if empty
+	Description string
+}
+
+// NewExecutor creates an Executor with the default concurrency limit, showing
+// [description] as the progress of each host
+func NewExecutor(description string) *Executor {
+	return &Executor{
+		Concurrency: constants.SSHDefaultConcurrency,
+		RetryDelay:  constants.SSHSleepBetweenChecks,
+		Description: description,
+	}
+}
+
+// Run runs [task] on [hosts], returning the result of each of them. Hosts are not waited
+// for once [ctx] is done or the user interrupts the CLI, and get the context error as result
+func (e *Executor) Run(ctx context.Context, hosts []*models.Host, task Task) *HostResults {
+	results := &HostResults{results: make([]HostResult, len(hosts))}
+	if len(hosts) == 0 {
+		return results
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var spinSession *ux.UserSpinner
+	spinners := make([]*ysmrr.Spinner, len(hosts))
+	if e.Description != "" {
+		spinSession = ux.NewUserSpinner()
+		defer spinSession.Stop()
+		for i, host := range hosts {
+			spinners[i] = spinSession.SpinToUser(utils.ScriptLog(host.NodeID, e.Description))
+		}
+	}
+	concurrency := e.Concurrency
+	if concurrency <= 0 || concurrency > len(hosts) {
+		concurrency = len(hosts)
+	}
+	slots := make(chan struct{}, concurrency)
+	setResult := func(i int, result HostResult) {
+		results.set(i, result)
+		if spinners[i] == nil {
+			return
+		}
+		if result.Err != nil {
+			ux.SpinFailWithError(spinners[i], "", result.Err)
+		} else {
+			ux.SpinComplete(spinners[i])
+		}
+	}
+	wg := sync.WaitGroup{}
+	// hosts are started in order, so that running one host at a time follows the host order
+	for i, host := range hosts {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			setResult(i, HostResult{NodeID: host.NodeID, CloudID: host.GetCloudID(), ExitCode: -1, Err: ctx.Err()})
+			continue
+		}
+		wg.Add(1)
+		go func(i int, host *models.Host) {
+			defer wg.Done()
+			result := e.runOnHost(ctx, host, task)
+			<-slots
+			setResult(i, result)
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// runOnHost runs [task] on [host], retrying it on failure
+func (e *Executor) runOnHost(ctx context.Context, host *models.Host, task Task) HostResult {
+	result := HostResult{NodeID: host.NodeID, CloudID: host.GetCloudID()}
+	startTime := time.Now()
+	for {
+		result.Attempts++
+		output, err := e.runOnce(ctx, host, task)
+		result.Stdout = string(output.Stdout)
+		result.Stderr = string(output.Stderr)
+		result.ExitCode = output.ExitCode
+		result.Err = err
+		if err == nil || result.Attempts > e.Retries || ctx.Err() != nil {
+			break
+		}
+		ux.Logger.Info("[%s] attempt %d failed, retrying: %s", host.NodeID, result.Attempts, err)
+		select {
+		case <-time.After(e.RetryDelay):
+		case <-ctx.Done():
+		}
+	}
+	result.Duration = time.Since(startTime)
+	return result
+}
+
+// runOnce runs [task] on [host] once, abandoning it on timeout or cancellation
+func (e *Executor) runOnce(ctx context.Context, host *models.Host, task Task) (models.CommandOutput, error) {
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+	type taskResult struct {
+		output models.CommandOutput
+		err    error
+	}
+	done := make(chan taskResult, 1)
+	go func() {
+		output, err := task(ctx, host)
+		done <- taskResult{output: output, err: err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil && r.output.ExitCode == 0 {
+			r.output.ExitCode = -1
+		}
+		return r.output, r.err
+	case <-ctx.Done():
+		err := ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timeout after %s: %w", e.Timeout, err)
+		}
+		return models.CommandOutput{ExitCode: -1}, err
+	}
+}
+
+// HostResult is the outcome of running a task on a host
+type HostResult struct {
+	NodeID   string
+	CloudID  string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+	Attempts int
+	Err      error
+}
+
+// MarshalJSON encodes the result with its error as a string
+func (r HostResult) MarshalJSON() ([]byte, error) {
+	errStr := ""
+	if r.Err != nil {
+		errStr = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		NodeID   string `json:"nodeID"`
+		CloudID  string `json:"cloudID"`
+		Stdout   string `json:"stdout"`
+		Stderr   string `json:"stderr"`
+		ExitCode int    `json:"exitCode"`
+		Duration string `json:"duration"`
+		Attempts int    `json:"attempts"`
+		Error    string `json:"error,omitempty"`
+	}{
+		NodeID:   r.NodeID,
+		CloudID:  r.CloudID,
+		Stdout:   r.Stdout,
+		Stderr:   r.Stderr,
+		ExitCode: r.ExitCode,
+		Duration: r.Duration.String(),
+		Attempts: r.Attempts,
+		Error:    errStr,
+	})
+}
+
+// HostResults holds the results of an Executor run, in the order of its hosts
+type HostResults struct {
+	results []HostResult
+	lock    sync.Mutex
+}
+
+func (hr *HostResults) set(i int, result HostResult) {
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+	hr.results[i] = result
+}
+
+// GetResults returns the result of each host
+func (hr *HostResults) GetResults() []HostResult {
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+	return append([]HostResult{}, hr.results...)
+}
+
+// GetResult returns the result of the host with [cloudID]
+func (hr *HostResults) GetResult(cloudID string) (HostResult, bool) {
+	for _, result := range hr.GetResults() {
+		if result.CloudID == cloudID {
+			return result, true
+		}
+	}
+	return HostResult{}, false
+}
+
+// GetErrorHostMap returns the errors of the failed hosts, by cloud ID
+func (hr *HostResults) GetErrorHostMap() map[string]error {
+	hostErrors := map[string]error{}
+	for _, result := range hr.GetResults() {
+		if result.Err != nil {
+			hostErrors[result.CloudID] = result.Err
+		}
+	}
+	return hostErrors
+}
+
+// GetErrorNodeIDMap returns the errors of the failed hosts, by ansible node ID
+func (hr *HostResults) GetErrorNodeIDMap() map[string]error {
+	hostErrors := map[string]error{}
+	for _, result := range hr.GetResults() {
+		if result.Err != nil {
+			hostErrors[result.NodeID] = result.Err
+		}
+	}
+	return hostErrors
+}
+
+// GetErrorHosts returns the cloud IDs of the failed hosts
+func (hr *HostResults) GetErrorHosts() []string {
+	hosts := []string{}
+	for _, result := range hr.GetResults() {
+		if result.Err != nil {
+			hosts = append(hosts, result.CloudID)
+		}
+	}
+	return hosts
+}
+
+// HasErrors returns true if the task failed on any host
+func (hr *HostResults) HasErrors() bool {
+	return len(hr.GetErrorHosts()) > 0
+}
+
+// JSON returns the results as an indented JSON list
+func (hr *HostResults) JSON() ([]byte, error) {
+	return json.MarshalIndent(hr.GetResults(), "", "  ")
+}
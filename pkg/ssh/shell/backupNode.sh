@@ -0,0 +1,13 @@
+#!/usr/bin/env bash
+set -e
+#name:TASK [archive node staking files and configs]
+cd /home/ubuntu/.avalanchego
+files="staking"
+if [ -d configs ]; then
+  files="$files configs"
+fi
+{{if .IncludeDB}}
+#name:TASK [archive node database]
+files="$files db"
+{{end}}
+tar -czf "{{ .BackupPath }}" $files
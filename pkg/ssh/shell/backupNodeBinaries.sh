@@ -0,0 +1,6 @@
+#!/usr/bin/env bash
+set -e
+#name:TASK [backup node binaries]
+{{range .NodeBinaryPaths}}
+cp -f "{{ . }}" "{{ . }}.bak"
+{{end}}
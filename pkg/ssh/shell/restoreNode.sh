@@ -0,0 +1,10 @@
+#!/usr/bin/env bash
+set -e
+#name:TASK [restore node staking files, configs and database]
+mkdir -p /home/ubuntu/.avalanchego
+cd /home/ubuntu/.avalanchego
+if tar -tzf "{{ .BackupPath }}" | grep -q '^db/'; then
+  rm -rf db
+fi
+tar -xzf "{{ .BackupPath }}"
+rm -f "{{ .BackupPath }}"
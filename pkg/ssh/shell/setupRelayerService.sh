@@ -1,5 +1,5 @@
 #!/usr/bin/env bash
 set -e
-~/bin/avalanche teleporter relayer prepareService
-sudo cp ~/.avalanche-cli/services/awm-relayer/awm-relayer.service /etc/systemd/system/awm-relayer.service
+~/bin/avalanche teleporter relayer prepareService{{ if .RelayerName }} --relayer {{ .RelayerName }}{{ end }}
+sudo cp ~/.avalanche-cli/services/{{ .RelayerServiceName }}/{{ .RelayerServiceName }}.service /etc/systemd/system/{{ .RelayerServiceName }}.service
 sudo systemctl daemon-reload
@@ -1,4 +1,4 @@
 #!/usr/bin/env bash
 set -e
-sudo systemctl enable awm-relayer
-sudo systemctl start awm-relayer
+sudo systemctl enable {{ .RelayerServiceName }}
+sudo systemctl start {{ .RelayerServiceName }}
@@ -1,3 +1,3 @@
 #!/usr/bin/env bash
 set -e
-sudo systemctl stop awm-relayer
+sudo systemctl stop {{ .RelayerServiceName }}
@@ -1,5 +1,7 @@
 #!/usr/bin/env bash
 #name:TASK [sync new prometheus config]
 sudo cp -f /tmp/prometheus.yml /etc/prometheus/prometheus.yml
+#name:TASK [init relayer targets]
+[ -f /etc/prometheus/awm-relayer-targets.json ] || echo '[]' | sudo tee /etc/prometheus/awm-relayer-targets.json > /dev/null
 #name:TASK [restart prometheus service]
 sudo systemctl restart prometheus
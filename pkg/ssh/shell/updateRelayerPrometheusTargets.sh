@@ -0,0 +1,3 @@
+#!/usr/bin/env bash
+#name:TASK [sync new relayer targets]
+sudo cp -f /tmp/awm-relayer-targets.json /etc/prometheus/awm-relayer-targets.json
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -44,6 +45,11 @@ type scriptInputs struct {
 	LoadTestGitCommit       string
 	CheckoutCommit          bool
 	LoadTestResultFile      string
+	NodeBinaryPaths         []string
+	BackupPath              string
+	IncludeDB               bool
+	RelayerName             string
+	RelayerServiceName      string
 }
 
 //go:embed shell/*.sh
@@ -140,37 +146,93 @@ func RunSSHRestartNode(host *models.Host) error {
 
 // RunSSHSetupAWMRelayerService runs script to set up an AWM Relayer Service
 func RunSSHSetupAWMRelayerService(host *models.Host) error {
+	return RunSSHSetupAWMRelayerInstanceService(host, "", constants.AWMRelayerInstallDir)
+}
+
+// RunSSHSetupAWMRelayerInstanceService runs script to set up the service [serviceName] of
+// the AWM Relayer instance [relayerName]
+func RunSSHSetupAWMRelayerInstanceService(host *models.Host, relayerName string, serviceName string) error {
 	return RunOverSSH(
 		"Setup AWM Relayer Service",
 		host,
 		constants.SSHScriptTimeout,
 		"shell/setupRelayerService.sh",
-		scriptInputs{},
+		scriptInputs{RelayerName: relayerName, RelayerServiceName: serviceName},
 	)
 }
 
 // RunSSHStartAWMRelayerService runs script to start an AWM Relayer Service
 func RunSSHStartAWMRelayerService(host *models.Host) error {
+	return RunSSHStartAWMRelayerInstanceService(host, constants.AWMRelayerInstallDir)
+}
+
+// RunSSHStartAWMRelayerInstanceService runs script to start the AWM Relayer Service [serviceName]
+func RunSSHStartAWMRelayerInstanceService(host *models.Host, serviceName string) error {
 	return RunOverSSH(
 		"Starts AWM Relayer Service",
 		host,
 		constants.SSHScriptTimeout,
 		"shell/startRelayerService.sh",
-		scriptInputs{},
+		scriptInputs{RelayerServiceName: serviceName},
 	)
 }
 
 // RunSSHStopAWMRelayerService runs script to start an AWM Relayer Service
 func RunSSHStopAWMRelayerService(host *models.Host) error {
+	return RunSSHStopAWMRelayerInstanceService(host, constants.AWMRelayerInstallDir)
+}
+
+// RunSSHStopAWMRelayerInstanceService runs script to stop the AWM Relayer Service [serviceName]
+func RunSSHStopAWMRelayerInstanceService(host *models.Host, serviceName string) error {
 	return RunOverSSH(
 		"Stops AWM Relayer Service",
 		host,
 		constants.SSHScriptTimeout,
 		"shell/stopRelayerService.sh",
-		scriptInputs{},
+		scriptInputs{RelayerServiceName: serviceName},
 	)
 }
 
+// RunSSHCheckAWMRelayerInstanceService returns true if the AWM Relayer Service [serviceName] is active
+func RunSSHCheckAWMRelayerInstanceService(host *models.Host, serviceName string) (bool, error) {
+	output, err := host.Command(fmt.Sprintf("systemctl is-active %s || true", serviceName), nil, constants.SSHScriptTimeout)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(output)) == "active", nil
+}
+
+// RunSSHGetAWMRelayerInstanceLogs returns the last [lines] log lines of the AWM Relayer Service
+// [serviceName]. All of them if [lines] is 0
+func RunSSHGetAWMRelayerInstanceLogs(host *models.Host, serviceName string, lines uint) ([]byte, error) {
+	linesFlag := ""
+	if lines > 0 {
+		linesFlag = fmt.Sprintf(" -n %d", lines)
+	}
+	return host.Command(fmt.Sprintf("sudo journalctl -u %s --no-pager%s", serviceName, linesFlag), nil, constants.SSHScriptTimeout)
+}
+
+// RunSSHGetAWMRelayerInstanceStorage returns the contents of the storage files of the relayer
+// whose storage is at [storageDir], indexed by file name
+func RunSSHGetAWMRelayerInstanceStorage(host *models.Host, storageDir string) (map[string][]byte, error) {
+	output, err := host.Command(
+		fmt.Sprintf(`for f in %s/*.json; do [ -f "$f" ] && echo "$(basename "$f") $(tr -d '\n' < "$f")"; done; true`, storageDir),
+		nil,
+		constants.SSHScriptTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fileName, content, found := strings.Cut(line, " ")
+		if found {
+			files[fileName] = []byte(content)
+		}
+	}
+	return files, nil
+}
+
 // RunSSHUpgradeAvalanchego runs script to upgrade avalanchego
 func RunSSHUpgradeAvalanchego(host *models.Host, avalancheGoVersion string) error {
 	if utils.IsE2E() && utils.E2EDocker() {
@@ -242,6 +304,81 @@ func RunSSHUpgradeSubnetEVM(host *models.Host, subnetEVMBinaryPath string) error
 	)
 }
 
+// RunSSHBackupNodeBinaries saves a copy of the given node binaries, to be restored
+// with RunSSHRestoreNodeBinaries if an upgrade goes wrong
+func RunSSHBackupNodeBinaries(host *models.Host, binaryPaths []string) error {
+	return RunOverSSH(
+		"Backup Node Binaries",
+		host,
+		constants.SSHScriptTimeout,
+		"shell/backupNodeBinaries.sh",
+		scriptInputs{NodeBinaryPaths: binaryPaths},
+	)
+}
+
+// RunSSHRestoreNodeBinaries restores the node binaries saved by RunSSHBackupNodeBinaries.
+// Avalanchego is expected to be stopped
+func RunSSHRestoreNodeBinaries(host *models.Host, binaryPaths []string) error {
+	return RunOverSSH(
+		"Restore Node Binaries",
+		host,
+		constants.SSHScriptTimeout,
+		"shell/restoreNodeBinaries.sh",
+		scriptInputs{NodeBinaryPaths: binaryPaths},
+	)
+}
+
+// RunSSHBackupNode archives the staking files and configs of the node into [remoteBackupPath],
+// also archiving its database if [includeDB] is set. Avalanchego is expected to be
+// stopped when archiving the database
+func RunSSHBackupNode(host *models.Host, remoteBackupPath string, includeDB bool) error {
+	return RunOverSSH(
+		"Backup Node",
+		host,
+		constants.SSHLongRunningScriptTimeout,
+		"shell/backupNode.sh",
+		scriptInputs{BackupPath: remoteBackupPath, IncludeDB: includeDB},
+	)
+}
+
+// RunSSHDownloadNodeBackup downloads the node archive created by RunSSHBackupNode
+// and removes it from the host
+func RunSSHDownloadNodeBackup(host *models.Host, remoteBackupPath string, localBackupPath string) error {
+	if err := host.Download(remoteBackupPath, localBackupPath, constants.SSHLongRunningScriptTimeout); err != nil {
+		return err
+	}
+	_, err := host.Command(fmt.Sprintf("rm -f %s", remoteBackupPath), nil, constants.SSHScriptTimeout)
+	return err
+}
+
+// RunSSHRestoreNode uploads a node archive created by RunSSHBackupNode and extracts it
+// into the avalanchego dir, replacing the database if the archive has one.
+// Avalanchego is expected to be stopped
+func RunSSHRestoreNode(host *models.Host, localBackupPath string) error {
+	if err := host.Upload(localBackupPath, constants.CloudNodeBackupPath, constants.SSHLongRunningScriptTimeout); err != nil {
+		return err
+	}
+	return RunOverSSH(
+		"Restore Node",
+		host,
+		constants.SSHLongRunningScriptTimeout,
+		"shell/restoreNode.sh",
+		scriptInputs{BackupPath: constants.CloudNodeBackupPath},
+	)
+}
+
+// RunSSHUploadNodeConfig uploads the avalanchego config file at [nodeInstanceDirPath]
+func RunSSHUploadNodeConfig(host *models.Host, nodeInstanceDirPath string) error {
+	if err := host.MkdirAll(constants.CloudNodeConfigPath, constants.SSHDirOpsTimeout); err != nil {
+		return err
+	}
+	return host.Upload(
+		filepath.Join(nodeInstanceDirPath, constants.NodeFileName),
+		filepath.Join(constants.CloudNodeConfigPath, constants.NodeFileName),
+		constants.SSHFileOpsTimeout,
+	)
+}
+
 func RunSSHCopyMonitoringDashboards(host *models.Host, monitoringDashboardPath string) error {
 	// TODO: download dashboards from github instead
 	remoteDashboardsPath := "/home/ubuntu/dashboards"
@@ -332,6 +469,34 @@ func RunSSHUpdatePrometheusConfig(host *models.Host, avalancheGoPorts, machinePo
 	)
 }
 
+// RunSSHUpdateRelayerPrometheusTargets makes the prometheus of [host] scrape the relayers
+// of [relayerTargets], a map from relayer name to its metrics host:port
+func RunSSHUpdateRelayerPrometheusTargets(host *models.Host, relayerTargets map[string]string) error {
+	const cloudNodeRelayerTargetsTemp = "/tmp/awm-relayer-targets.json"
+	targetsFile, err := os.CreateTemp("", "awm-relayer-targets")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(targetsFile.Name())
+	if err := monitoring.WriteRelayerPrometheusTargets(targetsFile.Name(), relayerTargets); err != nil {
+		return err
+	}
+	if err := host.Upload(
+		targetsFile.Name(),
+		cloudNodeRelayerTargetsTemp,
+		constants.SSHFileOpsTimeout,
+	); err != nil {
+		return err
+	}
+	return RunOverSSH(
+		"Update Relayer Prometheus Targets",
+		host,
+		constants.SSHScriptTimeout,
+		"shell/updateRelayerPrometheusTargets.sh",
+		scriptInputs{},
+	)
+}
+
 func RunSSHUpdateLokiConfig(host *models.Host, port int) error {
 	const cloudNodeLokiConfigTemp = "/tmp/loki.yml"
 	lokiConfig, err := os.CreateTemp("", "loki")
@@ -422,12 +587,18 @@ func RunSSHDownloadNodeMonitoringConfig(host *models.Host, nodeInstanceDirPath s
 }
 
 func RunSSHUploadNodeAWMRelayerConfig(host *models.Host, nodeInstanceDirPath string) error {
-	cloudAWMRelayerConfigDir := filepath.Join(constants.CloudNodeCLIConfigBasePath, constants.ServicesDir, constants.AWMRelayerInstallDir)
+	return RunSSHUploadNodeAWMRelayerInstanceConfig(host, nodeInstanceDirPath, constants.AWMRelayerInstallDir)
+}
+
+// RunSSHUploadNodeAWMRelayerInstanceConfig uploads the config of the AWM Relayer Service [serviceName],
+// prepared at [nodeInstanceDirPath]
+func RunSSHUploadNodeAWMRelayerInstanceConfig(host *models.Host, nodeInstanceDirPath string, serviceName string) error {
+	cloudAWMRelayerConfigDir := filepath.Join(constants.CloudNodeCLIConfigBasePath, constants.ServicesDir, serviceName)
 	if err := host.MkdirAll(cloudAWMRelayerConfigDir, constants.SSHDirOpsTimeout); err != nil {
 		return err
 	}
 	return host.Upload(
-		filepath.Join(nodeInstanceDirPath, constants.ServicesDir, constants.AWMRelayerInstallDir, constants.AWMRelayerConfigFilename),
+		filepath.Join(nodeInstanceDirPath, constants.ServicesDir, serviceName, constants.AWMRelayerConfigFilename),
 		filepath.Join(cloudAWMRelayerConfigDir, constants.AWMRelayerConfigFilename),
 		constants.SSHFileOpsTimeout,
 	)
@@ -0,0 +1,152 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// aliases of the primary network chains that support atomic transfers
+const (
+	PChainAlias = "P"
+	XChainAlias = "X"
+	CChainAlias = "C"
+)
+
+// GetPrimaryChainID returns the blockchain ID of the primary network chain [chainAlias]
+func GetPrimaryChainID(wallet primary.Wallet, chainAlias string) (ids.ID, error) {
+	switch chainAlias {
+	case PChainAlias:
+		return avagoconstants.PlatformChainID, nil
+	case XChainAlias:
+		return wallet.X().BlockchainID(), nil
+	case CChainAlias:
+		return wallet.C().BlockchainID(), nil
+	}
+	return ids.Empty, fmt.Errorf("invalid primary network chain %q", chainAlias)
+}
+
+// IssueAtomicExportTx exports [amount] AVAX from [sourceChain] to [destinationChain],
+// to be imported there by [owner]. Chains are given by their aliases (P, X or C).
+// Exports from the C-Chain are paid from the wallet EVM addresses
+func IssueAtomicExportTx(
+	wallet primary.Wallet,
+	usingLedger bool,
+	hasOnlyOneKey bool,
+	sourceChain string,
+	destinationChain string,
+	amount uint64,
+	owner *secp256k1fx.OutputOwners,
+) (ids.ID, error) {
+	if sourceChain == destinationChain {
+		return ids.Empty, fmt.Errorf("can't export from %s-Chain to itself", sourceChain)
+	}
+	destinationChainID, err := GetPrimaryChainID(wallet, destinationChain)
+	if err != nil {
+		return ids.Empty, err
+	}
+	output := &secp256k1fx.TransferOutput{
+		Amt:          amount,
+		OutputOwners: *owner,
+	}
+	transferableOutputs := []*avax.TransferableOutput{
+		{
+			Asset: avax.Asset{ID: wallet.P().AVAXAssetID()},
+			Out:   output,
+		},
+	}
+	showLedgerSignatureMsg(usingLedger, hasOnlyOneKey, fmt.Sprintf("%s -> %s Chain Export Transaction", sourceChain, destinationChain))
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	switch sourceChain {
+	case PChainAlias:
+		tx, err := wallet.P().IssueExportTx(destinationChainID, transferableOutputs, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	case XChainAlias:
+		tx, err := wallet.X().IssueExportTx(destinationChainID, transferableOutputs, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	case CChainAlias:
+		tx, err := wallet.C().IssueExportTx(destinationChainID, []*secp256k1fx.TransferOutput{output}, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	}
+	return ids.Empty, fmt.Errorf("invalid primary network chain %q", sourceChain)
+}
+
+// IssueAtomicImportTx imports into [destinationChain] all the funds exported to
+// the wallet from [sourceChain]. On the P-Chain and X-Chain they are given to
+// [owner], and on the C-Chain to the EVM address [to]
+func IssueAtomicImportTx(
+	wallet primary.Wallet,
+	usingLedger bool,
+	hasOnlyOneKey bool,
+	sourceChain string,
+	destinationChain string,
+	owner *secp256k1fx.OutputOwners,
+	to ethcommon.Address,
+) (ids.ID, error) {
+	if sourceChain == destinationChain {
+		return ids.Empty, fmt.Errorf("can't import into %s-Chain from itself", destinationChain)
+	}
+	sourceChainID, err := GetPrimaryChainID(wallet, sourceChain)
+	if err != nil {
+		return ids.Empty, err
+	}
+	showLedgerSignatureMsg(usingLedger, hasOnlyOneKey, fmt.Sprintf("%s -> %s Chain Import Transaction", sourceChain, destinationChain))
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	switch destinationChain {
+	case PChainAlias:
+		tx, err := wallet.P().IssueImportTx(sourceChainID, owner, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	case XChainAlias:
+		tx, err := wallet.X().IssueImportTx(sourceChainID, owner, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	case CChainAlias:
+		tx, err := wallet.C().IssueImportTx(sourceChainID, to, common.WithContext(ctx))
+		if tx == nil {
+			return ids.Empty, wrapIssueTxError(ctx, ids.Empty, err)
+		}
+		return tx.ID(), wrapIssueTxError(ctx, tx.ID(), err)
+	}
+	return ids.Empty, fmt.Errorf("invalid primary network chain %q", destinationChain)
+}
+
+// wrapIssueTxError gives context to an error obtained while building, signing
+// or issuing the tx [txID], distinguishing API timeouts
+func wrapIssueTxError(ctx context.Context, txID ids.ID, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case txID == ids.Empty:
+		return fmt.Errorf("error building or signing tx: %w", err)
+	case ctx.Err() != nil:
+		return fmt.Errorf("timeout issuing/verifying tx with ID %s: %w", txID, err)
+	default:
+		return fmt.Errorf("error issuing tx with ID %s: %w", txID, err)
+	}
+}
@@ -925,7 +925,7 @@ func SetDefaultSnapshot(snapshotsDir string, resetCurrentSnapshot bool, avagoVer
 		resetCurrentSnapshot = true
 	}
 	bootstrapSnapshotArchivePath := filepath.Join(snapshotsDir, bootstrapSnapshotArchiveName)
-	defaultSnapshotPath := filepath.Join(snapshotsDir, "anr-snapshot-"+constants.DefaultSnapshotName)
+	defaultSnapshotPath := filepath.Join(snapshotsDir, constants.ANRSnapshotPrefix+constants.DefaultSnapshotName)
 	defaultSnapshotInUse := false
 	if _, err := os.Stat(defaultSnapshotPath); err == nil {
 		defaultSnapshotInUse = true
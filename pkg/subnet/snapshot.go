@@ -0,0 +1,457 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/config"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// layout of the local snapshot archives
+	snapshotArchiveMetadataFile         = "metadata.json"
+	snapshotArchiveSnapshotDir          = "snapshot"
+	snapshotArchiveRelayerConfFile      = "relayer-config.json"
+	snapshotArchiveExtraNetworkDataFile = "extra-local-network-data.json"
+
+	unknownAvalancheGoVersion = "unknown"
+
+	// limit on the size of each file extracted from a snapshot archive
+	maxSnapshotArchiveFileSize = 16 * 1024 * 1024 * 1024 // 16 GB
+)
+
+var (
+	ErrLocalSnapshotNotFound = errors.New("local snapshot not found")
+
+	avalancheGoVersionInPathRegex = regexp.MustCompile(`avalanchego-(v\d+\.\d+\.\d+)`)
+)
+
+// LocalSnapshotInfo describes a named snapshot of the local network
+type LocalSnapshotInfo struct {
+	Name               string    `json:"name"`
+	Size               int64     `json:"size"`
+	ModTime            time.Time `json:"modTime"`
+	AvalancheGoVersion string    `json:"avalancheGoVersion"`
+	Subnets            []string  `json:"subnets"`
+	HasRelayerConfig   bool      `json:"hasRelayerConfig"`
+}
+
+// anrSnapshotNetworkConfig is the subset of the network config saved by
+// avalanche-network-runner in a snapshot that is needed to describe it
+type anrSnapshotNetworkConfig struct {
+	BinaryPath  string `json:"binaryPath"`
+	NodeConfigs []struct {
+		Flags      map[string]interface{} `json:"flags"`
+		ConfigFile string                 `json:"configFile"`
+		BinaryPath string                 `json:"binaryPath"`
+	} `json:"nodeConfigs"`
+}
+
+// LocalSnapshotExists returns true if the local snapshot [snapshotName] exists
+func LocalSnapshotExists(app *application.Avalanche, snapshotName string) bool {
+	return utils.DirectoryExists(app.GetANRSnapshotPath(snapshotName))
+}
+
+// ListLocalSnapshots describes all the named snapshots of the local network, sorted by name
+func ListLocalSnapshots(app *application.Avalanche) ([]LocalSnapshotInfo, error) {
+	matches, err := filepath.Glob(filepath.Join(app.GetSnapshotsDir(), constants.ANRSnapshotPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	snapshotNames := []string{}
+	for _, match := range matches {
+		if utils.DirectoryExists(match) {
+			snapshotNames = append(snapshotNames, strings.TrimPrefix(filepath.Base(match), constants.ANRSnapshotPrefix))
+		}
+	}
+	sort.Strings(snapshotNames)
+	infos := []LocalSnapshotInfo{}
+	for _, snapshotName := range snapshotNames {
+		info, err := GetLocalSnapshotInfo(app, snapshotName)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// GetLocalSnapshotInfo describes the local snapshot [snapshotName]: its size on disk,
+// the avalanchego version it was saved with and the subnets its nodes track
+func GetLocalSnapshotInfo(app *application.Avalanche, snapshotName string) (LocalSnapshotInfo, error) {
+	snapshotPath := app.GetANRSnapshotPath(snapshotName)
+	stat, err := os.Stat(snapshotPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return LocalSnapshotInfo{}, fmt.Errorf("%w: %s", ErrLocalSnapshotNotFound, snapshotName)
+		}
+		return LocalSnapshotInfo{}, err
+	}
+	size, err := dirSize(snapshotPath)
+	if err != nil {
+		return LocalSnapshotInfo{}, err
+	}
+	info := LocalSnapshotInfo{
+		Name:               snapshotName,
+		Size:               size,
+		ModTime:            stat.ModTime(),
+		AvalancheGoVersion: unknownAvalancheGoVersion,
+		Subnets:            []string{},
+		HasRelayerConfig:   utils.FileExists(app.GetAWMRelayerSnapshotConfPath(snapshotName)),
+	}
+	networkConfigBytes, err := os.ReadFile(filepath.Join(snapshotPath, "network.json"))
+	if err != nil {
+		// snapshot saved by an unexpected anr version, keep basic info only
+		return info, nil
+	}
+	var networkConfig anrSnapshotNetworkConfig
+	if err := json.Unmarshal(networkConfigBytes, &networkConfig); err != nil {
+		return info, nil
+	}
+	binaryPaths := []string{networkConfig.BinaryPath}
+	subnetIDs := map[string]struct{}{}
+	for _, nodeConfig := range networkConfig.NodeConfigs {
+		binaryPaths = append(binaryPaths, nodeConfig.BinaryPath)
+		trackedSubnets, _ := nodeConfig.Flags[config.TrackSubnetsKey].(string)
+		if nodeConfig.ConfigFile != "" {
+			var configFile map[string]interface{}
+			if err := json.Unmarshal([]byte(nodeConfig.ConfigFile), &configFile); err == nil {
+				if s, ok := configFile[config.TrackSubnetsKey].(string); ok {
+					trackedSubnets += "," + s
+				}
+			}
+		}
+		for _, subnetID := range strings.Split(trackedSubnets, ",") {
+			if subnetID = strings.TrimSpace(subnetID); subnetID != "" {
+				subnetIDs[subnetID] = struct{}{}
+			}
+		}
+	}
+	for _, binaryPath := range binaryPaths {
+		if matches := avalancheGoVersionInPathRegex.FindStringSubmatch(binaryPath); matches != nil {
+			info.AvalancheGoVersion = matches[1]
+			break
+		}
+	}
+	info.Subnets, err = subnetIDsToNames(app, maps.Keys(subnetIDs))
+	if err != nil {
+		return LocalSnapshotInfo{}, err
+	}
+	return info, nil
+}
+
+// subnetIDsToNames maps local subnet IDs to the names of the subnets configured
+// on this machine. IDs of unknown subnets are kept as they are
+func subnetIDsToNames(app *application.Avalanche, subnetIDs []string) ([]string, error) {
+	if len(subnetIDs) == 0 {
+		return []string{}, nil
+	}
+	idToName := map[string]string{}
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		if networkData, ok := sc.Networks[models.Local.String()]; ok {
+			idToName[networkData.SubnetID.String()] = subnetName
+		}
+	}
+	names := []string{}
+	for _, subnetID := range subnetIDs {
+		if name, ok := idToName[subnetID]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, subnetID)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteLocalSnapshot removes the local snapshot [snapshotName], together with
+// the relayer config and extra network data stored for it
+func DeleteLocalSnapshot(app *application.Avalanche, snapshotName string) error {
+	if !LocalSnapshotExists(app, snapshotName) {
+		return fmt.Errorf("%w: %s", ErrLocalSnapshotNotFound, snapshotName)
+	}
+	if err := os.RemoveAll(app.GetANRSnapshotPath(snapshotName)); err != nil {
+		return err
+	}
+	for _, p := range []string{
+		app.GetAWMRelayerSnapshotConfPath(snapshotName),
+		app.GetExtraLocalNetworkSnapshotPath(snapshotName),
+	} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportLocalSnapshot writes the local snapshot [snapshotName], together with its
+// relayer config and extra network data, as a single tar.gz archive at [archivePath]
+func ExportLocalSnapshot(app *application.Avalanche, snapshotName string, archivePath string) error {
+	info, err := GetLocalSnapshotInfo(app, snapshotName)
+	if err != nil {
+		return err
+	}
+	metadataBytes, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := writeTarFileEntry(tarWriter, snapshotArchiveMetadataFile, metadataBytes); err != nil {
+		return err
+	}
+	if err := addDirToTar(tarWriter, app.GetANRSnapshotPath(snapshotName), snapshotArchiveSnapshotDir); err != nil {
+		return err
+	}
+	for archiveName, p := range map[string]string{
+		snapshotArchiveRelayerConfFile:      app.GetAWMRelayerSnapshotConfPath(snapshotName),
+		snapshotArchiveExtraNetworkDataFile: app.GetExtraLocalNetworkSnapshotPath(snapshotName),
+	} {
+		if !utils.FileExists(p) {
+			continue
+		}
+		bs, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := writeTarFileEntry(tarWriter, archiveName, bs); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// ImportLocalSnapshot installs the local snapshot archive at [archivePath], as created
+// by ExportLocalSnapshot. The snapshot is named [snapshotName], or keeps its original
+// name if empty. An existing snapshot with the same name is only replaced if [force] is set.
+// Returns the name of the imported snapshot
+func ImportLocalSnapshot(
+	app *application.Avalanche,
+	archivePath string,
+	snapshotName string,
+	force bool,
+) (string, error) {
+	if err := os.MkdirAll(app.GetSnapshotsDir(), constants.DefaultPerms755); err != nil {
+		return "", err
+	}
+	// extract into a temporary dir in the same filesystem, so the final moves are renames
+	tmpDir, err := os.MkdirTemp(app.GetSnapshotsDir(), "import-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractTarGz(archivePath, tmpDir); err != nil {
+		return "", fmt.Errorf("failed extracting snapshot archive %s: %w", archivePath, err)
+	}
+	metadataBytes, err := os.ReadFile(filepath.Join(tmpDir, snapshotArchiveMetadataFile))
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot archive %s: %w", archivePath, err)
+	}
+	var metadata LocalSnapshotInfo
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return "", fmt.Errorf("invalid snapshot archive %s: %w", archivePath, err)
+	}
+	if !utils.DirectoryExists(filepath.Join(tmpDir, snapshotArchiveSnapshotDir)) {
+		return "", fmt.Errorf("invalid snapshot archive %s: no snapshot data found", archivePath)
+	}
+	if snapshotName == "" {
+		snapshotName = metadata.Name
+	}
+	if snapshotName == "" {
+		return "", fmt.Errorf("invalid snapshot archive %s: no snapshot name found", archivePath)
+	}
+	if LocalSnapshotExists(app, snapshotName) {
+		if !force {
+			return "", fmt.Errorf("local snapshot %s already exists", snapshotName)
+		}
+		if err := DeleteLocalSnapshot(app, snapshotName); err != nil {
+			return "", err
+		}
+	}
+	if err := os.Rename(filepath.Join(tmpDir, snapshotArchiveSnapshotDir), app.GetANRSnapshotPath(snapshotName)); err != nil {
+		return "", err
+	}
+	for archiveName, p := range map[string]string{
+		snapshotArchiveRelayerConfFile:      app.GetAWMRelayerSnapshotConfPath(snapshotName),
+		snapshotArchiveExtraNetworkDataFile: app.GetExtraLocalNetworkSnapshotPath(snapshotName),
+	} {
+		extractedPath := filepath.Join(tmpDir, archiveName)
+		if !utils.FileExists(extractedPath) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), constants.DefaultPerms755); err != nil {
+			return "", err
+		}
+		if err := os.Rename(extractedPath, p); err != nil {
+			return "", err
+		}
+	}
+	return snapshotName, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func writeTarFileEntry(tarWriter *tar.Writer, name string, bs []byte) error {
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     constants.WriteReadReadPerms,
+		Size:     int64(len(bs)),
+		Typeflag: tar.TypeReg,
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tarWriter.Write(bs)
+	return err
+}
+
+// addDirToTar adds the contents of [dir] to the archive, under [archiveDir]
+func addDirToTar(tarWriter *tar.Writer, dir string, archiveDir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			// snapshots only contain dirs and regular files
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(archiveDir, relPath))
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tarWriter, f)
+		return err
+	})
+}
+
+// extractTarGz extracts the tar.gz archive at [archivePath] into [targetDir],
+// streaming it from disk, as snapshot archives can be big
+func extractTarGz(archivePath string, targetDir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(gzipReader)
+	cleanTargetDir := filepath.Clean(targetDir)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(cleanTargetDir, header.Name)
+		if target != cleanTargetDir && !strings.HasPrefix(target, cleanTargetDir+string(os.PathSeparator)) {
+			return fmt.Errorf("content filepath is tainted: %s", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, constants.DefaultPerms755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), constants.DefaultPerms755); err != nil {
+				return err
+			}
+			if err := writeFileFromReader(target, tarReader, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		default:
+			if !slices.Contains([]byte{tar.TypeXGlobalHeader, tar.TypeXHeader}, header.Typeflag) {
+				return fmt.Errorf("unsupported entry %s in archive", header.Name)
+			}
+		}
+	}
+}
+
+func writeFileFromReader(p string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, r, maxSnapshotArchiveFileSize); err != nil && !errors.Is(err, io.EOF) {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newSnapshotTestApp(t *testing.T) *application.Avalanche {
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	return app
+}
+
+// writeTestSnapshot creates a minimal anr snapshot, with nodes tracking [subnetID]
+func writeTestSnapshot(t *testing.T, app *application.Avalanche, snapshotName string, subnetID ids.ID) {
+	snapshotPath := app.GetANRSnapshotPath(snapshotName)
+	dbPath := filepath.Join(snapshotPath, "db", "node1", "network-1337")
+	if err := os.MkdirAll(dbPath, constants.DefaultPerms755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dbPath, "000001.log"), []byte("db contents"), constants.WriteReadReadPerms); err != nil {
+		t.Fatal(err)
+	}
+	networkConfig := map[string]interface{}{
+		"binaryPath": "/home/user/.avalanche-cli/bin/avalanchego/avalanchego-v1.11.1/avalanchego",
+		"nodeConfigs": []map[string]interface{}{
+			{
+				"name":       "node1",
+				"configFile": `{"track-subnets":"` + subnetID.String() + `"}`,
+			},
+		},
+	}
+	networkConfigBytes, err := json.Marshal(networkConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshotPath, "network.json"), networkConfigBytes, constants.WriteReadReadPerms); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalSnapshotInfo(t *testing.T) {
+	require := setupTest(t)
+	app := newSnapshotTestApp(t)
+	subnetID := ids.GenerateTestID()
+	unknownSubnetID := ids.GenerateTestID()
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name: "testSubnet",
+		Networks: map[string]models.NetworkData{
+			models.Local.String(): {SubnetID: subnetID},
+		},
+	}))
+	writeTestSnapshot(t, app, "snap1", subnetID)
+	writeTestSnapshot(t, app, "snap2", unknownSubnetID)
+
+	infos, err := ListLocalSnapshots(app)
+	require.NoError(err)
+	require.Len(infos, 2)
+	require.Equal("snap1", infos[0].Name)
+	require.Equal("v1.11.1", infos[0].AvalancheGoVersion)
+	require.Equal([]string{"testSubnet"}, infos[0].Subnets)
+	networkConfigBytes := mustReadFile(t, filepath.Join(app.GetANRSnapshotPath("snap1"), "network.json"))
+	require.Equal(int64(len("db contents")+len(networkConfigBytes)), infos[0].Size)
+	require.False(infos[0].HasRelayerConfig)
+	require.Equal([]string{unknownSubnetID.String()}, infos[1].Subnets)
+
+	_, err = GetLocalSnapshotInfo(app, "missing")
+	require.True(errors.Is(err, ErrLocalSnapshotNotFound))
+}
+
+func TestLocalSnapshotExportImport(t *testing.T) {
+	require := setupTest(t)
+	srcApp := newSnapshotTestApp(t)
+	subnetID := ids.GenerateTestID()
+	writeTestSnapshot(t, srcApp, "snap", subnetID)
+	relayerConf := []byte(`{"relayer":"conf"}`)
+	require.NoError(os.MkdirAll(srcApp.GetAWMRelayerSnapshotConfsDir(), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(srcApp.GetAWMRelayerSnapshotConfPath("snap"), relayerConf, constants.WriteReadReadPerms))
+
+	archivePath := filepath.Join(t.TempDir(), "snap.tar.gz")
+	require.NoError(ExportLocalSnapshot(srcApp, "snap", archivePath))
+
+	dstApp := newSnapshotTestApp(t)
+	name, err := ImportLocalSnapshot(dstApp, archivePath, "", false)
+	require.NoError(err)
+	require.Equal("snap", name)
+	require.Equal(
+		mustReadFile(t, filepath.Join(srcApp.GetANRSnapshotPath("snap"), "db", "node1", "network-1337", "000001.log")),
+		mustReadFile(t, filepath.Join(dstApp.GetANRSnapshotPath("snap"), "db", "node1", "network-1337", "000001.log")),
+	)
+	require.Equal(relayerConf, mustReadFile(t, dstApp.GetAWMRelayerSnapshotConfPath("snap")))
+
+	// same name again needs force
+	_, err = ImportLocalSnapshot(dstApp, archivePath, "", false)
+	require.Error(err)
+	_, err = ImportLocalSnapshot(dstApp, archivePath, "", true)
+	require.NoError(err)
+
+	// import under a different name
+	name, err = ImportLocalSnapshot(dstApp, archivePath, "renamed", false)
+	require.NoError(err)
+	require.Equal("renamed", name)
+	infos, err := ListLocalSnapshots(dstApp)
+	require.NoError(err)
+	require.Len(infos, 2)
+	require.True(infos[0].HasRelayerConfig)
+	require.True(infos[1].HasRelayerConfig)
+
+	require.NoError(DeleteLocalSnapshot(dstApp, "renamed"))
+	require.False(LocalSnapshotExists(dstApp, "renamed"))
+	require.NoFileExists(dstApp.GetAWMRelayerSnapshotConfPath("renamed"))
+	require.True(errors.Is(DeleteLocalSnapshot(dstApp, "renamed"), ErrLocalSnapshotNotFound))
+}
+
+func mustReadFile(t *testing.T, p string) []byte {
+	bs, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/config"
+)
+
+// LocalNodeOverrides holds the avalanchego config overrides of a single local network node
+type LocalNodeOverrides struct {
+	// avalanchego flags, including the staking keys if given
+	Flags map[string]interface{}
+	// chain alias (or blockchain ID) to chain config
+	ChainConfigs map[string]string
+	// true if the node uses its own staking keys instead of the genesis ones
+	CustomStakingKeys bool
+}
+
+// LocalNetworkTopology describes a local network to be started from scratch
+type LocalNetworkTopology struct {
+	NumNodes uint32
+	// chain configs applied to all nodes
+	ChainConfigs map[string]string
+	// overrides by node name (node1, node2, ...)
+	Nodes map[string]*LocalNodeOverrides
+}
+
+// LoadLocalNetworkTopology creates the topology of a local network with [numNodes] nodes, taking
+// the node overrides from [nodeConfigDir], if given. The dir is expected to have the layout:
+//
+//	chain-configs/<chain>/config.json    chain configs for all nodes
+//	node<i>/flags.json                   avalanchego flags for node i
+//	node<i>/chain-configs/<chain>/config.json
+//	node<i>/staking/staker.key           staking keys for node i (signer.key is optional)
+//	node<i>/staking/staker.crt
+//	node<i>/staking/signer.key
+func LoadLocalNetworkTopology(numNodes uint32, nodeConfigDir string) (*LocalNetworkTopology, error) {
+	if numNodes == 0 {
+		return nil, errors.New("the local network must have at least one node")
+	}
+	topology := &LocalNetworkTopology{
+		NumNodes:     numNodes,
+		ChainConfigs: map[string]string{},
+		Nodes:        map[string]*LocalNodeOverrides{},
+	}
+	if nodeConfigDir == "" {
+		return topology, nil
+	}
+	entries, err := os.ReadDir(nodeConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading node config dir: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		entryPath := filepath.Join(nodeConfigDir, entry.Name())
+		if entry.Name() == constants.LocalChainConfigsDirName {
+			topology.ChainConfigs, err = loadChainConfigs(entryPath)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		nodeIndex, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "node"))
+		if !strings.HasPrefix(entry.Name(), "node") || err != nil {
+			return nil, fmt.Errorf("unexpected dir %s in node config dir: expected node<i> or %s", entry.Name(), constants.LocalChainConfigsDirName)
+		}
+		if nodeIndex < 1 || nodeIndex > int(numNodes) {
+			return nil, fmt.Errorf("node config dir has overrides for %s, but the network has %d nodes", entry.Name(), numNodes)
+		}
+		overrides, err := loadNodeOverrides(entryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading overrides for %s: %w", entry.Name(), err)
+		}
+		topology.Nodes[entry.Name()] = overrides
+	}
+	return topology, nil
+}
+
+// CustomNodeConfigs returns the avalanchego flags for each node, JSON encoded, as
+// expected by avalanche-network-runner. All nodes are included, as the runner takes
+// the number of nodes from it
+func (t *LocalNetworkTopology) CustomNodeConfigs() (map[string]string, error) {
+	customNodeConfigs := map[string]string{}
+	for i := uint32(1); i <= t.NumNodes; i++ {
+		nodeName := fmt.Sprintf("node%d", i)
+		flags := map[string]interface{}{}
+		if overrides, ok := t.Nodes[nodeName]; ok {
+			flags = overrides.Flags
+		}
+		flagsBytes, err := json.Marshal(flags)
+		if err != nil {
+			return nil, err
+		}
+		customNodeConfigs[nodeName] = string(flagsBytes)
+	}
+	return customNodeConfigs, nil
+}
+
+// NodesWithChainConfigs returns the sorted names of the nodes that have their own chain configs
+func (t *LocalNetworkTopology) NodesWithChainConfigs() []string {
+	nodeNames := []string{}
+	for nodeName, overrides := range t.Nodes {
+		if len(overrides.ChainConfigs) > 0 {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+// NodesWithCustomStakingKeys returns the sorted names of the nodes that have their own staking keys
+func (t *LocalNetworkTopology) NodesWithCustomStakingKeys() []string {
+	nodeNames := []string{}
+	for nodeName, overrides := range t.Nodes {
+		if overrides.CustomStakingKeys {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+func loadNodeOverrides(nodeDir string) (*LocalNodeOverrides, error) {
+	overrides := &LocalNodeOverrides{
+		Flags:        map[string]interface{}{},
+		ChainConfigs: map[string]string{},
+	}
+	flagsPath := filepath.Join(nodeDir, constants.LocalNodeFlagsFileName)
+	flagsBytes, err := os.ReadFile(flagsPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(flagsBytes, &overrides.Flags); err != nil {
+			return nil, fmt.Errorf("invalid flags file %s: %w", flagsPath, err)
+		}
+	}
+	chainConfigsDir := filepath.Join(nodeDir, constants.LocalChainConfigsDirName)
+	if _, err := os.Stat(chainConfigsDir); err == nil {
+		overrides.ChainConfigs, err = loadChainConfigs(chainConfigsDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	stakingDir := filepath.Join(nodeDir, constants.LocalStakingDirName)
+	if _, err := os.Stat(stakingDir); err == nil {
+		if err := loadStakingFlags(stakingDir, overrides.Flags); err != nil {
+			return nil, err
+		}
+		overrides.CustomStakingKeys = true
+	}
+	return overrides, nil
+}
+
+// loadChainConfigs reads <chainConfigsDir>/<chain>/config.json for each chain
+func loadChainConfigs(chainConfigsDir string) (map[string]string, error) {
+	chainConfigs := map[string]string{}
+	entries, err := os.ReadDir(chainConfigsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		chainConfigPath := filepath.Join(chainConfigsDir, entry.Name(), "config"+constants.JSONSuffix)
+		chainConfigBytes, err := os.ReadFile(chainConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid(chainConfigBytes) {
+			return nil, fmt.Errorf("invalid chain config %s: not a JSON document", chainConfigPath)
+		}
+		chainConfigs[entry.Name()] = string(chainConfigBytes)
+	}
+	return chainConfigs, nil
+}
+
+// loadStakingFlags sets the avalanchego content flags for the staking keys found in [stakingDir].
+// Content flags take precedence over the key files the runner writes for each node
+func loadStakingFlags(stakingDir string, flags map[string]interface{}) error {
+	stakingFiles := []struct {
+		fileName string
+		flag     string
+		required bool
+	}{
+		{constants.StakerKeyFileName, config.StakingTLSKeyContentKey, true},
+		{constants.StakerCertFileName, config.StakingCertContentKey, true},
+		{constants.BLSKeyFileName, config.StakingSignerKeyContentKey, false},
+	}
+	for _, stakingFile := range stakingFiles {
+		stakingFilePath := filepath.Join(stakingDir, stakingFile.fileName)
+		stakingFileBytes, err := os.ReadFile(stakingFilePath)
+		if errors.Is(err, os.ErrNotExist) && !stakingFile.required {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		flags[stakingFile.flag] = base64.StdEncoding.EncodeToString(stakingFileBytes)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanchego/config"
+)
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	if err := os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), constants.WriteReadReadPerms); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadLocalNetworkTopology(t *testing.T) {
+	require := setupTest(t)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "chain-configs", "C", "config.json"), `{"log-level":"debug"}`)
+	writeTestFile(t, filepath.Join(dir, "node2", "flags.json"), `{"log-level":"trace"}`)
+	writeTestFile(t, filepath.Join(dir, "node2", "chain-configs", "C", "config.json"), `{"pruning-enabled":false}`)
+	writeTestFile(t, filepath.Join(dir, "node3", "staking", "staker.key"), "key")
+	writeTestFile(t, filepath.Join(dir, "node3", "staking", "staker.crt"), "cert")
+
+	topology, err := LoadLocalNetworkTopology(7, dir)
+	require.NoError(err)
+	require.Equal(uint32(7), topology.NumNodes)
+	require.Equal(map[string]string{"C": `{"log-level":"debug"}`}, topology.ChainConfigs)
+	require.Equal([]string{"node2"}, topology.NodesWithChainConfigs())
+	require.Equal([]string{"node3"}, topology.NodesWithCustomStakingKeys())
+
+	customNodeConfigs, err := topology.CustomNodeConfigs()
+	require.NoError(err)
+	require.Len(customNodeConfigs, 7)
+	require.Equal("{}", customNodeConfigs["node1"])
+	require.Equal(`{"log-level":"trace"}`, customNodeConfigs["node2"])
+	var node3Flags map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(customNodeConfigs["node3"]), &node3Flags))
+	require.Equal(base64.StdEncoding.EncodeToString([]byte("key")), node3Flags[config.StakingTLSKeyContentKey])
+	require.Equal(base64.StdEncoding.EncodeToString([]byte("cert")), node3Flags[config.StakingCertContentKey])
+	require.NotContains(node3Flags, config.StakingSignerKeyContentKey)
+
+	// overrides for a node outside of the network
+	_, err = LoadLocalNetworkTopology(2, dir)
+	require.ErrorContains(err, "node3")
+
+	// missing staking cert
+	require.NoError(os.Remove(filepath.Join(dir, "node3", "staking", "staker.crt")))
+	_, err = LoadLocalNetworkTopology(7, dir)
+	require.Error(err)
+
+	_, err = LoadLocalNetworkTopology(0, "")
+	require.Error(err)
+}
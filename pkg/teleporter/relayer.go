@@ -28,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/awm-relayer/config"
 	offchainregistry "github.com/ava-labs/awm-relayer/messages/off-chain-registry"
+	"golang.org/x/exp/slices"
 )
 
 var teleporterRelayerRequiredBalance = big.NewInt(0).Mul(big.NewInt(1e18), big.NewInt(500)) // 500 AVAX
@@ -59,28 +60,47 @@ func FundRelayer(
 	prefundedPrivateKey string,
 	teleporterRelayerAddress string,
 ) error {
-	// get teleporter relayer balance
+	_, err := TopUpRelayer(
+		rpcURL,
+		prefundedPrivateKey,
+		teleporterRelayerAddress,
+		teleporterRelayerRequiredBalance,
+		teleporterRelayerRequiredBalance,
+	)
+	return err
+}
+
+// TopUpRelayer funds [relayerAddress] up to [targetBalance] with [fundingPrivateKey], if its balance
+// is below [minBalance]. Returns the amount sent
+func TopUpRelayer(
+	rpcURL string,
+	fundingPrivateKey string,
+	relayerAddress string,
+	minBalance *big.Int,
+	targetBalance *big.Int,
+) (*big.Int, error) {
 	client, err := evm.GetClient(rpcURL)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	teleporterRelayerBalance, err := evm.GetAddressBalance(client, teleporterRelayerAddress)
+	relayerBalance, err := evm.GetAddressBalance(client, relayerAddress)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if teleporterRelayerBalance.Cmp(teleporterRelayerRequiredBalance) < 0 {
-		toFund := big.NewInt(0).Sub(teleporterRelayerRequiredBalance, teleporterRelayerBalance)
-		err := evm.FundAddress(
-			client,
-			prefundedPrivateKey,
-			teleporterRelayerAddress,
-			toFund,
-		)
-		if err != nil {
-			return err
-		}
+	toFund := big.NewInt(0)
+	if relayerBalance.Cmp(minBalance) >= 0 || relayerBalance.Cmp(targetBalance) >= 0 {
+		return toFund, nil
 	}
-	return nil
+	toFund.Sub(targetBalance, relayerBalance)
+	if err := evm.FundAddress(
+		client,
+		fundingPrivateKey,
+		relayerAddress,
+		toFund,
+	); err != nil {
+		return nil, err
+	}
+	return toFund, nil
 }
 
 type relayerRunFile struct {
@@ -97,14 +117,22 @@ func DeployRelayer(
 	if err := RelayerCleanup(runFilePath, storageDir); err != nil {
 		return err
 	}
-	downloader := application.NewDownloader()
-	version, err := downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.AWMRelayerRepoName))
-	if err != nil {
+	return StartRelayer(binDir, configPath, logFilePath, runFilePath)
+}
+
+// StartRelayer executes the latest awm-relayer with the config at [configPath], stopping the
+// relayer of [runFilePath] if running. The relayer storage is kept, so it can resume from
+// the last processed blocks
+func StartRelayer(
+	binDir string,
+	configPath string,
+	logFilePath string,
+	runFilePath string,
+) error {
+	if err := StopRelayer(runFilePath); err != nil {
 		return err
 	}
-	ux.Logger.PrintToUser("using latest awm-relayer version (%s)", version)
-	versionBinDir := filepath.Join(binDir, version)
-	binPath, err := installRelayer(versionBinDir, version)
+	binPath, err := InstallRelayer(binDir)
 	if err != nil {
 		return err
 	}
@@ -119,6 +147,11 @@ func RelayerCleanup(runFilePath string, storageDir string) error {
 	if err := os.RemoveAll(storageDir); err != nil {
 		return err
 	}
+	return StopRelayer(runFilePath)
+}
+
+// StopRelayer stops the relayer process of [runFilePath], if running
+func StopRelayer(runFilePath string) error {
 	if !utils.FileExists(runFilePath) {
 		return nil
 	}
@@ -149,6 +182,27 @@ func RelayerCleanup(runFilePath string, storageDir string) error {
 	return removeRelayerRunFile(runFilePath)
 }
 
+// IsRelayerRunning returns true if the relayer process of [runFilePath] is running, together
+// with its pid
+func IsRelayerRunning(runFilePath string) (bool, int, error) {
+	if !utils.FileExists(runFilePath) {
+		return false, 0, nil
+	}
+	bs, err := os.ReadFile(runFilePath)
+	if err != nil {
+		return false, 0, err
+	}
+	rf := relayerRunFile{}
+	if err := json.Unmarshal(bs, &rf); err != nil {
+		return false, 0, err
+	}
+	proc, err := os.FindProcess(rf.Pid)
+	if err != nil {
+		return false, rf.Pid, nil
+	}
+	return proc.Signal(syscall.Signal(0)) == nil, rf.Pid, nil
+}
+
 func removeRelayerRunFile(runFilePath string) error {
 	err := os.Remove(runFilePath)
 	if err != nil {
@@ -316,40 +370,146 @@ func addChainToRelayerConfig(
 	relayerRewardAddress string,
 	relayerFundedAddressKey string,
 ) {
-	source := &config.SourceBlockchain{
-		SubnetID:     subnetID,
-		BlockchainID: blockchainID,
-		VM:           config.EVM.String(),
-		RPCEndpoint:  fmt.Sprintf("http://%s:%d/ext/bc/%s/rpc", host, port, blockchainID),
-		WSEndpoint:   fmt.Sprintf("ws://%s:%d/ext/bc/%s/ws", host, port, blockchainID),
-		MessageContracts: map[string]config.MessageProtocolConfig{
-			teleporterContractAddress: {
-				MessageFormat: config.TELEPORTER.String(),
-				Settings: map[string]interface{}{
-					"reward-address": relayerRewardAddress,
-				},
-			},
-			offchainregistry.OffChainRegistrySourceAddress.Hex(): {
-				MessageFormat: config.OFF_CHAIN_REGISTRY.String(),
-				Settings: map[string]interface{}{
-					"teleporter-registry-address": teleporterRegistryAddress,
-				},
+	source := newRelayerSource(host, port, subnetID, blockchainID, []string{teleporterContractAddress}, teleporterRegistryAddress, relayerRewardAddress)
+	destination := newRelayerDestination(host, port, subnetID, blockchainID, relayerFundedAddressKey)
+	if !utils.Any(relayerConfig.SourceBlockchains, func(s *config.SourceBlockchain) bool { return s.BlockchainID == blockchainID }) {
+		relayerConfig.SourceBlockchains = append(relayerConfig.SourceBlockchains, source)
+	}
+	if !utils.Any(relayerConfig.DestinationBlockchains, func(s *config.DestinationBlockchain) bool { return s.BlockchainID == blockchainID }) {
+		relayerConfig.DestinationBlockchains = append(relayerConfig.DestinationBlockchains, destination)
+	}
+}
+
+func newRelayerSource(
+	host string,
+	port uint32,
+	subnetID string,
+	blockchainID string,
+	teleporterContractAddresses []string,
+	teleporterRegistryAddress string,
+	relayerRewardAddress string,
+) *config.SourceBlockchain {
+	messageContracts := map[string]config.MessageProtocolConfig{
+		offchainregistry.OffChainRegistrySourceAddress.Hex(): {
+			MessageFormat: config.OFF_CHAIN_REGISTRY.String(),
+			Settings: map[string]interface{}{
+				"teleporter-registry-address": teleporterRegistryAddress,
 			},
 		},
 	}
-	destination := &config.DestinationBlockchain{
+	for _, teleporterContractAddress := range teleporterContractAddresses {
+		messageContracts[teleporterContractAddress] = config.MessageProtocolConfig{
+			MessageFormat: config.TELEPORTER.String(),
+			Settings: map[string]interface{}{
+				"reward-address": relayerRewardAddress,
+			},
+		}
+	}
+	return &config.SourceBlockchain{
+		SubnetID:         subnetID,
+		BlockchainID:     blockchainID,
+		VM:               config.EVM.String(),
+		RPCEndpoint:      fmt.Sprintf("http://%s:%d/ext/bc/%s/rpc", host, port, blockchainID),
+		WSEndpoint:       fmt.Sprintf("ws://%s:%d/ext/bc/%s/ws", host, port, blockchainID),
+		MessageContracts: messageContracts,
+	}
+}
+
+func newRelayerDestination(
+	host string,
+	port uint32,
+	subnetID string,
+	blockchainID string,
+	relayerFundedAddressKey string,
+) *config.DestinationBlockchain {
+	return &config.DestinationBlockchain{
 		SubnetID:          subnetID,
 		BlockchainID:      blockchainID,
 		VM:                config.EVM.String(),
 		RPCEndpoint:       fmt.Sprintf("http://%s:%d/ext/bc/%s/rpc", host, port, blockchainID),
 		AccountPrivateKey: relayerFundedAddressKey,
 	}
-	if !utils.Any(relayerConfig.SourceBlockchains, func(s *config.SourceBlockchain) bool { return s.BlockchainID == blockchainID }) {
-		relayerConfig.SourceBlockchains = append(relayerConfig.SourceBlockchains, source)
+}
+
+// RelayerChain is a teleporter enabled blockchain relayed by a relayer
+type RelayerChain struct {
+	SubnetID     string
+	BlockchainID string
+	// TeleporterMessengerAddresses are the messengers whose messages are relayed. There can be
+	// more than one after teleporter upgrades
+	TeleporterMessengerAddresses []string
+	TeleporterRegistryAddress    string
+}
+
+// RelayerRoute is a source to destination path relayed by a relayer
+type RelayerRoute struct {
+	Source      RelayerChain
+	Destination RelayerChain
+}
+
+// WriteRoutesRelayerConfig writes to [relayerConfigPath] a relayer config that only relays the
+// messages of [routes], paying for the deliveries with [relayerFundedAddressKey]. Metrics are
+// served at [metricsPort], or at the relayer default one if 0
+func WriteRoutesRelayerConfig(
+	relayerConfigPath string,
+	relayerStorageDir string,
+	processMissedBlocks bool,
+	metricsPort uint16,
+	relayerRewardAddress string,
+	relayerFundedAddressKey string,
+	network models.Network,
+	routes []RelayerRoute,
+) error {
+	host, port, err := GetURIHostAndPort(network.Endpoint)
+	if err != nil {
+		return err
 	}
-	if !utils.Any(relayerConfig.DestinationBlockchains, func(s *config.DestinationBlockchain) bool { return s.BlockchainID == blockchainID }) {
-		relayerConfig.DestinationBlockchains = append(relayerConfig.DestinationBlockchains, destination)
+	awmRelayerConfig := createRelayerConfig(
+		logging.Info.LowerString(),
+		relayerStorageDir,
+		network.Endpoint,
+	)
+	awmRelayerConfig.ProcessMissedBlocks = processMissedBlocks
+	if metricsPort != 0 {
+		awmRelayerConfig.MetricsPort = metricsPort
+	}
+	sources := map[string]*config.SourceBlockchain{}
+	for _, route := range routes {
+		source, ok := sources[route.Source.BlockchainID]
+		if !ok {
+			source = newRelayerSource(
+				host,
+				port,
+				route.Source.SubnetID,
+				route.Source.BlockchainID,
+				route.Source.TeleporterMessengerAddresses,
+				route.Source.TeleporterRegistryAddress,
+				relayerRewardAddress,
+			)
+			sources[route.Source.BlockchainID] = source
+			awmRelayerConfig.SourceBlockchains = append(awmRelayerConfig.SourceBlockchains, source)
+		}
+		// restrict the source to the destinations of its routes
+		if !slices.Contains(source.SupportedDestinations, route.Destination.BlockchainID) {
+			source.SupportedDestinations = append(source.SupportedDestinations, route.Destination.BlockchainID)
+		}
+		if !utils.Any(awmRelayerConfig.DestinationBlockchains, func(d *config.DestinationBlockchain) bool {
+			return d.BlockchainID == route.Destination.BlockchainID
+		}) {
+			awmRelayerConfig.DestinationBlockchains = append(
+				awmRelayerConfig.DestinationBlockchains,
+				newRelayerDestination(host, port, route.Destination.SubnetID, route.Destination.BlockchainID, relayerFundedAddressKey),
+			)
+		}
+	}
+	bs, err := json.MarshalIndent(awmRelayerConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(relayerConfigPath), constants.DefaultPerms755); err != nil {
+		return err
 	}
+	return os.WriteFile(relayerConfigPath, bs, constants.WriteReadReadPerms)
 }
 
 // Get the host and port from a URI. The URI should be in the format http://host:port or https://host:port or host:port
@@ -0,0 +1,183 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/awm-relayer/config"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// relayerLatestProcessedBlockKey is the key the relayer stores the last block it processed
+// of a source blockchain under, in the storage file of the blockchain
+const relayerLatestProcessedBlockKey = "latestProcessedBlock"
+
+// RelayerDestinationStatus is the balance of the relayer key on a destination blockchain
+type RelayerDestinationStatus struct {
+	BlockchainID string
+	RPCEndpoint  string
+	Address      string
+	Balance      *big.Int
+	// Err is set if the balance could not be obtained
+	Err error
+}
+
+// RelayerSourceStatus is the progress of the relayer on a source blockchain
+type RelayerSourceStatus struct {
+	BlockchainID string
+	RPCEndpoint  string
+	// ProcessedHeight is the last height the relayer processed. Only valid if Processed
+	// is set, as the relayer does not store it until it processes its first block
+	ProcessedHeight uint64
+	Processed       bool
+	Height          uint64
+	// Err is set if the blockchain height could not be obtained
+	Err error
+}
+
+// LoadRelayerConfig loads the relayer config at [relayerConfigPath]
+func LoadRelayerConfig(relayerConfigPath string) (config.Config, error) {
+	awmRelayerConfig := config.Config{}
+	if !utils.FileExists(relayerConfigPath) {
+		return awmRelayerConfig, fmt.Errorf("relayer config %s not found", relayerConfigPath)
+	}
+	bs, err := os.ReadFile(relayerConfigPath)
+	if err != nil {
+		return awmRelayerConfig, err
+	}
+	if err := json.Unmarshal(bs, &awmRelayerConfig); err != nil {
+		return awmRelayerConfig, fmt.Errorf("invalid relayer config %s: %w", relayerConfigPath, err)
+	}
+	return awmRelayerConfig, nil
+}
+
+// GetRelayerDestinationsStatus returns the balance of the relayer key of each destination
+// of [relayerConfig]
+func GetRelayerDestinationsStatus(relayerConfig config.Config) []RelayerDestinationStatus {
+	statuses := []RelayerDestinationStatus{}
+	for _, destination := range relayerConfig.DestinationBlockchains {
+		status := RelayerDestinationStatus{
+			BlockchainID: destination.BlockchainID,
+			RPCEndpoint:  destination.RPCEndpoint,
+		}
+		status.Address, status.Balance, status.Err = getRelayerKeyBalance(destination.RPCEndpoint, destination.AccountPrivateKey)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func getRelayerKeyBalance(rpcURL string, privateKey string) (string, *big.Int, error) {
+	pk, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid relayer key: %w", err)
+	}
+	address := crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return address, nil, err
+	}
+	balance, err := evm.GetAddressBalance(client, address)
+	if err != nil {
+		return address, nil, err
+	}
+	return address, balance, nil
+}
+
+// GetRelayerSourcesStatus returns, for each source of [relayerConfig], the last height processed
+// according to [processedHeights], indexed by blockchain ID, together with its current height
+func GetRelayerSourcesStatus(relayerConfig config.Config, processedHeights map[string]uint64) []RelayerSourceStatus {
+	statuses := []RelayerSourceStatus{}
+	for _, source := range relayerConfig.SourceBlockchains {
+		status := RelayerSourceStatus{
+			BlockchainID: source.BlockchainID,
+			RPCEndpoint:  source.RPCEndpoint,
+		}
+		status.ProcessedHeight, status.Processed = processedHeights[source.BlockchainID]
+		status.Height, status.Err = getBlockchainHeight(source.RPCEndpoint)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func getBlockchainHeight(rpcURL string) (uint64, error) {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return client.BlockNumber(ctx)
+}
+
+// GetRelayerStorageHeights returns the last heights processed by the relayer whose storage
+// is at [storageDir], indexed by source blockchain ID. Empty if the relayer did not store
+// anything yet
+func GetRelayerStorageHeights(storageDir string) (map[string]uint64, error) {
+	files := map[string][]byte{}
+	if utils.DirectoryExists(storageDir) {
+		entries, err := os.ReadDir(storageDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			bs, err := os.ReadFile(filepath.Join(storageDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			files[entry.Name()] = bs
+		}
+	}
+	return ParseRelayerStorageHeights(files)
+}
+
+// ParseRelayerStorageHeights returns the last processed heights stored in the relayer storage
+// [files], indexed by file name, as a map from source blockchain ID to height
+func ParseRelayerStorageHeights(files map[string][]byte) (map[string]uint64, error) {
+	heights := map[string]uint64{}
+	for fileName, bs := range files {
+		blockchainID := strings.TrimSuffix(filepath.Base(fileName), ".json")
+		height, found, err := parseRelayerStorageHeight(bs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relayer storage for %s: %w", blockchainID, err)
+		}
+		if found {
+			heights[blockchainID] = height
+		}
+	}
+	return heights, nil
+}
+
+// parseRelayerStorageHeight returns the last processed height kept in the storage file
+// of a source blockchain, if already stored
+func parseRelayerStorageHeight(bs []byte) (uint64, bool, error) {
+	state := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bs, &state); err != nil {
+		return 0, false, err
+	}
+	value, ok := state[relayerLatestProcessedBlockKey]
+	if !ok {
+		return 0, false, nil
+	}
+	// heights are stored as strings, but accept numbers too
+	var heightStr string
+	if err := json.Unmarshal(value, &heightStr); err != nil {
+		heightStr = string(value)
+	}
+	height, err := strconv.ParseUint(heightStr, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package teleporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	avalancheWarp "github.com/ava-labs/avalanchego/vms/platformvm/warp"
+	"github.com/ava-labs/avalanchego/vms/platformvm/warp/payload"
+	"github.com/ava-labs/awm-relayer/config"
+	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
+	"github.com/ava-labs/subnet-evm/core/types"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	warpPrecompile "github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/predicate"
+	subnetEvmWarp "github.com/ava-labs/subnet-evm/warp"
+	teleporterRegistry "github.com/ava-labs/teleporter/abi-bindings/go/Teleporter/upgrades/TeleporterRegistry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	warpAPIEnabledKey        = "warp-api-enabled"
+	warpOffChainMessagesKey  = "warp-off-chain-messages"
+	addProtocolVersionTxGas  = 500_000
+	addProtocolVersionMsgIdx = 0
+)
+
+// RegistryEntry is a teleporter messenger registered in a teleporter registry
+type RegistryEntry struct {
+	Version uint64
+	Address string
+}
+
+// GetRegistryEntries returns the messengers registered in the teleporter registry at
+// [registryAddress], and the latest version, which is the one used by teleporter apps
+func GetRegistryEntries(client ethclient.Client, registryAddress string) ([]RegistryEntry, uint64, error) {
+	registry, err := teleporterRegistry.NewTeleporterRegistryCaller(common.HexToAddress(registryAddress), client)
+	if err != nil {
+		return nil, 0, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	latestVersion, err := registry.LatestVersion(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failure getting latest version of teleporter registry %s: %w", registryAddress, err)
+	}
+	entries := []RegistryEntry{}
+	for version := uint64(1); version <= latestVersion.Uint64(); version++ {
+		address, err := registry.GetAddressFromVersion(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(version))
+		if err != nil {
+			// versions can be skipped when registered
+			continue
+		}
+		entries = append(entries, RegistryEntry{Version: version, Address: address.Hex()})
+	}
+	return entries, latestVersion.Uint64(), nil
+}
+
+// NewRegistryMessage returns the warp off-chain message that registers the messenger at
+// [messengerAddress] as [version] of the teleporter registry at [registryAddress], on blockchain
+// [blockchainID] of network [networkID]
+func NewRegistryMessage(
+	networkID uint32,
+	blockchainID ids.ID,
+	registryAddress string,
+	version uint64,
+	messengerAddress string,
+) (*avalancheWarp.UnsignedMessage, error) {
+	entry := teleporterRegistry.ProtocolRegistryEntry{
+		Version:         new(big.Int).SetUint64(version),
+		ProtocolAddress: common.HexToAddress(messengerAddress),
+	}
+	payloadBytes, err := teleporterRegistry.PackTeleporterRegistryWarpPayload(entry, common.HexToAddress(registryAddress))
+	if err != nil {
+		return nil, err
+	}
+	// off-chain messages have no source address, which the registry requires
+	addressedCall, err := payload.NewAddressedCall([]byte{}, payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	return avalancheWarp.NewUnsignedMessage(networkID, blockchainID, addressedCall.Bytes())
+}
+
+// AddOffChainMessageToChainConfig returns [chainConfigBytes] with [msg] added to its warp off-chain
+// messages, so that validators sign it, and with the warp API enabled, to get the signatures
+func AddOffChainMessageToChainConfig(chainConfigBytes []byte, msg *avalancheWarp.UnsignedMessage) ([]byte, error) {
+	chainConfig := map[string]interface{}{}
+	if len(chainConfigBytes) > 0 {
+		if err := json.Unmarshal(chainConfigBytes, &chainConfig); err != nil {
+			return nil, fmt.Errorf("invalid chain config: %w", err)
+		}
+	}
+	chainConfig[warpAPIEnabledKey] = true
+	msgHex := hexutil.Encode(msg.Bytes())
+	offChainMessages := []interface{}{}
+	if messages, ok := chainConfig[warpOffChainMessagesKey].([]interface{}); ok {
+		offChainMessages = messages
+	}
+	if !utils.Any(offChainMessages, func(m interface{}) bool { return m == msgHex }) {
+		offChainMessages = append(offChainMessages, msgHex)
+	}
+	chainConfig[warpOffChainMessagesKey] = offChainMessages
+	return json.MarshalIndent(chainConfig, "", "  ")
+}
+
+// GetSignedRegistryMessage gets the signature of [msg] by the validators of [subnetID], through
+// the warp API of the node at [nodeURI]
+func GetSignedRegistryMessage(
+	nodeURI string,
+	subnetID ids.ID,
+	blockchainID ids.ID,
+	msg *avalancheWarp.UnsignedMessage,
+) ([]byte, error) {
+	warpClient, err := subnetEvmWarp.NewClient(nodeURI, blockchainID.String())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	return warpClient.GetMessageAggregateSignature(ctx, msg.ID(), warpPrecompile.WarpDefaultQuorumNumerator, subnetID.String())
+}
+
+// AddProtocolVersion registers a messenger in the teleporter registry at [registryAddress] by
+// issuing [signedMessage], a signed registry message, with the key [privateKey]. Returns the
+// registered version
+func AddProtocolVersion(
+	client ethclient.Client,
+	privateKey string,
+	registryAddress string,
+	signedMessage []byte,
+) (uint64, error) {
+	pk, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return 0, err
+	}
+	callData, err := teleporterRegistry.PackAddProtocolVersion(addProtocolVersionMsgIdx)
+	if err != nil {
+		return 0, err
+	}
+	gasFeeCap, gasTipCap, nonce, err := evm.CalculateTxParams(client, crypto.PubkeyToAddress(pk.PublicKey).Hex())
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	registry := common.HexToAddress(registryAddress)
+	tx := predicate.NewPredicateTx(
+		chainID,
+		nonce,
+		&registry,
+		addProtocolVersionTxGas,
+		gasFeeCap,
+		gasTipCap,
+		big.NewInt(0),
+		callData,
+		types.AccessList{},
+		warpPrecompile.ContractAddress,
+		signedMessage,
+	)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), pk)
+	if err != nil {
+		return 0, err
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return 0, err
+	}
+	receipt, success, err := evm.WaitForTransaction(client, signedTx)
+	if err != nil {
+		return 0, err
+	}
+	if !success {
+		return 0, errors.New("failed receipt status registering teleporter messenger")
+	}
+	registryFilterer, err := teleporterRegistry.NewTeleporterRegistryFilterer(registry, client)
+	if err != nil {
+		return 0, err
+	}
+	event, err := evm.GetEventFromLogs(receipt.Logs, registryFilterer.ParseAddProtocolVersion)
+	if err != nil {
+		return 0, err
+	}
+	return event.Version.Uint64(), nil
+}
+
+// AddMessengerToRelayerConfig makes the relayer at [relayerConfigPath] deliver the messages sent
+// by the messenger at [messengerAddress] on [blockchainID], besides the ones of its previous
+// messengers. Returns false if the relayer does not relay from [blockchainID]
+func AddMessengerToRelayerConfig(
+	relayerConfigPath string,
+	blockchainID string,
+	messengerAddress string,
+	relayerRewardAddress string,
+) (bool, error) {
+	if !utils.FileExists(relayerConfigPath) {
+		return false, nil
+	}
+	bs, err := os.ReadFile(relayerConfigPath)
+	if err != nil {
+		return false, err
+	}
+	awmRelayerConfig := config.Config{}
+	if err := json.Unmarshal(bs, &awmRelayerConfig); err != nil {
+		return false, err
+	}
+	updated := false
+	for _, source := range awmRelayerConfig.SourceBlockchains {
+		if source.BlockchainID != blockchainID {
+			continue
+		}
+		if _, ok := source.MessageContracts[messengerAddress]; !ok {
+			source.MessageContracts[messengerAddress] = config.MessageProtocolConfig{
+				MessageFormat: config.TELEPORTER.String(),
+				Settings: map[string]interface{}{
+					"reward-address": relayerRewardAddress,
+				},
+			}
+		}
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+	bs, err = json.MarshalIndent(awmRelayerConfig, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(relayerConfigPath, bs, constants.WriteReadReadPerms)
+}
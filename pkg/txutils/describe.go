@@ -0,0 +1,244 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package txutils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"golang.org/x/exp/slices"
+)
+
+// TxValidatorDescription describes the validator added or removed by a tx
+type TxValidatorDescription struct {
+	NodeID    string     `json:"nodeID"`
+	Weight    uint64     `json:"weight,omitempty"`
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+// TxChainDescription describes the blockchain created by a tx
+type TxChainDescription struct {
+	Name          string   `json:"name"`
+	VMID          string   `json:"vmID"`
+	FxIDs         []string `json:"fxIDs,omitempty"`
+	GenesisSize   int      `json:"genesisSize"`
+	GenesisSHA256 string   `json:"genesisSHA256"`
+}
+
+// TxOwnerDescription describes the new subnet owner set by a tx
+type TxOwnerDescription struct {
+	Addresses []string `json:"addresses"`
+	Threshold uint32   `json:"threshold"`
+	Locktime  uint64   `json:"locktime,omitempty"`
+}
+
+// TxElasticSubnetDescription describes the elastic subnet parameters set by a tx
+type TxElasticSubnetDescription struct {
+	AssetID                  string        `json:"assetID"`
+	InitialSupply            uint64        `json:"initialSupply"`
+	MaximumSupply            uint64        `json:"maximumSupply"`
+	MinConsumptionRate       uint64        `json:"minConsumptionRate"`
+	MaxConsumptionRate       uint64        `json:"maxConsumptionRate"`
+	MinValidatorStake        uint64        `json:"minValidatorStake"`
+	MaxValidatorStake        uint64        `json:"maxValidatorStake"`
+	MinStakeDuration         time.Duration `json:"minStakeDuration"`
+	MaxStakeDuration         time.Duration `json:"maxStakeDuration"`
+	MinDelegationFee         uint32        `json:"minDelegationFee"`
+	MinDelegatorStake        uint64        `json:"minDelegatorStake"`
+	MaxValidatorWeightFactor byte          `json:"maxValidatorWeightFactor"`
+	UptimeRequirement        uint32        `json:"uptimeRequirement"`
+}
+
+// TxDescription is a decoded, human and machine readable, view of a P-Chain tx
+type TxDescription struct {
+	TxID     string `json:"txID"`
+	Type     string `json:"type"`
+	Network  string `json:"network"`
+	SubnetID string `json:"subnetID,omitempty"`
+	// amount burned by the tx, in nAVAX
+	Fee  uint64 `json:"fee"`
+	Memo string `json:"memo,omitempty"`
+
+	Validator     *TxValidatorDescription     `json:"validator,omitempty"`
+	Chain         *TxChainDescription         `json:"chain,omitempty"`
+	NewOwner      *TxOwnerDescription         `json:"newOwner,omitempty"`
+	ElasticSubnet *TxElasticSubnetDescription `json:"elasticSubnet,omitempty"`
+
+	// subnet auth signers, only available if the subnet control keys are known
+	AuthSigners      []string `json:"authSigners,omitempty"`
+	Signers          []string `json:"signers,omitempty"`
+	RemainingSigners []string `json:"remainingSigners,omitempty"`
+	FullySigned      bool     `json:"fullySigned"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// DescribeTx decodes [tx]. If [controlKeys] is not nil, it is used to compute the
+// subnet auth signers of the tx, and must be in the same order as obtained by GetOwners
+func DescribeTx(tx *txs.Tx, controlKeys []string, now time.Time) (*TxDescription, error) {
+	network, err := GetNetwork(tx)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, err := GetSubnetID(tx)
+	if err != nil {
+		return nil, err
+	}
+	desc := &TxDescription{
+		TxID:    tx.ID().String(),
+		Network: network.Name(),
+	}
+	if subnetID != ids.Empty {
+		desc.SubnetID = subnetID.String()
+	}
+	hrp := key.GetHRP(network.ID)
+	var (
+		baseTx    *txs.BaseTx
+		stakeOuts []*avax.TransferableOutput
+	)
+	switch unsignedTx := tx.Unsigned.(type) {
+	case *txs.CreateChainTx:
+		baseTx = &unsignedTx.BaseTx
+		desc.Type = "CreateChain"
+		genesisHash := sha256.Sum256(unsignedTx.GenesisData)
+		desc.Chain = &TxChainDescription{
+			Name:          unsignedTx.ChainName,
+			VMID:          unsignedTx.VMID.String(),
+			GenesisSize:   len(unsignedTx.GenesisData),
+			GenesisSHA256: hex.EncodeToString(genesisHash[:]),
+		}
+		for _, fxID := range unsignedTx.FxIDs {
+			desc.Chain.FxIDs = append(desc.Chain.FxIDs, fxID.String())
+		}
+	case *txs.AddSubnetValidatorTx:
+		baseTx = &unsignedTx.BaseTx
+		desc.Type = "AddSubnetValidator"
+		desc.Validator = describeValidator(unsignedTx.Validator)
+	case *txs.AddPermissionlessValidatorTx:
+		baseTx = &unsignedTx.BaseTx
+		stakeOuts = unsignedTx.StakeOuts
+		desc.Type = "AddPermissionlessValidator"
+		desc.Validator = describeValidator(unsignedTx.Validator)
+	case *txs.RemoveSubnetValidatorTx:
+		baseTx = &unsignedTx.BaseTx
+		desc.Type = "RemoveSubnetValidator"
+		desc.Validator = &TxValidatorDescription{NodeID: unsignedTx.NodeID.String()}
+	case *txs.TransferSubnetOwnershipTx:
+		baseTx = &unsignedTx.BaseTx
+		desc.Type = "TransferSubnetOwnership"
+		owner, ok := unsignedTx.Owner.(*secp256k1fx.OutputOwners)
+		if !ok {
+			return nil, fmt.Errorf("unexpected new owner type %T", unsignedTx.Owner)
+		}
+		desc.NewOwner = &TxOwnerDescription{
+			Threshold: owner.Threshold,
+			Locktime:  owner.Locktime,
+		}
+		for _, addr := range owner.Addrs {
+			addrStr, err := address.Format("P", hrp, addr[:])
+			if err != nil {
+				return nil, err
+			}
+			desc.NewOwner.Addresses = append(desc.NewOwner.Addresses, addrStr)
+		}
+	case *txs.TransformSubnetTx:
+		baseTx = &unsignedTx.BaseTx
+		desc.Type = "TransformSubnet"
+		desc.ElasticSubnet = &TxElasticSubnetDescription{
+			AssetID:                  unsignedTx.AssetID.String(),
+			InitialSupply:            unsignedTx.InitialSupply,
+			MaximumSupply:            unsignedTx.MaximumSupply,
+			MinConsumptionRate:       unsignedTx.MinConsumptionRate,
+			MaxConsumptionRate:       unsignedTx.MaxConsumptionRate,
+			MinValidatorStake:        unsignedTx.MinValidatorStake,
+			MaxValidatorStake:        unsignedTx.MaxValidatorStake,
+			MinStakeDuration:         time.Duration(unsignedTx.MinStakeDuration) * time.Second,
+			MaxStakeDuration:         time.Duration(unsignedTx.MaxStakeDuration) * time.Second,
+			MinDelegationFee:         unsignedTx.MinDelegationFee,
+			MinDelegatorStake:        unsignedTx.MinDelegatorStake,
+			MaxValidatorWeightFactor: unsignedTx.MaxValidatorWeightFactor,
+			UptimeRequirement:        unsignedTx.UptimeRequirement,
+		}
+	default:
+		return nil, fmt.Errorf("unexpected unsigned tx type %T", tx.Unsigned)
+	}
+	desc.Memo = string(baseTx.Memo)
+	desc.Fee = getBurnedAmount(baseTx, stakeOuts)
+
+	if controlKeys != nil && desc.Type != "AddPermissionlessValidator" {
+		authSigners, remainingSigners, err := GetRemainingSigners(tx, controlKeys)
+		if err != nil {
+			desc.Warnings = append(desc.Warnings, fmt.Sprintf(
+				"the tx subnet auth does not match the current subnet control keys (%s). It may have been created before a subnet ownership change, and will be rejected",
+				err,
+			))
+		} else {
+			desc.AuthSigners = authSigners
+			desc.RemainingSigners = remainingSigners
+			for _, signer := range authSigners {
+				if !slices.Contains(remainingSigners, signer) {
+					desc.Signers = append(desc.Signers, signer)
+				}
+			}
+			desc.FullySigned = len(remainingSigners) == 0
+		}
+	}
+	if desc.Type == "AddPermissionlessValidator" {
+		desc.FullySigned = true
+	}
+	if !desc.FullySigned && len(desc.RemainingSigners) > 0 {
+		desc.Warnings = append(desc.Warnings,
+			"the tx funds are not locked until it is committed. If the fee payer spends them in the meantime, the tx will be rejected and must be created again",
+		)
+	}
+	if desc.Validator != nil && desc.Validator.EndTime != nil && !desc.Validator.EndTime.After(now) {
+		desc.Warnings = append(desc.Warnings, "the validation end time has already passed. The tx will be rejected")
+	}
+	return desc, nil
+}
+
+func describeValidator(validator txs.Validator) *TxValidatorDescription {
+	startTime := time.Unix(int64(validator.Start), 0).UTC()
+	endTime := time.Unix(int64(validator.End), 0).UTC()
+	return &TxValidatorDescription{
+		NodeID:    validator.NodeID.String(),
+		Weight:    validator.Wght,
+		StartTime: &startTime,
+		EndTime:   &endTime,
+	}
+}
+
+// getBurnedAmount returns the amount consumed by the tx and not returned in its
+// outputs (nor staked), for the asset used to pay the fee
+func getBurnedAmount(baseTx *txs.BaseTx, stakeOuts []*avax.TransferableOutput) uint64 {
+	if len(baseTx.Ins) == 0 {
+		return 0
+	}
+	feeAssetID := baseTx.Ins[0].AssetID()
+	var consumed, produced uint64
+	for _, in := range baseTx.Ins {
+		if in.AssetID() == feeAssetID {
+			consumed += in.In.Amount()
+		}
+	}
+	for _, outs := range [][]*avax.TransferableOutput{baseTx.Outs, stakeOuts} {
+		for _, out := range outs {
+			if out.AssetID() == feeAssetID {
+				produced += out.Out.Amount()
+			}
+		}
+	}
+	if produced > consumed {
+		return 0
+	}
+	return consumed - produced
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/big"
 	"math/rand"
 	"net/http"
 	"os"
@@ -360,3 +361,25 @@ func AddSingleQuotes(s []string) []string {
 		return item
 	})
 }
+
+// ToTokenUnits converts [amount], expressed in token units, to the smallest
+// unit of a token of [decimals] decimals
+func ToTokenUnits(amount float64, decimals uint8) (*big.Int, error) {
+	intPart, fracPart, _ := strings.Cut(strconv.FormatFloat(amount, 'f', -1, 64), ".")
+	if len(fracPart) > int(decimals) {
+		return nil, fmt.Errorf("amount %f has more than %d decimals", amount, decimals)
+	}
+	fracPart += strings.Repeat("0", int(decimals)-len(fracPart))
+	v, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %f", amount)
+	}
+	return v, nil
+}
+
+// FromTokenUnits formats [amount], expressed in the smallest unit of a token
+// of [decimals] decimals, in token units
+func FromTokenUnits(amount *big.Int, decimals uint8) string {
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(divisor)).Text('f', -1)
+}
@@ -171,3 +171,19 @@ func TestAddSingleQuotes(t *testing.T) {
 		t.Errorf("AddSingleQuotes(%v) = %v, expected %v", input, output, expected)
 	}
 }
+
+func TestTokenUnits(t *testing.T) {
+	amount, err := ToTokenUnits(1.5, 18)
+	if err != nil {
+		t.Fatalf("ToTokenUnits(1.5, 18) failed: %s", err)
+	}
+	if amount.String() != "1500000000000000000" {
+		t.Errorf("ToTokenUnits(1.5, 18) = %s, expected 1500000000000000000", amount)
+	}
+	if s := FromTokenUnits(amount, 18); s != "1.5" {
+		t.Errorf("FromTokenUnits(%s, 18) = %s, expected 1.5", amount, s)
+	}
+	if _, err := ToTokenUnits(0.001, 2); err == nil {
+		t.Errorf("ToTokenUnits(0.001, 2) expected to fail")
+	}
+}
@@ -15,12 +15,20 @@ import (
 	"golang.org/x/crypto/ssh/agent"
 )
 
-// GetSSHConnectionString returns the SSH connection string for the given public IP and certificate file path.
-func GetSSHConnectionString(publicIP, certFilePath string) string {
+// GetSSHConnectionString returns the SSH connection string for the given user, public IP, port and certificate file path.
+// Empty user and zero port default to the ones used on cloud servers
+func GetSSHConnectionString(sshUser, publicIP string, port uint, certFilePath string) string {
+	if sshUser == "" {
+		sshUser = constants.AnsibleSSHUser
+	}
+	portParam := ""
+	if port != 0 && port != constants.SSHTCPPort {
+		portParam = fmt.Sprintf(" -p %d", port)
+	}
 	if certFilePath != "" {
-		return fmt.Sprintf("ssh %s %s@%s -i %s", constants.AnsibleSSHShellParams, constants.AnsibleSSHUser, publicIP, certFilePath)
+		return fmt.Sprintf("ssh %s%s %s@%s -i %s", constants.AnsibleSSHShellParams, portParam, sshUser, publicIP, certFilePath)
 	} else {
-		return fmt.Sprintf("ssh %s %s@%s", constants.AnsibleSSHUseAgentParams, constants.AnsibleSSHUser, publicIP)
+		return fmt.Sprintf("ssh %s%s %s@%s", constants.AnsibleSSHUseAgentParams, portParam, sshUser, publicIP)
 	}
 }
 
@@ -0,0 +1,112 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat defines how commands render their results to the user
+type OutputFormat string
+
+const (
+	TableOutputFormat OutputFormat = "table"
+	JSONOutputFormat  OutputFormat = "json"
+	YAMLOutputFormat  OutputFormat = "yaml"
+
+	// OutputSchemaVersion must be increased on any breaking change to
+	// the structured output of any command
+	OutputSchemaVersion = "1"
+
+	// StructuredOutputAnnotation marks the commands that support json/yaml output
+	StructuredOutputAnnotation = "structuredOutput"
+)
+
+var outputFormat = TableOutputFormat
+
+// StructuredOutput is the envelope used for every json/yaml command result
+type StructuredOutput struct {
+	SchemaVersion string      `json:"schemaVersion"`
+	Kind          string      `json:"kind"`
+	Data          interface{} `json:"data"`
+}
+
+// ParseOutputFormat validates the user given output format
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(s); f {
+	case TableOutputFormat, JSONOutputFormat, YAMLOutputFormat:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format %q. Valid values are %q, %q, %q",
+		s, TableOutputFormat, JSONOutputFormat, YAMLOutputFormat)
+}
+
+// SetOutputFormat sets the output format to be used by all commands
+func SetOutputFormat(f OutputFormat) {
+	outputFormat = f
+}
+
+// GetOutputFormat returns the output format selected by the user
+func GetOutputFormat() OutputFormat {
+	return outputFormat
+}
+
+// IsStructuredOutput returns true if the user asked for a machine readable output
+func IsStructuredOutput() bool {
+	return outputFormat == JSONOutputFormat || outputFormat == YAMLOutputFormat
+}
+
+// StructuredOutputAnnotations returns the command annotations of a command
+// that supports json/yaml output
+func StructuredOutputAnnotations() map[string]string {
+	return map[string]string{StructuredOutputAnnotation: "true"}
+}
+
+// SupportsStructuredOutput returns true if the command [annotations] mark
+// it as supporting json/yaml output
+func SupportsStructuredOutput(annotations map[string]string) bool {
+	return annotations[StructuredOutputAnnotation] == "true"
+}
+
+// PrintStructured writes [data] to stdout, wrapped in the versioned
+// envelope, using the selected output format
+func PrintStructured(kind string, data interface{}) error {
+	return WriteStructured(os.Stdout, outputFormat, kind, data)
+}
+
+// WriteStructured writes [data] to [w], wrapped in the versioned envelope,
+// using format [f]. yaml output is derived from the json encoding so
+// that both formats share exactly the same schema
+func WriteStructured(w io.Writer, f OutputFormat, kind string, data interface{}) error {
+	out := StructuredOutput{
+		SchemaVersion: OutputSchemaVersion,
+		Kind:          kind,
+		Data:          data,
+	}
+	jsonBytes, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	switch f {
+	case JSONOutputFormat:
+		_, err = fmt.Fprintln(w, string(jsonBytes))
+		return err
+	case YAMLOutputFormat:
+		var generic interface{}
+		if err := json.Unmarshal(jsonBytes, &generic); err != nil {
+			return err
+		}
+		yamlBytes, err := yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(yamlBytes)
+		return err
+	}
+	return fmt.Errorf("output format %q is not a structured format", f)
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2022, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ux
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	require := require.New(t)
+
+	for _, s := range []string{"table", "json", "yaml"} {
+		f, err := ParseOutputFormat(s)
+		require.NoError(err)
+		require.Equal(OutputFormat(s), f)
+	}
+	_, err := ParseOutputFormat("xml")
+	require.Error(err)
+}
+
+func TestWriteStructured(t *testing.T) {
+	require := require.New(t)
+
+	type row struct {
+		Name   string `json:"name"`
+		Weight uint64 `json:"weight"`
+	}
+	data := []row{{Name: "node1", Weight: 20}}
+
+	var jsonBuf bytes.Buffer
+	require.NoError(WriteStructured(&jsonBuf, JSONOutputFormat, "test", data))
+	var fromJSON map[string]interface{}
+	require.NoError(json.Unmarshal(jsonBuf.Bytes(), &fromJSON))
+	require.Equal(OutputSchemaVersion, fromJSON["schemaVersion"])
+	require.Equal("test", fromJSON["kind"])
+
+	var yamlBuf bytes.Buffer
+	require.NoError(WriteStructured(&yamlBuf, YAMLOutputFormat, "test", data))
+	var fromYAML map[string]interface{}
+	require.NoError(yaml.Unmarshal(yamlBuf.Bytes(), &fromYAML))
+	require.Equal(OutputSchemaVersion, fromYAML["schemaVersion"])
+	rows, ok := fromYAML["data"].([]interface{})
+	require.True(ok)
+	require.Len(rows, 1)
+	require.Equal("node1", rows[0].(map[string]interface{})["name"])
+
+	require.Error(WriteStructured(&jsonBuf, TableOutputFormat, "test", data))
+}